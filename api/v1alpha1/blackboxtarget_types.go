@@ -29,9 +29,25 @@ type BlackboxTargetStatus struct {
 	// Important: Run "operator-sdk generate k8s" to regenerate code after modifying this file
 	// Add custom validation using kubebuilder tags: https://book.kubebuilder.io/beyond_basics/generating_crd.html
 	Phase int `json:"phase"`
+	// Targets holds the last probe result of every target
+	Targets []BlackboxTargetProbeStatus `json:"targets,omitempty"`
+}
+
+// BlackboxTargetProbeStatus is the last probe result of a target as reported by the OBO prometheus
+type BlackboxTargetProbeStatus struct {
+	Service string `json:"service"`
+	Url     string `json:"url"`
+	// Success is unset until a probe result is available
+	Success        *bool            `json:"success,omitempty"`
+	HTTPStatusCode int              `json:"httpStatusCode,omitempty"`
+	Latency        *metav1.Duration `json:"latency,omitempty"`
+	TLSExpiry      *metav1.Time     `json:"tlsExpiry,omitempty"`
+	LastProbeTime  *metav1.Time     `json:"lastProbeTime,omitempty"`
+	Message        string           `json:"message,omitempty"`
 }
 
 //+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
 
 // BlackboxTarget is the Schema for the blackboxtargets API
 type BlackboxTarget struct {
@@ -15,3 +15,27 @@ func IsRHOAMSingletenant(installType InstallationType) bool {
 func isMultitenant(installType InstallationType) bool {
 	return installType == InstallationTypeMultitenantManagedApi
 }
+
+// IsDeveloper is a lightweight installation of RHSSO and 3scale for development, without rate limiting,
+// quota or the observability stack. The reconcilers check the capabilities below rather than the installation
+// type, so the reason a component is skipped is explicit where it is skipped
+func IsDeveloper(installType InstallationType) bool {
+	return installType == InstallationTypeDeveloper
+}
+
+// HasObservability returns whether the installation runs the observability stack, which the alerts, the
+// alertmanager configuration and the alert silences are reconciled in
+func HasObservability(installType InstallationType) bool {
+	return !IsDeveloper(installType)
+}
+
+// HasRateLimiting returns whether the installation rate limits the 3scale API traffic. Installations without
+// rate limiting have a fixed quota instead of the one of the quota addon parameter
+func HasRateLimiting(installType InstallationType) bool {
+	return !IsDeveloper(installType)
+}
+
+// HasMinimalResources returns whether the product components run with minimal resource requests
+func HasMinimalResources(installType InstallationType) bool {
+	return IsDeveloper(installType)
+}
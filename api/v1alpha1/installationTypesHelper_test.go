@@ -20,6 +20,11 @@ func TestRHOAMInstallType(t *testing.T) {
 			installType:     "Dummy Type",
 			expectedOutcome: false,
 		},
+		{
+			name:            "test that isRHOAM returns false for developer installations",
+			installType:     InstallationTypeDeveloper,
+			expectedOutcome: false,
+		},
 	}
 	for _, c := range tests {
 		t.Run(c.name, func(t *testing.T) {
@@ -84,3 +89,68 @@ func TestRHOAMMultitenant(t *testing.T) {
 		})
 	}
 }
+
+func TestIsDeveloper(t *testing.T) {
+	tests := []struct {
+		name            string
+		installType     InstallationType
+		expectedOutcome bool
+	}{
+		{
+			name:            "test that isDeveloper returns true",
+			installType:     InstallationTypeDeveloper,
+			expectedOutcome: true,
+		},
+		{
+			name:            "test that isDeveloper returns false",
+			installType:     InstallationTypeManagedApi,
+			expectedOutcome: false,
+		},
+	}
+	for _, c := range tests {
+		t.Run(c.name, func(t *testing.T) {
+			v := IsDeveloper(c.installType)
+			if v != c.expectedOutcome {
+				t.Errorf("Outcome does not match expected value - got %v; expecting %v", v, c.expectedOutcome)
+			}
+		})
+	}
+}
+
+func TestInstallationCapabilities(t *testing.T) {
+	tests := []struct {
+		name                    string
+		installType             InstallationType
+		expectedObservability   bool
+		expectedRateLimiting    bool
+		expectedMinimalResource bool
+	}{
+		{
+			name:                    "test that the developer installation runs with minimal resources only",
+			installType:             InstallationTypeDeveloper,
+			expectedObservability:   false,
+			expectedRateLimiting:    false,
+			expectedMinimalResource: true,
+		},
+		{
+			name:                    "test that the managed api installation runs observability and rate limiting",
+			installType:             InstallationTypeManagedApi,
+			expectedObservability:   true,
+			expectedRateLimiting:    true,
+			expectedMinimalResource: false,
+		},
+	}
+	for _, c := range tests {
+		t.Run(c.name, func(t *testing.T) {
+			if v := HasObservability(c.installType); v != c.expectedObservability {
+				t.Errorf("HasObservability does not match expected value - got %v; expecting %v", v, c.expectedObservability)
+			}
+			if v := HasRateLimiting(c.installType); v != c.expectedRateLimiting {
+				t.Errorf("HasRateLimiting does not match expected value - got %v; expecting %v", v, c.expectedRateLimiting)
+			}
+			if v := HasMinimalResources(c.installType); v != c.expectedMinimalResource {
+				t.Errorf("HasMinimalResources does not match expected value - got %v; expecting %v", v, c.expectedMinimalResource)
+			}
+		})
+	}
+}
@@ -31,7 +31,11 @@ func (c RHMIConditionType) String() string {
 	return path.Join(group, string(c))
 }
 
-const HealthyConditionType RHMIConditionType = "Healthy"
+const (
+	HealthyConditionType        RHMIConditionType = "Healthy"
+	UpgradeGatesConditionType   RHMIConditionType = "UpgradeGates"
+	STSCredentialsConditionType RHMIConditionType = "STSCredentials"
+)
 
 func (i *RHMI) InstalledCondition() metav1.Condition {
 	return addoninstance.NewAddonInstanceConditionInstalled(
@@ -98,6 +102,30 @@ func (i *RHMI) ReadyToBeDeletedCondition() metav1.Condition {
 	}
 }
 
+func (i *RHMI) UpgradeGatesPassedCondition() metav1.Condition {
+	return newRHMICondition(UpgradeGatesConditionType, metav1.ConditionTrue, "Passed", "All upgrade gates passed")
+}
+
+func (i *RHMI) UpgradeGatesBlockedCondition(failedGates []string) metav1.Condition {
+	return newRHMICondition(UpgradeGatesConditionType, metav1.ConditionFalse, "Blocked", fmt.Sprintf("Upgrade gates failed: %s", failedGates))
+}
+
+// UpgradeGateCondition returns the condition recording the result of a single pre-approval upgrade gate
+func (i *RHMI) UpgradeGateCondition(gate string, passed bool, msg string) metav1.Condition {
+	if passed {
+		return newRHMICondition(UpgradeGatesConditionType+RHMIConditionType(gate), metav1.ConditionTrue, "Passed", msg)
+	}
+	return newRHMICondition(UpgradeGatesConditionType+RHMIConditionType(gate), metav1.ConditionFalse, "Failed", msg)
+}
+
+func (i *RHMI) STSCredentialsHealthyCondition() metav1.Condition {
+	return newRHMICondition(STSCredentialsConditionType, metav1.ConditionTrue, "Healthy", "STS role assumed with the web identity token")
+}
+
+func (i *RHMI) STSCredentialsUnhealthyCondition(msg string) metav1.Condition {
+	return newRHMICondition(STSCredentialsConditionType, metav1.ConditionFalse, "Unhealthy", msg)
+}
+
 func newRHMICondition(conditionType RHMIConditionType, conditionStatus metav1.ConditionStatus, reason, msg string) metav1.Condition {
 	return metav1.Condition{
 		Type:    conditionType.String(),
@@ -0,0 +1,140 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type HistoryEntryType string
+type HistoryOutcome string
+
+var (
+	HistoryEntryUpgrade     HistoryEntryType = "upgrade"
+	HistoryEntryQuotaChange HistoryEntryType = "quotaChange"
+	HistoryEntryUninstall   HistoryEntryType = "uninstall"
+
+	HistoryOutcomeInProgress HistoryOutcome = "in progress"
+	HistoryOutcomeSucceeded  HistoryOutcome = "succeeded"
+	HistoryOutcomeSuperseded HistoryOutcome = "superseded"
+	HistoryOutcomeFailed     HistoryOutcome = "failed"
+
+	// MaxHistoryEntries bounds the number of entries kept in the status history, the oldest are dropped first
+	MaxHistoryEntries = 10
+)
+
+// RHMIHistoryEntry records a version upgrade, quota change or uninstall attempt
+type RHMIHistoryEntry struct {
+	Type      HistoryEntryType   `json:"type"`
+	From      string             `json:"from,omitempty"`
+	To        string             `json:"to,omitempty"`
+	StartTime metav1.Time        `json:"startTime"`
+	EndTime   *metav1.Time       `json:"endTime,omitempty"`
+	Stages    []RHMIHistoryStage `json:"stages,omitempty"`
+	Outcome   HistoryOutcome     `json:"outcome"`
+	Message   string             `json:"message,omitempty"`
+}
+
+// RHMIHistoryStage records how long a single stage took as part of a history entry
+type RHMIHistoryStage struct {
+	Name      StageName        `json:"name"`
+	StartTime metav1.Time      `json:"startTime"`
+	Duration  *metav1.Duration `json:"duration,omitempty"`
+}
+
+// GetInProgressHistoryEntry returns the in progress history entry of the given type, or nil if there is none
+func (i *RHMI) GetInProgressHistoryEntry(entryType HistoryEntryType) *RHMIHistoryEntry {
+	for idx := range i.Status.History {
+		entry := &i.Status.History[idx]
+		if entry.Type == entryType && entry.Outcome == HistoryOutcomeInProgress {
+			return entry
+		}
+	}
+	return nil
+}
+
+// StartHistoryEntry returns the in progress history entry of the given type, creating it if needed.
+// An in progress entry targeting a different value is closed as superseded. The last entry of the type is
+// resumed when it failed with the same target, so that a retry isn't recorded as a new entry
+func (i *RHMI) StartHistoryEntry(entryType HistoryEntryType, from, to string, now metav1.Time) *RHMIHistoryEntry {
+	if entry := i.GetInProgressHistoryEntry(entryType); entry != nil {
+		if entry.To == to {
+			return entry
+		}
+		entry.Complete(HistoryOutcomeSuperseded, "superseded by "+to, now)
+	}
+	if entry := i.getLastHistoryEntry(entryType); entry != nil && entry.Outcome == HistoryOutcomeFailed && entry.To == to {
+		entry.Outcome = HistoryOutcomeInProgress
+		entry.Message = ""
+		entry.EndTime = nil
+		return entry
+	}
+
+	i.Status.History = append(i.Status.History, RHMIHistoryEntry{
+		Type:      entryType,
+		From:      from,
+		To:        to,
+		StartTime: now,
+		Outcome:   HistoryOutcomeInProgress,
+	})
+	if len(i.Status.History) > MaxHistoryEntries {
+		i.Status.History = i.Status.History[len(i.Status.History)-MaxHistoryEntries:]
+	}
+
+	return &i.Status.History[len(i.Status.History)-1]
+}
+
+// IsHistoryEntryFailed returns whether the last entry of the given type failed targeting the given value
+func (i *RHMI) IsHistoryEntryFailed(entryType HistoryEntryType, to string) bool {
+	entry := i.getLastHistoryEntry(entryType)
+	return entry != nil && entry.Outcome == HistoryOutcomeFailed && entry.To == to
+}
+
+func (i *RHMI) getLastHistoryEntry(entryType HistoryEntryType) *RHMIHistoryEntry {
+	for idx := len(i.Status.History) - 1; idx >= 0; idx-- {
+		if i.Status.History[idx].Type == entryType {
+			return &i.Status.History[idx]
+		}
+	}
+	return nil
+}
+
+// StageStarted records the start time of a stage if it has not been recorded yet
+func (e *RHMIHistoryEntry) StageStarted(stage StageName, now metav1.Time) {
+	if e.getStage(stage) != nil {
+		return
+	}
+	e.Stages = append(e.Stages, RHMIHistoryStage{Name: stage, StartTime: now})
+}
+
+// StageCompleted records the duration of a stage if it has not been recorded yet
+func (e *RHMIHistoryEntry) StageCompleted(stage StageName, now metav1.Time) {
+	e.StageStarted(stage, now)
+	historyStage := e.getStage(stage)
+	if historyStage.Duration != nil {
+		return
+	}
+	historyStage.Duration = &metav1.Duration{Duration: now.Sub(historyStage.StartTime.Time)}
+}
+
+// Complete closes the history entry with the given outcome
+func (e *RHMIHistoryEntry) Complete(outcome HistoryOutcome, message string, now metav1.Time) {
+	e.Outcome = outcome
+	e.Message = message
+	e.EndTime = &now
+}
+
+// GetDuration returns the time between the start and the end of the entry, or zero if the entry is in progress
+func (e *RHMIHistoryEntry) GetDuration() metav1.Duration {
+	if e.EndTime == nil {
+		return metav1.Duration{}
+	}
+	return metav1.Duration{Duration: e.EndTime.Sub(e.StartTime.Time)}
+}
+
+func (e *RHMIHistoryEntry) getStage(stage StageName) *RHMIHistoryStage {
+	for idx := range e.Stages {
+		if e.Stages[idx].Name == stage {
+			return &e.Stages[idx]
+		}
+	}
+	return nil
+}
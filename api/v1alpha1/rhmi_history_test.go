@@ -0,0 +1,135 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestStartHistoryEntry(t *testing.T) {
+	start := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	later := metav1.NewTime(start.Add(time.Hour))
+
+	tests := []struct {
+		name         string
+		history      []RHMIHistoryEntry
+		to           string
+		wantLen      int
+		wantOutcomes []HistoryOutcome
+	}{
+		{
+			name:         "entry is created when there is none in progress",
+			to:           "1.2.0",
+			wantLen:      1,
+			wantOutcomes: []HistoryOutcome{HistoryOutcomeInProgress},
+		},
+		{
+			name: "in progress entry with the same target is reused",
+			history: []RHMIHistoryEntry{
+				{Type: HistoryEntryUpgrade, From: "1.1.0", To: "1.2.0", StartTime: start, Outcome: HistoryOutcomeInProgress},
+			},
+			to:           "1.2.0",
+			wantLen:      1,
+			wantOutcomes: []HistoryOutcome{HistoryOutcomeInProgress},
+		},
+		{
+			name: "in progress entry with a different target is superseded",
+			history: []RHMIHistoryEntry{
+				{Type: HistoryEntryUpgrade, From: "1.1.0", To: "1.2.0", StartTime: start, Outcome: HistoryOutcomeInProgress},
+			},
+			to:           "1.3.0",
+			wantLen:      2,
+			wantOutcomes: []HistoryOutcome{HistoryOutcomeSuperseded, HistoryOutcomeInProgress},
+		},
+		{
+			name: "in progress entries of other types are left untouched",
+			history: []RHMIHistoryEntry{
+				{Type: HistoryEntryQuotaChange, From: "1", To: "5", StartTime: start, Outcome: HistoryOutcomeInProgress},
+			},
+			to:           "1.2.0",
+			wantLen:      2,
+			wantOutcomes: []HistoryOutcome{HistoryOutcomeInProgress, HistoryOutcomeInProgress},
+		},
+		{
+			name: "failed entry with the same target is resumed",
+			history: []RHMIHistoryEntry{
+				{Type: HistoryEntryUpgrade, From: "1.1.0", To: "1.2.0", StartTime: start, EndTime: &later, Outcome: HistoryOutcomeFailed, Message: "stage failed"},
+			},
+			to:           "1.2.0",
+			wantLen:      1,
+			wantOutcomes: []HistoryOutcome{HistoryOutcomeInProgress},
+		},
+		{
+			name: "failed entry with a different target is kept",
+			history: []RHMIHistoryEntry{
+				{Type: HistoryEntryUpgrade, From: "1.1.0", To: "1.2.0", StartTime: start, EndTime: &later, Outcome: HistoryOutcomeFailed, Message: "stage failed"},
+			},
+			to:           "1.3.0",
+			wantLen:      2,
+			wantOutcomes: []HistoryOutcome{HistoryOutcomeFailed, HistoryOutcomeInProgress},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installation := &RHMI{Status: RHMIStatus{Version: "1.1.0", History: tt.history}}
+
+			entry := installation.StartHistoryEntry(HistoryEntryUpgrade, installation.Status.Version, tt.to, later)
+			if entry.To != tt.to || entry.Outcome != HistoryOutcomeInProgress {
+				t.Fatalf("unexpected entry returned: %+v", entry)
+			}
+			if len(installation.Status.History) != tt.wantLen {
+				t.Fatalf("expected %d history entries, got %d", tt.wantLen, len(installation.Status.History))
+			}
+			for i, outcome := range tt.wantOutcomes {
+				if installation.Status.History[i].Outcome != outcome {
+					t.Errorf("expected entry %d outcome %q, got %q", i, outcome, installation.Status.History[i].Outcome)
+				}
+			}
+		})
+	}
+}
+
+func TestStartHistoryEntryBounded(t *testing.T) {
+	installation := &RHMI{}
+	now := metav1.Now()
+	for i := 0; i < MaxHistoryEntries+3; i++ {
+		installation.StartHistoryEntry(HistoryEntryUpgrade, "", fmt.Sprintf("1.%d.0", i), now)
+	}
+
+	if len(installation.Status.History) != MaxHistoryEntries {
+		t.Fatalf("expected %d history entries, got %d", MaxHistoryEntries, len(installation.Status.History))
+	}
+	if installation.Status.History[0].To != "1.3.0" {
+		t.Fatalf("expected the oldest entries to be dropped, first entry is %s", installation.Status.History[0].To)
+	}
+}
+
+func TestHistoryEntryStageDurations(t *testing.T) {
+	start := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	installation := &RHMI{}
+
+	entry := installation.StartHistoryEntry(HistoryEntryUpgrade, "1.1.0", "1.2.0", start)
+	entry.StageStarted(InstallStage, start)
+	// a stage reported again as in progress keeps its original start time
+	entry.StageStarted(InstallStage, metav1.NewTime(start.Add(time.Minute)))
+	entry.StageCompleted(InstallStage, metav1.NewTime(start.Add(10*time.Minute)))
+	// a completed stage keeps its original duration
+	entry.StageCompleted(InstallStage, metav1.NewTime(start.Add(20*time.Minute)))
+	entry.Complete(HistoryOutcomeSucceeded, "", metav1.NewTime(start.Add(15*time.Minute)))
+
+	if len(entry.Stages) != 1 {
+		t.Fatalf("expected 1 stage, got %d", len(entry.Stages))
+	}
+	if entry.Stages[0].Duration == nil || entry.Stages[0].Duration.Duration != 10*time.Minute {
+		t.Fatalf("expected stage duration of 10m, got %v", entry.Stages[0].Duration)
+	}
+	if entry.GetDuration().Duration != 15*time.Minute {
+		t.Fatalf("expected entry duration of 15m, got %v", entry.GetDuration().Duration)
+	}
+	if installation.GetInProgressHistoryEntry(HistoryEntryUpgrade) != nil {
+		t.Fatal("expected no in progress upgrade entry after completion")
+	}
+}
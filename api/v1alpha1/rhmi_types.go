@@ -43,6 +43,7 @@ var (
 
 	InstallationTypeManagedApi            InstallationType = "managed-api"
 	InstallationTypeMultitenantManagedApi InstallationType = "multitenant-managed-api"
+	InstallationTypeDeveloper             InstallationType = "developer"
 
 	BootstrapStage               StageName = "bootstrap"
 	InstallStage                 StageName = "installation"
@@ -82,10 +83,23 @@ var (
 	OperatorVersionMarin3r        OperatorVersion = "0.13.4"
 
 	// Event reasons to be used when emitting events
-	EventProcessingError       = "ProcessingError"
-	EventInstallationCompleted = "InstallationCompleted"
-	EventPreflightCheckPassed  = "PreflightCheckPassed"
-	EventUpgradeApproved       = "UpgradeApproved"
+	EventProcessingError          = "ProcessingError"
+	EventInstallationCompleted    = "InstallationCompleted"
+	EventPreflightCheckPassed     = "PreflightCheckPassed"
+	EventUpgradeApproved          = "UpgradeApproved"
+	EventUpgradeGatePassed        = "UpgradeGatePassed"
+	EventUpgradeGateFailed        = "UpgradeGateFailed"
+	EventOpenAPIOnboarded         = "OpenAPIOnboarded"
+	EventOpenAPIFailed            = "OpenAPIOnboardingFailed"
+	EventAlertsSilenced           = "AlertsSilenced"
+	EventAlertsUnsilenced         = "AlertsUnsilenced"
+	EventAlertsSilenceRenewed     = "AlertsSilenceRenewed"
+	EventUserProvisioned          = "UserProvisioned"
+	EventUserProvisioningError    = "UserProvisioningFailed"
+	EventUninstallDataExported    = "UninstallDataExported"
+	EventUninstallDataNotExported = "UninstallDataNotExported"
+	EventUninstallConfirmed       = "UninstallConfirmed"
+	EventUninstallCompleted       = "UninstallCompleted"
 
 	DefaultOriginPullSecretName      = "pull-secret"
 	DefaultOriginPullSecretNamespace = "openshift-config" // #nosec G101 -- This is a false positive
@@ -145,6 +159,20 @@ type RHMISpec struct {
 	//
 	// url
 	DeadMansSnitchSecret string `json:"deadMansSnitchSecret,omitempty"`
+
+	// DeletionProtection makes the uninstall two-phase. The 3scale
+	// and RHSSO data is exported first, and the products and cloud
+	// resources are only deleted once the uninstall is confirmed
+	// with the integreatly.org/confirm-uninstall annotation set to
+	// the confirmation token in the uninstall status
+	DeletionProtection bool `json:"deletionProtection,omitempty"`
+
+	// DisabledProducts are the optional products to turn off on
+	// the installation: grafana, rhssouser or marin3r, which
+	// provides the rate limiting of 3scale. A disabled product
+	// is uninstalled if it was installed, and installed again
+	// once it is removed from the list
+	DisabledProducts []ProductName `json:"disabledProducts,omitempty"`
 }
 
 type PullSecretSpec struct {
@@ -184,6 +212,28 @@ type RHMIStatus struct {
 	ToQuota            string                        `json:"toQuota,omitempty"`
 	CustomSmtp         *CustomSmtpStatus             `json:"customSmtp,omitempty"`
 	CustomDomain       *CustomDomainStatus           `json:"customDomain,omitempty"`
+	Conditions         []metav1.Condition            `json:"conditions,omitempty"`
+	History            []RHMIHistoryEntry            `json:"history,omitempty"`
+	Uninstall          *RHMIUninstallStatus          `json:"uninstall,omitempty"`
+	// The schema version of the config of each product and which reconciler last changed each of its keys
+	ProductConfigs map[ProductName]RHMIProductConfigStatus `json:"productConfigs,omitempty"`
+}
+
+// RHMIProductConfigStatus reports the schema version of the config of a product and the last change of
+// each of its keys
+type RHMIProductConfigStatus struct {
+	SchemaVersion int                   `json:"schemaVersion"`
+	Changes       []RHMIConfigKeyChange `json:"changes,omitempty"`
+}
+
+// RHMIConfigKeyChange is the last change of a key of the config of a product
+type RHMIConfigKeyChange struct {
+	Key       string      `json:"key"`
+	ChangedBy string      `json:"changedBy"`
+	ChangedAt metav1.Time `json:"changedAt"`
+	Deleted   bool        `json:"deleted,omitempty"`
+	// The reconciler whose concurrent change to the key was overwritten by this change
+	Overwrote string `json:"overwrote,omitempty"`
 }
 
 type RHMIStageStatus struct {
@@ -201,6 +251,22 @@ type RHMIProductStatus struct {
 	Mobile          bool            `json:"mobile,omitempty"`
 	Phase           StatusPhase     `json:"status"`
 	Uninstall       bool            `json:"uninstall,omitempty"`
+	// Disabled when the product is turned off in the spec and has been uninstalled
+	Disabled bool `json:"disabled,omitempty"`
+	// The declaration the product operator is installed from, including the runtime overrides
+	Declaration *RHMIProductDeclaration `json:"declaration,omitempty"`
+}
+
+// RHMIProductDeclaration reports where a product operator is installed from
+type RHMIProductDeclaration struct {
+	InstallFrom  string `json:"installFrom"`
+	Index        string `json:"index,omitempty"`
+	ManifestsDir string `json:"manifestsDir,omitempty"`
+	Channel      string `json:"channel,omitempty"`
+	Package      string `json:"package,omitempty"`
+	Overridden   bool   `json:"overridden,omitempty"`
+	// OverrideError is why the override of the declaration was skipped
+	OverrideError string `json:"overrideError,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -293,6 +359,16 @@ func (i *RHMI) IsCoreComponentsHealthy() bool {
 		i.IsProductInInstallStagePhaseComplete(Product3Scale)
 }
 
+// IsProductDisabled when the product is turned off in the spec
+func (i *RHMI) IsProductDisabled(productName ProductName) bool {
+	for _, disabled := range i.Spec.DisabledProducts {
+		if disabled == productName {
+			return true
+		}
+	}
+	return false
+}
+
 // +kubebuilder:object:root=true
 
 // RHMIList contains a list of RHMI
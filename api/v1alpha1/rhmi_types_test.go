@@ -319,3 +319,40 @@ func TestRHMI_IsUninstalling(t *testing.T) {
 		})
 	}
 }
+
+func TestRHMI_IsProductDisabled(t *testing.T) {
+	tests := []struct {
+		name             string
+		disabledProducts []ProductName
+		product          ProductName
+		want             bool
+	}{
+		{
+			name:             "test true when product is in the disabled products",
+			disabledProducts: []ProductName{ProductGrafana, ProductMarin3r},
+			product:          ProductMarin3r,
+			want:             true,
+		},
+		{
+			name:             "test false when product is not in the disabled products",
+			disabledProducts: []ProductName{ProductGrafana},
+			product:          ProductMarin3r,
+			want:             false,
+		},
+		{
+			name:    "test false when no products are disabled",
+			product: ProductMarin3r,
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &RHMI{
+				Spec: RHMISpec{DisabledProducts: tt.disabledProducts},
+			}
+			if got := i.IsProductDisabled(tt.product); got != tt.want {
+				t.Errorf("IsProductDisabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// UninstallConfirmationAnnotation confirms an uninstall with deletion protection. Its value must be the
+// confirmation token reported in the uninstall status once the product data is exported, or right away
+// when the data is in the cluster storage and can't be exported
+const UninstallConfirmationAnnotation = "integreatly.org/confirm-uninstall"
+
+// RHMIUninstallStatus reports the data exported by an uninstall with deletion protection and the token
+// that confirms the uninstall
+type RHMIUninstallStatus struct {
+	Exported          bool                  `json:"exported"`
+	ExportTime        *metav1.Time          `json:"exportTime,omitempty"`
+	Exports           []RHMIUninstallExport `json:"exports,omitempty"`
+	ConfirmationToken string                `json:"confirmationToken,omitempty"`
+	Confirmed         bool                  `json:"confirmed,omitempty"`
+	Message           string                `json:"message,omitempty"`
+}
+
+// RHMIUninstallExport is a snapshot of the data of a product taken before it is uninstalled. Snapshot is
+// the namespace/name of the snapshot CR and SnapshotID the ID of the snapshot in the cloud provider
+type RHMIUninstallExport struct {
+	Product      ProductName `json:"product"`
+	Type         string      `json:"type"`
+	ResourceName string      `json:"resourceName"`
+	Snapshot     string      `json:"snapshot"`
+	SnapshotID   string      `json:"snapshotID,omitempty"`
+}
+
+// IsUninstallConfirmed when the uninstall was confirmed with the token reported in the uninstall status
+func (i *RHMI) IsUninstallConfirmed() bool {
+	status := i.Status.Uninstall
+	if status == nil || status.ConfirmationToken == "" {
+		return false
+	}
+	return status.Confirmed || i.GetAnnotations()[UninstallConfirmationAnnotation] == status.ConfirmationToken
+}
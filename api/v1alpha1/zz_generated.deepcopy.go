@@ -21,6 +21,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -134,7 +135,7 @@ func (in *BlackboxTarget) DeepCopyInto(out *BlackboxTarget) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlackboxTarget.
@@ -187,6 +188,39 @@ func (in *BlackboxTargetList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlackboxTargetProbeStatus) DeepCopyInto(out *BlackboxTargetProbeStatus) {
+	*out = *in
+	if in.Success != nil {
+		in, out := &in.Success, &out.Success
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Latency != nil {
+		in, out := &in.Latency, &out.Latency
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.TLSExpiry != nil {
+		in, out := &in.TLSExpiry, &out.TLSExpiry
+		*out = (*in).DeepCopy()
+	}
+	if in.LastProbeTime != nil {
+		in, out := &in.LastProbeTime, &out.LastProbeTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlackboxTargetProbeStatus.
+func (in *BlackboxTargetProbeStatus) DeepCopy() *BlackboxTargetProbeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BlackboxTargetProbeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BlackboxTargetSpec) DeepCopyInto(out *BlackboxTargetSpec) {
 	*out = *in
@@ -210,6 +244,13 @@ func (in *BlackboxTargetSpec) DeepCopy() *BlackboxTargetSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BlackboxTargetStatus) DeepCopyInto(out *BlackboxTargetStatus) {
 	*out = *in
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]BlackboxTargetProbeStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlackboxTargetStatus.
@@ -287,7 +328,7 @@ func (in *RHMI) DeepCopyInto(out *RHMI) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -309,6 +350,70 @@ func (in *RHMI) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RHMIConfigKeyChange) DeepCopyInto(out *RHMIConfigKeyChange) {
+	*out = *in
+	in.ChangedAt.DeepCopyInto(&out.ChangedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIConfigKeyChange.
+func (in *RHMIConfigKeyChange) DeepCopy() *RHMIConfigKeyChange {
+	if in == nil {
+		return nil
+	}
+	out := new(RHMIConfigKeyChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RHMIHistoryEntry) DeepCopyInto(out *RHMIHistoryEntry) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Stages != nil {
+		in, out := &in.Stages, &out.Stages
+		*out = make([]RHMIHistoryStage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIHistoryEntry.
+func (in *RHMIHistoryEntry) DeepCopy() *RHMIHistoryEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(RHMIHistoryEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RHMIHistoryStage) DeepCopyInto(out *RHMIHistoryStage) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIHistoryStage.
+func (in *RHMIHistoryStage) DeepCopy() *RHMIHistoryStage {
+	if in == nil {
+		return nil
+	}
+	out := new(RHMIHistoryStage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RHMIList) DeepCopyInto(out *RHMIList) {
 	*out = *in
@@ -341,9 +446,51 @@ func (in *RHMIList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RHMIProductConfigStatus) DeepCopyInto(out *RHMIProductConfigStatus) {
+	*out = *in
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]RHMIConfigKeyChange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIProductConfigStatus.
+func (in *RHMIProductConfigStatus) DeepCopy() *RHMIProductConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RHMIProductConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RHMIProductDeclaration) DeepCopyInto(out *RHMIProductDeclaration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIProductDeclaration.
+func (in *RHMIProductDeclaration) DeepCopy() *RHMIProductDeclaration {
+	if in == nil {
+		return nil
+	}
+	out := new(RHMIProductDeclaration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RHMIProductStatus) DeepCopyInto(out *RHMIProductStatus) {
 	*out = *in
+	if in.Declaration != nil {
+		in, out := &in.Declaration, &out.Declaration
+		*out = new(RHMIProductDeclaration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIProductStatus.
@@ -361,6 +508,11 @@ func (in *RHMISpec) DeepCopyInto(out *RHMISpec) {
 	*out = *in
 	out.PullSecret = in.PullSecret
 	out.AlertingEmailAddresses = in.AlertingEmailAddresses
+	if in.DisabledProducts != nil {
+		in, out := &in.DisabledProducts, &out.DisabledProducts
+		*out = make([]ProductName, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMISpec.
@@ -380,7 +532,7 @@ func (in *RHMIStageStatus) DeepCopyInto(out *RHMIStageStatus) {
 		in, out := &in.Products, &out.Products
 		*out = make(map[ProductName]RHMIProductStatus, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			(*out)[key] = *val.DeepCopy()
 		}
 	}
 }
@@ -415,6 +567,32 @@ func (in *RHMIStatus) DeepCopyInto(out *RHMIStatus) {
 		*out = new(CustomDomainStatus)
 		**out = **in
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]RHMIHistoryEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Uninstall != nil {
+		in, out := &in.Uninstall, &out.Uninstall
+		*out = new(RHMIUninstallStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProductConfigs != nil {
+		in, out := &in.ProductConfigs, &out.ProductConfigs
+		*out = make(map[ProductName]RHMIProductConfigStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIStatus.
@@ -426,3 +604,42 @@ func (in *RHMIStatus) DeepCopy() *RHMIStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RHMIUninstallExport) DeepCopyInto(out *RHMIUninstallExport) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIUninstallExport.
+func (in *RHMIUninstallExport) DeepCopy() *RHMIUninstallExport {
+	if in == nil {
+		return nil
+	}
+	out := new(RHMIUninstallExport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RHMIUninstallStatus) DeepCopyInto(out *RHMIUninstallStatus) {
+	*out = *in
+	if in.ExportTime != nil {
+		in, out := &in.ExportTime, &out.ExportTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Exports != nil {
+		in, out := &in.Exports, &out.Exports
+		*out = make([]RHMIUninstallExport, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RHMIUninstallStatus.
+func (in *RHMIUninstallStatus) DeepCopy() *RHMIUninstallStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RHMIUninstallStatus)
+	in.DeepCopyInto(out)
+	return out
+}
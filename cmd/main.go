@@ -19,6 +19,8 @@ package main
 import (
 	"crypto/tls"
 	"flag"
+	"fmt"
+	"net/http"
 	"os"
 	"strings"
 	"time"
@@ -29,6 +31,7 @@ import (
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	"github.com/integr8ly/integreatly-operator/pkg/resources/k8s"
@@ -51,6 +54,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	rhmiv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	blackboxtargetcontroller "github.com/integr8ly/integreatly-operator/internal/controller/blackboxtarget"
 	namespacecontroller "github.com/integr8ly/integreatly-operator/internal/controller/namespacelabel"
 	controllers "github.com/integr8ly/integreatly-operator/internal/controller/rhmi"
 	rhmicontroller "github.com/integr8ly/integreatly-operator/internal/controller/rhmi"
@@ -58,6 +62,7 @@ import (
 	tenantcontroller "github.com/integr8ly/integreatly-operator/internal/controller/tenant"
 	usercontroller "github.com/integr8ly/integreatly-operator/internal/controller/user"
 	"github.com/integr8ly/integreatly-operator/pkg/addon"
+	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
 	"github.com/integr8ly/integreatly-operator/pkg/webhooks"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	// +kubebuilder:scaffold:imports
@@ -75,12 +80,19 @@ func init() {
 	customMetrics.Registry.MustRegister(integreatlymetrics.RHOAMVersion)
 	customMetrics.Registry.MustRegister(integreatlymetrics.RHOAMStatus)
 	customMetrics.Registry.MustRegister(integreatlymetrics.RHOAMProductStatus)
+	customMetrics.Registry.MustRegister(integreatlymetrics.RHOAMHistoryDuration)
+	customMetrics.Registry.MustRegister(integreatlymetrics.RHOAMHistoryStageDuration)
 	customMetrics.Registry.MustRegister(integreatlymetrics.RHOAMCluster)
 	customMetrics.Registry.MustRegister(integreatlymetrics.ThreeScaleUserAction)
 	customMetrics.Registry.MustRegister(integreatlymetrics.Quota)
 	customMetrics.Registry.MustRegister(integreatlymetrics.TenantsSummary)
 	customMetrics.Registry.MustRegister(integreatlymetrics.NoActivated3ScaleTenantAccount)
+	customMetrics.Registry.MustRegister(integreatlymetrics.ThreeScaleTenantProvisioning)
+	customMetrics.Registry.MustRegister(integreatlymetrics.RateLimitCounterRemaining)
+	customMetrics.Registry.MustRegister(integreatlymetrics.RateLimitCounterMaxValue)
+	customMetrics.Registry.MustRegister(integreatlymetrics.TenantAuthorizedRequests)
 	customMetrics.Registry.MustRegister(integreatlymetrics.InstallationControllerReconcileDelayed)
+	customMetrics.Registry.MustRegister(integreatlymetrics.STSCredentialsHealthy)
 	customMetrics.Registry.MustRegister(integreatlymetrics.CustomDomain)
 	customMetrics.Registry.MustRegister(integreatlymetrics.ThreeScalePortals)
 	customMetrics.Registry.MustRegister(integreatlymetrics.RhoamStateMetric)
@@ -98,6 +110,15 @@ func init() {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == renderAlertsCommand {
+		if err := renderAlerts(os.Args[2:]); err != nil {
+			// the controller logger is not set up for commands
+			fmt.Fprintf(os.Stderr, "failed to render alerts: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	var metricsAddr string
 	var secureMetrics bool
 	var tlsOpts []func(*tls.Config)
@@ -154,11 +175,12 @@ func main() {
 		metricsServerOptions.FilterProvider = filters.WithAuthenticationAndAuthorization
 	}
 	var mgr ctrl.Manager
+	// the probe server is added by addHealthProbeServer to also serve the health summary
 	mgr, err = ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Cache:                  managerCache,
 		Scheme:                 scheme,
 		Metrics:                metricsServerOptions,
-		HealthProbeBindAddress: probeAddr,
+		HealthProbeBindAddress: "0",
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "28185cee.integreatly.org",
 	})
@@ -183,6 +205,15 @@ func main() {
 			setupLog.Error(err, "unable to create controller", "controller", "User")
 			os.Exit(1)
 		}
+		blackboxTargetCtrl, err := blackboxtargetcontroller.New(mgr)
+		if err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "BlackboxTarget")
+			os.Exit(1)
+		}
+		if err = blackboxTargetCtrl.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to setup controller", "controller", "BlackboxTarget")
+			os.Exit(1)
+		}
 	}
 
 	if isSandbox {
@@ -249,12 +280,8 @@ func main() {
 		setupLog.Error(err, "Error setting up webhook server")
 	}
 
-	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
-		setupLog.Error(err, "unable to set up health check")
-		os.Exit(1)
-	}
-	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
-		setupLog.Error(err, "unable to set up ready check")
+	if err := addHealthProbeServer(mgr, probeAddr, watchNamespace); err != nil {
+		setupLog.Error(err, "unable to set up health probe server")
 		os.Exit(1)
 	}
 
@@ -265,6 +292,34 @@ func main() {
 	}
 }
 
+// addHealthProbeServer serves the liveness and readiness probes, and the health summary of the
+// installation for external monitoring as the probe server of the manager only serves the checks
+func addHealthProbeServer(mgr ctrl.Manager, addr, namespace string) error {
+	if addr == "" || addr == "0" {
+		return nil
+	}
+
+	healthzHandler := &healthz.Handler{Checks: map[string]healthz.Checker{"healthz": healthz.Ping}}
+	readyzHandler := &healthz.Handler{Checks: map[string]healthz.Checker{"readyz": healthz.Ping}}
+
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", http.StripPrefix("/healthz", healthzHandler))
+	mux.Handle("/healthz/", http.StripPrefix("/healthz", healthzHandler))
+	mux.Handle("/readyz", http.StripPrefix("/readyz", readyzHandler))
+	mux.Handle("/readyz/", http.StripPrefix("/readyz", readyzHandler))
+	mux.Handle(status.HealthSummaryPath, status.NewHealthSummaryHandler(mgr.GetClient(), namespace,
+		l.NewLoggerWithContext(l.Fields{l.ComponentLogContext: "health-summary"})))
+
+	return mgr.Add(&manager.Server{
+		Name: "health probe",
+		Server: &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 32 * time.Second,
+		},
+	})
+}
+
 func setupWebhooks(mgr ctrl.Manager) error {
 
 	decoder := admission.NewDecoder(mgr.GetScheme())
@@ -286,10 +341,12 @@ func setupWebhooks(mgr ctrl.Manager) error {
 		},
 	})
 
-	// The webhooks feature can't work when the operator runs locally, as it
-	// needs to be accessible by kubernetes and depends on the TLS certificates
-	// being mounted
-	webhooks.Config.Enabled = k8s.IsRunInCluster()
+	// When the operator runs locally, the webhooks only work if the cluster can
+	// reach the webhook server on the local URL. The TLS certificates are then
+	// generated instead of being mounted
+	webhooks.Config.LocalURL = os.Getenv(webhooks.LocalURLEnvVar)
+	webhooks.Config.LocalIgnoreFailures = os.Getenv(webhooks.LocalIgnoreFailuresEnvVar) == "true"
+	webhooks.Config.Enabled = k8s.IsRunInCluster() || webhooks.Config.LocalURL != ""
 
 	if err := webhooks.Config.SetupServer(mgr); err != nil {
 		return err
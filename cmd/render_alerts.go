@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	rhmiv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/alertcatalogue"
+	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const renderAlertsCommand = "render-alerts"
+
+// renderAlerts writes the PrometheusRules created for an installation type and quota, and a summary of
+// their alerts, to the output directory without connecting to a cluster
+func renderAlerts(args []string) error {
+	var opts alertcatalogue.Options
+	var outputDir, runbooksFile, overridesFile string
+	var requireRunbooks bool
+
+	flags := flag.NewFlagSet(renderAlertsCommand, flag.ExitOnError)
+	flags.StringVar(&opts.InstallationType, "installation-type", string(rhmiv1alpha1.InstallationTypeManagedApi), "The installation type to render the alerts of.")
+	flags.StringVar(&opts.Quota, "quota", "", "The quota parameter to render the alerts of, e.g. 200 for 20 Million requests per day.")
+	flags.StringVar(&opts.NamespacePrefix, "namespace-prefix", "", "The namespace prefix of the installation. Defaults to the prefix of the installation type.")
+	flags.StringVar(&outputDir, "output-dir", "alerts", "The directory the rules and the alert summary are written to.")
+	flags.StringVar(&runbooksFile, "runbooks", "", "A runbook catalogue ConfigMap manifest to render the SOP URLs of the alerts with.")
+	flags.StringVar(&overridesFile, "alert-overrides", "", "An alert overrides ConfigMap manifest to render the alerts with.")
+	flags.BoolVar(&requireRunbooks, "require-runbooks", false, "Fail if any alert has no runbook.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if opts.Quota == "" {
+		return fmt.Errorf("the --quota flag is required")
+	}
+	var err error
+	if opts.Runbooks, err = readConfigMap(runbooksFile); err != nil {
+		return fmt.Errorf("failed to read runbook catalogue: %w", err)
+	}
+	if opts.AlertOverrides, err = readConfigMap(overridesFile); err != nil {
+		return fmt.Errorf("failed to read alert overrides: %w", err)
+	}
+
+	// the alert builders log the alerts they skip at info level, only warnings are relevant here
+	logrus.SetLevel(logrus.WarnLevel)
+
+	catalogue, err := alertcatalogue.Build(context.Background(), opts, l.NewLogger())
+	if err != nil {
+		return err
+	}
+	if err := catalogue.Write(outputDir); err != nil {
+		return err
+	}
+
+	fmt.Printf("Rendered %d alerts in %d PrometheusRules to %s\n", len(catalogue.Alerts), len(catalogue.Rules), outputDir)
+
+	if missing := catalogue.MissingRunbooks(); len(missing) > 0 {
+		fmt.Printf("%d alerts have no runbook: %s\n", len(missing), strings.Join(missing, ", "))
+		if requireRunbooks {
+			return fmt.Errorf("%d alerts have no runbook", len(missing))
+		}
+	}
+	return nil
+}
+
+// readConfigMap reads a ConfigMap manifest, no file results in no ConfigMap
+func readConfigMap(file string) (*corev1.ConfigMap, error) {
+	if file == "" {
+		return nil, nil
+	}
+	manifest, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	configMap := &corev1.ConfigMap{}
+	if err := yaml.Unmarshal(manifest, configMap); err != nil {
+		return nil, err
+	}
+	return configMap, nil
+}
@@ -0,0 +1,347 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/metrics"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/k8s"
+	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/rhmi"
+	"github.com/integr8ly/integreatly-operator/utils"
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	monv1 "github.com/rhobs/obo-prometheus-operator/pkg/apis/monitoring/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// +kubebuilder:rbac:groups=integreatly.org,resources=blackboxtargets,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups=integreatly.org,resources=blackboxtargets/status,verbs=get;update;patch
+
+const (
+	// BuiltinTargetsName is the BlackboxTarget holding the 3scale and SSO targets managed by the operator.
+	// Any other BlackboxTarget in the operator namespace is probed alongside it
+	BuiltinTargetsName = "rhoam-builtin"
+
+	blackboxTargetLabel  = "integreatly.org/blackbox-target"
+	blackboxJobName      = "blackbox"
+	blackboxExporterName = "blackbox-exporter"
+	blackboxExporterPort = 9115
+	defaultModule        = "http_2xx"
+
+	statusRefreshInterval = time.Minute
+)
+
+var (
+	log = l.NewLoggerWithContext(l.Fields{l.ControllerLogContext: "blackboxtarget_controller"})
+
+	invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+)
+
+// PrometheusQueryAPI is the subset of the prometheus API used to read the probe results
+type PrometheusQueryAPI interface {
+	Query(ctx context.Context, query string, ts time.Time, opts ...prometheusv1.Option) (model.Value, prometheusv1.Warnings, error)
+}
+
+// BlackboxTargetReconciler renders every BlackboxTarget entry into an OBO Probe and reports the
+// probe results back in the BlackboxTarget status
+type BlackboxTargetReconciler struct {
+	k8sclient.Client
+	Scheme            *runtime.Scheme
+	operatorNamespace string
+	prometheus        PrometheusQueryAPI
+}
+
+func New(mgr manager.Manager) (*BlackboxTargetReconciler, error) {
+	restConfig := controllerruntime.GetConfigOrDie()
+	restConfig.Timeout = time.Second * 10
+
+	client, err := k8sclient.New(restConfig, k8sclient.Options{
+		Scheme: mgr.GetScheme(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	operatorNamespace, err := k8s.GetWatchNamespace()
+	if err != nil {
+		return nil, err
+	}
+
+	prometheus, err := metrics.GetOboPrometheusApiClient(operatorNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BlackboxTargetReconciler{
+		Client:            client,
+		Scheme:            mgr.GetScheme(),
+		operatorNamespace: operatorNamespace,
+		prometheus:        prometheus,
+	}, nil
+}
+
+func (r *BlackboxTargetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	enqueueBuiltinTargets := handler.EnqueueRequestsFromMapFunc(func(_ context.Context, _ k8sclient.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: k8sclient.ObjectKey{Name: BuiltinTargetsName, Namespace: r.operatorNamespace}}}
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		// status updates don't change the generation, the status is refreshed by the periodic requeue instead
+		For(&integreatlyv1alpha1.BlackboxTarget{}, builder.WithPredicates(utils.NamespacePredicate(r.operatorNamespace), predicate.GenerationChangedPredicate{})).
+		// The built-in targets follow the routing subdomain and product hosts of the installation
+		Watches(&integreatlyv1alpha1.RHMI{}, enqueueBuiltinTargets, builder.WithPredicates(utils.NamespacePredicate(r.operatorNamespace))).
+		Complete(r)
+}
+
+func (r *BlackboxTargetReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	log.Infof("Reconciling BlackboxTarget", l.Fields{"name": request.Name})
+
+	if request.Name == BuiltinTargetsName {
+		if err := r.reconcileBuiltinTargets(ctx); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	target := &integreatlyv1alpha1.BlackboxTarget{}
+	if err := r.Get(ctx, request.NamespacedName, target); err != nil {
+		if k8serr.IsNotFound(err) {
+			return ctrl.Result{}, r.deleteStaleProbes(ctx, request.Name, nil)
+		}
+		return ctrl.Result{}, err
+	}
+
+	probeNames := map[string]bool{}
+	for _, entry := range target.Spec.BlackboxTargets {
+		if probeNames[getProbeName(target.Name, entry.Service)] {
+			log.Warningf("Ignoring duplicate service in BlackboxTarget", l.Fields{"name": target.Name, "service": entry.Service})
+			continue
+		}
+		probe, err := r.reconcileProbe(ctx, target.Name, entry)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		probeNames[probe.Name] = true
+	}
+	if err := r.deleteStaleProbes(ctx, target.Name, probeNames); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	targets := r.getProbeStatus(ctx, target.Spec.BlackboxTargets)
+	if !equality.Semantic.DeepEqual(target.Status.Targets, targets) {
+		target.Status.Targets = targets
+		if err := r.Status().Update(ctx, target); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to update BlackboxTarget %s status: %w", target.Name, err)
+		}
+	}
+
+	// probe results keep changing, refresh the status periodically
+	return ctrl.Result{RequeueAfter: statusRefreshInterval}, nil
+}
+
+// reconcileBuiltinTargets keeps the built-in BlackboxTarget in line with the 3scale and SSO endpoints of the installation
+func (r *BlackboxTargetReconciler) reconcileBuiltinTargets(ctx context.Context) error {
+	installation, err := rhmi.GetRhmiCr(r.Client, ctx, r.operatorNamespace, log)
+	if err != nil {
+		return err
+	}
+	if installation == nil || installation.Spec.RoutingSubdomain == "" {
+		return nil
+	}
+
+	builtin := &integreatlyv1alpha1.BlackboxTarget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      BuiltinTargetsName,
+			Namespace: r.operatorNamespace,
+		},
+	}
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, builtin, func() error {
+		builtin.Spec.BlackboxTargets = getBuiltinTargets(installation)
+		return nil
+	})
+	return err
+}
+
+func getBuiltinTargets(installation *integreatlyv1alpha1.RHMI) []integreatlyv1alpha1.BlackboxtargetData {
+	subdomain := installation.Spec.RoutingSubdomain
+	targets := []integreatlyv1alpha1.BlackboxtargetData{
+		{Service: "3scale-admin-ui", Url: fmt.Sprintf("https://3scale-admin.%s/p/login/", subdomain), Module: defaultModule},
+		{Service: "3scale-developer-console-ui", Url: fmt.Sprintf("https://3scale.%s/", subdomain), Module: defaultModule},
+		{Service: "3scale-system-admin-ui", Url: fmt.Sprintf("https://master.%s/p/login/", subdomain), Module: defaultModule},
+	}
+
+	ssoTargets := []struct {
+		product integreatlyv1alpha1.ProductName
+		service string
+	}{
+		{product: integreatlyv1alpha1.ProductRHSSO, service: "rhsso-ui"},
+		{product: integreatlyv1alpha1.ProductRHSSOUser, service: "rhssouser-ui"},
+	}
+	for _, sso := range ssoTargets {
+		for _, stage := range installation.Status.Stages {
+			if product, ok := stage.Products[sso.product]; ok && product.Host != "" {
+				targets = append(targets, integreatlyv1alpha1.BlackboxtargetData{Service: sso.service, Url: product.Host, Module: defaultModule})
+				break
+			}
+		}
+	}
+
+	return targets
+}
+
+func (r *BlackboxTargetReconciler) reconcileProbe(ctx context.Context, targetName string, entry integreatlyv1alpha1.BlackboxtargetData) (*monv1.Probe, error) {
+	oboNamespace := config.GetOboNamespace(r.operatorNamespace)
+	module := entry.Module
+	if module == "" {
+		module = defaultModule
+	}
+
+	probe := &monv1.Probe{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getProbeName(targetName, entry.Service),
+			Namespace: oboNamespace,
+		},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, probe, func() error {
+		probe.Labels = map[string]string{
+			config.GetOboLabelSelectorKey(): config.GetOboLabelSelector(),
+			blackboxTargetLabel:             targetName,
+		}
+		probe.Spec = monv1.ProbeSpec{
+			JobName: blackboxJobName,
+			Module:  module,
+			ProberSpec: monv1.ProberSpec{
+				URL: fmt.Sprintf("%s.%s.svc:%d", blackboxExporterName, oboNamespace, blackboxExporterPort),
+			},
+			Targets: monv1.ProbeTargets{
+				StaticConfig: &monv1.ProbeTargetStaticConfig{
+					Targets: []string{entry.Url},
+					Labels:  map[string]string{"service": entry.Service},
+				},
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconcile probe %s: %w", probe.Name, err)
+	}
+
+	return probe, nil
+}
+
+// deleteStaleProbes removes the probes of the BlackboxTarget that are not in keep
+func (r *BlackboxTargetReconciler) deleteStaleProbes(ctx context.Context, targetName string, keep map[string]bool) error {
+	probes := &monv1.ProbeList{}
+	if err := r.List(ctx, probes, k8sclient.InNamespace(config.GetOboNamespace(r.operatorNamespace)), k8sclient.MatchingLabels{blackboxTargetLabel: targetName}); err != nil {
+		return fmt.Errorf("failed to list probes of BlackboxTarget %s: %w", targetName, err)
+	}
+
+	for i := range probes.Items {
+		probe := &probes.Items[i]
+		if keep[probe.Name] {
+			continue
+		}
+		log.Infof("Deleting stale probe", l.Fields{"probe": probe.Name, "blackboxTarget": targetName})
+		if err := r.Delete(ctx, probe); err != nil && !k8serr.IsNotFound(err) {
+			return fmt.Errorf("failed to delete stale probe %s: %w", probe.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// getProbeStatus reads the latest probe results of the entries from the OBO prometheus
+func (r *BlackboxTargetReconciler) getProbeStatus(ctx context.Context, entries []integreatlyv1alpha1.BlackboxtargetData) []integreatlyv1alpha1.BlackboxTargetProbeStatus {
+	statuses := make([]integreatlyv1alpha1.BlackboxTargetProbeStatus, 0, len(entries))
+	for _, entry := range entries {
+		statuses = append(statuses, integreatlyv1alpha1.BlackboxTargetProbeStatus{Service: entry.Service, Url: entry.Url})
+	}
+
+	results := map[string]map[string]*model.Sample{}
+	for _, metric := range []string{"probe_success", "probe_http_status_code", "probe_duration_seconds", "probe_ssl_earliest_cert_expiry"} {
+		samples, err := r.query(ctx, fmt.Sprintf("%s{job=%q}", metric, blackboxJobName))
+		if err != nil {
+			for i := range statuses {
+				statuses[i].Message = fmt.Sprintf("failed to query probe results: %v", err)
+			}
+			return statuses
+		}
+		results[metric] = samples
+	}
+
+	for i := range statuses {
+		status := &statuses[i]
+		key := sampleKey(status.Service, status.Url)
+
+		success, ok := results["probe_success"][key]
+		if !ok {
+			status.Message = "no probe result available yet"
+			continue
+		}
+		succeeded := success.Value == 1
+		status.Success = &succeeded
+		// the status is stored with a precision of a second, truncate so unchanged results compare equal
+		probeTime := metav1.NewTime(success.Timestamp.Time().Truncate(time.Second))
+		status.LastProbeTime = &probeTime
+
+		if sample, ok := results["probe_http_status_code"][key]; ok {
+			status.HTTPStatusCode = int(sample.Value)
+		}
+		if sample, ok := results["probe_duration_seconds"][key]; ok {
+			status.Latency = &metav1.Duration{Duration: time.Duration(float64(sample.Value) * float64(time.Second))}
+		}
+		if sample, ok := results["probe_ssl_earliest_cert_expiry"][key]; ok && sample.Value > 0 {
+			expiry := metav1.NewTime(time.Unix(int64(sample.Value), 0))
+			status.TLSExpiry = &expiry
+		}
+	}
+
+	return statuses
+}
+
+func (r *BlackboxTargetReconciler) query(ctx context.Context, query string) (map[string]*model.Sample, error) {
+	if r.prometheus == nil {
+		return nil, fmt.Errorf("no prometheus client available")
+	}
+
+	value, _, err := r.prometheus.Query(ctx, query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	vector, ok := value.(model.Vector)
+	if !ok {
+		return nil, fmt.Errorf("unexpected result type %s for query %s", value.Type(), query)
+	}
+
+	samples := map[string]*model.Sample{}
+	for _, sample := range vector {
+		samples[sampleKey(string(sample.Metric["service"]), string(sample.Metric[model.InstanceLabel]))] = sample
+	}
+	return samples, nil
+}
+
+func sampleKey(service, url string) string {
+	return service + "@" + url
+}
+
+func getProbeName(targetName, service string) string {
+	name := invalidNameChars.ReplaceAllString(strings.ToLower(fmt.Sprintf("%s-%s", targetName, service)), "-")
+	return strings.Trim(name, "-")
+}
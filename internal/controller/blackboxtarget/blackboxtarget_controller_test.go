@@ -0,0 +1,229 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/utils"
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	monv1 "github.com/rhobs/obo-prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+const operatorNamespace = "redhat-rhoam-operator"
+
+type prometheusMock struct {
+	results map[string]model.Vector
+	err     error
+}
+
+func (m *prometheusMock) Query(_ context.Context, query string, _ time.Time, _ ...prometheusv1.Option) (model.Value, prometheusv1.Warnings, error) {
+	if m.err != nil {
+		return nil, nil, m.err
+	}
+	metric := query[:strings.Index(query, "{")]
+	return m.results[metric], nil, nil
+}
+
+func sample(service, url string, value float64, timestamp time.Time) *model.Sample {
+	return &model.Sample{
+		Metric:    model.Metric{"service": model.LabelValue(service), model.InstanceLabel: model.LabelValue(url), "job": blackboxJobName},
+		Value:     model.SampleValue(value),
+		Timestamp: model.TimeFromUnix(timestamp.Unix()),
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oboNamespace := config.GetOboNamespace(operatorNamespace)
+	userTarget := &integreatlyv1alpha1.BlackboxTarget{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-apis", Namespace: operatorNamespace},
+		Spec: integreatlyv1alpha1.BlackboxTargetSpec{
+			BlackboxTargets: []integreatlyv1alpha1.BlackboxtargetData{
+				{Service: "orders", Url: "https://orders.example.com/health", Module: "http_2xx"},
+				{Service: "payments", Url: "https://payments.example.com/health"},
+			},
+		},
+	}
+	staleProbe := &monv1.Probe{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "team-apis-removed",
+			Namespace: oboNamespace,
+			Labels:    map[string]string{blackboxTargetLabel: "team-apis"},
+		},
+	}
+	otherProbe := &monv1.Probe{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "other-probe",
+			Namespace: oboNamespace,
+			Labels:    map[string]string{blackboxTargetLabel: "other"},
+		},
+	}
+	installation := &integreatlyv1alpha1.RHMI{
+		ObjectMeta: metav1.ObjectMeta{Name: "rhoam", Namespace: operatorNamespace},
+		Spec:       integreatlyv1alpha1.RHMISpec{RoutingSubdomain: "apps.example.com"},
+		Status: integreatlyv1alpha1.RHMIStatus{
+			Stages: map[integreatlyv1alpha1.StageName]integreatlyv1alpha1.RHMIStageStatus{
+				integreatlyv1alpha1.InstallStage: {
+					Products: map[integreatlyv1alpha1.ProductName]integreatlyv1alpha1.RHMIProductStatus{
+						integreatlyv1alpha1.ProductRHSSO:     {Host: "https://keycloak-rhsso.apps.example.com"},
+						integreatlyv1alpha1.ProductRHSSOUser: {Host: "https://keycloak-user-sso.apps.example.com"},
+					},
+				},
+			},
+		},
+	}
+
+	probeTime := time.Now().Truncate(time.Second)
+	expiry := probeTime.Add(30 * 24 * time.Hour)
+	prometheus := &prometheusMock{results: map[string]model.Vector{
+		"probe_success": {
+			sample("orders", "https://orders.example.com/health", 1, probeTime),
+			sample("payments", "https://payments.example.com/health", 0, probeTime),
+		},
+		"probe_http_status_code": {
+			sample("orders", "https://orders.example.com/health", 200, probeTime),
+			sample("payments", "https://payments.example.com/health", 503, probeTime),
+		},
+		"probe_duration_seconds": {
+			sample("orders", "https://orders.example.com/health", 0.25, probeTime),
+		},
+		"probe_ssl_earliest_cert_expiry": {
+			sample("orders", "https://orders.example.com/health", float64(expiry.Unix()), probeTime),
+		},
+	}}
+
+	statusUpdates := 0
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&integreatlyv1alpha1.BlackboxTarget{}).
+		WithObjects(userTarget, staleProbe, otherProbe, installation).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, client k8sclient.Client, subResourceName string, obj k8sclient.Object, opts ...k8sclient.SubResourceUpdateOption) error {
+				statusUpdates++
+				return client.SubResource(subResourceName).Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	r := &BlackboxTargetReconciler{
+		Client:            client,
+		Scheme:            scheme,
+		operatorNamespace: operatorNamespace,
+		prometheus:        prometheus,
+	}
+
+	t.Run("user targets are rendered into probes with their status", func(t *testing.T) {
+		result, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: k8sclient.ObjectKeyFromObject(userTarget)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.RequeueAfter != statusRefreshInterval {
+			t.Fatalf("expected requeue after %s, got %s", statusRefreshInterval, result.RequeueAfter)
+		}
+
+		probes := &monv1.ProbeList{}
+		if err := client.List(context.TODO(), probes, k8sclient.InNamespace(oboNamespace)); err != nil {
+			t.Fatal(err)
+		}
+		names := map[string]monv1.Probe{}
+		for _, probe := range probes.Items {
+			names[probe.Name] = probe
+		}
+		if _, ok := names["team-apis-removed"]; ok {
+			t.Error("expected the stale probe to be deleted")
+		}
+		if _, ok := names["other-probe"]; !ok {
+			t.Error("expected the probe of another BlackboxTarget to be kept")
+		}
+		payments, ok := names["team-apis-payments"]
+		if !ok {
+			t.Fatal("expected a probe for the payments target")
+		}
+		if payments.Spec.Module != defaultModule || payments.Spec.Targets.StaticConfig.Targets[0] != "https://payments.example.com/health" {
+			t.Errorf("unexpected probe spec %+v", payments.Spec)
+		}
+
+		updated := &integreatlyv1alpha1.BlackboxTarget{}
+		if err := client.Get(context.TODO(), k8sclient.ObjectKeyFromObject(userTarget), updated); err != nil {
+			t.Fatal(err)
+		}
+		if len(updated.Status.Targets) != 2 {
+			t.Fatalf("expected 2 target statuses, got %d", len(updated.Status.Targets))
+		}
+		orders := updated.Status.Targets[0]
+		if orders.Success == nil || !*orders.Success || orders.HTTPStatusCode != 200 || orders.Latency.Duration != 250*time.Millisecond || !orders.TLSExpiry.Time.Equal(expiry) {
+			t.Errorf("unexpected orders status %+v", orders)
+		}
+		paymentsStatus := updated.Status.Targets[1]
+		if paymentsStatus.Success == nil || *paymentsStatus.Success || paymentsStatus.HTTPStatusCode != 503 || paymentsStatus.TLSExpiry != nil {
+			t.Errorf("unexpected payments status %+v", paymentsStatus)
+		}
+	})
+
+	t.Run("unchanged probe results don't update the status", func(t *testing.T) {
+		statusUpdates = 0
+		if _, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: k8sclient.ObjectKeyFromObject(userTarget)}); err != nil {
+			t.Fatal(err)
+		}
+		if statusUpdates != 0 {
+			t.Fatalf("expected no status update, got %d", statusUpdates)
+		}
+	})
+
+	t.Run("built-in targets are created from the installation", func(t *testing.T) {
+		prometheus.err = fmt.Errorf("connection refused")
+		defer func() { prometheus.err = nil }()
+
+		if _, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: k8sclient.ObjectKey{Name: BuiltinTargetsName, Namespace: operatorNamespace}}); err != nil {
+			t.Fatal(err)
+		}
+
+		builtin := &integreatlyv1alpha1.BlackboxTarget{}
+		if err := client.Get(context.TODO(), k8sclient.ObjectKey{Name: BuiltinTargetsName, Namespace: operatorNamespace}, builtin); err != nil {
+			t.Fatal(err)
+		}
+		if len(builtin.Spec.BlackboxTargets) != 5 {
+			t.Fatalf("expected 5 built-in targets, got %v", builtin.Spec.BlackboxTargets)
+		}
+		if sso := builtin.Spec.BlackboxTargets[3]; sso.Service != "rhsso-ui" || sso.Url != "https://keycloak-rhsso.apps.example.com" {
+			t.Errorf("expected a target for the RHSSO route, got %+v", sso)
+		}
+		for _, status := range builtin.Status.Targets {
+			if status.Success != nil || !strings.Contains(status.Message, "connection refused") {
+				t.Errorf("expected the prometheus error to be reported, got %+v", status)
+			}
+		}
+	})
+
+	t.Run("probes are removed with their BlackboxTarget", func(t *testing.T) {
+		if err := client.Delete(context.TODO(), userTarget); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: k8sclient.ObjectKeyFromObject(userTarget)}); err != nil {
+			t.Fatal(err)
+		}
+
+		probes := &monv1.ProbeList{}
+		if err := client.List(context.TODO(), probes, k8sclient.MatchingLabels{blackboxTargetLabel: userTarget.Name}); err != nil {
+			t.Fatal(err)
+		}
+		if len(probes.Items) != 0 {
+			t.Fatalf("expected the probes to be deleted, got %d", len(probes.Items))
+		}
+	})
+}
@@ -140,10 +140,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, errors.Wrap(err, "failed to reconcile priority class")
 	}
 
-	phase, err = r.checkRateLimitAlertsConfig(ctx, serverClient)
-	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
-		events.HandleError(r.recorder, installation, phase, "Failed to check rate limit alert config settings", err)
-		return phase, errors.Wrap(err, "failed to check rate limit alert config settings")
+	installType := integreatlyv1alpha1.InstallationType(installation.Spec.Type)
+	if integreatlyv1alpha1.HasRateLimiting(installType) {
+		phase, err = r.checkRateLimitAlertsConfig(ctx, serverClient)
+		if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+			events.HandleError(r.recorder, installation, phase, "Failed to check rate limit alert config settings", err)
+			return phase, errors.Wrap(err, "failed to check rate limit alert config settings")
+		}
 	}
 
 	if err = r.processQuota(installation, request.Namespace, installationQuota, serverClient); err != nil {
@@ -159,7 +162,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, errors.Wrap(err, "reconciling custom SMTP has failed ")
 	}
 
-	if !resources.IsInProw(installation) {
+	if !resources.IsInProw(installation) && integreatlyv1alpha1.HasObservability(installType) {
 		// Creates the Alertmanager config secret
 		phase, err = obo.ReconcileAlertManagerSecrets(ctx, serverClient, r.installation)
 		r.log.Infof("ReconcileAlertManagerConfigSecret", l.Fields{"phase": phase})
@@ -171,36 +174,19 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 			return phase, err
 		}
 
-		// Creates an alert to check for the presence of sendgrid smtp secret
-		phase, err = resources.CreateSmtpSecretExists(ctx, serverClient, installation)
-		r.log.Infof("Reconcile SendgridSmtpSecretExists alert", l.Fields{"phase": phase})
-		if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
-			events.HandleError(r.recorder, installation, phase, "Failed to reconcile SendgridSmtpSecretExists alert", err)
-			return phase, err
-		}
-
-		// Creates an alert to check for the presence of DeadMansSnitch secret
-		phase, err = resources.CreateDeadMansSnitchSecretExists(ctx, serverClient, installation)
-		r.log.Infof("Reconcile DeadMansSnitchSecretExists alert", l.Fields{"phase": phase})
-		if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
-			events.HandleError(r.recorder, installation, phase, "Failed to reconcile DeadMansSnitchSecretExists alert", err)
-			return phase, err
-		}
-
-		// Creates an alert to check for the presence of addon-managed-api-service-parameters secret
-		phase, err = resources.CreateAddonManagedApiServiceParametersExists(ctx, serverClient, installation)
-		r.log.Infof("Reconcile AddonManagedApiServiceParametersExists alert", l.Fields{"phase": phase})
-		if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
-			events.HandleError(r.recorder, installation, phase, "Failed to reconcile AddonManagedApiServiceParametersExists alert", err)
-			return phase, err
-		}
-
-		// Creates remaining OBO alerts
-		phase, err = obo.OboAlertsReconciler(r.log, r.installation).ReconcileAlerts(ctx, serverClient)
-		r.log.Infof("Reconcile OBO alerts", l.Fields{"phase": phase})
-		if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
-			events.HandleError(r.recorder, installation, phase, "Failed to reconcile OBO alerts", err)
-			return phase, err
+		// Creates the alerts on the secrets the installation depends on and the remaining OBO alerts
+		addonParametersSecret, err := addon.GetAddonParametersSecret(ctx, serverClient, installation.Namespace)
+		if err != nil {
+			events.HandleError(r.recorder, installation, integreatlyv1alpha1.PhaseFailed, "Failed to get addon parameters secret", err)
+			return integreatlyv1alpha1.PhaseFailed, err
+		}
+		for _, alertsReconciler := range BootstrapAlertReconcilers(r.log, installation, addonParametersSecret.Name) {
+			phase, err = alertsReconciler.ReconcileAlerts(ctx, serverClient)
+			r.log.Infof("Reconcile alerts", l.Fields{"productName": alertsReconciler.ProductName, "phase": phase})
+			if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+				events.HandleError(r.recorder, installation, phase, "Failed to reconcile "+alertsReconciler.ProductName+" alerts", err)
+				return phase, err
+			}
 		}
 
 	}
@@ -214,6 +200,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 	return integreatlyv1alpha1.PhaseCompleted, nil
 }
 
+// BootstrapAlertReconcilers returns the reconcilers of the alerts created in the bootstrap stage
+func BootstrapAlertReconcilers(log l.Logger, installation *integreatlyv1alpha1.RHMI, addonParametersSecretName string) []*resources.AlertReconcilerImpl {
+	return []*resources.AlertReconcilerImpl{
+		resources.InstallationAlertsReconciler(log, installation, addonParametersSecretName),
+		obo.OboAlertsReconciler(log, installation),
+	}
+}
+
 func (r *Reconciler) setTenantMetrics(ctx context.Context, serverClient k8sclient.Client) error {
 	tenants := &integreatlyv1alpha1.APIManagementTenantList{}
 	err := serverClient.List(ctx, tenants)
@@ -280,6 +274,7 @@ func (r *Reconciler) checkCloudResourcesConfig(ctx context.Context, serverClient
 			}
 		}
 		cloudConfig.Data["workshop"] = `{"blobstorage":"openshift", "smtpcredentials":"openshift", "redis":"openshift", "postgres":"openshift"}`
+		cloudConfig.Data["developer"] = `{"blobstorage":"openshift", "smtpcredentials":"openshift", "redis":"openshift", "postgres":"openshift"}`
 		return nil
 	}); err != nil {
 		return integreatlyv1alpha1.PhaseInProgress, err
@@ -306,49 +301,7 @@ func (r *Reconciler) checkRateLimitAlertsConfig(ctx context.Context, serverClien
 			return nil
 		}
 
-		maxRate1 := "90%"
-		maxRate2 := "95%"
-
-		defaultConfig := map[string]*marin3rconfig.AlertConfig{
-			"api-usage-alert-level1": {
-				Type:     marin3rconfig.AlertTypeThreshold,
-				RuleName: "RHOAMApiUsageLevel1ThresholdExceeded",
-				Level:    "info",
-				Threshold: &marin3rconfig.AlertThresholdConfig{
-					MinRate: "80%",
-					MaxRate: &maxRate1,
-				},
-				Period: "4h",
-			},
-			"api-usage-alert-level2": {
-				Type:     marin3rconfig.AlertTypeThreshold,
-				RuleName: "RHOAMApiUsageLevel2ThresholdExceeded",
-				Level:    "info",
-				Threshold: &marin3rconfig.AlertThresholdConfig{
-					MinRate: "90%",
-					MaxRate: &maxRate2,
-				},
-				Period: "2h",
-			},
-			"api-usage-alert-level3": {
-				Type:     marin3rconfig.AlertTypeThreshold,
-				RuleName: "RHOAMApiUsageLevel3ThresholdExceeded",
-				Level:    "info",
-				Threshold: &marin3rconfig.AlertThresholdConfig{
-					MinRate: "95%",
-					MaxRate: nil,
-				},
-				Period: "30m",
-			},
-			"rate-limit-spike": {
-				Type:     marin3rconfig.AlertTypeSpike,
-				RuleName: "RHOAMApiUsageOverLimit",
-				Level:    "warning",
-				Period:   "30m",
-			},
-		}
-
-		defaultConfigJSON, err := json.MarshalIndent(defaultConfig, "", "  ")
+		defaultConfigJSON, err := json.MarshalIndent(marin3rconfig.DefaultAlertConfig(), "", "  ")
 		if err != nil {
 			return err
 		}
@@ -649,6 +602,16 @@ func (r *Reconciler) processQuota(installation *integreatlyv1alpha1.RHMI, namesp
 	installationQuota *quota.Quota, serverClient k8sclient.Client) error {
 	isQuotaUpdated := false
 
+	if !integreatlyv1alpha1.HasRateLimiting(integreatlyv1alpha1.InstallationType(installation.Spec.Type)) {
+		quota.SetDeveloperQuota(installationQuota)
+		if installation.Status.Quota != installationQuota.GetName() {
+			installation.Status.ToQuota = installationQuota.GetName()
+			isQuotaUpdated = true
+		}
+		installationQuota.SetIsUpdated(isQuotaUpdated)
+		return nil
+	}
+
 	quotaParam, err := getSecretQuotaParam(installation, serverClient, namespace)
 	if err != nil {
 		return err
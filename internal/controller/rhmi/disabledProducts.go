@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	rhmiv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+)
+
+// optionalProducts can be turned off with Spec.DisabledProducts, the other products are required by the installation
+var optionalProducts = map[rhmiv1alpha1.ProductName]bool{
+	rhmiv1alpha1.ProductGrafana:   true,
+	rhmiv1alpha1.ProductMarin3r:   true,
+	rhmiv1alpha1.ProductRHSSOUser: true,
+}
+
+// productDependencies are the products each product can't run without. A product can't be disabled while a
+// product that requires it is enabled. 3scale doesn't require marin3r, its rate limiting is removed when marin3r is
+// disabled, and marin3r doesn't require grafana, its API usage alerts aren't linked to the dashboard without it
+var productDependencies = map[rhmiv1alpha1.ProductName][]rhmiv1alpha1.ProductName{
+	// the SSO integration of 3scale uses the cluster SSO, not the user SSO, and its databases are provisioned by
+	// cloud resources
+	rhmiv1alpha1.Product3Scale:    {rhmiv1alpha1.ProductRHSSO, rhmiv1alpha1.ProductCloudResources},
+	rhmiv1alpha1.ProductRHSSO:     {rhmiv1alpha1.ProductCloudResources},
+	rhmiv1alpha1.ProductRHSSOUser: {rhmiv1alpha1.ProductCloudResources},
+	rhmiv1alpha1.ProductMarin3r:   {rhmiv1alpha1.ProductCloudResources},
+}
+
+// validateDisabledProducts returns an error when a disabled product isn't installed by the installation type, is
+// required by another enabled product, or isn't optional
+func validateDisabledProducts(installation *rhmiv1alpha1.RHMI, installationType *Type) error {
+	installed := map[rhmiv1alpha1.ProductName]bool{}
+	for _, stage := range installationType.GetInstallStages() {
+		for product := range stage.Products {
+			installed[product] = true
+		}
+	}
+
+	for _, product := range installation.Spec.DisabledProducts {
+		if !installed[product] {
+			return fmt.Errorf("disabled product %s is not installed by %s installations", product, installation.Spec.Type)
+		}
+
+		requiredBy := []string{}
+		for dependent, dependencies := range productDependencies {
+			if !installed[dependent] || installation.IsProductDisabled(dependent) {
+				continue
+			}
+			for _, dependency := range dependencies {
+				if dependency == product {
+					requiredBy = append(requiredBy, string(dependent))
+				}
+			}
+		}
+		if len(requiredBy) > 0 {
+			sort.Strings(requiredBy)
+			return fmt.Errorf("product %s can't be disabled, it is required by %s", product, strings.Join(requiredBy, ", "))
+		}
+
+		if !optionalProducts[product] {
+			return fmt.Errorf("product %s can't be disabled, only %s are optional", product, strings.Join(optionalProductNames(), ", "))
+		}
+	}
+	return nil
+}
+
+func optionalProductNames() []string {
+	names := make([]string, 0, len(optionalProducts))
+	for product := range optionalProducts {
+		names = append(names, string(product))
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"testing"
+
+	rhmiv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+)
+
+func TestValidateDisabledProducts(t *testing.T) {
+	scenarios := []struct {
+		Name             string
+		InstallationType string
+		DisabledProducts []rhmiv1alpha1.ProductName
+		WantErr          string
+	}{
+		{
+			Name:             "no disabled products",
+			InstallationType: string(rhmiv1alpha1.InstallationTypeManagedApi),
+		},
+		{
+			Name:             "optional product without dependents",
+			InstallationType: string(rhmiv1alpha1.InstallationTypeManagedApi),
+			DisabledProducts: []rhmiv1alpha1.ProductName{rhmiv1alpha1.ProductRHSSOUser},
+		},
+		{
+			Name:             "grafana with marin3r also disabled",
+			InstallationType: string(rhmiv1alpha1.InstallationTypeManagedApi),
+			DisabledProducts: []rhmiv1alpha1.ProductName{rhmiv1alpha1.ProductGrafana, rhmiv1alpha1.ProductMarin3r},
+		},
+		{
+			Name:             "grafana with marin3r enabled",
+			InstallationType: string(rhmiv1alpha1.InstallationTypeManagedApi),
+			DisabledProducts: []rhmiv1alpha1.ProductName{rhmiv1alpha1.ProductGrafana},
+		},
+		{
+			Name:             "required product",
+			InstallationType: string(rhmiv1alpha1.InstallationTypeManagedApi),
+			DisabledProducts: []rhmiv1alpha1.ProductName{rhmiv1alpha1.ProductRHSSO},
+			WantErr:          "product rhsso can't be disabled, it is required by 3scale",
+		},
+		{
+			Name:             "product that isn't optional",
+			InstallationType: string(rhmiv1alpha1.InstallationTypeManagedApi),
+			DisabledProducts: []rhmiv1alpha1.ProductName{rhmiv1alpha1.Product3Scale},
+			WantErr:          "product 3scale can't be disabled, only grafana, marin3r, rhssouser are optional",
+		},
+		{
+			Name:             "product not installed by the installation type",
+			InstallationType: string(rhmiv1alpha1.InstallationTypeMultitenantManagedApi),
+			DisabledProducts: []rhmiv1alpha1.ProductName{rhmiv1alpha1.ProductRHSSOUser},
+			WantErr:          "disabled product rhssouser is not installed by multitenant-managed-api installations",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			installationType, err := TypeFactory(scenario.InstallationType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			installation := &rhmiv1alpha1.RHMI{
+				Spec: rhmiv1alpha1.RHMISpec{
+					Type:             scenario.InstallationType,
+					DisabledProducts: scenario.DisabledProducts,
+				},
+			}
+
+			err = validateDisabledProducts(installation, installationType)
+			if scenario.WantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if scenario.WantErr != "" && (err == nil || err.Error() != scenario.WantErr) {
+				t.Fatalf("expected error %q, got %v", scenario.WantErr, err)
+			}
+		})
+	}
+}
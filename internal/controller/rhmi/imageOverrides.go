@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	rhmiv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/cluster"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/images"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const imagePullCheckTimeout = 10 * time.Second
+
+// newImagePullCheckClient returns the HTTP client that checks the image overrides. The mirrors of the overrides
+// are reached the same way as the cluster reaches them, through the cluster proxy
+func newImagePullCheckClient(serverClient k8sclient.Client) func(context.Context) (*http.Client, error) {
+	return func(ctx context.Context) (*http.Client, error) {
+		transport, err := cluster.GetProxyTransport(ctx, serverClient)
+		if err != nil {
+			return nil, err
+		}
+		return &http.Client{Timeout: imagePullCheckTimeout, Transport: transport}, nil
+	}
+}
+
+// checkImageOverrides returns why the operand image overrides can't be used, or an empty message when every
+// override is pullable with the pull secret of the installation. The HTTP client is only created when there
+// are overrides to check
+func checkImageOverrides(ctx context.Context, serverClient k8sclient.Client, installation *rhmiv1alpha1.RHMI, newHTTPClient func(context.Context) (*http.Client, error)) (string, error) {
+	overrides, err := images.GetOverrides(ctx, serverClient, installation.Namespace)
+	if err != nil {
+		return err.Error(), nil
+	}
+	if len(overrides) == 0 {
+		return "", nil
+	}
+
+	pullSecretSpec := installation.GetPullSecretSpec()
+	pullSecret := &corev1.Secret{}
+	if err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: pullSecretSpec.Name, Namespace: pullSecretSpec.Namespace}, pullSecret); err != nil {
+		if !k8serr.IsNotFound(err) {
+			return "", fmt.Errorf("failed to get pull secret %s: %w", pullSecretSpec.Name, err)
+		}
+		// the overrides can be in a registry that allows anonymous pulls
+		pullSecret = nil
+	}
+
+	httpClient, err := newHTTPClient(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	failures := []string{}
+	for _, name := range images.Names() {
+		image, ok := overrides[name]
+		if !ok {
+			continue
+		}
+		if err := images.CheckPullable(ctx, httpClient, image, pullSecret); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Sprintf("operand image overrides are not pullable: %s", strings.Join(failures, "; ")), nil
+	}
+	return "", nil
+}
@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	rhmiv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/images"
+	"github.com/integr8ly/integreatly-operator/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestCheckImageOverrides(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registry := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/manifests/9.7") {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer registry.Close()
+	host := strings.TrimPrefix(registry.URL, "https://")
+
+	installation := &rhmiv1alpha1.RHMI{
+		ObjectMeta: metav1.ObjectMeta{Name: "rhoam", Namespace: "redhat-rhoam-operator"},
+	}
+	overrides := func(data map[string]string) runtime.Object {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: images.OverridesConfigMapName, Namespace: installation.Namespace},
+			Data:       data,
+		}
+	}
+
+	scenarios := []struct {
+		Name        string
+		Objects     []runtime.Object
+		WantMessage string
+	}{
+		{
+			Name: "passes without overrides",
+		},
+		{
+			Name:        "fails on an unknown image",
+			Objects:     []runtime.Object{overrides(map[string]string{"limitador": host + "/rhcl-1/limitador-rhel9:1.0"})},
+			WantMessage: "unknown image limitador",
+		},
+		{
+			Name:    "passes when the overrides are pullable",
+			Objects: []runtime.Object{overrides(map[string]string{images.Grafana: host + "/rhel9/grafana:9.7"})},
+		},
+		{
+			Name:        "fails when an override is not pullable",
+			Objects:     []runtime.Object{overrides(map[string]string{images.Grafana: host + "/rhel9/grafana:missing"})},
+			WantMessage: "operand image overrides are not pullable: grafana: image " + host + "/rhel9/grafana:missing not found",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			serverClient := utils.NewTestClient(scheme, scenario.Objects...)
+
+			message, err := checkImageOverrides(context.TODO(), serverClient, installation, func(context.Context) (*http.Client, error) {
+				return registry.Client(), nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(message, scenario.WantMessage) || (scenario.WantMessage == "" && message != "") {
+				t.Errorf("expected message %q, got %q", scenario.WantMessage, message)
+			}
+		})
+	}
+}
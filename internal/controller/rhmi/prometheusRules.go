@@ -15,6 +15,7 @@ var (
 	installationNames = map[string]string{
 		string(integreatlyv1alpha1.InstallationTypeManagedApi):            "rhoam",
 		string(integreatlyv1alpha1.InstallationTypeMultitenantManagedApi): "rhoam",
+		string(integreatlyv1alpha1.InstallationTypeDeveloper):             "rhoam",
 	}
 )
 
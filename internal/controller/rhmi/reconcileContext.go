@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	rhmiv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// stageReconcileTimeout bounds the reconcile of all the products of a stage
+	stageReconcileTimeout = 20 * time.Minute
+	// productReconcileTimeout bounds the reconcile of a single product, so that a product waiting on a slow API
+	// doesn't hold back the products after it until the stage times out
+	productReconcileTimeout = 10 * time.Minute
+	// deletionCheckInterval is how often the RHMI CR is checked for deletion while the stages are reconciled
+	deletionCheckInterval = 10 * time.Second
+)
+
+// errInstallationDeleted cancels the install stages of an RHMI CR being deleted, the next reconcile uninstalls it
+var errInstallationDeleted = errors.New("installation is being deleted, install reconcile cancelled")
+
+// reconcileTimeoutError is the cause of the cancellation of a stage or product reconcile that took too long
+type reconcileTimeoutError struct {
+	Kind    string
+	Name    string
+	Timeout time.Duration
+}
+
+func (e *reconcileTimeoutError) Error() string {
+	return fmt.Sprintf("%s %s reconcile timed out after %s", e.Kind, e.Name, e.Timeout)
+}
+
+// withStageTimeout derives the context of the reconcile of a stage
+func withStageTimeout(ctx context.Context, stage rhmiv1alpha1.StageName) (context.Context, context.CancelFunc) {
+	return context.WithTimeoutCause(ctx, stageReconcileTimeout, &reconcileTimeoutError{Kind: "stage", Name: string(stage), Timeout: stageReconcileTimeout})
+}
+
+// withProductTimeout derives the context of the reconcile of a product
+func withProductTimeout(ctx context.Context, product rhmiv1alpha1.ProductName) (context.Context, context.CancelFunc) {
+	return context.WithTimeoutCause(ctx, productReconcileTimeout, &reconcileTimeoutError{Kind: "product", Name: string(product), Timeout: productReconcileTimeout})
+}
+
+// withDeletionCancel derives a context that is cancelled with errInstallationDeleted once the installation is
+// marked for deletion or removed. The returned cancel func must be called to stop watching the installation
+func (r *RHMIReconciler) withDeletionCancel(ctx context.Context, installation *rhmiv1alpha1.RHMI) (context.Context, context.CancelFunc) {
+	return withDeletionCancel(ctx, r.Client, k8sclient.ObjectKeyFromObject(installation), deletionCheckInterval)
+}
+
+func withDeletionCancel(ctx context.Context, client k8sclient.Client, key k8sclient.ObjectKey, interval time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			installation := &rhmiv1alpha1.RHMI{}
+			// errors other than not found are transient, the installation is checked again on the next tick
+			err := client.Get(ctx, key, installation)
+			if k8serr.IsNotFound(err) || (err == nil && installation.DeletionTimestamp != nil) {
+				cancel(errInstallationDeleted)
+				return
+			}
+		}
+	}()
+	return ctx, func() { cancel(context.Canceled) }
+}
+
+// withContextCause reports why ctx was cancelled alongside err, so that a timeout or a deletion is distinguishable
+// from the errors it caused in status.lastError. err is returned as is while ctx isn't done
+func withContextCause(ctx context.Context, err error) error {
+	if ctx.Err() == nil {
+		return err
+	}
+	cause := context.Cause(ctx)
+	if err == nil {
+		return cause
+	}
+	if errors.Is(err, cause) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", cause, err)
+}
@@ -0,0 +1,169 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	rhmiv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestWithContextCause(t *testing.T) {
+	reconcileErr := errors.New("failed to reach the 3scale API")
+	cancelled := func() context.Context {
+		ctx, cancel := withProductTimeout(context.Background(), rhmiv1alpha1.Product3Scale)
+		cancel()
+		return ctx
+	}
+	deadlineExceeded := func() context.Context {
+		ctx, cancel := context.WithTimeoutCause(context.Background(), 0, &reconcileTimeoutError{Kind: "product", Name: string(rhmiv1alpha1.Product3Scale), Timeout: productReconcileTimeout})
+		t.Cleanup(cancel)
+		<-ctx.Done()
+		return ctx
+	}
+
+	scenarios := []struct {
+		Name        string
+		Ctx         context.Context
+		Err         error
+		WantErr     error
+		WantMessage string
+		WantTimeout bool
+	}{
+		{
+			Name:        "error unchanged while the context isn't done",
+			Ctx:         context.Background(),
+			Err:         reconcileErr,
+			WantErr:     reconcileErr,
+			WantMessage: reconcileErr.Error(),
+		},
+		{
+			Name: "no error while the context isn't done",
+			Ctx:  context.Background(),
+		},
+		{
+			Name:        "timeout reported with the error it caused",
+			Ctx:         deadlineExceeded(),
+			Err:         reconcileErr,
+			WantErr:     reconcileErr,
+			WantMessage: "product 3scale reconcile timed out after 10m0s: failed to reach the 3scale API",
+			WantTimeout: true,
+		},
+		{
+			Name:        "timeout reported without an error",
+			Ctx:         deadlineExceeded(),
+			WantMessage: "product 3scale reconcile timed out after 10m0s",
+			WantTimeout: true,
+		},
+		{
+			Name:        "cause not repeated when the error wraps it",
+			Ctx:         withCause(errInstallationDeleted),
+			Err:         errInstallationDeleted,
+			WantErr:     errInstallationDeleted,
+			WantMessage: errInstallationDeleted.Error(),
+		},
+		{
+			Name:        "cancelled context reported as cancelled",
+			Ctx:         cancelled(),
+			Err:         reconcileErr,
+			WantErr:     context.Canceled,
+			WantMessage: "context canceled: failed to reach the 3scale API",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			err := withContextCause(scenario.Ctx, scenario.Err)
+			if scenario.WantMessage == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != scenario.WantMessage {
+				t.Fatalf("expected error %q, got %v", scenario.WantMessage, err)
+			}
+			if scenario.WantErr != nil && !errors.Is(err, scenario.WantErr) {
+				t.Errorf("expected error to wrap %v", scenario.WantErr)
+			}
+			var timeoutErr *reconcileTimeoutError
+			if errors.As(err, &timeoutErr) != scenario.WantTimeout {
+				t.Errorf("expected timeout %t, got error %v", scenario.WantTimeout, err)
+			}
+		})
+	}
+}
+
+func TestWithDeletionCancel(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	installation := func(deleted bool) *rhmiv1alpha1.RHMI {
+		rhmi := &rhmiv1alpha1.RHMI{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "rhoam",
+				Namespace:  "redhat-rhoam-operator",
+				Finalizers: []string{deletionFinalizer},
+			},
+		}
+		if deleted {
+			rhmi.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		}
+		return rhmi
+	}
+
+	scenarios := []struct {
+		Name       string
+		Objects    []runtime.Object
+		WantCancel bool
+	}{
+		{
+			Name:    "not cancelled while the installation exists",
+			Objects: []runtime.Object{installation(false)},
+		},
+		{
+			Name:       "cancelled when the installation is marked for deletion",
+			Objects:    []runtime.Object{installation(true)},
+			WantCancel: true,
+		},
+		{
+			Name:       "cancelled when the installation is removed",
+			WantCancel: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			client := utils.NewTestClient(scheme, scenario.Objects...)
+			ctx, cancel := withDeletionCancel(context.Background(), client, k8sclient.ObjectKeyFromObject(installation(false)), time.Millisecond)
+			defer cancel()
+
+			select {
+			case <-ctx.Done():
+				if !scenario.WantCancel {
+					t.Fatalf("unexpected cancellation: %v", context.Cause(ctx))
+				}
+				if !errors.Is(context.Cause(ctx), errInstallationDeleted) {
+					t.Fatalf("expected cause %v, got %v", errInstallationDeleted, context.Cause(ctx))
+				}
+			case <-time.After(100 * time.Millisecond):
+				if scenario.WantCancel {
+					t.Fatal("expected the context to be cancelled")
+				}
+			}
+		})
+	}
+}
+
+func withCause(cause error) context.Context {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(cause)
+	return ctx
+}
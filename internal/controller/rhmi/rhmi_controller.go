@@ -60,6 +60,7 @@ import (
 	usersv1 "github.com/openshift/api/user/v1"
 
 	rhmiv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/internal/controller/status"
 	"github.com/integr8ly/integreatly-operator/pkg/addon"
 	"github.com/integr8ly/integreatly-operator/pkg/config"
 	"github.com/integr8ly/integreatly-operator/pkg/metrics"
@@ -103,13 +104,22 @@ type RHMIReconciler struct {
 	customInformers map[string]map[string]*cache.Informer
 
 	productsInstallationLoader marketplace.ProductsInstallationLoader
+	// uninstallProductsInstallationLoader loads the declarations the products are removed with. The uninstall doesn't
+	// depend on where the product operators were installed from, so it doesn't read the overrides
+	uninstallProductsInstallationLoader marketplace.ProductsInstallationLoader
 
 	customEventChan chan event.GenericEvent // Channel for injecting reconcile events
+
+	stsCredentialsChecker   *sts.CredentialsChecker
+	stsCredentialsCheckedAt time.Time
 }
 
 func New(mgr ctrl.Manager) *RHMIReconciler {
 	restconfig := ctrl.GetConfigOrDie()
 	restconfig.Timeout = 10 * time.Second
+	// the product declaration overrides are read from the operator namespace
+	watchNamespace, _ := k8s.GetWatchNamespace()
+	productsInstallationLoader := marketplace.NewFSProductInstallationLoader(marketplace.GetProductsInstallationPath())
 	return &RHMIReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
@@ -118,10 +128,14 @@ func New(mgr ctrl.Manager) *RHMIReconciler {
 		restConfig:      restconfig,
 		customInformers: make(map[string]map[string]*cache.Informer),
 
-		productsInstallationLoader: marketplace.NewFSProductInstallationLoader(
-			marketplace.GetProductsInstallationPath(),
+		productsInstallationLoader: marketplace.NewConfigMapProductsInstallationLoader(
+			productsInstallationLoader,
+			mgr.GetClient(),
+			watchNamespace,
 		),
-		customEventChan: make(chan event.GenericEvent, 100), // buffered channel
+		uninstallProductsInstallationLoader: productsInstallationLoader,
+		customEventChan:                     make(chan event.GenericEvent, 100), // buffered channel
+		stsCredentialsChecker:               sts.NewCredentialsChecker(),
 	}
 }
 
@@ -192,17 +206,23 @@ func New(mgr ctrl.Manager) *RHMIReconciler {
 // Permission to get the ConfigMap that embeds the CSV for an InstallPlan
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get
 
+// Permission to record user provisioning events on the cluster scoped OpenShift users
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
 // Permission for marin3r resources
 // +kubebuilder:rbac:groups=marin3r.3scale.net,resources=envoyconfigs,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups=operator.marin3r.3scale.net,resources=discoveryservices,verbs=get;list;watch;create;update;delete
 
 // +kubebuilder:rbac:groups=scheduling.k8s.io,resources=*,verbs=*
 
+// Permission to mint tokens of the cloud resources operator service account to check its STS credentials
+// +kubebuilder:rbac:groups="",resources=serviceaccounts/token,verbs=create
+
 // Permission to list nodes in order to determine if a cluster is multi-az
 // +kubebuilder:rbac:groups="",resources=nodes,verbs=list
 
 // Permission to get cluster infrastructure details for alerting
-// +kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions;infrastructures;oauths,verbs=get;list;watch
+// +kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions;infrastructures;oauths;proxies,verbs=get;list;watch
 
 // Permission to remove crd for the marin3r operator upgrade from 0.5.1 to 0.7.0
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=delete;get;list
@@ -260,13 +280,14 @@ func (r *RHMIReconciler) Reconcile(ctx context.Context, request ctrl.Request) (c
 	installInProgress := false
 
 	installation := &rhmiv1alpha1.RHMI{}
-	err := r.Get(context.TODO(), request.NamespacedName, installation)
+	err := r.Get(ctx, request.NamespacedName, installation)
 	if err != nil {
 		if k8serr.IsNotFound(err) {
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, err
 	}
+	ctx = resources.WithAlertSettings(ctx)
 
 	alertsClient, err := k8sclient.New(r.mgr.GetConfig(), k8sclient.Options{
 		Scheme: r.mgr.GetScheme(),
@@ -276,7 +297,7 @@ func (r *RHMIReconciler) Reconcile(ctx context.Context, request ctrl.Request) (c
 	}
 
 	// remove installation, upgrade and missingMetrics alerts from openshift monitoring
-	err = r.removeInstallationRules(installation, context.TODO(), alertsClient)
+	err = r.removeInstallationRules(installation, ctx, alertsClient)
 	if err != nil {
 		log.Error("Error reconciling removing alerts installation, upgrade and missing metrics from openshift-monitoring namespace", nil, err)
 	}
@@ -297,7 +318,7 @@ func (r *RHMIReconciler) Reconcile(ctx context.Context, request ctrl.Request) (c
 	if installation.Spec.AlertingEmailAddresses.CSSRE == "" && cssreAlertingEmailAddress != "" {
 		log.Info("Adding CS-SRE alerting email address to RHMI CR")
 		installation.Spec.AlertingEmailAddresses.CSSRE = cssreAlertingEmailAddress
-		err = r.Update(context.TODO(), installation)
+		err = r.Update(ctx, installation)
 		if err != nil {
 			log.Error("Error while copying alerting email addresses to RHMI CR", nil, err)
 		}
@@ -307,14 +328,14 @@ func (r *RHMIReconciler) Reconcile(ctx context.Context, request ctrl.Request) (c
 	if installation.Spec.AlertingEmailAddresses.BusinessUnit == "" && buAlertingEmailAddress != "" {
 		log.Info("Adding BU alerting email address to RHMI CR")
 		installation.Spec.AlertingEmailAddresses.BusinessUnit = buAlertingEmailAddress
-		err = r.Update(context.TODO(), installation)
+		err = r.Update(ctx, installation)
 		if err != nil {
 			log.Error("Error while copying alerting email addresses to RHMI CR", nil, err)
 		}
 	}
 
 	customerAlertingEmailAddress, ok, err := addon.GetStringParameter(
-		context.TODO(),
+		ctx,
 		r.Client,
 		installation.Namespace,
 		"notification-email",
@@ -324,7 +345,7 @@ func (r *RHMIReconciler) Reconcile(ctx context.Context, request ctrl.Request) (c
 	} else if ok && installation.Spec.AlertingEmailAddress != customerAlertingEmailAddress {
 		log.Info("Updating customer email address from parameter")
 		installation.Spec.AlertingEmailAddress = customerAlertingEmailAddress
-		if err := r.Update(context.TODO(), installation); err != nil {
+		if err := r.Update(ctx, installation); err != nil {
 			log.Error("Error while updating customer email address to RHMI CR", nil, err)
 		}
 	}
@@ -336,20 +357,24 @@ func (r *RHMIReconciler) Reconcile(ctx context.Context, request ctrl.Request) (c
 
 	metrics.SetStatus(installation)
 
-	configManager, err := config.NewManager(context.TODO(), r.Client, request.NamespacedName.Namespace, installationCfgMap, installation)
+	configManager, err := config.NewManager(ctx, r.Client, request.NamespacedName.Namespace, installationCfgMap, installation)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	// bring the product configs written by a previous version of the operator up to date
+	if err := configManager.MigrateSchemas(); err != nil {
+		return ctrl.Result{}, err
+	}
 
 	// Reconcile the webhooks
-	if err := webhooks.Config.Reconcile(context.TODO(), r.Client, installation); err != nil {
+	if err := webhooks.Config.Reconcile(ctx, r.Client, installation); err != nil {
 		return ctrl.Result{}, err
 	}
 
 	if !resources.Contains(installation.GetFinalizers(), deletionFinalizer) && installation.GetDeletionTimestamp() == nil {
 		if resources.Contains(installation.GetFinalizers(), previousDeletionFinalizer) {
 			installation.SetFinalizers(resources.Replace(installation.GetFinalizers(), previousDeletionFinalizer, deletionFinalizer))
-			if err = r.Update(context.TODO(), installation); err != nil {
+			if err = r.Update(ctx, installation); err != nil {
 				return retryRequeue, nil
 			}
 		} else {
@@ -369,10 +394,10 @@ func (r *RHMIReconciler) Reconcile(ctx context.Context, request ctrl.Request) (c
 
 	// If the CR is being deleted, handle uninstall and return
 	if installation.DeletionTimestamp != nil {
-		return r.handleUninstall(installation, installType, request)
+		return r.handleUninstall(ctx, installation, installType, request)
 	}
 
-	clusterVersionCR, err := cluster.GetClusterVersionCR(context.TODO(), r.Client)
+	clusterVersionCR, err := cluster.GetClusterVersionCR(ctx, r.Client)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("error getting cluster version information: %w", err)
 	}
@@ -386,7 +411,7 @@ func (r *RHMIReconciler) Reconcile(ctx context.Context, request ctrl.Request) (c
 	if upgradeFirstReconcile(installation) || firstInstallFirstReconcile(installation) {
 		installation.Status.ToVersion = version.GetVersionByType(installation.Spec.Type)
 		log.Infof("Setting installation.Status.ToVersion on initial install", l.Fields{"version": version.GetVersionByType(installation.Spec.Type)})
-		if err := r.Status().Update(context.TODO(), installation); err != nil {
+		if err := r.Status().Update(ctx, installation); err != nil {
 			return retryRequeue, nil
 		}
 		metrics.SetVersions(string(installation.Status.Stage), installation.Status.Version, installation.Status.ToVersion, string(externalClusterId), installation.CreationTimestamp.Unix())
@@ -412,6 +437,17 @@ func (r *RHMIReconciler) Reconcile(ctx context.Context, request ctrl.Request) (c
 	}
 	metrics.SetRhoamState(state)
 
+	// the products stay as they are until the disabled products are fixed
+	if err := validateDisabledProducts(installation, installType); err != nil {
+		log.Warningf("invalid disabled products", l.Fields{"error": err})
+		installation.Status.LastError = err.Error()
+		return retryRequeue, r.updateStatusAndObject(originalInstallation, installation)
+	}
+
+	// the install stages stop when the installation is deleted, the uninstall is handled by the next reconcile
+	installCtx, cancelInstall := r.withDeletionCancel(ctx, installation)
+	defer cancelInstall()
+
 	installationQuota := &quota.Quota{}
 	installStages := installType.GetInstallStages()
 	for i := range installStages {
@@ -420,11 +456,17 @@ func (r *RHMIReconciler) Reconcile(ctx context.Context, request ctrl.Request) (c
 		var stagePhase rhmiv1alpha1.StatusPhase
 		var stageLog = l.NewLoggerWithContext(l.Fields{l.StageLogContext: stage.Name})
 
+		startStageHistory(installation, stage.Name, metav1.Now())
+		stageCtx, cancelStage := withStageTimeout(installCtx, stage.Name)
 		if stage.Name == rhmiv1alpha1.BootstrapStage {
-			stagePhase, err = r.bootstrapStage(installation, configManager, stageLog, installationQuota, request)
+			stagePhase, err = r.bootstrapStage(stageCtx, installation, configManager, stageLog, installationQuota, request)
 		} else {
-			stagePhase, err = r.processStage(installation, &stage, configManager, installationQuota, stageLog)
+			stagePhase, err = r.processStage(stageCtx, installation, &stage, configManager, installationQuota, stageLog)
 		}
+		if stagePhase != rhmiv1alpha1.PhaseCompleted {
+			err = withContextCause(stageCtx, err)
+		}
+		cancelStage()
 
 		if installation.Status.Stages == nil {
 			installation.Status.Stages = make(map[rhmiv1alpha1.StageName]rhmiv1alpha1.RHMIStageStatus)
@@ -434,6 +476,7 @@ func (r *RHMIReconciler) Reconcile(ctx context.Context, request ctrl.Request) (c
 			Phase:    stagePhase,
 			Products: stage.Products,
 		}
+		updateStageHistory(installation, stage.Name, stagePhase, err, metav1.Now())
 
 		if err != nil {
 			installation.Status.LastError = err.Error()
@@ -451,9 +494,11 @@ func (r *RHMIReconciler) Reconcile(ctx context.Context, request ctrl.Request) (c
 
 	// Entered on first reconcile where all stages reported complete after an upgrade / install
 	if installation.Status.ToVersion == version.GetVersionByType(installation.Spec.Type) && !installInProgress && !productVersionMismatchFound {
+		completeHistoryEntry(installation, rhmiv1alpha1.HistoryEntryUpgrade, metav1.Now())
 		installation.Status.Version = version.GetVersionByType(installation.Spec.Type)
 		installation.Status.ToVersion = ""
 		metrics.SetVersions(string(installation.Status.Stage), installation.Status.Version, installation.Status.ToVersion, string(externalClusterId), installation.CreationTimestamp.Unix())
+		completeHistoryEntry(installation, rhmiv1alpha1.HistoryEntryQuotaChange, metav1.Now())
 		installation.Status.Quota = installationQuota.GetName()
 		installation.Status.ToQuota = ""
 
@@ -475,13 +520,38 @@ func (r *RHMIReconciler) Reconcile(ctx context.Context, request ctrl.Request) (c
 		}
 
 		if installationQuota.IsUpdated() {
+			completeHistoryEntry(installation, rhmiv1alpha1.HistoryEntryQuotaChange, metav1.Now())
 			installation.Status.Quota = installationQuota.GetName()
 			installation.Status.ToQuota = ""
 			metrics.SetQuota(installation.Status.Quota, installation.Status.ToQuota)
 		}
 	}
+
+	if !resources.IsInProw(installation) && rhmiv1alpha1.HasObservability(rhmiv1alpha1.InstallationType(installation.Spec.Type)) {
+		if err := obo.ReconcileTransitionSilences(ctx, r.Client, installation, obo.NewAlertmanagerClient(installation.Namespace), r.mgr.GetEventRecorderFor("Alert Silences"), time.Now()); err != nil {
+			log.Warningf("failed to reconcile alert silences for upgrades and quota changes", l.Fields{"error": err})
+		}
+	}
+	// the conditions are otherwise only reported to the AddonInstance, which requires the addon-operator
+	monitoringStack, err := config.GetOboMonitoringStack(r.Client, config.GetOboNamespace(installation.Namespace))
+	if err != nil && !k8serr.IsNotFound(err) {
+		log.Warningf("failed to get the monitoring stack for the installation conditions", l.Fields{"error": err})
+	}
+	status.SetInstallationConditions(installation, monitoringStack, log)
+	if cloudResourcesConfig, err := configManager.ReadCloudResources(); err != nil {
+		log.Warningf("failed to read the cloud resources config for the STS credentials health", l.Fields{"error": err})
+	} else {
+		r.reconcileSTSCredentialsHealth(ctx, installation, cloudResourcesConfig.GetOperatorNamespace(), log)
+	}
+	if configMetadata, err := configManager.ReadConfigMetadata(); err != nil {
+		log.Warningf("failed to read the product config metadata", l.Fields{"error": err})
+	} else {
+		installation.Status.ProductConfigs = configMetadata.Status()
+	}
+
 	metrics.SetStatus(installation)
 	metrics.SetProductStatus(installation)
+	metrics.SetHistory(installation)
 
 	err = r.updateStatusAndObject(originalInstallation, installation)
 	return retryRequeue, err
@@ -521,16 +591,26 @@ func (r *RHMIReconciler) updateStatusAndObject(original, installation *rhmiv1alp
 	return nil
 }
 
-func (r *RHMIReconciler) handleUninstall(installation *rhmiv1alpha1.RHMI, installationType *Type, request ctrl.Request) (ctrl.Result, error) {
+func (r *RHMIReconciler) handleUninstall(ctx context.Context, installation *rhmiv1alpha1.RHMI, installationType *Type, request ctrl.Request) (ctrl.Result, error) {
 	retryRequeue := ctrl.Result{
 		Requeue:      true,
 		RequeueAfter: 10 * time.Second,
 	}
+	// with deletion protection, nothing is deleted until the data is exported and the uninstall confirmed
+	proceed, err := reconcileDeletionProtection(ctx, r.Client, installation, uninstallExportExecutors(installation), r.mgr.GetEventRecorderFor("Uninstall"))
+	if err != nil {
+		log.Error("failed to reconcile the deletion protection", nil, err)
+		return retryRequeue, nil
+	}
+	if !proceed {
+		return retryRequeue, nil
+	}
+
 	installationCfgMap := os.Getenv("INSTALLATION_CONFIG_MAP")
 	if installationCfgMap == "" {
 		installationCfgMap = installation.Spec.NamespacePrefix + DefaultInstallationConfigMapName
 	}
-	configManager, err := config.NewManager(context.TODO(), r.Client, installation.Namespace, installationCfgMap, installation)
+	configManager, err := config.NewManager(ctx, r.Client, installation.Namespace, installationCfgMap, installation)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -553,7 +633,7 @@ func (r *RHMIReconciler) handleUninstall(installation *rhmiv1alpha1.RHMI, instal
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	err = r.Client.List(context.TODO(), alerts, &k8sclient.ListOptions{
+	err = r.Client.List(ctx, alerts, &k8sclient.ListOptions{
 		LabelSelector: ls,
 	})
 	if err != nil {
@@ -561,7 +641,7 @@ func (r *RHMIReconciler) handleUninstall(installation *rhmiv1alpha1.RHMI, instal
 	}
 
 	for i := range alerts.Items {
-		err := r.Client.Delete(context.TODO(), &alerts.Items[i])
+		err := r.Client.Delete(ctx, &alerts.Items[i])
 		if err != nil {
 			return ctrl.Result{}, err
 		}
@@ -573,6 +653,7 @@ func (r *RHMIReconciler) handleUninstall(installation *rhmiv1alpha1.RHMI, instal
 
 	installation.Status.Stage = "deletion"
 	installation.Status.LastError = ""
+	uninstallHistory := installation.StartHistoryEntry(rhmiv1alpha1.HistoryEntryUninstall, installation.Status.Version, "", *installation.DeletionTimestamp)
 
 	// updates rhmi status metric to deletion
 	metrics.SetStatus(installation)
@@ -583,11 +664,12 @@ func (r *RHMIReconciler) handleUninstall(installation *rhmiv1alpha1.RHMI, instal
 	finalizers = append(finalizers, installation.Finalizers...)
 	for _, stage := range installationType.UninstallStages {
 		pendingUninstalls := false
+		uninstallHistory.StageStarted(stage.Name, metav1.Now())
 		if stage.Name == rhmiv1alpha1.BootstrapStage {
-			pendingUninstalls = r.handleUninstallBootstrap(installation, finalizers, stage, configManager, merr, request)
+			pendingUninstalls = r.handleUninstallBootstrap(ctx, installation, finalizers, stage, configManager, merr, request)
 		} else {
 			for product := range stage.Products {
-				productPending := r.handleUninstallProduct(installation, product, stage, finalizers, configManager, merr)
+				productPending := r.handleUninstallProduct(ctx, installation, product, stage, finalizers, configManager, merr)
 				if productPending && !pendingUninstalls {
 					pendingUninstalls = true
 				}
@@ -598,28 +680,31 @@ func (r *RHMIReconciler) handleUninstall(installation *rhmiv1alpha1.RHMI, instal
 		if pendingUninstalls {
 			if len(merr.Errors) > 0 {
 				installation.Status.LastError = merr.Error()
-				err = r.Client.Status().Update(context.TODO(), installation)
-				if err != nil {
-					merr.Add(err)
-				}
+				uninstallHistory.Complete(rhmiv1alpha1.HistoryOutcomeFailed, stageFailureMessage(stage.Name, merr), metav1.Now())
+			}
+			metrics.SetHistory(installation)
+			err = r.Client.Status().Update(ctx, installation)
+			if err != nil {
+				merr.Add(err)
 			}
-			err = r.Client.Update(context.TODO(), installation)
+			err = r.Client.Update(ctx, installation)
 			if err != nil {
 				merr.Add(err)
 			}
 			return retryRequeue, nil
 		}
+		uninstallHistory.StageCompleted(stage.Name, metav1.Now())
 	}
 
 	//all products gone and no errors, tidy up bootstrap stuff
 	if len(installation.Finalizers) == 1 && installation.Finalizers[0] == deletionFinalizer {
 		log.Infof("Finalizers: ", l.Fields{"length": len(installation.Finalizers)})
 		// delete ConfigMap after all product finalizers finished
-		err := r.Client.Delete(context.TODO(), &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: installationCfgMap, Namespace: installation.Namespace}})
+		err := r.Client.Delete(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: installationCfgMap, Namespace: installation.Namespace}})
 		if err != nil && !k8serr.IsNotFound(err) {
 			merr.Add(fmt.Errorf("failed to remove installation ConfigMap: %w", err))
 			installation.Status.LastError = merr.Error()
-			err = r.Client.Update(context.TODO(), installation)
+			err = r.Client.Update(ctx, installation)
 			if err != nil {
 				merr.Add(err)
 			}
@@ -630,22 +715,29 @@ func (r *RHMIReconciler) handleUninstall(installation *rhmiv1alpha1.RHMI, instal
 		if err != nil && !k8serr.IsNotFound(err) {
 			merr.Add(fmt.Errorf("failed to remove Cloud Resource ConfigMap: %w", err))
 			installation.Status.LastError = merr.Error()
-			err = r.Update(context.TODO(), installation)
+			err = r.Update(ctx, installation)
 			if err != nil {
 				merr.Add(err)
 			}
 			return ctrl.Result{}, merr
 		}
 
+		// the status is deleted with the CR, the uninstall history is kept in an event and the operator logs
+		uninstallHistory.Complete(rhmiv1alpha1.HistoryOutcomeSucceeded, "uninstall completed", metav1.Now())
+		metrics.SetHistory(installation)
+		summary := historyEntrySummary(uninstallHistory)
+		r.mgr.GetEventRecorderFor("Uninstall").Event(installation, corev1.EventTypeNormal, rhmiv1alpha1.EventUninstallCompleted, summary)
+		log.Infof("Uninstall history", l.Fields{"history": summary})
+
 		installation.SetFinalizers(resources.Remove(installation.GetFinalizers(), deletionFinalizer))
 
-		err = r.Update(context.TODO(), installation)
+		err = r.Update(ctx, installation)
 		if err != nil {
 			merr.Add(err)
 			return ctrl.Result{}, merr
 		}
 
-		err = addon.UninstallOperator(context.TODO(), r.Client, installation)
+		err = addon.UninstallOperator(ctx, r.Client, installation)
 		if err != nil {
 			merr.Add(err)
 			return ctrl.Result{}, merr
@@ -657,14 +749,14 @@ func (r *RHMIReconciler) handleUninstall(installation *rhmiv1alpha1.RHMI, instal
 
 	log.Info("updating uninstallation object")
 	// no finalizers left, update object
-	err = r.Update(context.TODO(), installation)
+	err = r.Update(ctx, installation)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 	return retryRequeue, nil
 }
 
-func (r *RHMIReconciler) handleUninstallProduct(installation *rhmiv1alpha1.RHMI, product rhmiv1alpha1.ProductName, stage Stage, finalizers []string, configManager *config.Manager, merr *resources.MultiErr) bool {
+func (r *RHMIReconciler) handleUninstallProduct(ctx context.Context, installation *rhmiv1alpha1.RHMI, product rhmiv1alpha1.ProductName, stage Stage, finalizers []string, configManager *config.Manager, merr *resources.MultiErr) bool {
 	productName := string(product)
 	log.Infof("Uninstalling ", l.Fields{"product": productName, "stage": stage.Name})
 	productStatus := installation.GetProductStatusObject(product)
@@ -673,9 +765,12 @@ func (r *RHMIReconciler) handleUninstallProduct(installation *rhmiv1alpha1.RHMI,
 		if !strings.Contains(productFinalizer, productName) {
 			continue
 		}
-		reconciler, err := products.NewReconciler(product, r.restConfig, configManager, installation, r.mgr, log, r.productsInstallationLoader)
+		productCtx, cancelProduct := withProductTimeout(ctx, product)
+		reconciler, err := products.NewReconciler(productCtx, product, r.restConfig, configManager, installation, r.mgr, log, r.uninstallProductsInstallationLoader)
 		if err != nil {
+			cancelProduct()
 			merr.Add(fmt.Errorf("failed to build reconciler for product %s: %w", productName, err))
+			return true
 		}
 		serverClient, err := k8sclient.New(r.restConfig, k8sclient.Options{
 			Scheme: r.mgr.GetScheme(),
@@ -687,7 +782,11 @@ func (r *RHMIReconciler) handleUninstallProduct(installation *rhmiv1alpha1.RHMI,
 		if productStatus.Uninstall || installation.DeletionTimestamp != nil {
 			uninstall = true
 		}
-		phase, err := reconciler.Reconcile(context.TODO(), installation, productStatus, serverClient, quota.QuotaProductConfig{}, uninstall)
+		phase, err := reconciler.Reconcile(productCtx, installation, productStatus, serverClient, quota.QuotaProductConfig{}, uninstall)
+		if phase != rhmiv1alpha1.PhaseCompleted {
+			err = withContextCause(productCtx, err)
+		}
+		cancelProduct()
 		if err != nil {
 			merr.Add(fmt.Errorf("failed to reconcile product %s: %w", productName, err))
 		}
@@ -699,7 +798,7 @@ func (r *RHMIReconciler) handleUninstallProduct(installation *rhmiv1alpha1.RHMI,
 	return false
 }
 
-func (r *RHMIReconciler) handleUninstallBootstrap(installation *rhmiv1alpha1.RHMI, finalizers []string, stage Stage, configManager *config.Manager, merr *resources.MultiErr, request ctrl.Request) bool {
+func (r *RHMIReconciler) handleUninstallBootstrap(ctx context.Context, installation *rhmiv1alpha1.RHMI, finalizers []string, stage Stage, configManager *config.Manager, merr *resources.MultiErr, request ctrl.Request) bool {
 	for _, productFinalizer := range finalizers {
 		if !strings.Contains(productFinalizer, "observability") {
 			continue
@@ -719,7 +818,10 @@ func (r *RHMIReconciler) handleUninstallBootstrap(installation *rhmiv1alpha1.RHM
 			merr.Add(fmt.Errorf("could not create server client: %w", err))
 		}
 
-		phase, err := reconciler.Reconcile(context.TODO(), installation, serverClient, &quota.Quota{}, request)
+		phase, err := reconciler.Reconcile(ctx, installation, serverClient, &quota.Quota{}, request)
+		if phase != rhmiv1alpha1.PhaseCompleted {
+			err = withContextCause(ctx, err)
+		}
 		if err != nil {
 			merr.Add(fmt.Errorf("failed to reconcile bootstrap: %w", err))
 		}
@@ -781,7 +883,24 @@ func (r *RHMIReconciler) preflightChecks(installation *rhmiv1alpha1.RHMI, instal
 		return result, nil
 	}
 
-	requiredSecrets := []string{installation.Spec.PagerDutySecret}
+	installType := rhmiv1alpha1.InstallationType(installation.Spec.Type)
+	if rhmiv1alpha1.IsDeveloper(installType) && strings.ToLower(installation.Spec.UseClusterStorage) != "true" {
+		installation.Status.PreflightStatus = rhmiv1alpha1.PreflightFail
+		installation.Status.PreflightMessage = "Spec.useClusterStorage must be set to 'true' for developer installations, which use in-cluster postgres and redis"
+		err := r.Status().Update(context.TODO(), installation)
+		if err != nil {
+			log.Infof("error updating status", l.Fields{"error": err.Error()})
+			return result, err
+		}
+		log.Warning("preflight checks failed on useClusterStorage value")
+		return result, nil
+	}
+
+	// the pagerduty secret configures the alertmanager
+	requiredSecrets := []string{}
+	if rhmiv1alpha1.HasObservability(installType) {
+		requiredSecrets = append(requiredSecrets, installation.Spec.PagerDutySecret)
+	}
 
 	for _, secretName := range requiredSecrets {
 		secret := &corev1.Secret{
@@ -813,11 +932,15 @@ func (r *RHMIReconciler) preflightChecks(installation *rhmiv1alpha1.RHMI, instal
 	}
 
 	// Check if the quota parameter is found from the add-on
-	okParam, err := addon.ExistsParameterByInstallation(context.TODO(), r.Client, installation, addon.QuotaParamName)
-	if err != nil {
-		preflightMessage := fmt.Sprintf("failed to retrieve addon parameter %s: %v", addon.QuotaParamName, err)
-		log.Warning(preflightMessage)
-		return result, err
+	okParam := !rhmiv1alpha1.HasRateLimiting(installType)
+	var err error
+	if !okParam {
+		okParam, err = addon.ExistsParameterByInstallation(context.TODO(), r.Client, installation, addon.QuotaParamName)
+		if err != nil {
+			preflightMessage := fmt.Sprintf("failed to retrieve addon parameter %s: %v", addon.QuotaParamName, err)
+			log.Warning(preflightMessage)
+			return result, err
+		}
 	}
 
 	// Check if the trial-quota parameter is found from the add-on when normal quota param is not found
@@ -910,7 +1033,33 @@ func (r *RHMIReconciler) preflightChecks(installation *rhmiv1alpha1.RHMI, instal
 		log.Info("STS mode enabled for cluster")
 	}
 
-	if !resources.IsInProw(installation) {
+	// the pull secret and the trusted CA of the cluster proxy are outside of the namespaces of the cache
+	uncachedClient, err := k8sclient.New(r.restConfig, k8sclient.Options{
+		Scheme: r.mgr.GetScheme(),
+	})
+	if err != nil {
+		return result, err
+	}
+	preflightMessage, err := checkImageOverrides(context.TODO(), uncachedClient, installation, newImagePullCheckClient(uncachedClient))
+	if err != nil {
+		return result, err
+	}
+	if preflightMessage != "" {
+		log.Warning(preflightMessage)
+		eventRecorder.Event(installation, "Warning", rhmiv1alpha1.EventProcessingError, preflightMessage)
+
+		installation.Status.PreflightStatus = rhmiv1alpha1.PreflightFail
+		installation.Status.PreflightMessage = preflightMessage
+		err = r.Status().Update(context.TODO(), installation)
+		if err != nil {
+			log.Infof("error updating status", l.Fields{"error": err.Error()})
+			return result, err
+		}
+		return result, nil
+	}
+
+	// the cluster package installs the observability stack
+	if !resources.IsInProw(installation) && rhmiv1alpha1.HasObservability(installType) {
 		err = r.checkClusterPackageAvailablity()
 		if err != nil {
 			log.Infof("error validating cluster package availability", l.Fields{"error": err.Error()})
@@ -983,7 +1132,7 @@ func (r *RHMIReconciler) checkNamespaceForProducts(ns corev1.Namespace, installa
 	}
 	for _, stage := range installationType.InstallStages {
 		for _, product := range stage.Products {
-			reconciler, err := products.NewReconciler(product.Name, r.restConfig, configManager, installation, r.mgr, log, r.productsInstallationLoader)
+			reconciler, err := products.NewReconciler(context.TODO(), product.Name, r.restConfig, configManager, installation, r.mgr, log, r.productsInstallationLoader)
 			if err != nil {
 				return foundProducts, err
 			}
@@ -1003,7 +1152,7 @@ func (r *RHMIReconciler) checkNamespaceForProducts(ns corev1.Namespace, installa
 	return foundProducts, nil
 }
 
-func (r *RHMIReconciler) bootstrapStage(installation *rhmiv1alpha1.RHMI, configManager config.ConfigReadWriter, log l.Logger, quota *quota.Quota, request ctrl.Request) (rhmiv1alpha1.StatusPhase, error) {
+func (r *RHMIReconciler) bootstrapStage(ctx context.Context, installation *rhmiv1alpha1.RHMI, configManager config.ConfigReadWriter, log l.Logger, quota *quota.Quota, request ctrl.Request) (rhmiv1alpha1.StatusPhase, error) {
 	installation.Status.Stage = rhmiv1alpha1.BootstrapStage
 	mpm := marketplace.NewManager()
 
@@ -1018,7 +1167,7 @@ func (r *RHMIReconciler) bootstrapStage(installation *rhmiv1alpha1.RHMI, configM
 		return rhmiv1alpha1.PhaseFailed, fmt.Errorf("could not create server client: %w", err)
 	}
 
-	phase, err := reconciler.Reconcile(context.TODO(), installation, serverClient, quota, request)
+	phase, err := reconciler.Reconcile(ctx, installation, serverClient, quota, request)
 	if err != nil || phase == rhmiv1alpha1.PhaseFailed {
 		return rhmiv1alpha1.PhaseFailed, fmt.Errorf("bootstrap stage reconcile failed: %w", err)
 	}
@@ -1026,7 +1175,7 @@ func (r *RHMIReconciler) bootstrapStage(installation *rhmiv1alpha1.RHMI, configM
 	return phase, nil
 }
 
-func (r *RHMIReconciler) processStage(installation *rhmiv1alpha1.RHMI, stage *Stage,
+func (r *RHMIReconciler) processStage(ctx context.Context, installation *rhmiv1alpha1.RHMI, stage *Stage,
 	configManager config.ConfigReadWriter, quotaconfig *quota.Quota, _ l.Logger) (rhmiv1alpha1.StatusPhase, error) {
 	incompleteStage := false
 	productVersionMismatchFound = false
@@ -1034,17 +1183,36 @@ func (r *RHMIReconciler) processStage(installation *rhmiv1alpha1.RHMI, stage *St
 	var mErr error
 	installation.Status.Stage = stage.Name
 
+	productsInstallation, err := r.productsInstallationLoader.GetProductsInstallation()
+	if err != nil {
+		return rhmiv1alpha1.PhaseFailed, fmt.Errorf("failed to load the product declarations: %w", err)
+	}
+	for product, reason := range productsInstallation.InvalidOverrides {
+		log.Warningf("Skipped the product declaration override", l.Fields{"product": product, "stage": stage.Name, "reason": reason})
+	}
+
 	for productName := range stage.Products {
+		// the products left are reconciled by the next reconcile
+		if ctx.Err() != nil {
+			return rhmiv1alpha1.PhaseInProgress, withContextCause(ctx, mErr)
+		}
 		productStatus := stage.Products[productName]
 		productLog := l.NewLoggerWithContext(l.Fields{l.ProductLogContext: productStatus.Name})
+		productStatus.Declaration = productDeclarationStatus(productsInstallation, productStatus.Name)
+
+		// a disabled product goes through the uninstall of its reconciler
+		disabled := installation.IsProductDisabled(productStatus.Name)
+		productStatus.Uninstall = disabled
 
-		reconciler, err := products.NewReconciler(productStatus.Name, r.restConfig, configManager, installation, r.mgr, productLog, r.productsInstallationLoader)
+		productCtx, cancelProduct := withProductTimeout(ctx, productStatus.Name)
+		reconciler, err := products.NewReconciler(productCtx, productStatus.Name, r.restConfig, configManager, installation, r.mgr, productLog, r.productsInstallationLoader)
 
 		if err != nil {
+			cancelProduct()
 			return rhmiv1alpha1.PhaseFailed, fmt.Errorf("failed to build a reconciler for %s: %w", productStatus.Name, err)
 		}
 
-		if !reconciler.VerifyVersion(installation) {
+		if !disabled && !reconciler.VerifyVersion(installation) {
 			productVersionMismatchFound = true
 		}
 
@@ -1052,6 +1220,7 @@ func (r *RHMIReconciler) processStage(installation *rhmiv1alpha1.RHMI, stage *St
 			Scheme: r.mgr.GetScheme(),
 		})
 		if err != nil {
+			cancelProduct()
 			return rhmiv1alpha1.PhaseFailed, fmt.Errorf("could not create server client: %w", err)
 		}
 
@@ -1059,7 +1228,17 @@ func (r *RHMIReconciler) processStage(installation *rhmiv1alpha1.RHMI, stage *St
 		if productStatus.Uninstall || installation.DeletionTimestamp != nil {
 			uninstall = true
 		}
-		productStatus.Phase, err = reconciler.Reconcile(context.TODO(), installation, &productStatus, serverClient, quotaconfig.GetProduct(productName), uninstall)
+		productStatus.Phase, err = reconciler.Reconcile(productCtx, installation, &productStatus, serverClient, quotaconfig.GetProduct(productName), uninstall)
+		if productStatus.Phase != rhmiv1alpha1.PhaseCompleted {
+			err = withContextCause(productCtx, err)
+		}
+		cancelProduct()
+		productStatus.Disabled = disabled && productStatus.Phase == rhmiv1alpha1.PhaseCompleted
+		if productStatus.Disabled {
+			productStatus.Host = ""
+			productStatus.Version = ""
+			productStatus.OperatorVersion = ""
+		}
 
 		if err != nil {
 			if mErr == nil {
@@ -1074,7 +1253,7 @@ func (r *RHMIReconciler) processStage(installation *rhmiv1alpha1.RHMI, stage *St
 			return rhmiv1alpha1.PhaseFailed, fmt.Errorf("failed to read productStatus config for %s: %v", string(productStatus.Name), err)
 		}
 
-		if productStatus.Phase == rhmiv1alpha1.PhaseCompleted {
+		if productStatus.Phase == rhmiv1alpha1.PhaseCompleted && !productStatus.Disabled {
 			for _, crd := range productConfig.GetWatchableCRDs() {
 				namespace := productConfig.GetNamespace()
 				gvk := crd.GetObjectKind().GroupVersionKind().String()
@@ -1106,6 +1285,95 @@ func (r *RHMIReconciler) processStage(installation *rhmiv1alpha1.RHMI, stage *St
 	return rhmiv1alpha1.PhaseCompleted, mErr
 }
 
+// productDeclarationStatus reports the effective declaration of the product, nil for the products that aren't
+// installed from a declaration
+func productDeclarationStatus(productsInstallation *marketplace.ProductsInstallation, product rhmiv1alpha1.ProductName) *rhmiv1alpha1.RHMIProductDeclaration {
+	declaration, ok := productsInstallation.Products[string(product)]
+	if !ok {
+		return nil
+	}
+
+	status := &rhmiv1alpha1.RHMIProductDeclaration{
+		InstallFrom:   string(declaration.InstallFrom),
+		Index:         declaration.Index,
+		Channel:       declaration.GetChannel(),
+		Package:       declaration.Package,
+		Overridden:    productsInstallation.Overridden[string(product)],
+		OverrideError: productsInstallation.InvalidOverrides[string(product)],
+	}
+	if declaration.ManifestsDir != nil {
+		status.ManifestsDir = *declaration.ManifestsDir
+	}
+	return status
+}
+
+// startStageHistory records the start of the stage against the in progress upgrade and quota change history
+// entries before the stage runs, starting the entries when the installation is targeting a new version or quota
+func startStageHistory(installation *rhmiv1alpha1.RHMI, stageName rhmiv1alpha1.StageName, start metav1.Time) {
+	for _, entry := range stageHistoryEntries(installation, start, false) {
+		entry.StageStarted(stageName, start)
+	}
+}
+
+// updateStageHistory records the outcome of the stage once it ran. A failed stage fails the entries once, they
+// stay failed while the stage keeps failing and are resumed when a stage completes
+func updateStageHistory(installation *rhmiv1alpha1.RHMI, stageName rhmiv1alpha1.StageName, stagePhase rhmiv1alpha1.StatusPhase, stageErr error, now metav1.Time) {
+	switch stagePhase {
+	case rhmiv1alpha1.PhaseCompleted:
+		for _, entry := range stageHistoryEntries(installation, now, true) {
+			entry.StageCompleted(stageName, now)
+		}
+	case rhmiv1alpha1.PhaseFailed:
+		for _, entry := range stageHistoryEntries(installation, now, false) {
+			entry.Complete(rhmiv1alpha1.HistoryOutcomeFailed, stageFailureMessage(stageName, stageErr), now)
+		}
+	}
+}
+
+// stageHistoryEntries returns the upgrade and quota change history entries the stages are recorded against.
+// Failed entries are left out unless resumeFailed is set
+func stageHistoryEntries(installation *rhmiv1alpha1.RHMI, now metav1.Time, resumeFailed bool) []*rhmiv1alpha1.RHMIHistoryEntry {
+	var entries []*rhmiv1alpha1.RHMIHistoryEntry
+	targets := []struct {
+		entryType rhmiv1alpha1.HistoryEntryType
+		from, to  string
+	}{
+		{rhmiv1alpha1.HistoryEntryUpgrade, installation.Status.Version, installation.Status.ToVersion},
+		{rhmiv1alpha1.HistoryEntryQuotaChange, installation.Status.Quota, installation.Status.ToQuota},
+	}
+	for _, target := range targets {
+		if target.to == "" || (!resumeFailed && installation.IsHistoryEntryFailed(target.entryType, target.to)) {
+			continue
+		}
+		entries = append(entries, installation.StartHistoryEntry(target.entryType, target.from, target.to, now))
+	}
+	return entries
+}
+
+func stageFailureMessage(stageName rhmiv1alpha1.StageName, err error) string {
+	if err == nil {
+		return fmt.Sprintf("stage %s failed", stageName)
+	}
+	return fmt.Sprintf("stage %s failed: %v", stageName, err)
+}
+
+// historyEntrySummary describes a completed history entry, for the records that outlive the RHMI CR
+func historyEntrySummary(entry *rhmiv1alpha1.RHMIHistoryEntry) string {
+	stages := make([]string, 0, len(entry.Stages))
+	for _, stage := range entry.Stages {
+		if stage.Duration != nil {
+			stages = append(stages, fmt.Sprintf("%s %s", stage.Name, stage.Duration.Round(time.Second)))
+		}
+	}
+	return fmt.Sprintf("%s of %s %s in %s, stages: %s", entry.Type, entry.From, entry.Outcome, entry.GetDuration().Round(time.Second), strings.Join(stages, ", "))
+}
+
+func completeHistoryEntry(installation *rhmiv1alpha1.RHMI, entryType rhmiv1alpha1.HistoryEntryType, now metav1.Time) {
+	if entry := installation.GetInProgressHistoryEntry(entryType); entry != nil {
+		entry.Complete(rhmiv1alpha1.HistoryOutcomeSucceeded, "", now)
+	}
+}
+
 // handle the deletion of CRO config map
 func (r *RHMIReconciler) handleCROConfigDeletion(rhmi rhmiv1alpha1.RHMI) error {
 	// get cloud resource config map
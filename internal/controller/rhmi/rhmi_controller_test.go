@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"os"
 	"reflect"
 	"testing"
@@ -59,6 +60,41 @@ func TestHandleCROConfigDeletion(t *testing.T) {
 	}
 }
 
+func TestUpdateStageHistory(t *testing.T) {
+	start := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	installation := &rhmiv1alpha1.RHMI{
+		Status: rhmiv1alpha1.RHMIStatus{Version: "1.1.0", ToVersion: "1.2.0"},
+	}
+
+	at := func(minutes int) metav1.Time {
+		return metav1.NewTime(start.Add(time.Duration(minutes) * time.Minute))
+	}
+
+	startStageHistory(installation, rhmiv1alpha1.InstallStage, at(0))
+	updateStageHistory(installation, rhmiv1alpha1.InstallStage, rhmiv1alpha1.PhaseFailed, errors.New("3scale failed"), at(1))
+	entry := installation.Status.History[0]
+	if entry.Outcome != rhmiv1alpha1.HistoryOutcomeFailed || entry.Message != "stage installation failed: 3scale failed" || !entry.Stages[0].StartTime.Equal(&start) {
+		t.Fatalf("expected the upgrade to fail with the stage error, got %+v", entry)
+	}
+
+	startStageHistory(installation, rhmiv1alpha1.InstallStage, at(2))
+	updateStageHistory(installation, rhmiv1alpha1.InstallStage, rhmiv1alpha1.PhaseFailed, errors.New("3scale failed again"), at(3))
+	entry = installation.Status.History[0]
+	if endTime := at(1); entry.Outcome != rhmiv1alpha1.HistoryOutcomeFailed || !entry.EndTime.Equal(&endTime) || entry.Message != "stage installation failed: 3scale failed" {
+		t.Fatalf("expected the upgrade to stay failed while the stage keeps failing, got %+v", entry)
+	}
+
+	startStageHistory(installation, rhmiv1alpha1.InstallStage, at(4))
+	updateStageHistory(installation, rhmiv1alpha1.InstallStage, rhmiv1alpha1.PhaseCompleted, nil, at(5))
+	if len(installation.Status.History) != 1 {
+		t.Fatalf("expected the failed upgrade to be resumed, got %+v", installation.Status.History)
+	}
+	entry = installation.Status.History[0]
+	if entry.Outcome != rhmiv1alpha1.HistoryOutcomeInProgress || entry.EndTime != nil || entry.Stages[0].Duration == nil || entry.Stages[0].Duration.Duration != 5*time.Minute {
+		t.Fatalf("expected the resumed upgrade to record the stage from its first start, got %+v", entry)
+	}
+}
+
 func TestFirstInstallFirstReconcile(t *testing.T) {
 	tests := []struct {
 		name         string
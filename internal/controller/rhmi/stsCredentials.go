@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	rhmiv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/metrics"
+	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/sts"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// stsCredentialsCheckInterval limits the calls to STS, the installation is reconciled more often
+const stsCredentialsCheckInterval = 5 * time.Minute
+
+// reconcileSTSCredentialsHealth checks that the STS role of the cloud resources operator can be assumed, so that
+// expired or revoked credentials are reported before the provisioning of the cloud resources fails.
+// cloudResourcesNamespace is the namespace of the STS credentials secret, empty until cloud resources is installed
+func (r *RHMIReconciler) reconcileSTSCredentialsHealth(ctx context.Context, installation *rhmiv1alpha1.RHMI, cloudResourcesNamespace string, log l.Logger) {
+	if cloudResourcesNamespace == "" || time.Since(r.stsCredentialsCheckedAt) < stsCredentialsCheckInterval {
+		return
+	}
+	r.stsCredentialsCheckedAt = time.Now()
+
+	isSTS, err := sts.IsClusterSTS(ctx, r.Client, log)
+	if err != nil {
+		log.Warningf("failed to check STS mode for the STS credentials health", l.Fields{"error": err})
+		return
+	}
+	if !isSTS {
+		meta.RemoveStatusCondition(&installation.Status.Conditions, rhmiv1alpha1.STSCredentialsConditionType.String())
+		metrics.ResetSTSCredentialsHealthy()
+		return
+	}
+
+	// the role is assumed with a token of the cloud resources operator, the principal its trust policy allows
+	roleARN, _, err := sts.GetSTSCredentials(ctx, r.Client, cloudResourcesNamespace)
+	if err == nil {
+		var token string
+		token, err = sts.RequestServiceAccountToken(ctx, r.Client, cloudResourcesNamespace, sts.CROServiceAccountName)
+		if err == nil {
+			err = r.stsCredentialsChecker.Check(ctx, roleARN, token)
+		}
+	}
+	if err != nil {
+		log.Warningf("STS credentials are not working", l.Fields{"error": err})
+		meta.SetStatusCondition(&installation.Status.Conditions, installation.STSCredentialsUnhealthyCondition(err.Error()))
+		metrics.SetSTSCredentialsHealthy(false)
+		return
+	}
+	meta.SetStatusCondition(&installation.Status.Conditions, installation.STSCredentialsHealthyCondition())
+	metrics.SetSTSCredentialsHealthy(true)
+}
@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	rhmiv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/metrics"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/logger"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/sts"
+	"github.com/integr8ly/integreatly-operator/utils"
+	cloudcredentialv1 "github.com/openshift/api/operator/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReconcileSTSCredentialsHealth(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	credentialsSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: sts.CredsSecretName, Namespace: "redhat-rhoam-cloud-resources-operator"},
+		Data: map[string][]byte{
+			sts.CredsSecretRoleARNKeyName:   []byte("arn:aws:iam::123456789012:role/rhoam"),
+			sts.CredsSecretTokenPathKeyName: []byte("/var/run/secrets/openshift/serviceaccount/token"),
+		},
+	}
+	serviceAccount := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: sts.CROServiceAccountName, Namespace: credentialsSecret.Namespace},
+	}
+	cloudCredential := func(mode cloudcredentialv1.CloudCredentialsMode) *cloudcredentialv1.CloudCredential {
+		return &cloudcredentialv1.CloudCredential{
+			ObjectMeta: metav1.ObjectMeta{Name: sts.ClusterCloudCredentialName},
+			Spec:       cloudcredentialv1.CloudCredentialSpec{CredentialsMode: mode},
+		}
+	}
+
+	scenarios := []struct {
+		Name            string
+		CredentialsMode cloudcredentialv1.CloudCredentialsMode
+		StatusCode      int
+		CheckedAt       time.Time
+		WantCondition   *metav1.ConditionStatus
+	}{
+		{
+			Name:            "healthy when the role is assumed",
+			CredentialsMode: cloudcredentialv1.CloudCredentialsModeManual,
+			StatusCode:      http.StatusOK,
+			WantCondition:   conditionStatusPtr(metav1.ConditionTrue),
+		},
+		{
+			Name:            "unhealthy when the role can't be assumed",
+			CredentialsMode: cloudcredentialv1.CloudCredentialsModeManual,
+			StatusCode:      http.StatusForbidden,
+			WantCondition:   conditionStatusPtr(metav1.ConditionFalse),
+		},
+		{
+			Name:            "no condition when the cluster isn't STS",
+			CredentialsMode: cloudcredentialv1.CloudCredentialsModeDefault,
+			StatusCode:      http.StatusOK,
+		},
+		{
+			Name:            "not checked again within the interval",
+			CredentialsMode: cloudcredentialv1.CloudCredentialsModeManual,
+			StatusCode:      http.StatusOK,
+			CheckedAt:       time.Now(),
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/xml")
+				w.WriteHeader(scenario.StatusCode)
+				if scenario.StatusCode == http.StatusOK {
+					_, _ = w.Write([]byte(`<AssumeRoleWithWebIdentityResponse><AssumeRoleWithWebIdentityResult><Credentials><AccessKeyId>id</AccessKeyId><SecretAccessKey>secret</SecretAccessKey><SessionToken>session</SessionToken><Expiration>2030-01-01T00:00:00Z</Expiration></Credentials></AssumeRoleWithWebIdentityResult></AssumeRoleWithWebIdentityResponse>`))
+					return
+				}
+				_, _ = w.Write([]byte(`<ErrorResponse><Error><Type>Sender</Type><Code>AccessDenied</Code><Message>Not authorized</Message></Error></ErrorResponse>`))
+			}))
+			defer server.Close()
+
+			r := &RHMIReconciler{
+				Client: utils.NewTestClient(scheme, credentialsSecret, serviceAccount, cloudCredential(scenario.CredentialsMode)),
+				stsCredentialsChecker: &sts.CredentialsChecker{
+					HTTPClient: server.Client(),
+					Endpoint:   server.URL,
+				},
+				stsCredentialsCheckedAt: scenario.CheckedAt,
+			}
+			installation := &rhmiv1alpha1.RHMI{}
+
+			r.reconcileSTSCredentialsHealth(context.TODO(), installation, credentialsSecret.Namespace, logger.NewLogger())
+
+			series := stsCredentialsHealthySeries()
+			if scenario.CredentialsMode != cloudcredentialv1.CloudCredentialsModeManual && series != 0 {
+				t.Errorf("expected no STS credentials health series on a cluster that isn't STS, got %d", series)
+			}
+			if scenario.WantCondition != nil && series != 1 {
+				t.Errorf("expected an STS credentials health series, got %d", series)
+			}
+
+			condition := meta.FindStatusCondition(installation.Status.Conditions, rhmiv1alpha1.STSCredentialsConditionType.String())
+			if scenario.WantCondition == nil {
+				if condition != nil {
+					t.Fatalf("expected no STS credentials condition, got %v", condition)
+				}
+				return
+			}
+			if condition == nil || condition.Status != *scenario.WantCondition {
+				t.Fatalf("expected STS credentials condition %s, got %v", *scenario.WantCondition, condition)
+			}
+		})
+	}
+}
+
+func conditionStatusPtr(status metav1.ConditionStatus) *metav1.ConditionStatus {
+	return &status
+}
+
+func stsCredentialsHealthySeries() int {
+	ch := make(chan prometheus.Metric, 10)
+	metrics.STSCredentialsHealthy.Collect(ch)
+	close(ch)
+	return len(ch)
+}
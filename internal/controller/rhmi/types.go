@@ -90,6 +90,40 @@ var (
 			},
 		},
 	}
+	allDeveloperStages = &Type{
+		[]Stage{
+			{
+				Name: integreatlyv1alpha1.BootstrapStage,
+			},
+			{
+				Name: integreatlyv1alpha1.InstallStage,
+				Products: map[integreatlyv1alpha1.ProductName]integreatlyv1alpha1.RHMIProductStatus{
+					// provisions the in-cluster postgres and redis of RHSSO and 3scale
+					integreatlyv1alpha1.ProductCloudResources: {Name: integreatlyv1alpha1.ProductCloudResources},
+					integreatlyv1alpha1.ProductRHSSO:          {Name: integreatlyv1alpha1.ProductRHSSO},
+					integreatlyv1alpha1.Product3Scale:         {Name: integreatlyv1alpha1.Product3Scale},
+				},
+			},
+		},
+		[]Stage{
+			{
+				Name: integreatlyv1alpha1.UninstallProductsStage,
+				Products: map[integreatlyv1alpha1.ProductName]integreatlyv1alpha1.RHMIProductStatus{
+					integreatlyv1alpha1.ProductRHSSO:  {Name: integreatlyv1alpha1.ProductRHSSO},
+					integreatlyv1alpha1.Product3Scale: {Name: integreatlyv1alpha1.Product3Scale},
+				},
+			},
+			{
+				Name: integreatlyv1alpha1.UninstallCloudResourcesStage,
+				Products: map[integreatlyv1alpha1.ProductName]integreatlyv1alpha1.RHMIProductStatus{
+					integreatlyv1alpha1.ProductCloudResources: {Name: integreatlyv1alpha1.ProductCloudResources},
+				},
+			},
+			{
+				Name: integreatlyv1alpha1.UninstallBootstrap,
+			},
+		},
+	}
 )
 
 type Type struct {
@@ -118,6 +152,8 @@ func TypeFactory(installationType string) (*Type, error) {
 		return newManagedApiType(), nil
 	case string(integreatlyv1alpha1.InstallationTypeMultitenantManagedApi):
 		return newMultitenantManagedApiType(), nil
+	case string(integreatlyv1alpha1.InstallationTypeDeveloper):
+		return newDeveloperType(), nil
 	default:
 		return nil, errors.New("unknown installation type: " + installationType)
 	}
@@ -131,6 +167,10 @@ func newMultitenantManagedApiType() *Type {
 	return allMultitenantManagedApiStages
 }
 
+func newDeveloperType() *Type {
+	return allDeveloperStages
+}
+
 // GetProductNamespaces builds the expected product namespaces for all possible RHOAM products
 func GetProductNamespaces(watchNamespace string) (map[string]cache.Config, error) {
 	namespacePrefix := strings.TrimSuffix(watchNamespace, "operator")
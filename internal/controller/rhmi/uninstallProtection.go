@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"time"
+
+	rhmiv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/backup"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/constants"
+	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const uninstallExportingMessage = "Exporting the product data, the uninstall waits for the snapshots to complete"
+
+// uninstallExportExecutors returns the executors that export the 3scale and RHSSO data before an
+// uninstall. Only the data in cloud resources is exported, there are no executors for the cluster
+// storage, which is deleted with the product namespaces
+func uninstallExportExecutors(installation *rhmiv1alpha1.RHMI) map[rhmiv1alpha1.ProductName]backup.BackupExecutor {
+	if installation.Spec.UseClusterStorage != "false" {
+		return map[rhmiv1alpha1.ProductName]backup.BackupExecutor{}
+	}
+
+	return map[rhmiv1alpha1.ProductName]backup.BackupExecutor{
+		rhmiv1alpha1.Product3Scale: backup.NewConcurrentBackupExecutor(
+			backup.NewAWSExportExecutor(installation.Namespace, constants.ThreeScalePostgresPrefix+installation.Name, backup.PostgresSnapshotType),
+			backup.NewAWSExportExecutor(installation.Namespace, constants.ThreeScaleBackendRedisPrefix+installation.Name, backup.RedisSnapshotType),
+			backup.NewAWSExportExecutor(installation.Namespace, constants.ThreeScaleSystemRedisPrefix+installation.Name, backup.RedisSnapshotType),
+		),
+		rhmiv1alpha1.ProductRHSSO: backup.NewAWSExportExecutor(installation.Namespace, constants.RHSSOPostgresPrefix+installation.Name, backup.PostgresSnapshotType),
+	}
+}
+
+// reconcileDeletionProtection returns whether the uninstall can delete the products and cloud resources.
+// With deletion protection, the first phase exports the product data and reports the snapshots and a
+// confirmation token in the uninstall status. The data in the cluster storage can't be exported, only the
+// token is reported. The second phase starts once the confirmation annotation is set to the token
+func reconcileDeletionProtection(ctx context.Context, serverClient k8sclient.Client, installation *rhmiv1alpha1.RHMI, executors map[rhmiv1alpha1.ProductName]backup.BackupExecutor, recorder record.EventRecorder) (bool, error) {
+	if !installation.Spec.DeletionProtection {
+		return true, nil
+	}
+
+	status := installation.Status.Uninstall
+	if status == nil {
+		status = &rhmiv1alpha1.RHMIUninstallStatus{}
+		installation.Status.Uninstall = status
+	}
+
+	if status.ConfirmationToken == "" {
+		// the cluster storage is deleted with the product namespaces, there is nothing the operator can export
+		if len(executors) == 0 {
+			token, err := generateConfirmationToken()
+			if err != nil {
+				return false, err
+			}
+			status.ConfirmationToken = token
+			status.Message = fmt.Sprintf("Product data not exported, it is in the cluster storage and is deleted with the product namespaces. Back it up, then set the %s annotation to %s to delete the products", rhmiv1alpha1.UninstallConfirmationAnnotation, token)
+			if err := serverClient.Status().Update(ctx, installation); err != nil {
+				return false, fmt.Errorf("failed to update the uninstall status: %w", err)
+			}
+			if recorder != nil {
+				recorder.Event(installation, corev1.EventTypeWarning, rhmiv1alpha1.EventUninstallDataNotExported, "The product data is in the cluster storage and can't be exported, the uninstall waits for the confirmation")
+			}
+			log.Warning("Uninstall waiting for confirmation, the product data in the cluster storage is not exported")
+			return false, nil
+		}
+
+		exports, done, err := exportUninstallData(ctx, serverClient, executors)
+		if err != nil {
+			status.Message = fmt.Sprintf("Failed to export the product data, the uninstall is retried: %v", err)
+			if updateErr := serverClient.Status().Update(ctx, installation); updateErr != nil {
+				return false, fmt.Errorf("failed to update the uninstall status: %w", updateErr)
+			}
+			return false, fmt.Errorf("failed to export the product data before the uninstall: %w", err)
+		}
+		if !done {
+			if status.Message != uninstallExportingMessage {
+				status.Message = uninstallExportingMessage
+				if err := serverClient.Status().Update(ctx, installation); err != nil {
+					return false, fmt.Errorf("failed to update the uninstall status: %w", err)
+				}
+			}
+			return false, nil
+		}
+
+		token, err := generateConfirmationToken()
+		if err != nil {
+			return false, err
+		}
+		now := metav1.Now()
+		status.Exported = true
+		status.ExportTime = &now
+		status.Exports = exports
+		status.ConfirmationToken = token
+		status.Message = fmt.Sprintf("Product data exported, set the %s annotation to %s to delete the products and cloud resources", rhmiv1alpha1.UninstallConfirmationAnnotation, token)
+		if err := serverClient.Status().Update(ctx, installation); err != nil {
+			return false, fmt.Errorf("failed to update the uninstall status: %w", err)
+		}
+		if recorder != nil {
+			recorder.Event(installation, corev1.EventTypeNormal, rhmiv1alpha1.EventUninstallDataExported, fmt.Sprintf("Exported %d snapshots, the uninstall waits for the confirmation", len(exports)))
+		}
+		log.Infof("Uninstall waiting for confirmation", l.Fields{"exports": len(exports)})
+		return false, nil
+	}
+
+	if status.Confirmed {
+		return true, nil
+	}
+	if !installation.IsUninstallConfirmed() {
+		if _, ok := installation.GetAnnotations()[rhmiv1alpha1.UninstallConfirmationAnnotation]; ok {
+			log.Warning("Uninstall confirmation annotation does not match the confirmation token")
+		}
+		return false, nil
+	}
+
+	// recorded so that removing the annotation doesn't stop an uninstall in progress
+	status.Confirmed = true
+	status.Message = "Uninstall confirmed"
+	if err := serverClient.Status().Update(ctx, installation); err != nil {
+		return false, fmt.Errorf("failed to update the uninstall status: %w", err)
+	}
+	if recorder != nil {
+		recorder.Event(installation, corev1.EventTypeNormal, rhmiv1alpha1.EventUninstallConfirmed, "Uninstall confirmed, deleting the products and cloud resources")
+	}
+	return true, nil
+}
+
+// exportUninstallData starts the snapshots of each product, and once they all completed returns where
+// they are. The snapshots are checked on each reconcile instead of blocking the uninstall until they complete
+func exportUninstallData(ctx context.Context, serverClient k8sclient.Client, executors map[rhmiv1alpha1.ProductName]backup.BackupExecutor) ([]rhmiv1alpha1.RHMIUninstallExport, bool, error) {
+	complete := true
+	for _, product := range []rhmiv1alpha1.ProductName{rhmiv1alpha1.Product3Scale, rhmiv1alpha1.ProductRHSSO} {
+		executor, ok := executors[product]
+		if !ok {
+			continue
+		}
+		// the exports are only taken for this uninstall, any export snapshot is reused
+		done, err := backup.StartBackup(ctx, serverClient, executor, time.Time{})
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to export %s data: %w", product, err)
+		}
+		complete = complete && done
+	}
+	if !complete {
+		return nil, false, nil
+	}
+
+	exports := []rhmiv1alpha1.RHMIUninstallExport{}
+	for _, product := range []rhmiv1alpha1.ProductName{rhmiv1alpha1.Product3Scale, rhmiv1alpha1.ProductRHSSO} {
+		executor, ok := executors[product]
+		if !ok {
+			continue
+		}
+		for _, snapshot := range backup.GetSnapshots(executor) {
+			exports = append(exports, rhmiv1alpha1.RHMIUninstallExport{
+				Product:      product,
+				Type:         string(snapshot.Type),
+				ResourceName: snapshot.ResourceName,
+				Snapshot:     path.Join(snapshot.Namespace, snapshot.Name),
+				SnapshotID:   snapshot.SnapshotID,
+			})
+		}
+	}
+	return exports, true, nil
+}
+
+func generateConfirmationToken() (string, error) {
+	token := make([]byte, 8)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("failed to generate the uninstall confirmation token: %w", err)
+	}
+	return hex.EncodeToString(token), nil
+}
@@ -0,0 +1,184 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	rhmiv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	moqclient "github.com/integr8ly/integreatly-operator/pkg/client"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/backup"
+	"github.com/integr8ly/integreatly-operator/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type testExportExecutor struct {
+	snapshots []backup.Snapshot
+	pending   bool
+	err       error
+	backups   int
+}
+
+func (e *testExportExecutor) PerformBackup(_ k8sclient.Client, _ time.Duration) error {
+	return errors.New("the export must not wait for the backup")
+}
+
+func (e *testExportExecutor) StartBackup(_ context.Context, _ k8sclient.Client, _ time.Time) (bool, error) {
+	e.backups++
+	return !e.pending, e.err
+}
+
+func (e *testExportExecutor) Snapshots() []backup.Snapshot {
+	return e.snapshots
+}
+
+func TestReconcileDeletionProtection(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot := backup.Snapshot{
+		Type:         backup.PostgresSnapshotType,
+		Namespace:    "redhat-rhoam-operator",
+		Name:         "threescale-postgres-rhoam-uninstall-export-snapshot",
+		ResourceName: "threescale-postgres-rhoam",
+		SnapshotID:   "rds:snapshot-1",
+	}
+	exported := func(token string, confirmed bool) *rhmiv1alpha1.RHMIUninstallStatus {
+		return &rhmiv1alpha1.RHMIUninstallStatus{Exported: true, ConfirmationToken: token, Confirmed: confirmed}
+	}
+
+	scenarios := []struct {
+		Name           string
+		Protection     bool
+		Annotation     string
+		Status         *rhmiv1alpha1.RHMIUninstallStatus
+		ClusterStorage bool
+		ExportPending  bool
+		ExportErr      error
+		WantProceed    bool
+		WantErr        bool
+		WantBackups    int
+		WantExported   bool
+		WantToken      bool
+	}{
+		{
+			Name:        "uninstall proceeds without deletion protection",
+			WantProceed: true,
+		},
+		{
+			Name:         "data is exported before waiting for the confirmation",
+			Protection:   true,
+			WantBackups:  1,
+			WantExported: true,
+			WantToken:    true,
+		},
+		{
+			Name:          "uninstall waits for the export to complete",
+			Protection:    true,
+			ExportPending: true,
+			WantBackups:   1,
+		},
+		{
+			Name:           "cluster storage data is not reported as exported",
+			Protection:     true,
+			ClusterStorage: true,
+			WantToken:      true,
+		},
+		{
+			Name:           "uninstall without an export proceeds once confirmed",
+			Protection:     true,
+			ClusterStorage: true,
+			Annotation:     "token",
+			Status:         &rhmiv1alpha1.RHMIUninstallStatus{ConfirmationToken: "token"},
+			WantProceed:    true,
+			WantToken:      true,
+		},
+		{
+			Name:        "failed export is retried",
+			Protection:  true,
+			ExportErr:   errors.New("snapshot failed"),
+			WantErr:     true,
+			WantBackups: 1,
+		},
+		{
+			Name:         "uninstall waits for a matching token",
+			Protection:   true,
+			Annotation:   "wrong-token",
+			Status:       exported("token", false),
+			WantExported: true,
+		},
+		{
+			Name:         "uninstall proceeds once confirmed",
+			Protection:   true,
+			Annotation:   "token",
+			Status:       exported("token", false),
+			WantProceed:  true,
+			WantExported: true,
+		},
+		{
+			Name:         "confirmed uninstall proceeds without the annotation",
+			Protection:   true,
+			Status:       exported("token", true),
+			WantProceed:  true,
+			WantExported: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			installation := &rhmiv1alpha1.RHMI{
+				ObjectMeta: metav1.ObjectMeta{Name: "rhoam", Namespace: "redhat-rhoam-operator"},
+				Spec:       rhmiv1alpha1.RHMISpec{DeletionProtection: scenario.Protection},
+				Status:     rhmiv1alpha1.RHMIStatus{Uninstall: scenario.Status},
+			}
+			if scenario.Annotation != "" {
+				installation.Annotations = map[string]string{rhmiv1alpha1.UninstallConfirmationAnnotation: scenario.Annotation}
+			}
+			serverClient := moqclient.NewSigsClientMoqWithSchemeWithStatusSubresource(scheme, installation)
+			executor := &testExportExecutor{snapshots: []backup.Snapshot{snapshot}, pending: scenario.ExportPending, err: scenario.ExportErr}
+			executors := map[rhmiv1alpha1.ProductName]backup.BackupExecutor{rhmiv1alpha1.Product3Scale: executor}
+			if scenario.ClusterStorage {
+				executors = map[rhmiv1alpha1.ProductName]backup.BackupExecutor{}
+			}
+
+			proceed, err := reconcileDeletionProtection(context.TODO(), serverClient, installation, executors, record.NewFakeRecorder(10))
+			if (err != nil) != scenario.WantErr {
+				t.Fatalf("expected error %v, got %v", scenario.WantErr, err)
+			}
+			if proceed != scenario.WantProceed {
+				t.Errorf("expected the uninstall to proceed %v, got %v", scenario.WantProceed, proceed)
+			}
+			if executor.backups != scenario.WantBackups {
+				t.Errorf("expected %d exports, got %d", scenario.WantBackups, executor.backups)
+			}
+			if !scenario.Protection {
+				return
+			}
+
+			updated := &rhmiv1alpha1.RHMI{}
+			if err := serverClient.Get(context.TODO(), k8sclient.ObjectKeyFromObject(installation), updated); err != nil {
+				t.Fatal(err)
+			}
+			status := updated.Status.Uninstall
+			if status == nil || status.Exported != scenario.WantExported {
+				t.Fatalf("expected exported to be %v, got %+v", scenario.WantExported, status)
+			}
+			if scenario.Status == nil && (status.ConfirmationToken != "") != scenario.WantToken {
+				t.Errorf("expected a confirmation token %v, got %q", scenario.WantToken, status.ConfirmationToken)
+			}
+			if scenario.Status == nil && scenario.WantExported {
+				if len(status.Exports) != 1 || status.Exports[0].Snapshot != "redhat-rhoam-operator/threescale-postgres-rhoam-uninstall-export-snapshot" || status.Exports[0].SnapshotID != "rds:snapshot-1" {
+					t.Errorf("expected the export location to be reported, got %+v", status.Exports)
+				}
+			}
+			if scenario.WantProceed && !status.Confirmed {
+				t.Error("expected the confirmation to be recorded")
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package status
+
+import (
+	"github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
+	addonv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+	obov1 "github.com/rhobs/observability-operator/pkg/apis/monitoring/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+const monitoringStackAvailableConditionType = "MonitoringStackAvailable"
+
+// installationConditionTypes are the types of the conditions built for the AddonInstance, a condition
+// of these types that is no longer reported is removed from the RHMI status
+var installationConditionTypes = []string{
+	addonv1alpha1.AddonInstanceConditionInstalled.String(),
+	addonv1alpha1.AddonInstanceConditionDegraded.String(),
+	v1alpha1.HealthyConditionType.String(),
+	monitoringStackAvailableConditionType,
+}
+
+// SetInstallationConditions sets the conditions reported to the AddonInstance on the RHMI status, so
+// that they are available on clusters without the addon-operator. monitoringStack is nil when it
+// can't be found
+func SetInstallationConditions(installation *v1alpha1.RHMI, monitoringStack *obov1.MonitoringStack, log l.Logger) {
+	if monitoringStack == nil {
+		monitoringStack = &obov1.MonitoringStack{}
+	}
+	r := &StatusReconciler{Log: log}
+	conditions := r.buildAddonInstanceConditions(installation, monitoringStack)
+
+	reported := map[string]bool{}
+	for _, condition := range conditions {
+		condition.ObservedGeneration = installation.Generation
+		meta.SetStatusCondition(&installation.Status.Conditions, condition)
+		reported[condition.Type] = true
+	}
+	for _, conditionType := range installationConditionTypes {
+		if !reported[conditionType] {
+			meta.RemoveStatusCondition(&installation.Status.Conditions, conditionType)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package status
+
+import (
+	"testing"
+
+	"github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/logger"
+	addonv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+	obov1 "github.com/rhobs/observability-operator/pkg/apis/monitoring/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetInstallationConditions(t *testing.T) {
+	completeStage := v1alpha1.RHMIStageStatus{
+		Name: v1alpha1.InstallStage,
+		Products: map[v1alpha1.ProductName]v1alpha1.RHMIProductStatus{
+			v1alpha1.ProductCloudResources: {Phase: v1alpha1.PhaseCompleted},
+			v1alpha1.ProductRHSSOUser:      {Phase: v1alpha1.PhaseCompleted},
+			v1alpha1.Product3Scale:         {Phase: v1alpha1.PhaseCompleted},
+		},
+	}
+	availableStack := &obov1.MonitoringStack{
+		Status: obov1.MonitoringStackStatus{
+			Conditions: []obov1.Condition{{Type: obov1.AvailableCondition, Status: obov1.ConditionTrue}},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		installation    *v1alpha1.RHMI
+		monitoringStack *obov1.MonitoringStack
+		wantTrue        []string
+		wantFalse       []string
+		wantAbsent      []string
+	}{
+		{
+			name: "test healthy installation",
+			installation: &v1alpha1.RHMI{Status: v1alpha1.RHMIStatus{
+				Version: "1.0.0",
+				Stage:   v1alpha1.CompleteStage,
+				Stages:  map[v1alpha1.StageName]v1alpha1.RHMIStageStatus{v1alpha1.InstallStage: completeStage},
+			}},
+			monitoringStack: availableStack,
+			wantTrue: []string{
+				addonv1alpha1.AddonInstanceConditionInstalled.String(),
+				v1alpha1.HealthyConditionType.String(),
+				monitoringStackAvailableConditionType,
+			},
+			wantFalse: []string{addonv1alpha1.AddonInstanceConditionDegraded.String()},
+		},
+		{
+			name: "test conditions no longer reported are removed",
+			installation: &v1alpha1.RHMI{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.Now()}, Status: v1alpha1.RHMIStatus{
+				Stage: v1alpha1.ProductsStage,
+				Conditions: []metav1.Condition{
+					{Type: addonv1alpha1.AddonInstanceConditionInstalled.String(), Status: metav1.ConditionTrue},
+					{Type: monitoringStackAvailableConditionType, Status: metav1.ConditionTrue},
+					{Type: v1alpha1.UpgradeGatesConditionType.String(), Status: metav1.ConditionTrue},
+				},
+			}},
+			wantTrue:   []string{addonv1alpha1.AddonInstanceConditionDegraded.String(), v1alpha1.UpgradeGatesConditionType.String()},
+			wantFalse:  []string{v1alpha1.HealthyConditionType.String()},
+			wantAbsent: []string{addonv1alpha1.AddonInstanceConditionInstalled.String(), monitoringStackAvailableConditionType},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetInstallationConditions(tt.installation, tt.monitoringStack, logger.NewLogger())
+
+			conditions := tt.installation.Status.Conditions
+			for _, conditionType := range tt.wantTrue {
+				if !meta.IsStatusConditionTrue(conditions, conditionType) {
+					t.Errorf("expected condition %s to be true, got %+v", conditionType, conditions)
+				}
+			}
+			for _, conditionType := range tt.wantFalse {
+				if !meta.IsStatusConditionFalse(conditions, conditionType) {
+					t.Errorf("expected condition %s to be false, got %+v", conditionType, conditions)
+				}
+			}
+			for _, conditionType := range tt.wantAbsent {
+				if meta.FindStatusCondition(conditions, conditionType) != nil {
+					t.Errorf("expected condition %s to be removed, got %+v", conditionType, conditions)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// HealthSummaryPath is the path of the health summary on the probe server
+const HealthSummaryPath = "/health-summary"
+
+// HealthSummary is the health of the installation and of each of its products
+type HealthSummary struct {
+	Name       string                          `json:"name"`
+	Version    string                          `json:"version,omitempty"`
+	Stage      v1alpha1.StageName              `json:"stage"`
+	Healthy    bool                            `json:"healthy"`
+	Degraded   bool                            `json:"degraded"`
+	Products   map[v1alpha1.ProductName]Health `json:"products"`
+	Conditions []metav1.Condition              `json:"conditions,omitempty"`
+}
+
+// Health is the health of a product
+type Health struct {
+	Version v1alpha1.ProductVersion `json:"version,omitempty"`
+	Phase   v1alpha1.StatusPhase    `json:"phase"`
+	Healthy bool                    `json:"healthy"`
+}
+
+// NewHealthSummaryHandler serves the health summary of the installation in namespace as JSON. The
+// response is 503 Service Unavailable when the installation can't be found or its core components are
+// unhealthy, so that it can be used as a plain HTTP check
+func NewHealthSummaryHandler(client k8sclient.Reader, namespace string, log l.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		installation, err := getInstallation(req, client, namespace)
+		if err != nil {
+			log.Warningf("Failed to get the installation for the health summary", l.Fields{"error": err})
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
+		summary := GetHealthSummary(installation)
+		w.Header().Set("Content-Type", "application/json")
+		if !summary.Healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if err := json.NewEncoder(w).Encode(summary); err != nil {
+			log.Warningf("Failed to write the health summary", l.Fields{"error": err})
+		}
+	})
+}
+
+// GetHealthSummary returns the health of the installation and of the products of its install stage
+func GetHealthSummary(installation *v1alpha1.RHMI) HealthSummary {
+	summary := HealthSummary{
+		Name:       installation.Name,
+		Version:    installation.Status.Version,
+		Stage:      installation.Status.Stage,
+		Healthy:    installation.IsCoreComponentsHealthy(),
+		Degraded:   installation.IsDegraded(),
+		Products:   map[v1alpha1.ProductName]Health{},
+		Conditions: installation.Status.Conditions,
+	}
+	for name, product := range installation.GetInstallStage().Products {
+		summary.Products[name] = Health{
+			Version: product.Version,
+			Phase:   product.Phase,
+			Healthy: product.Phase == v1alpha1.PhaseCompleted,
+		}
+	}
+	return summary
+}
+
+func getInstallation(req *http.Request, client k8sclient.Reader, namespace string) (*v1alpha1.RHMI, error) {
+	installations := &v1alpha1.RHMIList{}
+	if err := client.List(req.Context(), installations, k8sclient.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list the installations: %w", err)
+	}
+	if len(installations.Items) != 1 {
+		return nil, fmt.Errorf("expected one installation in %s, found %d", namespace, len(installations.Items))
+	}
+	return &installations.Items[0], nil
+}
@@ -0,0 +1,90 @@
+package status
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/logger"
+	"github.com/integr8ly/integreatly-operator/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestHealthSummaryHandler(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	installation := func(threeScalePhase v1alpha1.StatusPhase) *v1alpha1.RHMI {
+		return &v1alpha1.RHMI{
+			ObjectMeta: metav1.ObjectMeta{Name: "rhoam", Namespace: defaultTestNamespace},
+			Status: v1alpha1.RHMIStatus{
+				Version: "1.0.0",
+				Stage:   v1alpha1.CompleteStage,
+				Stages: map[v1alpha1.StageName]v1alpha1.RHMIStageStatus{
+					v1alpha1.InstallStage: {
+						Name: v1alpha1.InstallStage,
+						Products: map[v1alpha1.ProductName]v1alpha1.RHMIProductStatus{
+							v1alpha1.ProductCloudResources: {Phase: v1alpha1.PhaseCompleted},
+							v1alpha1.ProductRHSSOUser:      {Phase: v1alpha1.PhaseCompleted},
+							v1alpha1.Product3Scale:         {Phase: threeScalePhase, Version: "2.15"},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		objects        []runtime.Object
+		wantStatusCode int
+		wantHealthy    bool
+	}{
+		{
+			name:           "test healthy installation",
+			objects:        []runtime.Object{installation(v1alpha1.PhaseCompleted)},
+			wantStatusCode: http.StatusOK,
+			wantHealthy:    true,
+		},
+		{
+			name:           "test unhealthy product",
+			objects:        []runtime.Object{installation(v1alpha1.PhaseFailed)},
+			wantStatusCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "test no installation",
+			wantStatusCode: http.StatusServiceUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHealthSummaryHandler(utils.NewTestClient(scheme, tt.objects...), defaultTestNamespace, logger.NewLogger())
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, HealthSummaryPath, nil))
+
+			if recorder.Code != tt.wantStatusCode {
+				t.Fatalf("expected status code %d, got %d: %s", tt.wantStatusCode, recorder.Code, recorder.Body.String())
+			}
+			if len(tt.objects) == 0 {
+				return
+			}
+
+			summary := HealthSummary{}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &summary); err != nil {
+				t.Fatalf("expected a JSON summary: %v", err)
+			}
+			if summary.Healthy != tt.wantHealthy {
+				t.Errorf("expected healthy to be %v, got %+v", tt.wantHealthy, summary)
+			}
+			threeScale := summary.Products[v1alpha1.Product3Scale]
+			if threeScale.Healthy != tt.wantHealthy || threeScale.Version != "2.15" {
+				t.Errorf("expected the health of 3scale, got %+v", threeScale)
+			}
+		})
+	}
+}
@@ -205,7 +205,7 @@ func (r *StatusReconciler) appendMonitoringStackConditions(monitoringStack *obov
 
 			// Append the condition to the conditions list.
 			availableCondition := metav1.Condition{
-				Type:               "MonitoringStackAvailable",
+				Type:               monitoringStackAvailableConditionType,
 				Status:             metav1.ConditionStatus(cond.Status),
 				ObservedGeneration: cond.ObservedGeneration,
 				LastTransitionTime: cond.LastTransitionTime,
@@ -16,6 +16,8 @@ import (
 
 	"github.com/integr8ly/integreatly-operator/internal/controller/subscription/csvlocator"
 	"github.com/integr8ly/integreatly-operator/internal/controller/subscription/rhmiConfigs"
+	"github.com/integr8ly/integreatly-operator/internal/controller/subscription/upgradegates"
+	"github.com/integr8ly/integreatly-operator/pkg/metrics"
 
 	"k8s.io/apimachinery/pkg/util/wait"
 
@@ -25,7 +27,9 @@ import (
 
 	pkgerr "github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -80,6 +84,11 @@ func New(mgr manager.Manager) (*SubscriptionReconciler, error) {
 		),
 	))
 
+	alertsClient, err := metrics.GetOboPrometheusApiClient(operatorNs)
+	if err != nil {
+		return nil, err
+	}
+
 	return &SubscriptionReconciler{
 		mgr:                 mgr,
 		Client:              client,
@@ -87,6 +96,7 @@ func New(mgr manager.Manager) (*SubscriptionReconciler, error) {
 		operatorNamespace:   operatorNs,
 		catalogSourceClient: catalogSourceClient,
 		csvLocator:          csvLocator,
+		alertsClient:        alertsClient,
 	}, nil
 }
 
@@ -98,6 +108,7 @@ type SubscriptionReconciler struct {
 	mgr                 manager.Manager
 	catalogSourceClient catalogsourceClient.CatalogSourceClientInterface
 	csvLocator          csvlocator.CSVLocator
+	alertsClient        upgradegates.AlertsClient
 }
 
 // +kubebuilder:rbac:groups=operators.coreos.com,resources=subscriptions;subscriptions/status,verbs=get;list;watch;update;patch;delete,namespace=integreatly-operator
@@ -253,12 +264,32 @@ func (r *SubscriptionReconciler) HandleUpgrades(ctx context.Context, rhmiSubscri
 
 	if !isServiceAffecting && !latestInstallPlan.Spec.Approved {
 		eventRecorder := r.mgr.GetEventRecorderFor("Operator Upgrade")
+
+		gatesPassed, err := r.checkUpgradeGates(ctx, installation, eventRecorder)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !gatesPassed {
+			log.Info("upgrade gates not passed, requeue-ing")
+			return ctrl.Result{
+				Requeue:      true,
+				RequeueAfter: time.Minute,
+			}, nil
+		}
+
 		logrus.Infof("Approving install plan %s ", latestInstallPlan.Name)
 		err = rhmiConfigs.ApproveUpgrade(ctx, r.Client, installation, latestInstallPlan, eventRecorder)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
 
+		// Start the history entry on approval so the upgrade duration includes the CSV rollout.
+		// The installation controller picks up the same entry once ToVersion is set
+		installation.StartHistoryEntry(integreatlyv1alpha1.HistoryEntryUpgrade, installation.Status.Version, latestCSV.Spec.Version.String(), metav1.Now())
+		if err := r.Client.Status().Update(ctx, installation); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to record upgrade history: %w", err)
+		}
+
 		// Requeue the reconciler until the operator subscription upgrade is complete
 		return ctrl.Result{
 			Requeue:      true,
@@ -272,6 +303,32 @@ func (r *SubscriptionReconciler) HandleUpgrades(ctx context.Context, rhmiSubscri
 	}, nil
 }
 
+// checkUpgradeGates evaluates the configured pre-approval upgrade gates and records
+// their results as events and conditions on the installation
+func (r *SubscriptionReconciler) checkUpgradeGates(ctx context.Context, installation *integreatlyv1alpha1.RHMI, eventRecorder record.EventRecorder) (bool, error) {
+	cfg, err := upgradegates.GetConfig(ctx, r.Client, installation.Namespace)
+	if err != nil {
+		return false, err
+	}
+
+	gates, err := upgradegates.NewGates(cfg, r.alertsClient)
+	if err != nil {
+		return false, err
+	}
+
+	results := upgradegates.Evaluate(ctx, r.Client, installation, gates)
+	failedGates := upgradegates.RecordResults(installation, results, eventRecorder)
+	if len(failedGates) > 0 {
+		log.Infof("Upgrade gates failed", l.Fields{"gates": failedGates})
+	}
+
+	if err := r.Client.Status().Update(ctx, installation); err != nil {
+		return false, fmt.Errorf("failed to update upgrade gate conditions: %w", err)
+	}
+
+	return len(failedGates) == 0, nil
+}
+
 func (r *SubscriptionReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&operatorsv1alpha1.Subscription{}).
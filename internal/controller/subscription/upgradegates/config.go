@@ -0,0 +1,83 @@
+package upgradegates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ConfigMapName is the name of the ConfigMap in the operator namespace used to configure the upgrade gates
+	ConfigMapName = "upgrade-gates"
+	// ConfigMapKey is the ConfigMap data key containing the JSON gates configuration
+	ConfigMapKey = "gates"
+
+	defaultAlertSeverity = "critical"
+	defaultBackupMaxAge  = 24 * time.Hour
+)
+
+// GateConfig holds the settings of a single gate. Severity only applies to
+// the alerts gate and MaxAge only applies to the backup gate
+type GateConfig struct {
+	Enabled  bool   `json:"enabled"`
+	Severity string `json:"severity,omitempty"`
+	MaxAge   string `json:"maxAge,omitempty"`
+}
+
+// Config maps gate names to their settings
+type Config map[string]GateConfig
+
+// DefaultConfig is used for every gate that is not present in the upgrade-gates ConfigMap
+func DefaultConfig() Config {
+	return Config{
+		StagesCompletedGate: {Enabled: true},
+		NoAlertsFiringGate:  {Enabled: true, Severity: defaultAlertSeverity},
+		BackupFreshGate:     {Enabled: true, MaxAge: defaultBackupMaxAge.String()},
+		HealthyGate:         {Enabled: true},
+	}
+}
+
+// GetConfig reads the gates configuration from the upgrade-gates ConfigMap,
+// falling back to DefaultConfig for any gate not configured
+func GetConfig(ctx context.Context, client k8sclient.Client, namespace string) (Config, error) {
+	cfg := DefaultConfig()
+
+	configMap := &corev1.ConfigMap{}
+	if err := client.Get(ctx, k8sclient.ObjectKey{Name: ConfigMapName, Namespace: namespace}, configMap); err != nil {
+		if k8serr.IsNotFound(err) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("failed to get %s ConfigMap: %w", ConfigMapName, err)
+	}
+
+	configJSON, ok := configMap.Data[ConfigMapKey]
+	if !ok {
+		return cfg, nil
+	}
+
+	overrides := Config{}
+	if err := json.Unmarshal([]byte(configJSON), &overrides); err != nil {
+		return nil, fmt.Errorf("failed to parse %s in %s ConfigMap: %w", ConfigMapKey, ConfigMapName, err)
+	}
+
+	for name, gateConfig := range overrides {
+		defaults, ok := cfg[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown upgrade gate %s in %s ConfigMap", name, ConfigMapName)
+		}
+		if gateConfig.Severity == "" {
+			gateConfig.Severity = defaults.Severity
+		}
+		if gateConfig.MaxAge == "" {
+			gateConfig.MaxAge = defaults.MaxAge
+		}
+		cfg[name] = gateConfig
+	}
+
+	return cfg, nil
+}
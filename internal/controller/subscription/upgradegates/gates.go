@@ -0,0 +1,251 @@
+package upgradegates
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/products/marin3r"
+	"github.com/integr8ly/integreatly-operator/pkg/products/rhsso"
+	"github.com/integr8ly/integreatly-operator/pkg/products/rhssouser"
+	"github.com/integr8ly/integreatly-operator/pkg/products/threescale"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/backup"
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	StagesCompletedGate = "StagesCompleted"
+	NoAlertsFiringGate  = "NoAlertsFiring"
+	BackupFreshGate     = "BackupFresh"
+	HealthyGate         = "Healthy"
+)
+
+// Gate is a check that must pass before the RHOAM InstallPlan is approved
+type Gate interface {
+	Name() string
+	Check(ctx context.Context, client k8sclient.Client, installation *integreatlyv1alpha1.RHMI) (passed bool, message string, err error)
+}
+
+// AlertsClient is the subset of the prometheus API used by the alerts gate
+type AlertsClient interface {
+	Alerts(ctx context.Context) (prometheusv1.AlertsResult, error)
+}
+
+// Result is the outcome of a single gate
+type Result struct {
+	Gate    string
+	Passed  bool
+	Message string
+}
+
+// NewGates builds the enabled gates from the configuration
+func NewGates(cfg Config, alertsClient AlertsClient) ([]Gate, error) {
+	var gates []Gate
+
+	if cfg[StagesCompletedGate].Enabled {
+		gates = append(gates, &stagesCompletedGate{})
+	}
+	if gateConfig := cfg[NoAlertsFiringGate]; gateConfig.Enabled {
+		gates = append(gates, &noAlertsFiringGate{client: alertsClient, severity: gateConfig.Severity})
+	}
+	if gateConfig := cfg[BackupFreshGate]; gateConfig.Enabled {
+		maxAge, err := time.ParseDuration(gateConfig.MaxAge)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maxAge for upgrade gate %s: %w", BackupFreshGate, err)
+		}
+		gates = append(gates, &backupFreshGate{maxAge: maxAge})
+	}
+	if cfg[HealthyGate].Enabled {
+		gates = append(gates, &healthyGate{})
+	}
+
+	return gates, nil
+}
+
+// Evaluate runs every gate. A gate that can't be evaluated is reported as failed
+func Evaluate(ctx context.Context, client k8sclient.Client, installation *integreatlyv1alpha1.RHMI, gates []Gate) []Result {
+	results := make([]Result, 0, len(gates))
+	for _, gate := range gates {
+		passed, message, err := gate.Check(ctx, client, installation)
+		if err != nil {
+			passed = false
+			message = fmt.Sprintf("failed to evaluate gate: %v", err)
+		}
+		results = append(results, Result{Gate: gate.Name(), Passed: passed, Message: message})
+	}
+	return results
+}
+
+// RecordResults sets a condition per gate plus an overall condition on the installation status
+// and emits an event for every gate. It returns the names of the gates that failed
+func RecordResults(installation *integreatlyv1alpha1.RHMI, results []Result, recorder record.EventRecorder) []string {
+	var failed []string
+	for _, result := range results {
+		meta.SetStatusCondition(&installation.Status.Conditions, installation.UpgradeGateCondition(result.Gate, result.Passed, result.Message))
+		if result.Passed {
+			recorder.Eventf(installation, "Normal", integreatlyv1alpha1.EventUpgradeGatePassed, "Upgrade gate %s passed: %s", result.Gate, result.Message)
+			continue
+		}
+		failed = append(failed, result.Gate)
+		recorder.Eventf(installation, "Warning", integreatlyv1alpha1.EventUpgradeGateFailed, "Upgrade gate %s failed: %s", result.Gate, result.Message)
+	}
+
+	if len(failed) == 0 {
+		meta.SetStatusCondition(&installation.Status.Conditions, installation.UpgradeGatesPassedCondition())
+	} else {
+		meta.SetStatusCondition(&installation.Status.Conditions, installation.UpgradeGatesBlockedCondition(failed))
+	}
+
+	return failed
+}
+
+type stagesCompletedGate struct{}
+
+func (g *stagesCompletedGate) Name() string { return StagesCompletedGate }
+
+func (g *stagesCompletedGate) Check(_ context.Context, _ k8sclient.Client, installation *integreatlyv1alpha1.RHMI) (bool, string, error) {
+	var incomplete []string
+	for stageName, stage := range installation.Status.Stages {
+		if stage.Phase != integreatlyv1alpha1.PhaseCompleted {
+			incomplete = append(incomplete, string(stageName))
+		}
+		for productName, product := range stage.Products {
+			if product.Phase != integreatlyv1alpha1.PhaseCompleted {
+				incomplete = append(incomplete, fmt.Sprintf("%s/%s", stageName, productName))
+			}
+		}
+	}
+
+	if len(incomplete) > 0 {
+		return false, fmt.Sprintf("stages or products not completed: %s", strings.Join(incomplete, ", ")), nil
+	}
+	return true, "all stages and products completed", nil
+}
+
+type noAlertsFiringGate struct {
+	client   AlertsClient
+	severity string
+}
+
+func (g *noAlertsFiringGate) Name() string { return NoAlertsFiringGate }
+
+func (g *noAlertsFiringGate) Check(ctx context.Context, _ k8sclient.Client, _ *integreatlyv1alpha1.RHMI) (bool, string, error) {
+	if g.client == nil {
+		return false, "", fmt.Errorf("no prometheus client available")
+	}
+
+	alerts, err := g.client.Alerts(ctx)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to get alerts from prometheus: %w", err)
+	}
+
+	var firing []string
+	for _, alert := range alerts.Alerts {
+		if alert.State != prometheusv1.AlertStateFiring {
+			continue
+		}
+		if string(alert.Labels[model.LabelName("severity")]) != g.severity {
+			continue
+		}
+		firing = append(firing, string(alert.Labels[model.AlertNameLabel]))
+	}
+
+	if len(firing) > 0 {
+		return false, fmt.Sprintf("%s alerts firing: %s", g.severity, strings.Join(firing, ", ")), nil
+	}
+	return true, fmt.Sprintf("no %s alerts firing", g.severity), nil
+}
+
+type backupFreshGate struct {
+	maxAge time.Duration
+}
+
+func (g *backupFreshGate) Name() string { return BackupFreshGate }
+
+// Check starts the pre-upgrade backups of the products whose last backup is older than maxAge, the same backups
+// the products take before their own operators are upgraded. The gate doesn't wait for them, it passes once they
+// completed on a later check
+func (g *backupFreshGate) Check(ctx context.Context, client k8sclient.Client, installation *integreatlyv1alpha1.RHMI) (bool, string, error) {
+	executors := preUpgradeBackupExecutors(installation)
+	productNames := make([]integreatlyv1alpha1.ProductName, 0, len(executors))
+	for productName := range executors {
+		productNames = append(productNames, productName)
+	}
+	sort.Slice(productNames, func(i, j int) bool { return productNames[i] < productNames[j] })
+
+	var inProgress, failed []string
+	cutOff := time.Now().Add(-g.maxAge)
+	for _, productName := range productNames {
+		executor := executors[productName]
+		stale, err := backup.GetStaleResources(ctx, client, executor, cutOff)
+		if err != nil {
+			return false, "", fmt.Errorf("failed to check the %s backups: %w", productName, err)
+		}
+		if len(stale) == 0 {
+			continue
+		}
+		done, err := backup.StartBackup(ctx, client, executor, cutOff)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%v)", productName, err))
+			continue
+		}
+		if !done {
+			inProgress = append(inProgress, fmt.Sprintf("%s (%s)", productName, strings.Join(stale, ", ")))
+		}
+	}
+
+	if len(failed) > 0 {
+		return false, fmt.Sprintf("no backup newer than %s and the pre-upgrade backup failed for: %s", g.maxAge, strings.Join(failed, ", ")), nil
+	}
+	if len(inProgress) > 0 {
+		return false, fmt.Sprintf("no backup newer than %s, pre-upgrade backups in progress for: %s", g.maxAge, strings.Join(inProgress, ", ")), nil
+	}
+	return true, fmt.Sprintf("pre-upgrade backups newer than %s found for all products", g.maxAge), nil
+}
+
+// preUpgradeBackupExecutors returns the executors of the pre-upgrade backups of the installed products
+func preUpgradeBackupExecutors(installation *integreatlyv1alpha1.RHMI) map[integreatlyv1alpha1.ProductName]backup.BackupExecutor {
+	executors := map[integreatlyv1alpha1.ProductName]backup.BackupExecutor{}
+	for _, stage := range installation.Status.Stages {
+		for productName := range stage.Products {
+			if installation.IsProductDisabled(productName) {
+				continue
+			}
+			switch productName {
+			case integreatlyv1alpha1.Product3Scale:
+				executors[productName] = threescale.PreUpgradeBackupExecutor(installation)
+			case integreatlyv1alpha1.ProductMarin3r:
+				executors[productName] = marin3r.PreUpgradeBackupExecutor(installation)
+			case integreatlyv1alpha1.ProductRHSSO:
+				executors[productName] = rhsso.PreUpgradeBackupExecutor(installation)
+			case integreatlyv1alpha1.ProductRHSSOUser:
+				executors[productName] = rhssouser.PreUpgradeBackupExecutor(installation)
+			}
+		}
+	}
+	return executors
+}
+
+type healthyGate struct{}
+
+func (g *healthyGate) Name() string { return HealthyGate }
+
+func (g *healthyGate) Check(_ context.Context, _ k8sclient.Client, installation *integreatlyv1alpha1.RHMI) (bool, string, error) {
+	healthy := installation.IsCoreComponentsHealthy()
+	if condition := meta.FindStatusCondition(installation.Status.Conditions, integreatlyv1alpha1.HealthyConditionType.String()); condition != nil {
+		healthy = condition.Status == metav1.ConditionTrue
+	}
+
+	if !healthy {
+		return false, "core components are not healthy", nil
+	}
+	return true, "core components are healthy", nil
+}
@@ -0,0 +1,297 @@
+package upgradegates
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	crov1alpha1 "github.com/integr8ly/cloud-resource-operator/api/integreatly/v1alpha1"
+	croTypes "github.com/integr8ly/cloud-resource-operator/api/integreatly/v1alpha1/types"
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/utils"
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const defaultNamespace = "redhat-rhoam-operator"
+
+type alertsClientMock struct {
+	alerts []prometheusv1.Alert
+	err    error
+}
+
+func (m *alertsClientMock) Alerts(_ context.Context) (prometheusv1.AlertsResult, error) {
+	return prometheusv1.AlertsResult{Alerts: m.alerts}, m.err
+}
+
+func completedInstallation() *integreatlyv1alpha1.RHMI {
+	return &integreatlyv1alpha1.RHMI{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rhoam",
+			Namespace: defaultNamespace,
+		},
+		Spec: integreatlyv1alpha1.RHMISpec{
+			UseClusterStorage: "false",
+		},
+		Status: integreatlyv1alpha1.RHMIStatus{
+			Stages: map[integreatlyv1alpha1.StageName]integreatlyv1alpha1.RHMIStageStatus{
+				integreatlyv1alpha1.InstallStage: {
+					Name:  integreatlyv1alpha1.InstallStage,
+					Phase: integreatlyv1alpha1.PhaseCompleted,
+					Products: map[integreatlyv1alpha1.ProductName]integreatlyv1alpha1.RHMIProductStatus{
+						integreatlyv1alpha1.ProductCloudResources: {Name: integreatlyv1alpha1.ProductCloudResources, Phase: integreatlyv1alpha1.PhaseCompleted},
+						integreatlyv1alpha1.ProductRHSSOUser:      {Name: integreatlyv1alpha1.ProductRHSSOUser, Phase: integreatlyv1alpha1.PhaseCompleted},
+						integreatlyv1alpha1.Product3Scale:         {Name: integreatlyv1alpha1.Product3Scale, Phase: integreatlyv1alpha1.PhaseCompleted},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestGetConfig(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		client  k8sclient.Client
+		want    Config
+		wantErr bool
+	}{
+		{
+			name:   "defaults are used when the ConfigMap does not exist",
+			client: utils.NewTestClient(scheme),
+			want:   DefaultConfig(),
+		},
+		{
+			name: "gates are overridden from the ConfigMap",
+			client: utils.NewTestClient(scheme, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName, Namespace: defaultNamespace},
+				Data: map[string]string{
+					ConfigMapKey: `{"BackupFresh": {"enabled": true, "maxAge": "2h"}, "NoAlertsFiring": {"enabled": false}}`,
+				},
+			}),
+			want: Config{
+				StagesCompletedGate: {Enabled: true},
+				NoAlertsFiringGate:  {Enabled: false, Severity: defaultAlertSeverity},
+				BackupFreshGate:     {Enabled: true, MaxAge: "2h"},
+				HealthyGate:         {Enabled: true},
+			},
+		},
+		{
+			name: "unknown gates are rejected",
+			client: utils.NewTestClient(scheme, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName, Namespace: defaultNamespace},
+				Data: map[string]string{
+					ConfigMapKey: `{"Unknown": {"enabled": true}}`,
+				},
+			}),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetConfig(context.TODO(), tt.client, defaultNamespace)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			for name, gateConfig := range tt.want {
+				if got[name] != gateConfig {
+					t.Errorf("GetConfig() gate %s = %+v, want %+v", name, got[name], gateConfig)
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the pre-upgrade backups of the 3scale and user RHSSO cloud resources
+	postgresSnapshot := func(resourceName string, age time.Duration, phase croTypes.StatusPhase) *crov1alpha1.PostgresSnapshot {
+		return &crov1alpha1.PostgresSnapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              fmt.Sprintf("%s-preupgrade-snapshot-%s", resourceName, age),
+				Namespace:         defaultNamespace,
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+			},
+			Spec:   crov1alpha1.PostgresSnapshotSpec{ResourceName: resourceName},
+			Status: croTypes.ResourceTypeSnapshotStatus{Phase: phase},
+		}
+	}
+	redisSnapshot := func(resourceName string, age time.Duration) *crov1alpha1.RedisSnapshot {
+		return &crov1alpha1.RedisSnapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              fmt.Sprintf("%s-preupgrade-snapshot-%s", resourceName, age),
+				Namespace:         defaultNamespace,
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+			},
+			Spec:   crov1alpha1.RedisSnapshotSpec{ResourceName: resourceName},
+			Status: croTypes.ResourceTypeSnapshotStatus{Phase: croTypes.PhaseComplete},
+		}
+	}
+	backups := func(threeScalePostgresAge time.Duration, threeScalePostgresPhase croTypes.StatusPhase) []k8sclient.Object {
+		return []k8sclient.Object{
+			postgresSnapshot("threescale-postgres-rhmi", threeScalePostgresAge, threeScalePostgresPhase),
+			redisSnapshot("threescale-backend-redis-rhmi", time.Hour),
+			redisSnapshot("threescale-redis-rhmi", time.Hour),
+			postgresSnapshot("rhssouser-postgres-rhmi", time.Hour, croTypes.PhaseComplete),
+		}
+	}
+
+	allEnabled := Config{
+		StagesCompletedGate: {Enabled: true},
+		NoAlertsFiringGate:  {Enabled: true, Severity: "critical"},
+		BackupFreshGate:     {Enabled: true, MaxAge: "24h"},
+		HealthyGate:         {Enabled: true},
+	}
+
+	tests := []struct {
+		name         string
+		installation func() *integreatlyv1alpha1.RHMI
+		alerts       *alertsClientMock
+		objects      []k8sclient.Object
+		wantFailed   []string
+		// the pre-upgrade backups of the 3scale postgres after the check, only checked when the gate fails
+		wantPostgresBackups int
+	}{
+		{
+			name:         "all gates pass",
+			installation: completedInstallation,
+			alerts: &alertsClientMock{alerts: []prometheusv1.Alert{
+				{State: prometheusv1.AlertStateFiring, Labels: model.LabelSet{"alertname": "DeadMansSwitch", "severity": "none"}},
+				{State: prometheusv1.AlertStatePending, Labels: model.LabelSet{"alertname": "ThreeScaleApicastDown", "severity": "critical"}},
+			}},
+			objects: backups(time.Hour, croTypes.PhaseComplete),
+		},
+		{
+			name: "incomplete product fails the stages and healthy gates",
+			installation: func() *integreatlyv1alpha1.RHMI {
+				installation := completedInstallation()
+				stage := installation.Status.Stages[integreatlyv1alpha1.InstallStage]
+				stage.Products[integreatlyv1alpha1.Product3Scale] = integreatlyv1alpha1.RHMIProductStatus{Name: integreatlyv1alpha1.Product3Scale, Phase: integreatlyv1alpha1.PhaseInProgress}
+				return installation
+			},
+			alerts:     &alertsClientMock{},
+			objects:    backups(time.Hour, croTypes.PhaseComplete),
+			wantFailed: []string{StagesCompletedGate, HealthyGate},
+		},
+		{
+			name:         "critical alert firing fails the alerts gate",
+			installation: completedInstallation,
+			alerts: &alertsClientMock{alerts: []prometheusv1.Alert{
+				{State: prometheusv1.AlertStateFiring, Labels: model.LabelSet{"alertname": "ThreeScaleApicastDown", "severity": "critical"}},
+			}},
+			objects:    backups(time.Hour, croTypes.PhaseComplete),
+			wantFailed: []string{NoAlertsFiringGate},
+		},
+		{
+			name:         "prometheus errors fail the alerts gate",
+			installation: completedInstallation,
+			alerts:       &alertsClientMock{err: fmt.Errorf("connection refused")},
+			objects:      backups(time.Hour, croTypes.PhaseComplete),
+			wantFailed:   []string{NoAlertsFiringGate},
+		},
+		{
+			name:                "stale backup starts a pre-upgrade backup and fails the backup gate until it completes",
+			installation:        completedInstallation,
+			alerts:              &alertsClientMock{},
+			objects:             backups(48*time.Hour, croTypes.PhaseComplete),
+			wantFailed:          []string{BackupFreshGate},
+			wantPostgresBackups: 2,
+		},
+		{
+			name:                "failed backup is deleted and fails the backup gate until it is taken again",
+			installation:        completedInstallation,
+			alerts:              &alertsClientMock{},
+			objects:             backups(time.Hour, croTypes.PhaseFailed),
+			wantFailed:          []string{BackupFreshGate},
+			wantPostgresBackups: 0,
+		},
+		{
+			name: "backups of disabled products are not required",
+			installation: func() *integreatlyv1alpha1.RHMI {
+				installation := completedInstallation()
+				installation.Spec.DisabledProducts = []integreatlyv1alpha1.ProductName{integreatlyv1alpha1.ProductRHSSOUser}
+				return installation
+			},
+			alerts:  &alertsClientMock{},
+			objects: backups(time.Hour, croTypes.PhaseComplete)[:3],
+		},
+		{
+			name: "unhealthy condition fails the healthy gate",
+			installation: func() *integreatlyv1alpha1.RHMI {
+				installation := completedInstallation()
+				meta.SetStatusCondition(&installation.Status.Conditions, installation.UnHealthyCondition())
+				return installation
+			},
+			alerts:     &alertsClientMock{},
+			objects:    backups(time.Hour, croTypes.PhaseComplete),
+			wantFailed: []string{HealthyGate},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installation := tt.installation()
+			client := utils.NewTestClient(scheme)
+			for _, obj := range tt.objects {
+				if err := client.Create(context.TODO(), obj.DeepCopyObject().(k8sclient.Object)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			gates, err := NewGates(allEnabled, tt.alerts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			results := Evaluate(context.TODO(), client, installation, gates)
+			failed := RecordResults(installation, results, record.NewFakeRecorder(10))
+
+			if fmt.Sprint(failed) != fmt.Sprint(tt.wantFailed) {
+				t.Fatalf("expected failed gates %v, got %v", tt.wantFailed, failed)
+			}
+
+			if len(tt.wantFailed) == 1 && tt.wantFailed[0] == BackupFreshGate {
+				snapshots := &crov1alpha1.PostgresSnapshotList{}
+				if err := client.List(context.TODO(), snapshots, k8sclient.InNamespace(defaultNamespace)); err != nil {
+					t.Fatal(err)
+				}
+				threeScaleBackups := 0
+				for _, snapshot := range snapshots.Items {
+					if snapshot.Spec.ResourceName == "threescale-postgres-rhmi" {
+						threeScaleBackups++
+					}
+				}
+				if threeScaleBackups != tt.wantPostgresBackups {
+					t.Errorf("expected %d 3scale postgres backups, got %d", tt.wantPostgresBackups, threeScaleBackups)
+				}
+			}
+
+			overall := meta.FindStatusCondition(installation.Status.Conditions, integreatlyv1alpha1.UpgradeGatesConditionType.String())
+			if overall == nil {
+				t.Fatal("expected upgrade gates condition to be set")
+			}
+			if (overall.Status == metav1.ConditionTrue) != (len(tt.wantFailed) == 0) {
+				t.Fatalf("unexpected upgrade gates condition status %s", overall.Status)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"time"
 
@@ -22,6 +24,10 @@ import (
 // +kubebuilder:rbac:groups=user.openshift.io,resources=groups,resourceNames=rhmi-developers,verbs=update;delete
 // +kubebuilder:rbac:groups=user.openshift.io,resources=users,verbs=watch;get;list
 
+// managedMembersAnnotation records the members the operator added to the developers group, so that
+// only these are removed from it and the members added otherwise are kept
+const managedMembersAnnotation = "integreatly.org/managed-members"
+
 var log = l.NewLoggerWithContext(l.Fields{l.ControllerLogContext: "user_controller"})
 
 // UserReconciler reconciles a User object
@@ -54,7 +60,7 @@ func (r *UserReconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Re
 
 	rhmiGroup := &usersv1.Group{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: "rhmi-developers",
+			Name: userHelper.DevelopersGroupName,
 		},
 	}
 
@@ -71,10 +77,7 @@ func (r *UserReconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Re
 	}
 
 	or, err := controllerutil.CreateOrUpdate(ctx, r.Client, rhmiGroup, func() error {
-
-		rhmiGroup.Users = mapUserNames(users, groups)
-
-		return nil
+		return setManagedMembers(rhmiGroup, mapUserNames(users, groups))
 	})
 	log.Infof("Operation Result", l.Fields{"groupName": rhmiGroup.Name, "result": string(or)})
 
@@ -93,6 +96,31 @@ func mapUserNames(users *usersv1.UserList, groups *usersv1.GroupList) []string {
 	return result
 }
 
+// setManagedMembers adds the members to the group and removes the members the operator added before
+// that are no longer members
+func setManagedMembers(group *usersv1.Group, members []string) error {
+	previous := []string{}
+	if value, ok := group.Annotations[managedMembersAnnotation]; ok {
+		if err := json.Unmarshal([]byte(value), &previous); err != nil {
+			return fmt.Errorf("failed to parse %s annotation of group %s: %w", managedMembersAnnotation, group.Name, err)
+		}
+	} else {
+		// the members were all added by the operator before they were recorded
+		previous = group.Users
+	}
+
+	value, err := json.Marshal(members)
+	if err != nil {
+		return err
+	}
+	if group.Annotations == nil {
+		group.Annotations = map[string]string{}
+	}
+	group.Annotations[managedMembersAnnotation] = string(value)
+	group.Users = userHelper.MergeMapped(group.Users, previous, members)
+	return nil
+}
+
 func (r *UserReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&usersv1.User{}).
@@ -0,0 +1,271 @@
+// Package alertcatalogue builds every alert the operator creates for an installation type and quota
+// without a cluster. The alert reconcilers of the bootstrap stage and of each product are built from the
+// values the operator otherwise reads from the cluster and render their PrometheusRules, so that they can
+// be written to disk, diffed and tested with promtool
+package alertcatalogue
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	controllers "github.com/integr8ly/integreatly-operator/internal/controller/rhmi"
+	"github.com/integr8ly/integreatly-operator/pkg/addon"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/metrics"
+	"github.com/integr8ly/integreatly-operator/pkg/products"
+	"github.com/integr8ly/integreatly-operator/pkg/resources"
+	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
+	monv1 "github.com/rhobs/obo-prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// BootstrapProduct is the product the alerts created in the bootstrap stage are reported under
+	BootstrapProduct = "bootstrap"
+
+	// RulesDir is the directory the promtool rule files are written to
+	RulesDir = "rules"
+	// PrometheusRulesDir is the directory the PrometheusRule resources are written to
+	PrometheusRulesDir = "prometheusrules"
+	// SummaryFile is the name of the alert summary table
+	SummaryFile = "alerts.md"
+
+	defaultRoutingSubdomain = "apps.example.com"
+)
+
+var defaultNamespacePrefixes = map[string]string{
+	string(integreatlyv1alpha1.InstallationTypeManagedApi):            "redhat-rhoam-",
+	string(integreatlyv1alpha1.InstallationTypeMultitenantManagedApi): "sandbox-rhoam-",
+}
+
+// Options of the installation the alert catalogue is built for
+type Options struct {
+	InstallationType string
+	// Quota is the quota parameter, as set in the addon parameters
+	Quota string
+	// NamespacePrefix defaults to the prefix used by the installation type
+	NamespacePrefix string
+	// Runbooks is the runbook catalogue ConfigMap to render the sop_url annotations with, the default
+	// runbooks are used when it is not set
+	Runbooks *corev1.ConfigMap
+	// AlertOverrides is the alert overrides ConfigMap to render the alerts with
+	AlertOverrides *corev1.ConfigMap
+}
+
+// Alert is an entry of the alert summary
+type Alert struct {
+	Name     string
+	Severity string
+	Product  string
+	Rule     string
+	SOPURL   string
+}
+
+// Catalogue is the set of PrometheusRules created for an installation, with a summary of their alerts
+type Catalogue struct {
+	Rules  []monv1.PrometheusRule
+	Alerts []Alert
+}
+
+// Build builds the alert reconcilers of the bootstrap stage and of every product of the installation type
+// and collects the PrometheusRules they render
+func Build(ctx context.Context, opts Options, log l.Logger) (*Catalogue, error) {
+	installationType, err := controllers.TypeFactory(opts.InstallationType)
+	if err != nil {
+		return nil, err
+	}
+
+	namespacePrefix := opts.NamespacePrefix
+	if namespacePrefix == "" {
+		namespacePrefix = defaultNamespacePrefixes[opts.InstallationType]
+	}
+	installation := newInstallation(opts.InstallationType, namespacePrefix)
+
+	runbooks, err := resources.NewRunbookCatalogue(opts.Runbooks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load runbook catalogue: %w", err)
+	}
+	overrides, err := resources.NewAlertOverrides(opts.AlertOverrides)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load alert overrides: %w", err)
+	}
+	if invalid := overrides.Invalid(); len(invalid) > 0 {
+		return nil, fmt.Errorf("invalid alert overrides: %v", invalid)
+	}
+
+	installationQuota := &quota.Quota{}
+	quotaConfig := &corev1.ConfigMap{Data: map[string]string{quota.ConfigMapData: addon.GetQuotaConfig(opts.InstallationType)}}
+	if err := quota.GetQuota(ctx, nil, opts.Quota, quotaConfig, installationQuota); err != nil {
+		return nil, err
+	}
+	installation.Status.Quota = installationQuota.GetName()
+
+	configManager := config.NewOfflineManager(ctx, installation.Namespace, namespacePrefix+controllers.DefaultInstallationConfigMapName, installation)
+	inputs := resources.AlertInputs{
+		ContainerCPUMetric: metrics.ContainerCPUMetric,
+		GrafanaConsoleURL:  fmt.Sprintf("https://grafana-route-%scustomer-monitoring.%s", namespacePrefix, defaultRoutingSubdomain),
+	}
+
+	catalogue := &Catalogue{}
+	seen := map[string]bool{}
+
+	bootstrapReconcilers := controllers.BootstrapAlertReconcilers(log, installation, addon.DefaultSecretName)
+	if err := catalogue.collect(BootstrapProduct, bootstrapReconcilers, runbooks, overrides, seen); err != nil {
+		return nil, err
+	}
+
+	for _, product := range installationProducts(installationType) {
+		provider, err := products.NewAlertsProvider(product, configManager, installation, log)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s reconciler: %w", product, err)
+		}
+		alertReconcilers, err := provider.AlertReconcilers(inputs, installationQuota.GetProduct(product))
+		if err != nil {
+			return nil, fmt.Errorf("failed to build %s alerts: %w", product, err)
+		}
+		if err := catalogue.collect(string(product), alertReconcilers, runbooks, overrides, seen); err != nil {
+			return nil, err
+		}
+	}
+
+	return catalogue, nil
+}
+
+// collect adds the PrometheusRules rendered by the alert reconcilers to the catalogue. Rules already
+// rendered by another reconciler are skipped, as the operator creates them once
+func (c *Catalogue) collect(product string, alertReconcilers []*resources.AlertReconcilerImpl, runbooks *resources.RunbookCatalogue, overrides resources.AlertOverrides, seen map[string]bool) error {
+	for _, alertReconciler := range alertReconcilers {
+		rules, err := alertReconciler.PrometheusRules(runbooks, overrides)
+		if err != nil {
+			return fmt.Errorf("failed to render %s alerts: %w", product, err)
+		}
+		sort.Slice(rules, func(i, j int) bool {
+			return rules[i].Namespace+"/"+rules[i].Name < rules[j].Namespace+"/"+rules[j].Name
+		})
+
+		for _, rule := range rules {
+			key := rule.Namespace + "/" + rule.Name
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			c.Rules = append(c.Rules, rule)
+			for _, group := range rule.Spec.Groups {
+				for _, r := range group.Rules {
+					if r.Alert == "" {
+						continue
+					}
+					c.Alerts = append(c.Alerts, Alert{
+						Name:     r.Alert,
+						Severity: r.Labels["severity"],
+						Product:  product,
+						Rule:     rule.Name,
+						SOPURL:   r.Annotations[resources.SOPURLAnnotation],
+					})
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Write writes a promtool rule file and the PrometheusRule resource of each rule of the catalogue, and
+// the alert summary table, to dir
+func (c *Catalogue) Write(dir string) error {
+	for _, subDir := range []string{RulesDir, PrometheusRulesDir} {
+		if err := os.MkdirAll(filepath.Join(dir, subDir), 0750); err != nil {
+			return err
+		}
+	}
+
+	for _, rule := range c.Rules {
+		ruleFile, err := yaml.Marshal(rule.Spec)
+		if err != nil {
+			return fmt.Errorf("failed to marshal rule %s: %w", rule.Name, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, RulesDir, rule.Name+".yaml"), ruleFile, 0600); err != nil {
+			return err
+		}
+
+		rule.TypeMeta = metav1.TypeMeta{APIVersion: monv1.SchemeGroupVersion.String(), Kind: monv1.PrometheusRuleKind}
+		resource, err := yaml.Marshal(rule)
+		if err != nil {
+			return fmt.Errorf("failed to marshal rule %s: %w", rule.Name, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, PrometheusRulesDir, rule.Name+".yaml"), resource, 0600); err != nil {
+			return err
+		}
+	}
+
+	return os.WriteFile(filepath.Join(dir, SummaryFile), []byte(c.Summary()), 0600)
+}
+
+// Summary returns a markdown table of the alerts of the catalogue, sorted by product and alert name
+func (c *Catalogue) Summary() string {
+	alerts := make([]Alert, len(c.Alerts))
+	copy(alerts, c.Alerts)
+	sort.SliceStable(alerts, func(i, j int) bool {
+		if alerts[i].Product != alerts[j].Product {
+			return alerts[i].Product < alerts[j].Product
+		}
+		return alerts[i].Name < alerts[j].Name
+	})
+
+	summary := &strings.Builder{}
+	summary.WriteString("| Alert | Severity | Product | PrometheusRule | SOP URL |\n")
+	summary.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, alert := range alerts {
+		fmt.Fprintf(summary, "| %s | %s | %s | %s | %s |\n", alert.Name, alert.Severity, alert.Product, alert.Rule, alert.SOPURL)
+	}
+	return summary.String()
+}
+
+// MissingRunbooks returns the names of the alerts without a runbook
+func (c *Catalogue) MissingRunbooks() []string {
+	missing := []string{}
+	for _, alert := range c.Alerts {
+		if alert.SOPURL == "" {
+			missing = append(missing, alert.Name)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+func newInstallation(installationType, namespacePrefix string) *integreatlyv1alpha1.RHMI {
+	return &integreatlyv1alpha1.RHMI{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rhoam",
+			Namespace: namespacePrefix + "operator",
+		},
+		Spec: integreatlyv1alpha1.RHMISpec{
+			Type:              installationType,
+			NamespacePrefix:   namespacePrefix,
+			RoutingSubdomain:  defaultRoutingSubdomain,
+			UseClusterStorage: "false",
+		},
+	}
+}
+
+// installationProducts returns the products installed by the installation type in a stable order
+func installationProducts(installationType *controllers.Type) []integreatlyv1alpha1.ProductName {
+	productNames := []integreatlyv1alpha1.ProductName{}
+	for _, stage := range installationType.GetInstallStages() {
+		for product := range stage.Products {
+			productNames = append(productNames, product)
+		}
+	}
+	sort.Slice(productNames, func(i, j int) bool {
+		return productNames[i] < productNames[j]
+	})
+	return productNames
+}
@@ -0,0 +1,201 @@
+package alertcatalogue
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/resources"
+	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
+	monv1 "github.com/rhobs/obo-prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestBuild(t *testing.T) {
+	scenarios := []struct {
+		Name             string
+		Options          Options
+		WantErr          bool
+		WantAlerts       map[string]string
+		WantMissingAlert string
+	}{
+		{
+			Name:    "managed api alerts are built for the quota",
+			Options: Options{InstallationType: string(integreatlyv1alpha1.InstallationTypeManagedApi), Quota: "200"},
+			WantAlerts: map[string]string{
+				"SendgridSmtpSecretExists":                            BootstrapProduct,
+				"RHOAMThreeScaleApicastProductionServiceEndpointDown": string(integreatlyv1alpha1.Product3Scale),
+				"Marin3rDiscoveryServiceEndpointDown":                 string(integreatlyv1alpha1.ProductMarin3r),
+				"GrafanaServiceEndpointDown":                          string(integreatlyv1alpha1.ProductGrafana),
+			},
+		},
+		{
+			Name:             "multitenant alerts do not include user SSO",
+			Options:          Options{InstallationType: string(integreatlyv1alpha1.InstallationTypeMultitenantManagedApi), Quota: "1"},
+			WantAlerts:       map[string]string{"SendgridSmtpSecretExists": BootstrapProduct},
+			WantMissingAlert: "RHOAMUserRhssoKeycloakServiceEndpointDown",
+		},
+		{
+			Name:    "unknown quotas are rejected",
+			Options: Options{InstallationType: string(integreatlyv1alpha1.InstallationTypeManagedApi), Quota: "3"},
+			WantErr: true,
+		},
+		{
+			Name:    "unknown installation types are rejected",
+			Options: Options{InstallationType: "unknown", Quota: "200"},
+			WantErr: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			catalogue, err := Build(context.TODO(), scenario.Options, l.NewLogger())
+			if (err != nil) != scenario.WantErr {
+				t.Fatalf("unexpected error %v, wantErr %v", err, scenario.WantErr)
+			}
+			if scenario.WantErr {
+				return
+			}
+
+			products := map[string]string{}
+			for _, alert := range catalogue.Alerts {
+				products[alert.Name] = alert.Product
+			}
+			for alert, product := range scenario.WantAlerts {
+				if products[alert] != product {
+					t.Errorf("expected alert %s of product %s, got %q", alert, product, products[alert])
+				}
+			}
+			if _, ok := products[scenario.WantMissingAlert]; scenario.WantMissingAlert != "" && ok {
+				t.Errorf("unexpected alert %s", scenario.WantMissingAlert)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	catalogue, err := Build(context.TODO(), Options{InstallationType: string(integreatlyv1alpha1.InstallationTypeManagedApi), Quota: "200"}, l.NewLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err := catalogue.Write(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	ruleFiles, err := os.ReadDir(filepath.Join(dir, RulesDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ruleFiles) != len(catalogue.Rules) {
+		t.Fatalf("expected %d rule files, got %d", len(catalogue.Rules), len(ruleFiles))
+	}
+
+	ruleFile, err := os.ReadFile(filepath.Join(dir, RulesDir, catalogue.Rules[0].Name+".yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := monv1.PrometheusRuleSpec{}
+	if err := yaml.UnmarshalStrict(ruleFile, &spec); err != nil {
+		t.Fatalf("expected a promtool rule file, got %s: %v", ruleFile, err)
+	}
+	if len(spec.Groups) == 0 {
+		t.Fatalf("expected rule groups in %s", ruleFile)
+	}
+
+	resource, err := os.ReadFile(filepath.Join(dir, PrometheusRulesDir, catalogue.Rules[0].Name+".yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(resource), "kind: PrometheusRule") {
+		t.Errorf("expected a PrometheusRule resource, got %s", resource)
+	}
+
+	summary, err := os.ReadFile(filepath.Join(dir, SummaryFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(summary)), "\n")
+	if len(lines) != len(catalogue.Alerts)+2 {
+		t.Fatalf("expected a summary row per alert, got %d rows for %d alerts", len(lines)-2, len(catalogue.Alerts))
+	}
+	if !strings.HasPrefix(lines[2], "| ") || !strings.Contains(string(summary), "| SendgridSmtpSecretExists | critical | bootstrap | sendgrid-smtp-secret-exists-rule | ") {
+		t.Errorf("unexpected summary %s", summary)
+	}
+}
+
+func TestBuildRunbooks(t *testing.T) {
+	runbooks := &corev1.ConfigMap{
+		Data: map[string]string{
+			"baseURL":  "https://runbooks.example.com/rhoam",
+			"runbooks": `{"ThreeScaleUserCreationFailed": "users.md"}`,
+		},
+	}
+	catalogue, err := Build(context.TODO(), Options{InstallationType: string(integreatlyv1alpha1.InstallationTypeManagedApi), Quota: "200", Runbooks: runbooks}, l.NewLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, alert := range catalogue.Alerts {
+		if alert.SOPURL != "" && !strings.HasPrefix(alert.SOPURL, "https://runbooks.example.com/rhoam/") {
+			t.Errorf("expected the runbook of %s to be moved to the base URL, got %s", alert.Name, alert.SOPURL)
+		}
+	}
+	for _, alert := range catalogue.MissingRunbooks() {
+		if alert == "ThreeScaleUserCreationFailed" {
+			t.Errorf("expected %s to have a runbook", alert)
+		}
+		if alert == "SendgridSmtpSecretExists" {
+			t.Errorf("expected %s to keep its runbook", alert)
+		}
+	}
+}
+
+func TestAlertsHaveRunbooks(t *testing.T) {
+	quotas := map[string]string{
+		string(integreatlyv1alpha1.InstallationTypeManagedApi):            "200",
+		string(integreatlyv1alpha1.InstallationTypeMultitenantManagedApi): "1",
+	}
+	for installationType, quota := range quotas {
+		catalogue, err := Build(context.TODO(), Options{InstallationType: installationType, Quota: quota}, l.NewLogger())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if missing := catalogue.MissingRunbooks(); len(missing) > 0 {
+			t.Errorf("alerts of %s installations without a runbook, add a sop_url annotation to them: %v", installationType, missing)
+		}
+	}
+}
+
+func TestAlertThresholds(t *testing.T) {
+	overrides := &corev1.ConfigMap{
+		Data: map[string]string{"alerts": `{"PostgresCPUHigh": {"threshold": 70}, "ThreeScaleContainerHighMemory": {"threshold": 85}}`},
+	}
+	catalogue, err := Build(context.TODO(), Options{InstallationType: string(integreatlyv1alpha1.InstallationTypeManagedApi), Quota: "200", AlertOverrides: overrides}, l.NewLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exprs := map[string]string{}
+	for _, rule := range catalogue.Rules {
+		for _, group := range rule.Spec.Groups {
+			for _, alert := range group.Rules {
+				if strings.Contains(alert.Expr.StrVal, resources.ThresholdPlaceholder) {
+					t.Errorf("expected the threshold of %s to be declared, got %s", alert.Alert, alert.Expr.StrVal)
+				}
+				exprs[alert.Alert] = alert.Expr.StrVal
+			}
+		}
+	}
+	if !strings.HasSuffix(exprs["PostgresCPUHigh"], "> 70") || !strings.HasSuffix(exprs["ThreeScaleContainerHighMemory"], "> 85") {
+		t.Errorf("expected the thresholds to be overridden, got %s and %s", exprs["PostgresCPUHigh"], exprs["ThreeScaleContainerHighMemory"])
+	}
+	if !strings.HasSuffix(exprs["RedisCpuUsageHigh"], "> 80") {
+		t.Errorf("expected the declared threshold, got %s", exprs["RedisCpuUsageHigh"])
+	}
+}
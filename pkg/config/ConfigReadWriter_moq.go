@@ -51,7 +51,7 @@ var _ ConfigReadWriter = &ConfigReadWriterMock{}
 //			ReadThreeScaleFunc: func() (*ThreeScale, error) {
 //				panic("mock out the ReadThreeScale method")
 //			},
-//			WriteConfigFunc: func(config ConfigReadable) error {
+//			WriteConfigFunc: func(config ConfigReadable, writer string) error {
 //				panic("mock out the WriteConfig method")
 //			},
 //			readConfigForProductFunc: func(product integreatlyv1alpha1.ProductName) (ProductConfig, error) {
@@ -98,7 +98,7 @@ type ConfigReadWriterMock struct {
 	ReadThreeScaleFunc func() (*ThreeScale, error)
 
 	// WriteConfigFunc mocks the WriteConfig method.
-	WriteConfigFunc func(config ConfigReadable) error
+	WriteConfigFunc func(config ConfigReadable, writer string) error
 
 	// readConfigForProductFunc mocks the readConfigForProduct method.
 	readConfigForProductFunc func(product integreatlyv1alpha1.ProductName) (ProductConfig, error)
@@ -144,6 +144,8 @@ type ConfigReadWriterMock struct {
 		WriteConfig []struct {
 			// Config is the config argument value.
 			Config ConfigReadable
+			// Writer is the writer argument value.
+			Writer string
 		}
 		// readConfigForProduct holds details about calls to the readConfigForProduct method.
 		readConfigForProduct []struct {
@@ -469,19 +471,21 @@ func (mock *ConfigReadWriterMock) ReadThreeScaleCalls() []struct {
 }
 
 // WriteConfig calls WriteConfigFunc.
-func (mock *ConfigReadWriterMock) WriteConfig(config ConfigReadable) error {
+func (mock *ConfigReadWriterMock) WriteConfig(config ConfigReadable, writer string) error {
 	if mock.WriteConfigFunc == nil {
 		panic("ConfigReadWriterMock.WriteConfigFunc: method is nil but ConfigReadWriter.WriteConfig was just called")
 	}
 	callInfo := struct {
 		Config ConfigReadable
+		Writer string
 	}{
 		Config: config,
+		Writer: writer,
 	}
 	mock.lockWriteConfig.Lock()
 	mock.calls.WriteConfig = append(mock.calls.WriteConfig, callInfo)
 	mock.lockWriteConfig.Unlock()
-	return mock.WriteConfigFunc(config)
+	return mock.WriteConfigFunc(config, writer)
 }
 
 // WriteConfigCalls gets all the calls that were made to WriteConfig.
@@ -490,9 +494,11 @@ func (mock *ConfigReadWriterMock) WriteConfig(config ConfigReadable) error {
 //	len(mockedConfigReadWriter.WriteConfigCalls())
 func (mock *ConfigReadWriterMock) WriteConfigCalls() []struct {
 	Config ConfigReadable
+	Writer string
 } {
 	var calls []struct {
 		Config ConfigReadable
+		Writer string
 	}
 	mock.lockWriteConfig.RLock()
 	calls = mock.calls.WriteConfig
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 
@@ -14,6 +16,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -30,7 +33,19 @@ func NewManager(ctx context.Context, client k8sclient.Client, namespace string,
 	if !errors.IsNotFound(err) && err != nil {
 		return nil, err
 	}
-	return &Manager{Client: client, Namespace: namespace, cfgmap: cfgmap, context: ctx, installation: installation}, nil
+	return &Manager{Client: client, Namespace: namespace, cfgmap: cfgmap, context: ctx, installation: installation, readConfigs: map[integreatlyv1alpha1.ProductName]ProductConfig{}}, nil
+}
+
+// NewOfflineManager creates a manager that keeps the configuration in memory instead of a ConfigMap. It is used
+// to build the resources of the products without a cluster
+func NewOfflineManager(ctx context.Context, namespace string, configMapName string, installation *integreatlyv1alpha1.RHMI) *Manager {
+	cfgmap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      configMapName,
+		},
+	}
+	return &Manager{Namespace: namespace, cfgmap: cfgmap, context: ctx, installation: installation, readConfigs: map[integreatlyv1alpha1.ProductName]ProductConfig{}}
 }
 
 //go:generate moq -out ConfigReadWriter_moq.go . ConfigReadWriter
@@ -39,7 +54,7 @@ type ConfigReadWriter interface {
 	GetOauthClientsSecretName() string
 	GetGHOauthClientsSecretName() string
 	GetBackupsSecretName() string
-	WriteConfig(config ConfigReadable) error
+	WriteConfig(config ConfigReadable, writer string) error
 	ReadRHSSO() (*RHSSO, error)
 	ReadRHSSOUser() (*RHSSOUser, error)
 	ReadThreeScale() (*ThreeScale, error)
@@ -77,9 +92,13 @@ type ConfigReadable interface {
 }
 
 type Manager struct {
-	Client       k8sclient.Client
-	Namespace    string
-	cfgmap       *corev1.ConfigMap
+	Client    k8sclient.Client
+	Namespace string
+	// guards cfgmap and readConfigs, the reconcilers read and write the config from several goroutines
+	mu     sync.Mutex
+	cfgmap *corev1.ConfigMap
+	// the product configs as they were last read, the changes of a write are made against them
+	readConfigs  map[integreatlyv1alpha1.ProductName]ProductConfig
 	context      context.Context
 	installation *integreatlyv1alpha1.RHMI
 }
@@ -168,24 +187,163 @@ func (m *Manager) ReadMarin3r() (*Marin3r, error) {
 	return NewMarin3r(config), nil
 }
 
-func (m *Manager) WriteConfig(config ConfigReadable) error {
-	stringConfig, err := yaml.Marshal(config.Read())
+// WriteConfig writes the product config with optimistic concurrency, recording the writer as the
+// reconciler that changed its keys. When the ConfigMap changed since it was read, it is read again and
+// the keys changed by the writer are applied to the latest config. A key another writer changed as well
+// is set to the value of this writer, and the overwritten writer is recorded
+func (m *Manager) WriteConfig(config ConfigReadable, writer string) error {
+	product := config.GetProductName()
+	schema := getProductSchema(product)
+	desired := config.Read()
+	if err := schema.Fields.Validate(desired); err != nil {
+		return fmt.Errorf("invalid %s config: %w", product, err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.readConfigs == nil {
+		m.readConfigs = map[integreatlyv1alpha1.ProductName]ProductConfig{}
+	}
+	base, ok := m.readConfigs[product]
+	if !ok {
+		var err error
+		if base, err = m.decodeConfigForProduct(product); err != nil {
+			return err
+		}
+	}
+
+	return retry.OnError(retry.DefaultRetry, isWriteConflict, func() error {
+		latest, err := m.decodeConfigForProduct(product)
+		if err != nil {
+			return err
+		}
+		merged, conflicts := mergeProductConfig(base, desired, latest)
+		if err := m.writeProductConfig(product, latest, merged, conflicts, writer, schema.Version); err != nil {
+			return err
+		}
+		m.readConfigs[product] = merged
+		return nil
+	})
+}
+
+// MigrateSchemas migrates the product configs written with an older schema version to the current one
+func (m *Manager) MigrateSchemas() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	metadata, err := GetConfigMetadata(m.cfgmap)
+	if err != nil {
+		return err
+	}
+
+	for product, schema := range productSchemas {
+		version := metadata.schemaVersion(product)
+		if _, ok := m.cfgmap.Data[string(product)]; !ok || version >= schema.Version {
+			continue
+		}
+		if len(schema.Migrations) < schema.Version-1 {
+			return fmt.Errorf("missing migrations of the %s config to version %d", product, schema.Version)
+		}
+
+		err := retry.OnError(retry.DefaultRetry, isWriteConflict, func() error {
+			current, err := m.decodeConfigForProduct(product)
+			if err != nil {
+				return err
+			}
+			migrated := ProductConfig{}
+			for key, value := range current {
+				migrated[key] = value
+			}
+			for v := version; v < schema.Version; v++ {
+				migrated = schema.Migrations[v-1](migrated)
+			}
+			if err := schema.Fields.Validate(migrated); err != nil {
+				return err
+			}
+			return m.writeProductConfig(product, current, migrated, nil, SchemaMigrationWriter, schema.Version)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to migrate the %s config from version %d to %d: %w", product, version, schema.Version, err)
+		}
+	}
+	return nil
+}
+
+// ReadConfigMetadata returns the schema version of each product config and which reconciler last
+// changed each key and when
+func (m *Manager) ReadConfigMetadata() (ConfigMetadata, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return GetConfigMetadata(m.cfgmap)
+}
+
+// writeProductConfig writes the updated product config with the resourceVersion the ConfigMap was last
+// read with. On a conflict the ConfigMap is read again so that the write can be retried
+func (m *Manager) writeProductConfig(product integreatlyv1alpha1.ProductName, current, updated ProductConfig, conflicts []string, writer string, schemaVersion int) error {
+	stringConfig, err := yaml.Marshal(updated)
+	if err != nil {
+		return err
+	}
+	cfgmap := m.cfgmap.DeepCopy()
+	metadata, err := GetConfigMetadata(cfgmap)
 	if err != nil {
 		return err
 	}
-	err = m.Client.Get(m.context, k8sclient.ObjectKey{Name: m.cfgmap.Name, Namespace: m.Namespace}, m.cfgmap)
-	if errors.IsNotFound(err) {
-		m.cfgmap.Data = map[string]string{string(config.GetProductName()): string(stringConfig)}
-		return m.Client.Create(m.context, m.cfgmap)
+	metadata.recordChanges(product, current, updated, conflicts, writer, schemaVersion, time.Now().UTC())
+	if err := setConfigMetadata(cfgmap, metadata); err != nil {
+		return err
+	}
+	if cfgmap.Data == nil {
+		cfgmap.Data = map[string]string{}
 	}
-	if m.cfgmap.Data == nil {
-		m.cfgmap.Data = map[string]string{}
+	cfgmap.Data[string(product)] = string(stringConfig)
+
+	// offline managers have no client to write the ConfigMap with
+	if m.Client == nil {
+		m.cfgmap = cfgmap
+		return nil
+	}
+
+	if cfgmap.ResourceVersion == "" {
+		err = m.Client.Create(m.context, cfgmap)
+	} else {
+		err = m.Client.Update(m.context, cfgmap)
 	}
-	m.cfgmap.Data[string(config.GetProductName())] = string(stringConfig)
-	return m.Client.Update(m.context, m.cfgmap)
+	if isWriteConflict(err) {
+		latest := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: m.cfgmap.Name, Namespace: m.Namespace}}
+		if err := m.Client.Get(m.context, k8sclient.ObjectKey{Name: latest.Name, Namespace: latest.Namespace}, latest); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		m.cfgmap = latest
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	m.cfgmap = cfgmap
+	return nil
+}
+
+func isWriteConflict(err error) bool {
+	return errors.IsConflict(err) || errors.IsAlreadyExists(err)
 }
 
 func (m *Manager) readConfigForProduct(product integreatlyv1alpha1.ProductName) (ProductConfig, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	config, err := m.decodeConfigForProduct(product)
+	if err != nil {
+		return nil, err
+	}
+	if m.readConfigs == nil {
+		m.readConfigs = map[integreatlyv1alpha1.ProductName]ProductConfig{}
+	}
+	m.readConfigs[product] = ProductConfig{}
+	for key, value := range config {
+		m.readConfigs[product][key] = value
+	}
+	return config, nil
+}
+
+func (m *Manager) decodeConfigForProduct(product integreatlyv1alpha1.ProductName) (ProductConfig, error) {
 	config := m.cfgmap.Data[string(product)]
 	decoder := yaml.NewDecoder(strings.NewReader(config))
 	retConfig := ProductConfig{}
@@ -2,7 +2,10 @@ package config
 
 import (
 	"context"
+	"reflect"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/integr8ly/integreatly-operator/utils"
@@ -89,7 +92,7 @@ func TestWriteConfig(t *testing.T) {
 		if err != nil {
 			t.Fatalf("could not create manager %v", err)
 		}
-		if err = mgr.WriteConfig(test.toWrite); err != nil {
+		if err = mgr.WriteConfig(test.toWrite, "test"); err != nil {
 			t.Fatalf("could not write config %v", err)
 		}
 		readCfgMap := &corev1.ConfigMap{
@@ -116,6 +119,30 @@ func TestWriteConfig(t *testing.T) {
 	}
 }
 
+func TestOfflineManagerWriteConfig(t *testing.T) {
+	config := ProductConfig{"testKey": "testVal"}
+	mgr := NewOfflineManager(context.TODO(), mockNamespaceName, mockConfigMapName, &integreatlyv1alpha1.RHMI{})
+
+	if err := mgr.WriteConfig(&ConfigReadableMock{
+		GetProductNameFunc: func() integreatlyv1alpha1.ProductName {
+			return mockProductName
+		},
+		ReadFunc: func() ProductConfig {
+			return config
+		},
+	}, "test"); err != nil {
+		t.Fatalf("could not write config %v", err)
+	}
+
+	read, err := mgr.readConfigForProduct(mockProductName)
+	if err != nil {
+		t.Fatalf("could not read config %v", err)
+	}
+	if !reflect.DeepEqual(read, config) {
+		t.Fatalf("expected %v but got %v", config, read)
+	}
+}
+
 func TestReadConfigForProduct(t *testing.T) {
 	scheme, err := utils.NewTestScheme()
 	if err != nil {
@@ -167,3 +194,342 @@ func TestReadConfigForProduct(t *testing.T) {
 	}
 
 }
+
+func TestWriteConfigConcurrency(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configReadable := func(config ProductConfig) ConfigReadable {
+		return &ConfigReadableMock{
+			GetProductNameFunc: func() integreatlyv1alpha1.ProductName {
+				return mockProductName
+			},
+			ReadFunc: func() ProductConfig {
+				return config
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		first         ProductConfig
+		second        ProductConfig
+		expected      ProductConfig
+		wantOverwrote map[string]string
+	}{
+		{
+			name:     "changes to different keys are merged",
+			first:    ProductConfig{"shared": "value", "first": "1"},
+			second:   ProductConfig{"shared": "value", "second": "2"},
+			expected: ProductConfig{"shared": "value", "first": "1", "second": "2"},
+		},
+		{
+			name:     "the same change by both writers is not a conflict",
+			first:    ProductConfig{"shared": "changed"},
+			second:   ProductConfig{"shared": "changed"},
+			expected: ProductConfig{"shared": "changed"},
+		},
+		{
+			name:          "the latest of different changes to the same key is kept",
+			first:         ProductConfig{"shared": "first"},
+			second:        ProductConfig{"shared": "second"},
+			expected:      ProductConfig{"shared": "second"},
+			wantOverwrote: map[string]string{"shared": "first"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := utils.NewTestClient(scheme, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      mockConfigMapName,
+					Namespace: mockNamespaceName,
+				},
+				Data: map[string]string{
+					mockProductName: "shared: value",
+				},
+			})
+
+			// both managers read the config before either of them writes it
+			first, err := NewManager(context.TODO(), fakeClient, mockNamespaceName, mockConfigMapName, &integreatlyv1alpha1.RHMI{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			second, err := NewManager(context.TODO(), fakeClient, mockNamespaceName, mockConfigMapName, &integreatlyv1alpha1.RHMI{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := first.readConfigForProduct(mockProductName); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := second.readConfigForProduct(mockProductName); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := first.WriteConfig(configReadable(test.first), "first"); err != nil {
+				t.Fatalf("could not write config %v", err)
+			}
+			if err := second.WriteConfig(configReadable(test.second), "second"); err != nil {
+				t.Fatalf("could not write config %v", err)
+			}
+
+			mgr, err := NewManager(context.TODO(), fakeClient, mockNamespaceName, mockConfigMapName, &integreatlyv1alpha1.RHMI{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			config, err := mgr.readConfigForProduct(mockProductName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(config, test.expected) {
+				t.Fatalf("expected config %v, got %v", test.expected, config)
+			}
+
+			metadata, err := mgr.ReadConfigMetadata()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for key := range test.expected {
+				if change, ok := metadata[mockProductName].Changes[key]; key != "shared" && (!ok || change.ChangedBy == "" || change.ChangedAt.IsZero()) {
+					t.Errorf("expected the change of %s to be recorded, got %+v", key, metadata[mockProductName])
+				}
+			}
+			for key, writer := range test.wantOverwrote {
+				if change := metadata[mockProductName].Changes[key]; change.ChangedBy != "second" || change.Overwrote != writer {
+					t.Errorf("expected the change of %s by %s to be recorded as overwritten, got %+v", key, writer, change)
+				}
+			}
+		})
+	}
+}
+
+func TestMigrateSchemas(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defaultSchemas := productSchemas
+	defer func() {
+		productSchemas = defaultSchemas
+	}()
+	productSchemas = map[integreatlyv1alpha1.ProductName]ProductSchema{
+		mockProductName: {
+			Version: 3,
+			Migrations: []ProductMigration{
+				func(config ProductConfig) ProductConfig {
+					config["host"] = config["url"]
+					delete(config, "url")
+					return config
+				},
+				func(config ProductConfig) ProductConfig {
+					config["namespace"] = "prefix-" + config["namespace"]
+					return config
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		data     string
+		metadata string
+		expected ProductConfig
+	}{
+		{
+			name:     "unversioned configs are migrated from version 1",
+			data:     "url: https://example.com\nnamespace: mock",
+			expected: ProductConfig{"host": "https://example.com", "namespace": "prefix-mock"},
+		},
+		{
+			name:     "only the missing migrations are run",
+			data:     "host: https://example.com\nnamespace: mock",
+			metadata: `{"mock": {"schemaVersion": 2}}`,
+			expected: ProductConfig{"host": "https://example.com", "namespace": "prefix-mock"},
+		},
+		{
+			name:     "current configs are left unchanged",
+			data:     "host: https://example.com\nnamespace: prefix-mock",
+			metadata: `{"mock": {"schemaVersion": 3}}`,
+			expected: ProductConfig{"host": "https://example.com", "namespace": "prefix-mock"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfgmap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      mockConfigMapName,
+					Namespace: mockNamespaceName,
+				},
+				Data: map[string]string{
+					mockProductName: test.data,
+				},
+			}
+			if test.metadata != "" {
+				cfgmap.Annotations = map[string]string{ConfigMetadataAnnotation: test.metadata}
+			}
+			fakeClient := utils.NewTestClient(scheme, cfgmap)
+
+			mgr, err := NewManager(context.TODO(), fakeClient, mockNamespaceName, mockConfigMapName, &integreatlyv1alpha1.RHMI{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := mgr.MigrateSchemas(); err != nil {
+				t.Fatalf("could not migrate schemas %v", err)
+			}
+
+			mgr, err = NewManager(context.TODO(), fakeClient, mockNamespaceName, mockConfigMapName, &integreatlyv1alpha1.RHMI{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			config, err := mgr.readConfigForProduct(mockProductName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(config, test.expected) {
+				t.Fatalf("expected config %v, got %v", test.expected, config)
+			}
+			metadata, err := mgr.ReadConfigMetadata()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if metadata[mockProductName].SchemaVersion != 3 {
+				t.Fatalf("expected schema version 3, got %+v", metadata[mockProductName])
+			}
+		})
+	}
+}
+
+func TestWriteConfigValidation(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		config  ProductConfig
+		wantErr string
+	}{
+		{
+			name:   "configs with the keys of the schema are written",
+			config: ProductConfig{"NAMESPACE": "user-sso", "DEVELOPERS_GROUP_CONFIGURED": "true"},
+		},
+		{
+			name:    "keys the schema does not declare are rejected",
+			config:  ProductConfig{"NAMESPACE": "user-sso", "UNKNOWN": "value"},
+			wantErr: "unknown key UNKNOWN",
+		},
+		{
+			name:    "values of another type are rejected",
+			config:  ProductConfig{"DEVELOPERS_GROUP_CONFIGURED": "yes"},
+			wantErr: "must be a bool",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mgr, err := NewManager(context.TODO(), utils.NewTestClient(scheme), mockNamespaceName, mockConfigMapName, &integreatlyv1alpha1.RHMI{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = mgr.WriteConfig(NewRHSSOUser(test.config), "test")
+			if test.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)) {
+				t.Fatalf("expected error containing %q, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMigrateSchemasToTypedFields(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeClient := utils.NewTestClient(scheme, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mockConfigMapName,
+			Namespace: mockNamespaceName,
+		},
+		Data: map[string]string{
+			string(integreatlyv1alpha1.ProductRHSSOUser): "NAMESPACE: user-sso\nDEVELOPERS_GROUP_CONFIGURED: \"True\"\nREMOVED_KEY: value",
+		},
+	})
+
+	mgr, err := NewManager(context.TODO(), fakeClient, mockNamespaceName, mockConfigMapName, &integreatlyv1alpha1.RHMI{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.MigrateSchemas(); err != nil {
+		t.Fatalf("could not migrate schemas %v", err)
+	}
+
+	config, err := mgr.readConfigForProduct(integreatlyv1alpha1.ProductRHSSOUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := ProductConfig{"NAMESPACE": "user-sso", "DEVELOPERS_GROUP_CONFIGURED": "true"}
+	if !reflect.DeepEqual(config, expected) {
+		t.Fatalf("expected config %v, got %v", expected, config)
+	}
+
+	metadata, err := mgr.ReadConfigMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	status := metadata.Status()[integreatlyv1alpha1.ProductRHSSOUser]
+	if status.SchemaVersion != 2 {
+		t.Fatalf("expected schema version 2, got %+v", status)
+	}
+	keys := []string{}
+	for _, change := range status.Changes {
+		if change.ChangedBy != SchemaMigrationWriter {
+			t.Errorf("expected the change of %s to be recorded as a migration, got %+v", change.Key, change)
+		}
+		keys = append(keys, change.Key)
+	}
+	if strings.Join(keys, ",") != "DEVELOPERS_GROUP_CONFIGURED,REMOVED_KEY" {
+		t.Errorf("expected the migrated keys to be reported, got %v", status.Changes)
+	}
+}
+
+// TestManagerConcurrentReads tests that a manager shared by the workers of a reconcile can be read and written
+// from several goroutines. The race detector (go test -race) reports the unguarded accesses
+func TestManagerConcurrentReads(t *testing.T) {
+	mgr := NewOfflineManager(context.TODO(), mockNamespaceName, mockConfigMapName, &integreatlyv1alpha1.RHMI{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if _, err := mgr.ReadRHSSO(); err != nil {
+					t.Error(err)
+					return
+				}
+				if _, err := mgr.ReadThreeScale(); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+			if err := mgr.WriteConfig(&ConfigReadableMock{
+				GetProductNameFunc: func() integreatlyv1alpha1.ProductName {
+					return mockProductName
+				},
+				ReadFunc: func() ProductConfig {
+					return ProductConfig{"worker": strconv.Itoa(i)}
+				},
+			}, "test"); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -2,6 +2,8 @@ package config
 
 import (
 	"context"
+	"fmt"
+
 	obov1 "github.com/rhobs/observability-operator/pkg/apis/monitoring/v1alpha1"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -14,6 +16,14 @@ const (
 	OboNamespaceSuffix     = "-observability"
 	OboMonitoringStackName = "rhoam"
 
+	// Prometheus service created by the OBO monitoring stack
+	OboPrometheusServiceName = OboMonitoringStackName + "-prometheus"
+	OboPrometheusServicePort = 9090
+
+	// Alertmanager service created by the OBO monitoring stack
+	OboAlertmanagerServiceName = OboMonitoringStackName + "-alertmanager"
+	OboAlertmanagerServicePort = 9093
+
 	// Alertmanager configuration
 	AlertManagerConfigSecretName            = "alertmanager-rhoam"
 	AlertManagerConfigSecretFileName        = "alertmanager.yaml"
@@ -31,6 +41,16 @@ func GetOboNamespace(installationNamespace string) string {
 	return installationNamespace + OboNamespaceSuffix
 }
 
+// GetOboPrometheusURL returns the in-cluster URL of the prometheus API of the OBO monitoring stack
+func GetOboPrometheusURL(installationNamespace string) string {
+	return fmt.Sprintf("http://%s.%s.svc:%d", OboPrometheusServiceName, GetOboNamespace(installationNamespace), OboPrometheusServicePort)
+}
+
+// GetOboAlertmanagerURL returns the in-cluster URL of the alertmanager API of the OBO monitoring stack
+func GetOboAlertmanagerURL(installationNamespace string) string {
+	return fmt.Sprintf("http://%s.%s.svc:%d", OboAlertmanagerServiceName, GetOboNamespace(installationNamespace), OboAlertmanagerServicePort)
+}
+
 func GetOboLabelSelector() string {
 	return OboLabelSelector
 }
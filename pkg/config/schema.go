@@ -0,0 +1,302 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ConfigMetadataAnnotation records on the installation ConfigMap the schema version of each product
+	// configuration, and which reconciler last changed each key and when
+	ConfigMetadataAnnotation = "integreatly.org/config-metadata"
+
+	// SchemaMigrationWriter is recorded as the writer of the keys changed by a schema migration
+	SchemaMigrationWriter = "schema-migration"
+
+	// configurations written before the schemas were versioned are version 1
+	unversionedSchemaVersion = 1
+)
+
+// FieldType is the type of the values of a key of a product configuration
+type FieldType string
+
+const (
+	StringField FieldType = "string"
+	BoolField   FieldType = "bool"
+)
+
+// ProductFields maps the keys of a product configuration to the type of their values
+type ProductFields map[string]FieldType
+
+// ProductMigration migrates the configuration of a product to the next schema version
+type ProductMigration func(config ProductConfig) ProductConfig
+
+// ProductSchema is the schema version the configuration of a product is written with and the keys it
+// has. Migrations[i] migrates a configuration from version i+1 to version i+2, so there is one
+// migration less than the version
+type ProductSchema struct {
+	Version    int
+	Fields     ProductFields
+	Migrations []ProductMigration
+}
+
+var (
+	commonFields = ProductFields{
+		"HOST":               StringField,
+		"NAMESPACE":          StringField,
+		"OPERATOR_NAMESPACE": StringField,
+	}
+	threeScaleFields = commonFields.with(ProductFields{
+		"OPERATOR":                      StringField,
+		"VERSION":                       StringField,
+		"BLACKBOX_TARGET_PATH_ADMIN_UI": StringField,
+		"OPENAPI_NAMESPACES":            StringField,
+	})
+	rhssoFields = commonFields.with(ProductFields{
+		"OPERATOR": StringField,
+		"REALM":    StringField,
+		"VERSION":  StringField,
+	})
+	rhssoUserFields = rhssoFields.with(ProductFields{
+		"BLACKBOX_TARGET_PATH":        StringField,
+		"DEVELOPERS_GROUP_CONFIGURED": BoolField,
+	})
+	cloudResourcesFields = commonFields.with(ProductFields{
+		"STRATEGIES_CONFIG_MAP_NAME": StringField,
+	})
+	grafanaFields = ProductFields{
+		"HOST":      StringField,
+		"NAMESPACE": StringField,
+		"VERSION":   StringField,
+	}
+)
+
+// version 2 of the schemas types the keys, the configurations are conformed to them
+var productSchemas = map[integreatlyv1alpha1.ProductName]ProductSchema{
+	integreatlyv1alpha1.Product3Scale:         typedSchema(threeScaleFields),
+	integreatlyv1alpha1.ProductRHSSO:          typedSchema(rhssoFields),
+	integreatlyv1alpha1.ProductRHSSOUser:      typedSchema(rhssoUserFields),
+	integreatlyv1alpha1.ProductCloudResources: typedSchema(cloudResourcesFields),
+	integreatlyv1alpha1.ProductMarin3r:        typedSchema(commonFields),
+	integreatlyv1alpha1.ProductGrafana:        typedSchema(grafanaFields),
+}
+
+func typedSchema(fields ProductFields) ProductSchema {
+	return ProductSchema{
+		Version:    2,
+		Fields:     fields,
+		Migrations: []ProductMigration{fields.conform},
+	}
+}
+
+func getProductSchema(product integreatlyv1alpha1.ProductName) ProductSchema {
+	if schema, ok := productSchemas[product]; ok {
+		return schema
+	}
+	return ProductSchema{Version: unversionedSchemaVersion}
+}
+
+func (f ProductFields) with(fields ProductFields) ProductFields {
+	merged := ProductFields{}
+	for key, fieldType := range f {
+		merged[key] = fieldType
+	}
+	for key, fieldType := range fields {
+		merged[key] = fieldType
+	}
+	return merged
+}
+
+// Validate returns an error when the config has keys the fields don't declare or values that are not
+// of the type of their key. Configs of products without fields are not validated
+func (f ProductFields) Validate(config ProductConfig) error {
+	if f == nil {
+		return nil
+	}
+	for key, value := range config {
+		fieldType, ok := f[key]
+		if !ok {
+			return fmt.Errorf("unknown key %s", key)
+		}
+		if fieldType == BoolField {
+			if _, err := strconv.ParseBool(value); err != nil {
+				return fmt.Errorf("invalid value %q of key %s, must be a %s", value, key, fieldType)
+			}
+		}
+	}
+	return nil
+}
+
+// conform drops the keys the fields don't declare, which are no longer read, and writes the values of
+// the bool keys as true or false
+func (f ProductFields) conform(config ProductConfig) ProductConfig {
+	for key, value := range config {
+		fieldType, ok := f[key]
+		if !ok {
+			delete(config, key)
+			continue
+		}
+		if fieldType == BoolField {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				delete(config, key)
+				continue
+			}
+			config[key] = strconv.FormatBool(parsed)
+		}
+	}
+	return config
+}
+
+// KeyChange is the last change of a key of a product configuration
+type KeyChange struct {
+	ChangedBy string    `json:"changedBy"`
+	ChangedAt time.Time `json:"changedAt"`
+	Deleted   bool      `json:"deleted,omitempty"`
+	// Overwrote is the writer whose concurrent change to the key was overwritten by this change
+	Overwrote string `json:"overwrote,omitempty"`
+}
+
+// ProductConfigMetadata is the schema version of a product configuration and the last change of each
+// of its keys
+type ProductConfigMetadata struct {
+	SchemaVersion int                  `json:"schemaVersion"`
+	Changes       map[string]KeyChange `json:"changes,omitempty"`
+}
+
+// ConfigMetadata maps the products to the metadata of their configuration
+type ConfigMetadata map[integreatlyv1alpha1.ProductName]ProductConfigMetadata
+
+// GetConfigMetadata reads the metadata of the product configurations from the installation ConfigMap
+func GetConfigMetadata(cfgmap *corev1.ConfigMap) (ConfigMetadata, error) {
+	metadata := ConfigMetadata{}
+	value, ok := cfgmap.Annotations[ConfigMetadataAnnotation]
+	if !ok || value == "" {
+		return metadata, nil
+	}
+	if err := json.Unmarshal([]byte(value), &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse %s annotation: %w", ConfigMetadataAnnotation, err)
+	}
+	return metadata, nil
+}
+
+func setConfigMetadata(cfgmap *corev1.ConfigMap, metadata ConfigMetadata) error {
+	value, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s annotation: %w", ConfigMetadataAnnotation, err)
+	}
+	if cfgmap.Annotations == nil {
+		cfgmap.Annotations = map[string]string{}
+	}
+	cfgmap.Annotations[ConfigMetadataAnnotation] = string(value)
+	return nil
+}
+
+// schemaVersion returns the version the configuration of the product was written with
+func (m ConfigMetadata) schemaVersion(product integreatlyv1alpha1.ProductName) int {
+	if productMetadata, ok := m[product]; ok && productMetadata.SchemaVersion > 0 {
+		return productMetadata.SchemaVersion
+	}
+	return unversionedSchemaVersion
+}
+
+// recordChanges records the keys that differ between the current and the new configuration of the
+// product as changed by the writer, and the schema version the configuration is written with. The
+// conflicts are the keys another writer changed since the writer read the configuration
+func (m ConfigMetadata) recordChanges(product integreatlyv1alpha1.ProductName, current, updated ProductConfig, conflicts []string, writer string, schemaVersion int, now time.Time) {
+	productMetadata := m[product]
+	if productMetadata.Changes == nil {
+		productMetadata.Changes = map[string]KeyChange{}
+	}
+	productMetadata.SchemaVersion = schemaVersion
+
+	overwrote := map[string]string{}
+	for _, key := range conflicts {
+		overwrote[key] = productMetadata.Changes[key].ChangedBy
+	}
+	for key, value := range updated {
+		if currentValue, ok := current[key]; !ok || currentValue != value {
+			productMetadata.Changes[key] = KeyChange{ChangedBy: writer, ChangedAt: now, Overwrote: overwrote[key]}
+		}
+	}
+	for key := range current {
+		if _, ok := updated[key]; !ok {
+			productMetadata.Changes[key] = KeyChange{ChangedBy: writer, ChangedAt: now, Deleted: true, Overwrote: overwrote[key]}
+		}
+	}
+	m[product] = productMetadata
+}
+
+// Status returns the schema version of the configuration of each product and the last change of each
+// of its keys, as reported in the status of the installation
+func (m ConfigMetadata) Status() map[integreatlyv1alpha1.ProductName]integreatlyv1alpha1.RHMIProductConfigStatus {
+	if len(m) == 0 {
+		return nil
+	}
+	status := map[integreatlyv1alpha1.ProductName]integreatlyv1alpha1.RHMIProductConfigStatus{}
+	for product, productMetadata := range m {
+		keys := make([]string, 0, len(productMetadata.Changes))
+		for key := range productMetadata.Changes {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		productStatus := integreatlyv1alpha1.RHMIProductConfigStatus{SchemaVersion: productMetadata.SchemaVersion}
+		for _, key := range keys {
+			change := productMetadata.Changes[key]
+			productStatus.Changes = append(productStatus.Changes, integreatlyv1alpha1.RHMIConfigKeyChange{
+				Key:       key,
+				ChangedBy: change.ChangedBy,
+				ChangedAt: metav1.NewTime(change.ChangedAt),
+				Deleted:   change.Deleted,
+				Overwrote: change.Overwrote,
+			})
+		}
+		status[product] = productStatus
+	}
+	return status
+}
+
+// mergeProductConfig applies the keys the writer changed since it read the base configuration onto the
+// latest configuration. The conflicts are the keys another writer changed to a different value in the
+// meantime, the change of the writer is applied to them as it is the latest
+func mergeProductConfig(base, desired, latest ProductConfig) (ProductConfig, []string) {
+	merged := ProductConfig{}
+	for key, value := range latest {
+		merged[key] = value
+	}
+
+	keys := map[string]bool{}
+	for key := range base {
+		keys[key] = true
+	}
+	for key := range desired {
+		keys[key] = true
+	}
+	conflicts := []string{}
+	for key := range keys {
+		baseValue, inBase := base[key]
+		desiredValue, inDesired := desired[key]
+		if inBase == inDesired && baseValue == desiredValue {
+			continue
+		}
+		latestValue, inLatest := latest[key]
+		if (inLatest != inBase || latestValue != baseValue) && (inLatest != inDesired || latestValue != desiredValue) {
+			conflicts = append(conflicts, key)
+		}
+		if inDesired {
+			merged[key] = desiredValue
+		} else {
+			delete(merged, key)
+		}
+	}
+	sort.Strings(conflicts)
+	return merged, conflicts
+}
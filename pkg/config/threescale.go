@@ -2,6 +2,7 @@ package config
 
 import (
 	"errors"
+	"strings"
 
 	threescaleapps "github.com/3scale/3scale-operator/apis/apps"
 	threescalev1alpha1 "github.com/3scale/3scale-operator/apis/apps/v1alpha1"
@@ -52,6 +53,17 @@ func (t *ThreeScale) GetNamespace() string {
 	return t.config["NAMESPACE"]
 }
 
+// GetOpenAPINamespaces returns the namespaces OpenAPI documents are onboarded from, a comma separated list
+func (t *ThreeScale) GetOpenAPINamespaces() []string {
+	var namespaces []string
+	for _, namespace := range strings.Split(t.config["OPENAPI_NAMESPACES"], ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
+
 func (t *ThreeScale) GetOperatorNamespace() string {
 	return t.config["OPERATOR_NAMESPACE"]
 }
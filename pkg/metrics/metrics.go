@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
 	"github.com/integr8ly/integreatly-operator/pkg/resources"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/cluster"
 	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
@@ -166,6 +168,83 @@ var (
 		},
 	)
 
+	// RHOAMHistoryDuration exposes the duration of the upgrades, quota changes and uninstall attempts
+	// recorded in the RHMI status history. In progress entries report the time elapsed so far
+	RHOAMHistoryDuration = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rhoam_history_duration_seconds",
+			Help: "Duration in seconds of the RHOAM upgrades, quota changes and uninstall attempts recorded in the installation history",
+		},
+		[]string{
+			"type",    // "upgrade/quotaChange/uninstall"
+			"from",    // version or quota before the change
+			"to",      // version or quota after the change
+			"outcome", // "in progress/succeeded/superseded/failed"
+			"start",   // start time of the entry in unix seconds, tells apart the entries with the same change
+		},
+	)
+
+	// RHOAMHistoryStageDuration exposes the duration of each completed stage of the history entries
+	RHOAMHistoryStageDuration = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rhoam_history_stage_duration_seconds",
+			Help: "Duration in seconds of the stages of the RHOAM upgrades, quota changes and uninstall attempts recorded in the installation history",
+		},
+		[]string{
+			"type",
+			"to",
+			"stage",
+			"start",
+		},
+	)
+
+	// ThreeScaleTenantProvisioning tracks the progress of the multi tenant 3scale account provisioning
+	ThreeScaleTenantProvisioning = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "threescale_tenant_provisioning",
+			Help: "Number of 3scale tenant accounts per provisioning state",
+		},
+		[]string{
+			"state", // "pending/creating/ready/failed"
+		},
+	)
+
+	// RateLimitCounterRemaining and RateLimitCounterMaxValue expose the live limitador
+	// counters, so the usage of each tenant can be compared with its limit
+	RateLimitCounterRemaining = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rhoam_ratelimit_counter_remaining",
+			Help: "Requests remaining in the current window of a rate limit counter",
+		},
+		[]string{
+			"limit_namespace",
+			"descriptor",
+		},
+	)
+
+	RateLimitCounterMaxValue = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rhoam_ratelimit_counter_max_value",
+			Help: "Requests allowed per window of a rate limit counter",
+		},
+		[]string{
+			"limit_namespace",
+			"descriptor",
+		},
+	)
+
+	// TenantAuthorizedRequests is the number of requests of a tenant authorized by the rate limit service since
+	// its usage counter started. The counter starts again from zero once a day, so the value is read with increase()
+	TenantAuthorizedRequests = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rhoam_tenant_authorized_requests",
+			Help: "Requests of a tenant authorized by the rate limit service since its usage counter started",
+		},
+		[]string{
+			"tenant",
+		},
+	)
+
 	NoActivated3ScaleTenantAccount = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "no_activated_3scale_tenant_account",
@@ -176,6 +255,16 @@ var (
 		},
 	)
 
+	// STSCredentialsHealthy has no series until the credentials are checked on an STS cluster, where the cloud
+	// resources operator assumes a role, so that its alert doesn't fire on the other clusters
+	STSCredentialsHealthy = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rhoam_sts_credentials_healthy",
+			Help: "Measures if the STS role can be assumed with the web identity token",
+		},
+		[]string{},
+	)
+
 	InstallationControllerReconcileDelayed = prometheus.NewGauge(
 		prometheus.GaugeOpts{
 			Name: "installation_controller_reconcile_delayed",
@@ -251,6 +340,28 @@ func SetProductStatus(installation *integreatlyv1alpha1.RHMI) {
 	}
 }
 
+// SetHistory exposes the durations of the entries in the installation history
+func SetHistory(installation *integreatlyv1alpha1.RHMI) {
+	RHOAMHistoryDuration.Reset()
+	RHOAMHistoryStageDuration.Reset()
+
+	for _, entry := range installation.Status.History {
+		duration := entry.GetDuration().Duration
+		if entry.EndTime == nil {
+			duration = time.Since(entry.StartTime.Time)
+		}
+		start := strconv.FormatInt(entry.StartTime.Unix(), 10)
+		RHOAMHistoryDuration.WithLabelValues(string(entry.Type), entry.From, entry.To, string(entry.Outcome), start).Set(duration.Seconds())
+
+		for _, stage := range entry.Stages {
+			if stage.Duration == nil {
+				continue
+			}
+			RHOAMHistoryStageDuration.WithLabelValues(string(entry.Type), entry.To, string(stage.Name), start).Set(stage.Duration.Seconds())
+		}
+	}
+}
+
 func SetVersions(stage string, version string, toVersion string, externalID string, firstInstallTimestamp int64) {
 	RHOAMVersion.Reset()
 	status := resources.InstallationState(version, toVersion)
@@ -286,6 +397,31 @@ func SetTenantsSummary(tenants *integreatlyv1alpha1.APIManagementTenantList) {
 	}
 }
 
+func SetThreeScaleTenantProvisioning(states map[string]int) {
+	ThreeScaleTenantProvisioning.Reset()
+	for state, count := range states {
+		ThreeScaleTenantProvisioning.WithLabelValues(state).Set(float64(count))
+	}
+}
+
+func ResetRateLimitCounters() {
+	RateLimitCounterRemaining.Reset()
+	RateLimitCounterMaxValue.Reset()
+}
+
+func SetRateLimitCounter(limitNamespace, descriptor string, maxValue, remaining float64) {
+	RateLimitCounterRemaining.WithLabelValues(limitNamespace, descriptor).Set(remaining)
+	RateLimitCounterMaxValue.WithLabelValues(limitNamespace, descriptor).Set(maxValue)
+}
+
+func ResetTenantAuthorizedRequests() {
+	TenantAuthorizedRequests.Reset()
+}
+
+func SetTenantAuthorizedRequests(tenant string, requests float64) {
+	TenantAuthorizedRequests.WithLabelValues(tenant).Set(requests)
+}
+
 func ResetNoActivated3ScaleTenantAccount() {
 	NoActivated3ScaleTenantAccount.Reset()
 }
@@ -294,11 +430,31 @@ func SetNoActivated3ScaleTenantAccount(username string) {
 	NoActivated3ScaleTenantAccount.WithLabelValues(username).Set(float64(1))
 }
 
+func SetSTSCredentialsHealthy(healthy bool) {
+	if healthy {
+		STSCredentialsHealthy.WithLabelValues().Set(1)
+	} else {
+		STSCredentialsHealthy.WithLabelValues().Set(0)
+	}
+}
+
+// ResetSTSCredentialsHealthy removes the series of the STS credentials health, on clusters that aren't STS
+func ResetSTSCredentialsHealthy() {
+	STSCredentialsHealthy.Reset()
+}
+
 func SetQuota(quota string, toQuota string) {
 	Quota.Reset()
 	Quota.WithLabelValues(quota, toQuota).Set(float64(1))
 }
 
+const (
+	// ContainerCPUMetric is the recording rule of the container CPU usage
+	ContainerCPUMetric = "node_namespace_pod_container:container_cpu_usage_seconds_total:sum_irate"
+	// ContainerCPUMetricBefore49 is the recording rule of the container CPU usage before OpenShift 4.9
+	ContainerCPUMetricBefore49 = "node_namespace_pod_container:container_cpu_usage_seconds_total:sum_rate"
+)
+
 // GetContainerCPUMetric node_namespace_pod_container:container_cpu_usage_seconds_total:sum_rate was renamed in 4.9
 func GetContainerCPUMetric(ctx context.Context, serverClient k8sclient.Client, l l.Logger) (string, error) {
 	before49, err := cluster.ClusterVersionBefore49(ctx, serverClient, l)
@@ -306,9 +462,9 @@ func GetContainerCPUMetric(ctx context.Context, serverClient k8sclient.Client, l
 		return "", err
 	}
 	if before49 {
-		return "node_namespace_pod_container:container_cpu_usage_seconds_total:sum_rate", nil
+		return ContainerCPUMetricBefore49, nil
 	} else {
-		return "node_namespace_pod_container:container_cpu_usage_seconds_total:sum_irate", nil
+		return ContainerCPUMetric, nil
 	}
 }
 
@@ -394,3 +550,15 @@ func GetApiClient(route string, token prometheusConfig.Secret) (prometheusv1.API
 	v1api := prometheusv1.NewAPI(client)
 	return v1api, nil
 }
+
+// GetOboPrometheusApiClient returns a client for the prometheus API of the RHOAM OBO monitoring stack
+func GetOboPrometheusApiClient(installationNamespace string) (prometheusv1.API, error) {
+	client, err := prometheusApi.NewClient(prometheusApi.Config{
+		Address: config.GetOboPrometheusURL(installationNamespace),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return prometheusv1.NewAPI(client), nil
+}
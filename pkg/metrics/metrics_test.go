@@ -4,12 +4,14 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/integr8ly/integreatly-operator/api/v1alpha1"
 	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
 
 	"github.com/integr8ly/integreatly-operator/utils"
 	configv1 "github.com/openshift/api/config/v1"
+	"github.com/prometheus/client_golang/prometheus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -225,3 +227,34 @@ func Test_GetStats(t *testing.T) {
 		})
 	}
 }
+
+func TestSetHistory(t *testing.T) {
+	start := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(start.Add(time.Hour))
+	stages := []v1alpha1.RHMIHistoryStage{{Name: v1alpha1.UninstallProductsStage, StartTime: start, Duration: &metav1.Duration{Duration: time.Minute}}}
+	installation := &v1alpha1.RHMI{
+		Status: v1alpha1.RHMIStatus{
+			History: []v1alpha1.RHMIHistoryEntry{
+				{Type: v1alpha1.HistoryEntryUninstall, From: "1.2.0", StartTime: start, EndTime: &end, Stages: stages, Outcome: v1alpha1.HistoryOutcomeFailed},
+				{Type: v1alpha1.HistoryEntryUninstall, From: "1.2.0", StartTime: end, EndTime: &end, Stages: stages, Outcome: v1alpha1.HistoryOutcomeFailed},
+			},
+		},
+	}
+
+	SetHistory(installation)
+
+	// the entries with the same change are kept apart by their start time
+	if got := countSeries(RHOAMHistoryDuration); got != 2 {
+		t.Errorf("expected 2 history duration series, got %d", got)
+	}
+	if got := countSeries(RHOAMHistoryStageDuration); got != 2 {
+		t.Errorf("expected 2 history stage duration series, got %d", got)
+	}
+}
+
+func countSeries(collector prometheus.Collector) int {
+	ch := make(chan prometheus.Metric, 10)
+	collector.Collect(ch)
+	close(ch)
+	return len(ch)
+}
@@ -3,6 +3,8 @@ package cloudresources
 import (
 	"context"
 	"fmt"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
 	"strings"
 
 	croResources "github.com/integr8ly/cloud-resource-operator/pkg/resources"
@@ -16,7 +18,9 @@ import (
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func (r *Reconciler) newAlertsReconciler(ctx context.Context, client k8sclient.Client, logger l.Logger, installType string, namespace string) (resources.AlertReconciler, error) {
+// newAlertsReconciler returns the reconciler of the cloud resources operator alerts. The snapshot alert of the
+// Redis instances is only created when there are any
+func (r *Reconciler) newAlertsReconciler(logger l.Logger, installType string, namespace string, redisNames []string) *resources.AlertReconcilerImpl {
 	installationName := resources.InstallationNames[installType]
 
 	alertsReconciler := &resources.AlertReconcilerImpl{
@@ -89,18 +93,13 @@ func (r *Reconciler) newAlertsReconciler(ctx context.Context, client k8sclient.C
 		},
 	}
 
-	return addElasticCacheSnapshotNotFoundAlert(ctx, client, logger, installationName, *alertsReconciler, r.installation.Namespace)
+	addElasticCacheSnapshotNotFoundAlert(installationName, alertsReconciler, redisNames)
+	return alertsReconciler
 }
 
-func addElasticCacheSnapshotNotFoundAlert(ctx context.Context, client k8sclient.Client, logger l.Logger, installationName string, alertsReconciler resources.AlertReconcilerImpl, ns string) (resources.AlertReconciler, error) {
-
-	names, err := getRedisCRsNames(ctx, client, ns)
-	if err != nil {
-		logger.Error("Error getting redis names", nil, err)
-		return &alertsReconciler, err
-	}
+func addElasticCacheSnapshotNotFoundAlert(installationName string, alertsReconciler *resources.AlertReconcilerImpl, names []string) {
 	if len(names) == 0 {
-		return &alertsReconciler, nil
+		return
 	}
 
 	metricsCheck := ""
@@ -124,8 +123,6 @@ func addElasticCacheSnapshotNotFoundAlert(ctx context.Context, client k8sclient.
 		})
 		alertsReconciler.Alerts[0].Rules = alertListType
 	}
-
-	return &alertsReconciler, nil
 }
 
 func getRedisCRsNames(ctx context.Context, client k8sclient.Client, ns string) ([]string, error) {
@@ -153,3 +150,11 @@ func sanitize(metricsCheck string) string {
 	// Convention for CRs is - but _ for prom metrics
 	return strings.ToLower(strings.ReplaceAll(metricsCheck, "-", "_"))
 }
+
+// AlertReconcilers returns the reconcilers of all the alerts created for the cloud resources operator,
+// so that they can be rendered without a cluster
+func (r *Reconciler) AlertReconcilers(inputs resources.AlertInputs, _ quota.ProductConfig) ([]*resources.AlertReconcilerImpl, error) {
+	return []*resources.AlertReconcilerImpl{
+		r.newAlertsReconciler(r.log, r.installation.Spec.Type, config.GetOboNamespace(r.installation.Namespace), inputs.RedisNames),
+	}, nil
+}
@@ -90,7 +90,7 @@ func NewReconciler(configManager config.ConfigReadWriter, installation *integrea
 		productConfig.SetOperatorNamespace(productConfig.GetNamespace() + "-operator")
 	}
 
-	if err := configManager.WriteConfig(productConfig); err != nil {
+	if err := configManager.WriteConfig(productConfig, "cloudresources.NewReconciler"); err != nil {
 		return nil, fmt.Errorf("error writing cloudresources config : %w", err)
 	}
 
@@ -273,23 +273,26 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, nil
 	}
 
-	alertsReconciler, err := r.newAlertsReconciler(ctx, client, r.log, r.installation.Spec.Type, config.GetOboNamespace(r.installation.Namespace))
-	if err != nil {
-		events.HandleError(r.recorder, installation, phase, "Failed to get new alerts reconciler", err)
-		r.log.Error("Error getting cloud resources alerts reconciler", nil, err)
-		return integreatlyv1alpha1.PhaseFailed, err
-	}
+	if integreatlyv1alpha1.HasObservability(integreatlyv1alpha1.InstallationType(installation.Spec.Type)) {
+		redisNames, err := getRedisCRsNames(ctx, client, r.installation.Namespace)
+		if err != nil {
+			events.HandleError(r.recorder, installation, phase, "Failed to get new alerts reconciler", err)
+			r.log.Error("Error getting redis names", nil, err)
+			return integreatlyv1alpha1.PhaseFailed, err
+		}
+		alertsReconciler := r.newAlertsReconciler(r.log, r.installation.Spec.Type, config.GetOboNamespace(r.installation.Namespace), redisNames)
 
-	phase, err = alertsReconciler.ReconcileAlerts(ctx, client)
-	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
-		events.HandleError(r.recorder, installation, phase, "Failed to reconcile operator endpoint available alerts", err)
-		return phase, err
+		phase, err = alertsReconciler.ReconcileAlerts(ctx, client)
+		if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+			events.HandleError(r.recorder, installation, phase, "Failed to reconcile operator endpoint available alerts", err)
+			return phase, err
+		}
 	}
 	productStatus.Host = r.Config.GetHost()
 	productStatus.Version = r.Config.GetProductVersion()
 	productStatus.OperatorVersion = r.Config.GetOperatorVersion()
 
-	err = r.ConfigManager.WriteConfig(r.Config)
+	err = r.ConfigManager.WriteConfig(r.Config, "cloudresources.Reconcile")
 	if err != nil {
 		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("could not write cloud resources config: %w", err)
 	}
@@ -3,6 +3,7 @@ package grafana
 import (
 	"context"
 	"fmt"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
 
 	"github.com/integr8ly/integreatly-operator/pkg/resources"
 	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
@@ -13,7 +14,7 @@ import (
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func (r *Reconciler) newAlertReconciler(logger l.Logger, installType string, namespace string) resources.AlertReconciler {
+func (r *Reconciler) newAlertReconciler(logger l.Logger, installType string, namespace string) *resources.AlertReconcilerImpl {
 	installationName := resources.InstallationNames[installType]
 	alertNamePrefix := "customer-monitoring-po-"
 
@@ -83,3 +84,11 @@ func (r *Reconciler) removeGrafanaOperatorAlerts(nsPrefix string, ctx context.Co
 	}
 	return nil
 }
+
+// AlertReconcilers returns the reconcilers of all the alerts created for Grafana, so that they can be
+// rendered without a cluster
+func (r *Reconciler) AlertReconcilers(_ resources.AlertInputs, _ quota.ProductConfig) ([]*resources.AlertReconcilerImpl, error) {
+	return []*resources.AlertReconcilerImpl{
+		r.newAlertReconciler(r.log, r.installation.Spec.Type, r.Config.GetNamespace()),
+	}, nil
+}
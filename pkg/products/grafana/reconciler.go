@@ -11,6 +11,7 @@ import (
 	"github.com/integr8ly/integreatly-operator/pkg/config"
 	"github.com/integr8ly/integreatly-operator/pkg/resources"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/events"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/images"
 	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/marketplace"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/owner"
@@ -65,7 +66,7 @@ func NewReconciler(configManager config.ConfigReadWriter, installation *integrea
 	}
 
 	productConfig.SetNamespace(installation.Spec.NamespacePrefix + defaultInstallationNamespace)
-	if err := configManager.WriteConfig(productConfig); err != nil {
+	if err := configManager.WriteConfig(productConfig, "grafana.NewReconciler"); err != nil {
 		return nil, fmt.Errorf("error writing grafana config : %w", err)
 	}
 
@@ -144,7 +145,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 
 	if string(r.Config.GetProductVersion()) != string(integreatlyv1alpha1.VersionGrafana) {
 		r.Config.SetProductVersion(string(integreatlyv1alpha1.VersionGrafana))
-		if err := r.ConfigManager.WriteConfig(r.Config); err != nil {
+		if err := r.ConfigManager.WriteConfig(r.Config, "grafana.Reconcile"); err != nil {
 			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("error writing grafana config : %w", err)
 		}
 	}
@@ -218,7 +219,14 @@ func (r *Reconciler) ReconcileGrafanaDeployment(ctx context.Context, client k8sc
 		},
 	}
 
-	_, err := controllerutil.CreateOrUpdate(ctx, client, grafanaDeployment, func() error {
+	overrides, err := images.GetOverrides(ctx, client, r.installation.Namespace)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to resolve the grafana images: %w", err)
+	}
+	grafanaImage := overrides.Resolve(images.Grafana)
+	oauthProxyImage := overrides.Resolve(images.GrafanaOAuthProxy)
+
+	_, err = controllerutil.CreateOrUpdate(ctx, client, grafanaDeployment, func() error {
 		if grafanaDeployment.Labels == nil {
 			grafanaDeployment.Labels = map[string]string{}
 		}
@@ -228,6 +236,8 @@ func (r *Reconciler) ReconcileGrafanaDeployment(ctx context.Context, client k8sc
 		}
 
 		grafanaDeployment.Labels["app"] = "grafana"
+		images.SetResolvedImage(grafanaDeployment, images.Grafana, grafanaImage)
+		images.SetResolvedImage(grafanaDeployment, images.GrafanaOAuthProxy, oauthProxyImage)
 		grafanaDeployment.Spec.Selector = &metav1.LabelSelector{
 			MatchLabels: map[string]string{
 				"app": "grafana",
@@ -347,7 +357,7 @@ func (r *Reconciler) ReconcileGrafanaDeployment(ctx context.Context, client k8sc
 		// Container #1
 		grafanaDeployment.Spec.Template.Spec.Containers[0].TerminationMessagePath = "/dev/termination-log"
 		grafanaDeployment.Spec.Template.Spec.Containers[0].Name = "grafana"
-		grafanaDeployment.Spec.Template.Spec.Containers[0].Image = "registry.redhat.io/rhel9/grafana:9.7-1767725073"
+		grafanaDeployment.Spec.Template.Spec.Containers[0].Image = grafanaImage
 		grafanaDeployment.Spec.Template.Spec.Containers[0].VolumeMounts = []corev1.VolumeMount{
 			{
 				MountPath: "/etc/grafana/",
@@ -473,7 +483,7 @@ func (r *Reconciler) ReconcileGrafanaDeployment(ctx context.Context, client k8sc
 		// container #2
 		grafanaDeployment.Spec.Template.Spec.Containers[1].TerminationMessagePath = "/dev/termination-log"
 		grafanaDeployment.Spec.Template.Spec.Containers[1].Name = "grafana-proxy"
-		grafanaDeployment.Spec.Template.Spec.Containers[1].Image = "registry.redhat.io/openshift4/ose-oauth-proxy-rhel9:v4.16.0-202512111314.p2.g565f7ed.assembly.stream.el9"
+		grafanaDeployment.Spec.Template.Spec.Containers[1].Image = oauthProxyImage
 		grafanaDeployment.Spec.Template.Spec.Containers[1].VolumeMounts = []corev1.VolumeMount{
 			{
 				MountPath: "/etc/tls/private",
@@ -529,7 +539,7 @@ func (r *Reconciler) reconcileHost(ctx context.Context, serverClient k8sclient.C
 	}
 
 	r.Config.SetHost("https://" + grafanaRoute.Spec.Host)
-	err = r.ConfigManager.WriteConfig(r.Config)
+	err = r.ConfigManager.WriteConfig(r.Config, "grafana.reconcileHost")
 	if err != nil {
 		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("could not set Grafana route: %w", err)
 	}
@@ -19,7 +19,7 @@ var (
 	totalRequestsMetric = "authorized_calls"
 )
 
-func (r *Reconciler) newAlertsReconciler(grafanaDashboardURL string, namespace string) (resources.AlertReconciler, error) {
+func (r *Reconciler) newAlertsReconciler(grafanaDashboardURL string, namespace string) (*resources.AlertReconcilerImpl, error) {
 
 	requestsAllowedPerSecond, err := r.getRateLimitInSeconds(r.RateLimitConfig.Unit, r.RateLimitConfig.RequestsPerUnit)
 	if err != nil {
@@ -39,6 +39,14 @@ func (r *Reconciler) newAlertsReconciler(grafanaDashboardURL string, namespace s
 	}, nil
 }
 
+// apiUsageAlertName is the name of the PrometheusRule of an API usage alert of the alerts configuration
+func apiUsageAlertName(alertName string, installationName string) string {
+	if installationName == string(integreatlyv1alpha1.InstallationTypeManagedApi) {
+		return "marin3r-" + alertName
+	}
+	return alertName
+}
+
 // mapAlertsConfiguration maps each value from alertsConfig into a
 // resources.AlertConfiguration object, resulting into a list of the
 // prometheus alerts to be created
@@ -46,10 +54,7 @@ func mapAlertsConfiguration(logger l.Logger, namespace, rateLimitUnit string, ra
 	result := make([]resources.AlertConfiguration, 0, len(alertsConfig))
 
 	for alertName, alertConfig := range alertsConfig {
-
-		if installationName == string(integreatlyv1alpha1.InstallationTypeManagedApi) {
-			alertName = "marin3r-" + alertName
-		}
+		alertName = apiUsageAlertName(alertName, installationName)
 
 		switch alertConfig.Type {
 		case marin3rconfig.AlertTypeSpike:
@@ -57,9 +62,10 @@ func mapAlertsConfiguration(logger l.Logger, namespace, rateLimitUnit string, ra
 				"max_over_time((sum(increase(authorized_calls[1m])) + sum(increase(limited_calls[1m])))[%s:]) > %d",
 				alertConfig.Period, rateLimitRequestsPerUnit)
 			annotations := map[string]string{
-				"message":        fmt.Sprintf("hard limit of %d breached at least once in the last %s", rateLimitRequestsPerUnit, alertConfig.Period),
-				"grafanaConsole": grafanaDashboardURL,
+				"message": fmt.Sprintf("hard limit of %d breached at least once in the last %s", rateLimitRequestsPerUnit, alertConfig.Period),
+				"sop_url": resources.SopUrlRHOAMApiUsageOverLimit,
 			}
+			addGrafanaConsole(annotations, grafanaDashboardURL)
 			alert := mapSpikeAlert(alertConfig, alertName, namespace, expr, annotations, installationName)
 			result = append(result, alert)
 		case marin3rconfig.AlertTypeThreshold:
@@ -94,8 +100,9 @@ func mapAlertsConfiguration(logger l.Logger, namespace, rateLimitUnit string, ra
 					"Total API usage in your API Management service is between %s and %s of the allowable threshold, %d requests per %s, during the last %s",
 					alertConfig.Threshold.MinRate, upperMessage, rateLimitRequestsPerUnit, rateLimitUnit, alertConfig.Period,
 				),
-				"grafanaConsole": grafanaDashboardURL,
+				"sop_url": resources.SopUrlRHOAMApiUsageThresholdExceeded,
 			}
+			addGrafanaConsole(annotations, grafanaDashboardURL)
 			alert := mapThresholdAlert(alertConfig, alertName, namespace, expr, annotations, installationName)
 
 			result = append(result, alert)
@@ -107,6 +114,13 @@ func mapAlertsConfiguration(logger l.Logger, namespace, rateLimitUnit string, ra
 	return result, nil
 }
 
+// addGrafanaConsole links the alert to the rate limiting dashboard, the alerts aren't linked while grafana is disabled
+func addGrafanaConsole(annotations map[string]string, grafanaDashboardURL string) {
+	if grafanaDashboardURL != "" {
+		annotations["grafanaConsole"] = grafanaDashboardURL
+	}
+}
+
 func mapSpikeAlert(alertConfig *marin3rconfig.AlertConfig, alertName string, namespace string, expr string, annotations map[string]string, installationName string) resources.AlertConfiguration {
 	return resources.AlertConfiguration{
 		AlertName: alertName,
@@ -4,8 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -14,6 +17,16 @@ const (
 	AlertConfigMapName     = "rate-limit-alerts"
 	ManagedApiServiceQuota = "RHOAM SERVICE SKU"
 
+	DescriptorConfigMapName = "rate-limit-descriptors"
+
+	TenantIdentificationConfigMapName = "tenant-identification"
+	TenantIdentificationHostPattern   = "hostPattern"
+	TenantIdentificationHeader        = "header"
+	TenantIdentificationSNI           = "sni"
+	TenantIdentificationHostLookup    = "hostLookup"
+	// DefaultTenantHostPattern takes the tenant from apicast hosts named <tenant>-apicast-<env>.<domain>
+	DefaultTenantHostPattern = "^(.-)%-apicast"
+
 	AlertTypeThreshold = "Threshold"
 	AlertTypeSpike     = "Spike"
 
@@ -26,6 +39,94 @@ type RateLimitConfig struct {
 	RequestsPerUnit uint32 `json:"requests_per_unit"`
 }
 
+// RateLimitDescriptorConfig caps the requests matching a path prefix, HTTP
+// method and host independently of the SKU wide limit. When CredentialHeader is
+// set the limit applies to each value of the header, e.g. per 3scale user key
+type RateLimitDescriptorConfig struct {
+	Name             string `json:"name"`
+	PathPrefix       string `json:"pathPrefix,omitempty"`
+	Method           string `json:"method,omitempty"`
+	Host             string `json:"host,omitempty"`
+	CredentialHeader string `json:"credentialHeader,omitempty"`
+	Unit             string `json:"unit"`
+	RequestsPerUnit  uint32 `json:"requests_per_unit"`
+}
+
+var descriptorNameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+const (
+	// DescriptorMatchKey is the descriptor entry holding the name of the per API descriptor a request matches
+	DescriptorMatchKey = "header_match"
+	// descriptorUnmatchedPrefix marks the entry of a request that doesn't match a per API descriptor. The colon
+	// isn't allowed in descriptor names, so the value can't be mistaken for a match
+	descriptorUnmatchedPrefix = "unmatched:"
+)
+
+// DescriptorIndex is the position of the i-th per API descriptor in the descriptors envoy sends to limitador.
+// The SKU wide descriptor comes first, followed by exactly one descriptor per API whether the request matches
+// it or not, so the position doesn't depend on the request
+func DescriptorIndex(i int) int {
+	return i + 1
+}
+
+// UnmatchedValue is the DescriptorMatchKey value sent for a request that doesn't match the descriptor
+func (d RateLimitDescriptorConfig) UnmatchedValue() string {
+	return descriptorUnmatchedPrefix + d.Name
+}
+
+func (d RateLimitDescriptorConfig) Validate() error {
+	if !descriptorNameRegex.MatchString(d.Name) {
+		return fmt.Errorf("invalid descriptor name %q, must consist of lower case alphanumeric characters or '-'", d.Name)
+	}
+	if d.PathPrefix == "" && d.Method == "" && d.Host == "" {
+		return fmt.Errorf("descriptor %s must match on at least one of pathPrefix, method or host", d.Name)
+	}
+	if d.PathPrefix != "" && !strings.HasPrefix(d.PathPrefix, "/") {
+		return fmt.Errorf("descriptor %s pathPrefix must start with /", d.Name)
+	}
+	if d.Method != "" && d.Method != strings.ToUpper(d.Method) {
+		return fmt.Errorf("descriptor %s method must be upper case", d.Name)
+	}
+	if _, ok := conversionFactors[d.Unit]; !ok {
+		return fmt.Errorf("descriptor %s has unexpected unit %q", d.Name, d.Unit)
+	}
+	if d.RequestsPerUnit == 0 {
+		return fmt.Errorf("descriptor %s must allow at least one request per unit", d.Name)
+	}
+	return nil
+}
+
+// TenantIdentificationConfig defines how the multitenant apicast envoy filter
+// derives the tenant of a request, which is used as the per tenant rate limit key
+type TenantIdentificationConfig struct {
+	Mode string `json:"mode"`
+	// HostPattern is a Lua pattern with a single capture applied to the Host
+	HostPattern string `json:"hostPattern,omitempty"`
+	// Header is the request header carrying the tenant
+	Header string `json:"header,omitempty"`
+	// Hosts maps hosts to tenants, in addition to the hosts of the 3scale tenant accounts
+	Hosts map[string]string `json:"hosts,omitempty"`
+}
+
+var headerNameRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func (c TenantIdentificationConfig) Validate() error {
+	switch c.Mode {
+	case TenantIdentificationHostPattern:
+		if strings.Count(c.HostPattern, "(") != 1 || strings.Count(c.HostPattern, ")") != 1 {
+			return fmt.Errorf("tenant host pattern %q must contain exactly one capture", c.HostPattern)
+		}
+	case TenantIdentificationHeader:
+		if !headerNameRegex.MatchString(c.Header) {
+			return fmt.Errorf("invalid tenant header %q", c.Header)
+		}
+	case TenantIdentificationSNI, TenantIdentificationHostLookup:
+	default:
+		return fmt.Errorf("unexpected tenant identification mode %q", c.Mode)
+	}
+	return nil
+}
+
 type AlertConfig struct {
 	Type      string                `json:"type"`
 	Level     string                `json:"level"`
@@ -39,6 +140,52 @@ type AlertThresholdConfig struct {
 	MaxRate *string `json:"maxRate,omitempty"`
 }
 
+// DefaultAlertConfig returns the API usage alerts created when the rate limit
+// alerts ConfigMap does not define any
+func DefaultAlertConfig() map[string]*AlertConfig {
+	maxRate1 := "90%"
+	maxRate2 := "95%"
+
+	return map[string]*AlertConfig{
+		"api-usage-alert-level1": {
+			Type:     AlertTypeThreshold,
+			RuleName: "RHOAMApiUsageLevel1ThresholdExceeded",
+			Level:    "info",
+			Threshold: &AlertThresholdConfig{
+				MinRate: "80%",
+				MaxRate: &maxRate1,
+			},
+			Period: "4h",
+		},
+		"api-usage-alert-level2": {
+			Type:     AlertTypeThreshold,
+			RuleName: "RHOAMApiUsageLevel2ThresholdExceeded",
+			Level:    "info",
+			Threshold: &AlertThresholdConfig{
+				MinRate: "90%",
+				MaxRate: &maxRate2,
+			},
+			Period: "2h",
+		},
+		"api-usage-alert-level3": {
+			Type:     AlertTypeThreshold,
+			RuleName: "RHOAMApiUsageLevel3ThresholdExceeded",
+			Level:    "info",
+			Threshold: &AlertThresholdConfig{
+				MinRate: "95%",
+				MaxRate: nil,
+			},
+			Period: "30m",
+		},
+		"rate-limit-spike": {
+			Type:     AlertTypeSpike,
+			RuleName: "RHOAMApiUsageOverLimit",
+			Level:    "warning",
+			Period:   "30m",
+		},
+	}
+}
+
 func GetAlertConfig(ctx context.Context, client k8sclient.Client, namespace string) (map[string]*AlertConfig, error) {
 	alertsConfig := map[string]*AlertConfig{}
 	err := getFromJSONConfigMap(
@@ -50,6 +197,61 @@ func GetAlertConfig(ctx context.Context, client k8sclient.Client, namespace stri
 	return alertsConfig, err
 }
 
+// GetRateLimitDescriptorConfig returns the per API rate limit descriptors. They
+// are optional, so a missing ConfigMap results in no descriptors
+func GetRateLimitDescriptorConfig(ctx context.Context, client k8sclient.Client, namespace string) ([]RateLimitDescriptorConfig, error) {
+	descriptors := []RateLimitDescriptorConfig{}
+	err := getFromJSONConfigMap(
+		ctx, client,
+		DescriptorConfigMapName, namespace, "descriptors",
+		&descriptors,
+	)
+	if k8serr.IsNotFound(err) {
+		return []RateLimitDescriptorConfig{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	for _, descriptor := range descriptors {
+		if err := descriptor.Validate(); err != nil {
+			return nil, err
+		}
+		if names[descriptor.Name] {
+			return nil, fmt.Errorf("duplicate rate limit descriptor %s", descriptor.Name)
+		}
+		names[descriptor.Name] = true
+	}
+
+	return descriptors, nil
+}
+
+// GetTenantIdentificationConfig returns how tenants are identified by the
+// multitenant envoy filters, defaulting to the apicast host naming scheme
+func GetTenantIdentificationConfig(ctx context.Context, client k8sclient.Client, namespace string) (*TenantIdentificationConfig, error) {
+	identification := &TenantIdentificationConfig{}
+	err := getFromJSONConfigMap(
+		ctx, client,
+		TenantIdentificationConfigMapName, namespace, "config",
+		identification,
+	)
+	if k8serr.IsNotFound(err) {
+		identification.Mode = TenantIdentificationHostPattern
+	} else if err != nil {
+		return nil, err
+	}
+
+	if identification.Mode == TenantIdentificationHostPattern && identification.HostPattern == "" {
+		identification.HostPattern = DefaultTenantHostPattern
+	}
+
+	if err := identification.Validate(); err != nil {
+		return nil, err
+	}
+	return identification, nil
+}
+
 func GetQuota(_ context.Context, _ k8sclient.Client) (string, error) {
 	return ManagedApiServiceQuota, nil
 }
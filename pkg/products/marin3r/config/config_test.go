@@ -96,3 +96,140 @@ func TestGetAlertConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestGetRateLimitDescriptorConfig(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	descriptorsConfigMap := func(descriptors string) []runtime.Object {
+		return []runtime.Object{
+			&corev1.ConfigMap{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      DescriptorConfigMapName,
+					Namespace: "redhat-test-operator",
+				},
+				Data: map[string]string{"descriptors": descriptors},
+			},
+		}
+	}
+
+	scenarios := []struct {
+		Name        string
+		InitialObjs []runtime.Object
+		Expected    []RateLimitDescriptorConfig
+		WantErr     bool
+	}{
+		{
+			Name:     "No descriptors configured",
+			Expected: []RateLimitDescriptorConfig{},
+		},
+		{
+			Name: "Descriptors configured",
+			InitialObjs: descriptorsConfigMap(`[
+				{"name": "orders", "pathPrefix": "/orders", "method": "POST", "unit": "minute", "requests_per_unit": 100},
+				{"name": "payments", "host": "payments.example.com", "credentialHeader": "user_key", "unit": "hour", "requests_per_unit": 5}
+			]`),
+			Expected: []RateLimitDescriptorConfig{
+				{Name: "orders", PathPrefix: "/orders", Method: "POST", Unit: Minute, RequestsPerUnit: 100},
+				{Name: "payments", Host: "payments.example.com", CredentialHeader: "user_key", Unit: Hour, RequestsPerUnit: 5},
+			},
+		},
+		{
+			Name:        "Descriptor without matchers",
+			InitialObjs: descriptorsConfigMap(`[{"name": "orders", "unit": "minute", "requests_per_unit": 100}]`),
+			WantErr:     true,
+		},
+		{
+			Name:        "Descriptor with an invalid unit",
+			InitialObjs: descriptorsConfigMap(`[{"name": "orders", "pathPrefix": "/orders", "unit": "week", "requests_per_unit": 100}]`),
+			WantErr:     true,
+		},
+		{
+			Name: "Duplicate descriptors",
+			InitialObjs: descriptorsConfigMap(`[
+				{"name": "orders", "pathPrefix": "/orders", "unit": "minute", "requests_per_unit": 100},
+				{"name": "orders", "pathPrefix": "/v2/orders", "unit": "minute", "requests_per_unit": 100}
+			]`),
+			WantErr: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			k8sClient := utils.NewTestClient(scheme, scenario.InitialObjs...)
+			descriptors, err := GetRateLimitDescriptorConfig(context.TODO(), k8sClient, "redhat-test-operator")
+			if (err != nil) != scenario.WantErr {
+				t.Fatalf("unexpected error %v, wantErr %v", err, scenario.WantErr)
+			}
+			if !scenario.WantErr && !reflect.DeepEqual(descriptors, scenario.Expected) {
+				t.Errorf("expected descriptors %v, got %v", scenario.Expected, descriptors)
+			}
+		})
+	}
+}
+
+func TestGetTenantIdentificationConfig(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	identificationConfigMap := func(config string) []runtime.Object {
+		return []runtime.Object{
+			&corev1.ConfigMap{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      TenantIdentificationConfigMapName,
+					Namespace: "redhat-test-operator",
+				},
+				Data: map[string]string{"config": config},
+			},
+		}
+	}
+
+	scenarios := []struct {
+		Name        string
+		InitialObjs []runtime.Object
+		Expected    *TenantIdentificationConfig
+		WantErr     bool
+	}{
+		{
+			Name:     "Defaults to the apicast host pattern",
+			Expected: &TenantIdentificationConfig{Mode: TenantIdentificationHostPattern, HostPattern: DefaultTenantHostPattern},
+		},
+		{
+			Name:        "Host lookup with custom domains",
+			InitialObjs: identificationConfigMap(`{"mode": "hostLookup", "hosts": {"api.acme.com": "acme"}}`),
+			Expected:    &TenantIdentificationConfig{Mode: TenantIdentificationHostLookup, Hosts: map[string]string{"api.acme.com": "acme"}},
+		},
+		{
+			Name:        "Header without a name",
+			InitialObjs: identificationConfigMap(`{"mode": "header"}`),
+			WantErr:     true,
+		},
+		{
+			Name:        "Host pattern without a capture",
+			InitialObjs: identificationConfigMap(`{"mode": "hostPattern", "hostPattern": "^api%."}`),
+			WantErr:     true,
+		},
+		{
+			Name:        "Unknown mode",
+			InitialObjs: identificationConfigMap(`{"mode": "cookie"}`),
+			WantErr:     true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			k8sClient := utils.NewTestClient(scheme, scenario.InitialObjs...)
+			identification, err := GetTenantIdentificationConfig(context.TODO(), k8sClient, "redhat-test-operator")
+			if (err != nil) != scenario.WantErr {
+				t.Fatalf("unexpected error %v, wantErr %v", err, scenario.WantErr)
+			}
+			if !scenario.WantErr && !reflect.DeepEqual(identification, scenario.Expected) {
+				t.Errorf("expected %v, got %v", scenario.Expected, identification)
+			}
+		})
+	}
+}
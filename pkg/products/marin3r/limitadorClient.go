@@ -1,68 +1,80 @@
 package marin3r
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"github.com/integr8ly/integreatly-operator/pkg/resources"
 	"net/http"
+	"net/url"
+	"sort"
+	"strings"
 	"time"
+
+	"github.com/integr8ly/integreatly-operator/pkg/resources"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
+)
+
+const (
+	limitadorAdminPort      = 8080
+	limitadorRequestTimeout = 10 * time.Second
+	limitadorRetries        = 3
+	limitadorRetryInterval  = 500 * time.Millisecond
 )
 
 type LimitadorClientInterface interface {
-	GetLimitsByName(string) ([]limitadorLimit, error)
-	DeleteLimitsByNameUsingPod(string, string) error
+	GetLimits(ctx context.Context, namespace string) ([]limitadorLimit, error)
+	GetCounters(ctx context.Context, namespace string) ([]LimitadorCounter, error)
+	DeleteLimits(ctx context.Context, namespace string) error
 }
 
+// LimitadorCounter is the live state of a limit for one set of descriptor values,
+// as returned by the limitador admin API
+type LimitadorCounter struct {
+	Limit            limitadorLimit    `json:"limit"`
+	SetVariables     map[string]string `json:"set_variables"`
+	Remaining        int64             `json:"remaining"`
+	ExpiresInSeconds uint64            `json:"expires_in_seconds"`
+}
+
+// Descriptor returns the descriptor values the counter applies to in a stable
+// key=value form. Counters of limits without variables have an empty descriptor
+func (c LimitadorCounter) Descriptor() string {
+	pairs := make([]string, 0, len(c.SetVariables))
+	for key, value := range c.SetVariables {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// LimitadorClient talks to the limitador admin API through the rate limit service
 type LimitadorClient struct {
-	PodExecutor resources.PodExecutorInterface
-	PodName     string
-	Namespace   string
+	*resources.RetryingAPIClient
 }
 
 var _ LimitadorClientInterface = &LimitadorClient{}
 
-func NewLimitadorClient(podExecutor resources.PodExecutorInterface, nameSpace, podName string) *LimitadorClient {
+func NewLimitadorClient(namespace string) *LimitadorClient {
 	return &LimitadorClient{
-		PodExecutor: podExecutor,
-		Namespace:   nameSpace,
-		PodName:     podName,
+		RetryingAPIClient: resources.NewRetryingAPIClient("limitador admin API", fmt.Sprintf("http://%s.%s.svc:%d", quota.RateLimitName, namespace, limitadorAdminPort), limitadorRequestTimeout, limitadorRetries, limitadorRetryInterval),
 	}
 }
 
-func (l LimitadorClient) GetLimitsByName(limitName string) ([]limitadorLimit, error) {
-	response, _, err := l.PodExecutor.ExecuteRemoteCommand(l.Namespace, l.PodName, []string{"/bin/sh",
-		"-c", fmt.Sprintf("curl -fsSL http://127.0.0.1:8080/limits/%s", limitName)})
-	if err != nil {
+func (l *LimitadorClient) GetLimits(ctx context.Context, namespace string) ([]limitadorLimit, error) {
+	limits := []limitadorLimit{}
+	if err := l.Do(ctx, http.MethodGet, "limits/"+url.PathEscape(namespace), nil, &limits); err != nil {
 		return nil, err
 	}
+	return limits, nil
+}
 
-	limitadorLimitsInRedis := []limitadorLimit{}
-	err = json.Unmarshal([]byte(response), &limitadorLimitsInRedis)
-	if err != nil {
+func (l *LimitadorClient) GetCounters(ctx context.Context, namespace string) ([]LimitadorCounter, error) {
+	counters := []LimitadorCounter{}
+	if err := l.Do(ctx, http.MethodGet, "counters/"+url.PathEscape(namespace), nil, &counters); err != nil {
 		return nil, err
 	}
-
-	return limitadorLimitsInRedis, nil
+	return counters, nil
 }
 
-func (l LimitadorClient) DeleteLimitsByNameUsingPod(limitName, rateLimitPodIP string) error {
-	client := &http.Client{
-		Transport: &http.Transport{
-			DisableKeepAlives: true,
-			IdleConnTimeout:   10 * time.Second,
-		},
-		Timeout: 10 * time.Second,
-	}
-	url := fmt.Sprintf("http://%s:8080/limits/%s", rateLimitPodIP, limitName)
-	req, err := http.NewRequest("DELETE", url, nil)
-	if err != nil {
-		return err
-	}
-
-	_, err = client.Do(req)
-	if err != nil {
-		return err
-	}
-
-	return nil
+func (l *LimitadorClient) DeleteLimits(ctx context.Context, namespace string) error {
+	return l.Do(ctx, http.MethodDelete, "limits/"+url.PathEscape(namespace), nil, nil)
 }
@@ -0,0 +1,203 @@
+package marin3r
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/integr8ly/integreatly-operator/pkg/metrics"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
+	"github.com/integr8ly/integreatly-operator/utils"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const countersResponse = `[
+  {"limit":{"namespace":"apicast-ratelimit","max_value":200,"seconds":60,"conditions":["header_match == per-mt-limit"],"variables":["tenant"]},"set_variables":{"tenant":"tenant-a"},"remaining":50,"expires_in_seconds":12},
+  {"limit":{"name":"tenant-usage","namespace":"apicast-ratelimit","max_value":4294967295,"seconds":86400,"conditions":["header_match == per-mt-limit"],"variables":["tenant"]},"set_variables":{"tenant":"tenant-a"},"remaining":4294967170,"expires_in_seconds":3600},
+  {"limit":{"namespace":"apicast-ratelimit","max_value":1000,"seconds":60,"conditions":[],"variables":[]},"set_variables":{},"remaining":900,"expires_in_seconds":12}
+]`
+
+func newTestLimitadorClient(server *httptest.Server) *LimitadorClient {
+	client := NewLimitadorClient("redhat-test-marin3r")
+	client.BaseURL = server.URL
+	client.Backoff = wait.Backoff{Duration: time.Millisecond, Factor: 1, Steps: 3}
+	return client
+}
+
+func TestLimitadorClient(t *testing.T) {
+	scenarios := []struct {
+		Name      string
+		Responses []int
+		Call      func(*LimitadorClient) error
+		WantCalls int
+		WantErr   bool
+	}{
+		{
+			Name:      "limits are listed",
+			Responses: []int{http.StatusOK},
+			Call: func(c *LimitadorClient) error {
+				limits, err := c.GetLimits(context.TODO(), "apicast-ratelimit")
+				if err != nil {
+					return err
+				}
+				if len(limits) != 1 || limits[0].MaxValue != 1 {
+					return fmt.Errorf("unexpected limits %v", limits)
+				}
+				return nil
+			},
+			WantCalls: 1,
+		},
+		{
+			Name:      "counters are read per descriptor",
+			Responses: []int{http.StatusOK},
+			Call: func(c *LimitadorClient) error {
+				counters, err := c.GetCounters(context.TODO(), "apicast-ratelimit")
+				if err != nil {
+					return err
+				}
+				if len(counters) != 3 || counters[0].Descriptor() != "tenant=tenant-a" || counters[0].Remaining != 50 || counters[1].Limit.Name != tenantUsageLimitName || counters[2].Descriptor() != "" {
+					return fmt.Errorf("unexpected counters %+v", counters)
+				}
+				return nil
+			},
+			WantCalls: 1,
+		},
+		{
+			Name:      "server errors are retried",
+			Responses: []int{http.StatusServiceUnavailable, http.StatusOK},
+			Call: func(c *LimitadorClient) error {
+				return c.DeleteLimits(context.TODO(), "apicast-ratelimit")
+			},
+			WantCalls: 2,
+		},
+		{
+			Name:      "retries are bounded",
+			Responses: []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusOK},
+			Call: func(c *LimitadorClient) error {
+				_, err := c.GetCounters(context.TODO(), "apicast-ratelimit")
+				return err
+			},
+			WantCalls: 3,
+			WantErr:   true,
+		},
+		{
+			Name:      "client errors are not retried",
+			Responses: []int{http.StatusMethodNotAllowed, http.StatusOK},
+			Call: func(c *LimitadorClient) error {
+				return c.DeleteLimits(context.TODO(), "apicast-ratelimit")
+			},
+			WantCalls: 1,
+			WantErr:   true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := scenario.Responses[calls]
+				calls++
+				w.WriteHeader(status)
+				if status != http.StatusOK {
+					return
+				}
+				switch r.URL.Path {
+				case "/limits/apicast-ratelimit":
+					if r.Method == http.MethodGet {
+						_, _ = w.Write([]byte(`[{"namespace":"apicast-ratelimit","max_value":1,"seconds":60,"conditions":[],"variables":[]}]`))
+					}
+				case "/counters/apicast-ratelimit":
+					_, _ = w.Write([]byte(countersResponse))
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			err := scenario.Call(newTestLimitadorClient(server))
+			if (err != nil) != scenario.WantErr {
+				t.Fatalf("unexpected error %v, wantErr %v", err, scenario.WantErr)
+			}
+			if calls != scenario.WantCalls {
+				t.Fatalf("expected %d requests, got %d", scenario.WantCalls, calls)
+			}
+		})
+	}
+}
+
+func gaugeValue(t *testing.T, gauge prometheus.Gauge) float64 {
+	metric := &dto.Metric{}
+	if err := gauge.Write(metric); err != nil {
+		t.Fatal(err)
+	}
+	return metric.GetGauge().GetValue()
+}
+
+func TestReportRateLimitCounters(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(countersResponse))
+	}))
+	defer server.Close()
+	counterReports = &counterReportThrottle{interval: time.Hour, last: map[string]time.Time{}}
+
+	deployment := func(readyReplicas int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: quota.RateLimitName, Namespace: "redhat-test-marin3r"},
+			Status:     appsv1.DeploymentStatus{ReadyReplicas: readyReplicas},
+		}
+	}
+
+	reconciler := &RateLimitServiceReconciler{
+		Namespace:       "redhat-test-marin3r",
+		LimitadorClient: newTestLimitadorClient(server),
+	}
+
+	metrics.ResetRateLimitCounters()
+	if err := reconciler.ReportRateLimitCounters(context.TODO(), utils.NewTestClient(scheme, deployment(0))); err != nil {
+		t.Fatal(err)
+	}
+	collected := make(chan prometheus.Metric, 10)
+	metrics.RateLimitCounterRemaining.Collect(collected)
+	if count := len(collected); count != 0 {
+		t.Fatalf("expected no counters to be reported before the service is ready, got %d", count)
+	}
+
+	if err := reconciler.ReportRateLimitCounters(context.TODO(), utils.NewTestClient(scheme, deployment(1))); err != nil {
+		t.Fatal(err)
+	}
+	if remaining := gaugeValue(t, metrics.RateLimitCounterRemaining.WithLabelValues("apicast-ratelimit", "tenant=tenant-a")); remaining != 50 {
+		t.Errorf("expected 50 requests remaining for tenant-a, got %v", remaining)
+	}
+	if maxValue := gaugeValue(t, metrics.RateLimitCounterMaxValue.WithLabelValues("apicast-ratelimit", "")); maxValue != 1000 {
+		t.Errorf("expected a max value of 1000 for the global limit, got %v", maxValue)
+	}
+	if authorized := gaugeValue(t, metrics.TenantAuthorizedRequests.WithLabelValues("tenant-a")); authorized != 125 {
+		t.Errorf("expected 125 authorized requests for tenant-a, got %v", authorized)
+	}
+	collected = make(chan prometheus.Metric, 10)
+	metrics.RateLimitCounterRemaining.Collect(collected)
+	if count := len(collected); count != 2 {
+		t.Errorf("expected the tenant usage counters not to be reported as rate limit counters, got %d counters", count)
+	}
+
+	if err := reconciler.ReportRateLimitCounters(context.TODO(), utils.NewTestClient(scheme, deployment(1))); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 {
+		t.Fatalf("expected the counters to be read once per interval, got %d requests", requests)
+	}
+}
@@ -1,15 +1,25 @@
 package marin3r
 
 import (
+	"context"
 	"fmt"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	marin3rconfig "github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/constants"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/integr8ly/integreatly-operator/pkg/resources"
 	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
 	monv1 "github.com/rhobs/obo-prometheus-operator/pkg/apis/monitoring/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
-func (r *Reconciler) newAlertReconciler(logger l.Logger, installType string, namespace string) resources.AlertReconciler {
+// rateLimitingDashboardPath is the path of the rate limiting dashboard linked from the API usage alerts
+const rateLimitingDashboardPath = "/d/66ab72e0d012aacf34f907be9d81cd9e/rate-limiting"
+
+func (r *Reconciler) newAlertReconciler(logger l.Logger, installType string, namespace string) *resources.AlertReconcilerImpl {
 	installationName := resources.InstallationNames[installType]
 
 	alertNamePrefix := "marin3r-"
@@ -118,3 +128,55 @@ func (r *Reconciler) newAlertReconciler(logger l.Logger, installType string, nam
 		},
 	}
 }
+
+// AlertReconcilers returns the reconcilers of all the alerts created for marin3r, including the API usage
+// alerts of the quota and the alerts of the rate limit redis instance, so that they can be rendered
+// without a cluster. The API usage alerts the bootstrap stage configures by default are used unless
+// the alerts were read from the cluster
+func (r *Reconciler) AlertReconcilers(inputs resources.AlertInputs, productConfig quota.ProductConfig) ([]*resources.AlertReconcilerImpl, error) {
+	namespace := config.GetOboNamespace(r.installation.Namespace)
+	r.RateLimitConfig = productConfig.GetRateLimitConfig()
+	if r.AlertsConfig == nil {
+		r.AlertsConfig = marin3rconfig.DefaultAlertConfig()
+	}
+
+	apiUsageAlertsReconciler, err := r.newAlertsReconciler(inputs.GrafanaConsoleURL+rateLimitingDashboardPath, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	rejectedRequestsAlertReconciler, err := r.newRejectedRequestsAlertsReconciler(r.log, r.installation.Spec.Type, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*resources.AlertReconcilerImpl{
+		r.newAlertReconciler(r.log, r.installation.Spec.Type, namespace),
+		apiUsageAlertsReconciler,
+		rejectedRequestsAlertReconciler,
+		resources.RedisAlertsReconciler(r.installation, constants.RateLimitRedisPrefix+r.installation.Name, defaultInstallationNamespace, r.log),
+	}, nil
+}
+
+// deleteAlerts deletes the alerts of marin3r from the observability namespace, which is kept when marin3r is
+// disabled on the installation
+func (r *Reconciler) deleteAlerts(ctx context.Context, client k8sclient.Client) error {
+	namespace := config.GetOboNamespace(r.installation.Namespace)
+
+	alertsConfig, err := marin3rconfig.GetAlertConfig(ctx, client, r.installation.Namespace)
+	if err != nil && !k8serr.IsNotFound(err) {
+		return err
+	}
+
+	alerts := r.newAlertReconciler(r.log, r.installation.Spec.Type, namespace).Alerts
+	alerts = append(alerts, resources.AlertConfiguration{AlertName: rejectedRequestsAlertName, Namespace: namespace, Rules: []monv1.Rule{}})
+	for alertName := range alertsConfig {
+		alerts = append(alerts, resources.AlertConfiguration{
+			AlertName: apiUsageAlertName(alertName, r.installation.Spec.Type),
+			Namespace: namespace,
+			Rules:     []monv1.Rule{},
+		})
+	}
+
+	return resources.DeleteAlerts(ctx, client, alerts)
+}
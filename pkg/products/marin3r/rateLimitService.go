@@ -6,8 +6,10 @@ import (
 	"fmt"
 	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
 	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/metrics"
 	marin3rconfig "github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
 	"github.com/integr8ly/integreatly-operator/pkg/resources"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/images"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/ratelimit"
 	"gopkg.in/yaml.v2"
@@ -23,6 +25,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sort"
 	"strconv"
+	"sync"
+	"time"
 )
 
 const (
@@ -36,7 +40,12 @@ const (
 	multitenantDescriptorValue    = "per-mt-limit"
 	RateLimitingConfigMapName     = "ratelimit-config"
 	RateLimitingConfigMapDataName = "apicast-ratelimiting.yaml"
-	rateLimitImage                = "registry.redhat.io/rhcl-1/limitador-rhel9@sha256:aff28d76f9cfeefafd6b652e055bb25e1a2bf250e062e3e9b5e54db873f9eeb7"
+	// the counters are read on the marin3r reconcile, which runs much more often than they are scraped
+	rateLimitCounterReportInterval = time.Minute
+	// tenantUsageLimitName names the limit counting the authorized requests of each tenant. It never rejects a
+	// request, its window only bounds how long a counter lives before it starts again from zero
+	tenantUsageLimitName    = "tenant-usage"
+	tenantUsageLimitSeconds = 24 * 60 * 60
 )
 
 type RateLimitServiceReconciler struct {
@@ -46,6 +55,8 @@ type RateLimitServiceReconciler struct {
 	RateLimitConfig marin3rconfig.RateLimitConfig
 	PodExecutor     resources.PodExecutorInterface
 	ConfigManager   config.ConfigReadWriter
+	LimitadorClient LimitadorClientInterface
+	Descriptors     []marin3rconfig.RateLimitDescriptorConfig
 }
 
 func NewRateLimitServiceReconciler(config marin3rconfig.RateLimitConfig, installation *integreatlyv1alpha1.RHMI, namespace, redisSecretName string, podExecutor resources.PodExecutorInterface, configManager config.ConfigReadWriter) *RateLimitServiceReconciler {
@@ -56,10 +67,12 @@ func NewRateLimitServiceReconciler(config marin3rconfig.RateLimitConfig, install
 		RedisSecretName: redisSecretName,
 		PodExecutor:     podExecutor,
 		ConfigManager:   configManager,
+		LimitadorClient: NewLimitadorClient(namespace),
 	}
 }
 
 type limitadorLimit struct {
+	Name       string   `yaml:"name,omitempty" json:"name,omitempty"`
 	Namespace  string   `yaml:"namespace" json:"namespace"`
 	MaxValue   uint32   `yaml:"max_value" json:"max_value"`
 	Seconds    uint64   `yaml:"seconds" json:"seconds"`
@@ -98,6 +111,75 @@ func (r *RateLimitServiceReconciler) ReconcileRateLimitService(ctx context.Conte
 
 }
 
+// WithDescriptors adds a limit for each per API descriptor to the limitador configuration
+func (r *RateLimitServiceReconciler) WithDescriptors(descriptors []marin3rconfig.RateLimitDescriptorConfig) *RateLimitServiceReconciler {
+	r.Descriptors = descriptors
+	return r
+}
+
+// ReportRateLimitCounters reads the live counters of the RHOAM rate limits from
+// limitador and exposes them as metrics. Nothing is reported until the rate
+// limit service has a ready replica, and the counters are read at most once
+// every rateLimitCounterReportInterval
+func (r *RateLimitServiceReconciler) ReportRateLimitCounters(ctx context.Context, client k8sclient.Client) error {
+	if !counterReports.due(r.Namespace, time.Now()) {
+		return nil
+	}
+
+	deployment := &appsv1.Deployment{}
+	if err := client.Get(ctx, k8sclient.ObjectKey{Name: quota.RateLimitName, Namespace: r.Namespace}, deployment); err != nil {
+		if k8sError.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if deployment.Status.ReadyReplicas == 0 {
+		return nil
+	}
+
+	// failed reads are throttled too, so an unreachable limitador doesn't hold up every reconcile with its retries
+	counterReports.attempted(r.Namespace, time.Now())
+	counters, err := r.LimitadorClient.GetCounters(ctx, ratelimit.RateLimitDomain)
+	if err != nil {
+		return fmt.Errorf("failed to get rate limit counters: %w", err)
+	}
+
+	metrics.ResetRateLimitCounters()
+	metrics.ResetTenantAuthorizedRequests()
+	for _, counter := range counters {
+		// limitador's own metrics don't identify the tenant, the tenant usage report is built from these counters
+		if counter.Limit.Name == tenantUsageLimitName {
+			metrics.SetTenantAuthorizedRequests(counter.SetVariables[headerKey], float64(int64(counter.Limit.MaxValue)-counter.Remaining))
+			continue
+		}
+		metrics.SetRateLimitCounter(counter.Limit.Namespace, counter.Descriptor(), float64(counter.Limit.MaxValue), float64(counter.Remaining))
+	}
+
+	return nil
+}
+
+// counterReportThrottle remembers when the counters of each rate limit service were last read. It outlives
+// the reconcilers, which are created for every reconcile of the installation
+type counterReportThrottle struct {
+	mu       sync.Mutex
+	interval time.Duration
+	last     map[string]time.Time
+}
+
+var counterReports = &counterReportThrottle{interval: rateLimitCounterReportInterval, last: map[string]time.Time{}}
+
+func (t *counterReportThrottle) due(namespace string, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return now.Sub(t.last[namespace]) >= t.interval
+}
+
+func (t *counterReportThrottle) attempted(namespace string, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.last[namespace] = now
+}
+
 func (r *RateLimitServiceReconciler) reconcileConfigMap(ctx context.Context, client k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
 	var err error
 
@@ -174,6 +256,11 @@ func (r *RateLimitServiceReconciler) reconcileDeployment(ctx context.Context, cl
 		}
 	}
 
+	rateLimitImage, err := images.Resolve(ctx, client, r.Installation.Namespace, images.Limitador)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to resolve the rate limit image: %w", err)
+	}
+
 	_, err = controllerutil.CreateOrUpdate(ctx, client, deployment, func() error {
 		limitsFile := fmt.Sprintf("apicast-ratelimiting-%s.yaml", r.uniqueKey(r.RateLimitConfig, currentRateLimit))
 
@@ -182,6 +269,7 @@ func (r *RateLimitServiceReconciler) reconcileDeployment(ctx context.Context, cl
 		}
 
 		deployment.Labels["app"] = quota.RateLimitName
+		images.SetResolvedImage(deployment, images.Limitador, rateLimitImage)
 		deployment.Spec.Selector = &v1.LabelSelector{
 			MatchLabels: map[string]string{
 				"app": quota.RateLimitName,
@@ -529,24 +617,72 @@ func (r *RateLimitServiceReconciler) getMultitenantRHOAMLimitadorSetting(ctx con
 				headerKey,
 			},
 		},
+		{
+			Name:      tenantUsageLimitName,
+			Namespace: ratelimit.RateLimitDomain,
+			MaxValue:  math.MaxUint32,
+			Seconds:   tenantUsageLimitSeconds,
+			Conditions: []string{
+				fmt.Sprintf("%s == %s", headerMatch, multitenantDescriptorValue),
+			},
+			Variables: []string{
+				headerKey,
+			},
+		},
 	}, nil
 }
 
 func (r *RateLimitServiceReconciler) getLimitadorSetting(ctx context.Context, client k8sclient.Client) ([]limitadorLimit, error) {
+	var limitadorLimit []limitadorLimit
+	var err error
 	if !integreatlyv1alpha1.IsRHOAMMultitenant(integreatlyv1alpha1.InstallationType(r.Installation.Spec.Type)) {
-		limitadorLimit, err := r.getRHOAMLimitadorSetting()
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshall rate limit config: %v", err)
-		}
-		return limitadorLimit, nil
+		limitadorLimit, err = r.getRHOAMLimitadorSetting()
+	} else {
+		limitadorLimit, err = r.getMultitenantRHOAMLimitadorSetting(ctx, client)
 	}
-
-	limitadorLimit, err := r.getMultitenantRHOAMLimitadorSetting(ctx, client)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshall rate limit config: %v", err)
 	}
 
-	return limitadorLimit, nil
+	descriptorLimits, err := r.getDescriptorLimitadorSetting()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshall rate limit descriptors: %v", err)
+	}
+
+	return append(limitadorLimit, descriptorLimits...), nil
+}
+
+// getDescriptorLimitadorSetting returns the limits matching the per API
+// descriptors rendered into the apicast envoy config. Each limit reads the
+// descriptor envoy sends for its API, at marin3rconfig.DescriptorIndex
+func (r *RateLimitServiceReconciler) getDescriptorLimitadorSetting() ([]limitadorLimit, error) {
+	limits := []limitadorLimit{}
+	for i, descriptor := range r.Descriptors {
+		unitInSeconds, err := r.getUnitInSeconds(descriptor.Unit)
+		if err != nil {
+			return nil, err
+		}
+
+		index := marin3rconfig.DescriptorIndex(i)
+		conditions := []string{
+			fmt.Sprintf(`descriptors[%d]['%s'] == "%s"`, index, marin3rconfig.DescriptorMatchKey, descriptor.Name),
+		}
+		variables := []string{}
+		if descriptor.CredentialHeader != "" {
+			// requests without the credential header are only counted against the SKU wide limit
+			conditions = append(conditions, fmt.Sprintf(`'%s' in descriptors[%d]`, descriptor.CredentialHeader, index))
+			variables = append(variables, fmt.Sprintf("descriptors[%d]['%s']", index, descriptor.CredentialHeader))
+		}
+
+		limits = append(limits, limitadorLimit{
+			Namespace:  ratelimit.RateLimitDomain,
+			MaxValue:   descriptor.RequestsPerUnit,
+			Seconds:    unitInSeconds,
+			Conditions: conditions,
+			Variables:  variables,
+		})
+	}
+	return limits, nil
 }
 
 func (r *RateLimitServiceReconciler) differentLimitSettings(redisLimits []limitadorLimit, currentLimits []limitadorLimit) bool {
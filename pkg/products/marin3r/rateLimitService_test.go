@@ -3,7 +3,9 @@ package marin3r
 import (
 	"context"
 	"fmt"
+	"math"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/integr8ly/integreatly-operator/pkg/config"
@@ -14,6 +16,9 @@ import (
 
 	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
 	marin3rconfig "github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -468,6 +473,7 @@ func TestRateLimitServiceReconciler_getLimitadorSetting(t *testing.T) {
 		Installation    *integreatlyv1alpha1.RHMI
 		RateLimitConfig marin3rconfig.RateLimitConfig
 		PodExecutor     resources.PodExecutorInterface
+		Descriptors     []marin3rconfig.RateLimitDescriptorConfig
 	}
 	type args struct {
 		ctx    context.Context
@@ -559,6 +565,18 @@ func TestRateLimitServiceReconciler_getLimitadorSetting(t *testing.T) {
 						headerKey,
 					},
 				},
+				{
+					Name:      tenantUsageLimitName,
+					Namespace: ratelimit.RateLimitDomain,
+					MaxValue:  math.MaxUint32,
+					Seconds:   tenantUsageLimitSeconds,
+					Conditions: []string{
+						fmt.Sprintf("%s == %s", headerMatch, multitenantDescriptorValue),
+					},
+					Variables: []string{
+						headerKey,
+					},
+				},
 			},
 		},
 		{
@@ -588,6 +606,7 @@ func TestRateLimitServiceReconciler_getLimitadorSetting(t *testing.T) {
 				Installation:    tt.fields.Installation,
 				RateLimitConfig: tt.fields.RateLimitConfig,
 				PodExecutor:     tt.fields.PodExecutor,
+				Descriptors:     tt.fields.Descriptors,
 			}
 			got, err := r.getLimitadorSetting(tt.args.ctx, tt.args.client)
 			if (err != nil) != tt.wantErr {
@@ -600,3 +619,95 @@ func TestRateLimitServiceReconciler_getLimitadorSetting(t *testing.T) {
 		})
 	}
 }
+
+func TestDescriptorLimitadorSettingMatchesEnvoyDescriptors(t *testing.T) {
+	env, err := cel.NewEnv(cel.Variable("descriptors", cel.ListType(cel.MapType(cel.StringType, cel.StringType))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	evaluate := func(expression string, descriptors []map[string]string) ref.Val {
+		ast, issues := env.Compile(expression)
+		if issues != nil && issues.Err() != nil {
+			t.Fatalf("invalid expression %s: %v", expression, issues.Err())
+		}
+		program, err := env.Program(ast)
+		if err != nil {
+			t.Fatal(err)
+		}
+		value, _, err := program.Eval(map[string]interface{}{"descriptors": descriptors})
+		if err != nil {
+			t.Fatalf("failed to evaluate %s against %v: %v", expression, descriptors, err)
+		}
+		return value
+	}
+
+	r := &RateLimitServiceReconciler{
+		Descriptors: []marin3rconfig.RateLimitDescriptorConfig{
+			{Name: "orders", PathPrefix: "/orders", Unit: "minute", RequestsPerUnit: 100},
+			{Name: "payments-per-key", Host: "payments.example.com", CredentialHeader: "user_key", Unit: "hour", RequestsPerUnit: 5},
+		},
+	}
+	limits, err := r.getDescriptorLimitadorSetting()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the descriptors are laid out the way the apicast envoy config sends them, see marin3rconfig.DescriptorIndex
+	skuWide := map[string]string{genericKey: ratelimit.RateLimitDescriptorValue}
+	tenant := map[string]string{headerMatch: multitenantDescriptorValue, headerKey: "acme"}
+	scenarios := []struct {
+		name        string
+		descriptors []map[string]string
+		// wantCounters holds the counter key of each limit that applies, limits without variables have an empty key
+		wantCounters map[uint32]string
+	}{
+		{
+			name:         "request matching the first descriptor",
+			descriptors:  []map[string]string{skuWide, {"header_match": "orders"}, {"header_match": "unmatched:payments-per-key"}},
+			wantCounters: map[uint32]string{100: ""},
+		},
+		{
+			name:         "request matching the second descriptor with a credential",
+			descriptors:  []map[string]string{skuWide, {"header_match": "unmatched:orders"}, {"header_match": "payments-per-key", "user_key": "key-a"}, tenant},
+			wantCounters: map[uint32]string{5: "key-a"},
+		},
+		{
+			name:         "request matching the second descriptor without a credential",
+			descriptors:  []map[string]string{skuWide, {"header_match": "unmatched:orders"}, {"header_match": "payments-per-key"}},
+			wantCounters: map[uint32]string{},
+		},
+		{
+			name:         "request matching both descriptors",
+			descriptors:  []map[string]string{skuWide, {"header_match": "orders"}, {"header_match": "payments-per-key", "user_key": "key-b"}, tenant},
+			wantCounters: map[uint32]string{100: "", 5: "key-b"},
+		},
+		{
+			name:         "request matching no descriptor",
+			descriptors:  []map[string]string{skuWide, {"header_match": "unmatched:orders"}, {"header_match": "unmatched:payments-per-key"}},
+			wantCounters: map[uint32]string{},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			counters := map[uint32]string{}
+			for _, limit := range limits {
+				applies := true
+				for _, condition := range limit.Conditions {
+					applies = applies && evaluate(condition, scenario.descriptors) == types.True
+				}
+				if !applies {
+					continue
+				}
+				key := []string{}
+				for _, variable := range limit.Variables {
+					key = append(key, fmt.Sprint(evaluate(variable, scenario.descriptors).Value()))
+				}
+				counters[limit.MaxValue] = strings.Join(key, ",")
+			}
+			if !reflect.DeepEqual(counters, scenario.wantCounters) {
+				t.Errorf("expected counters %v, got %v", scenario.wantCounters, counters)
+			}
+		})
+	}
+}
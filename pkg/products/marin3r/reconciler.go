@@ -3,6 +3,7 @@ package marin3r
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/integr8ly/integreatly-operator/pkg/products/grafana"
 
@@ -48,10 +49,12 @@ type Reconciler struct {
 	Config          *config.Marin3r
 	RateLimitConfig marin3rconfig.RateLimitConfig
 	AlertsConfig    map[string]*marin3rconfig.AlertConfig
+	Descriptors     []marin3rconfig.RateLimitDescriptorConfig
 	installation    *integreatlyv1alpha1.RHMI
 	mpm             marketplace.MarketplaceInterface
 	log             l.Logger
 	recorder        record.EventRecorder
+	prometheus      PrometheusQueryAPI
 }
 
 func (r *Reconciler) GetPreflightObject(ns string) k8sclient.Object {
@@ -84,7 +87,7 @@ func NewReconciler(configManager config.ConfigReadWriter, installation *integrea
 		productConfig.SetOperatorNamespace(productConfig.GetNamespace() + "-operator")
 	}
 
-	if err := configManager.WriteConfig(productConfig); err != nil {
+	if err := configManager.WriteConfig(productConfig, "marin3r.NewReconciler"); err != nil {
 		return nil, fmt.Errorf("error writing marin3r config : %w", err)
 	}
 
@@ -121,6 +124,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to delete discovery service: %v", err)
 		}
 
+		if err := r.deleteAlerts(ctx, client); err != nil {
+			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to delete marin3r alerts: %v", err)
+		}
+
 		phase, err = resources.RemoveNamespace(ctx, installation, client, productNamespace, r.log)
 		if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
 			return phase, err
@@ -151,6 +158,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 	}
 	r.AlertsConfig = alertsConfig
 
+	descriptors, err := marin3rconfig.GetRateLimitDescriptorConfig(ctx, client, r.installation.Namespace)
+	if err != nil {
+		events.HandleError(r.recorder, installation, phase, "Failed to obtain rate limit descriptors config", err)
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
+	r.Descriptors = descriptors
+
 	phase, err = r.ReconcileNamespace(ctx, operatorNamespace, installation, client, r.log)
 	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
 		events.HandleError(r.recorder, installation, phase, fmt.Sprintf("Failed to reconcile %s ns", operatorNamespace), err)
@@ -215,8 +229,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, nil
 	}
 
-	phase, err = NewRateLimitServiceReconciler(r.RateLimitConfig, installation, productNamespace, externalRedisSecretName, resources.NewPodExecutor(r.log), r.ConfigManager).
-		ReconcileRateLimitService(ctx, client, productConfig)
+	rateLimitServiceReconciler := NewRateLimitServiceReconciler(r.RateLimitConfig, installation, productNamespace, externalRedisSecretName, resources.NewPodExecutor(r.log), r.ConfigManager).
+		WithDescriptors(r.Descriptors)
+	phase, err = rateLimitServiceReconciler.ReconcileRateLimitService(ctx, client, productConfig)
 	if err != nil {
 		events.HandleError(r.recorder, installation, phase, "Failed to reconcile rate limit service", err)
 		return phase, err
@@ -225,6 +240,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, nil
 	}
 
+	if err := rateLimitServiceReconciler.ReportRateLimitCounters(ctx, client); err != nil {
+		r.log.Warningf("Failed to report rate limit counters", l.Fields{"error": err})
+	}
+
 	phase, err = r.reconcileServiceMonitor(ctx, client, productNamespace)
 	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
 		events.HandleError(r.recorder, installation, phase, "Failed to reconcile Prometheus service monitor", err)
@@ -254,6 +273,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, err
 	}
 
+	if integreatlyv1alpha1.IsRHOAMMultitenant(integreatlyv1alpha1.InstallationType(installation.Spec.Type)) {
+		if err := r.reconcileTenantUsageReport(ctx, client, time.Now()); err != nil {
+			r.log.Warningf("Failed to reconcile tenant usage report", l.Fields{"error": err})
+		}
+	}
+
 	productStatus.Host = r.Config.GetHost()
 	productStatus.Version = r.Config.GetProductVersion()
 	productStatus.OperatorVersion = r.Config.GetOperatorVersion()
@@ -265,26 +290,29 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 
 func (r *Reconciler) reconcileAlerts(ctx context.Context, client k8sclient.Client, installation *integreatlyv1alpha1.RHMI, namespace string) (integreatlyv1alpha1.StatusPhase, error) {
 	if !resources.IsInProw(installation) {
-		grafanaConsoleURL, err := grafana.GetGrafanaConsoleURL(ctx, client, installation)
-		if err != nil {
-			if installStage, ok := installation.Status.Stages[integreatlyv1alpha1.InstallStage]; ok {
-				if installStage.Products != nil {
-					grafanaProduct, grafanaProductExists := installStage.Products[integreatlyv1alpha1.ProductGrafana]
-					// Ignore the Forbidden and NotFound errors if Grafana is not installed yet
-					if !grafanaProductExists ||
-						(grafanaProduct.Phase != integreatlyv1alpha1.PhaseCompleted &&
-							(k8serr.IsForbidden(err) || k8serr.IsNotFound(err))) {
-
-						r.log.Info("Failed to get Grafana console URL. Awaiting completion of Grafana installation")
-						return integreatlyv1alpha1.PhaseInProgress, nil
+		grafanaDashboardURL := ""
+		if !installation.IsProductDisabled(integreatlyv1alpha1.ProductGrafana) {
+			grafanaConsoleURL, err := grafana.GetGrafanaConsoleURL(ctx, client, installation)
+			if err != nil {
+				if installStage, ok := installation.Status.Stages[integreatlyv1alpha1.InstallStage]; ok {
+					if installStage.Products != nil {
+						grafanaProduct, grafanaProductExists := installStage.Products[integreatlyv1alpha1.ProductGrafana]
+						// Ignore the Forbidden and NotFound errors if Grafana is not installed yet
+						if !grafanaProductExists ||
+							(grafanaProduct.Phase != integreatlyv1alpha1.PhaseCompleted &&
+								(k8serr.IsForbidden(err) || k8serr.IsNotFound(err))) {
+
+							r.log.Info("Failed to get Grafana console URL. Awaiting completion of Grafana installation")
+							return integreatlyv1alpha1.PhaseInProgress, nil
+						}
 					}
 				}
+				r.log.Error("failed to get Grafana console URL", nil, err)
+				return integreatlyv1alpha1.PhaseFailed, err
 			}
-			r.log.Error("failed to get Grafana console URL", nil, err)
-			return integreatlyv1alpha1.PhaseFailed, err
+			grafanaDashboardURL = grafanaConsoleURL + rateLimitingDashboardPath
 		}
 
-		grafanaDashboardURL := fmt.Sprintf("%s/d/66ab72e0d012aacf34f907be9d81cd9e/rate-limiting", grafanaConsoleURL)
 		alertReconciler, err := r.newAlertsReconciler(grafanaDashboardURL, namespace)
 		if err != nil {
 			return integreatlyv1alpha1.PhaseFailed, err
@@ -359,12 +387,6 @@ func (r *Reconciler) reconcileRedis(ctx context.Context, client k8sclient.Client
 		return phase, nil
 	}
 
-	// create Redis Cpu Usage High alert
-	err = resources.CreateRedisCpuUsageAlerts(ctx, client, r.installation, rateLimitRedis, r.log)
-	if err != nil {
-		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to create rate limit redis prometheus Cpu usage high alerts for threescale: %s", err)
-	}
-
 	return phase, nil
 }
 
@@ -443,13 +465,18 @@ func (r *Reconciler) reconcileSubscription(ctx context.Context, serverClient k8s
 }
 
 func (r *Reconciler) preUpgradeBackupExecutor() backup.BackupExecutor {
-	if r.installation.Spec.UseClusterStorage != "false" {
+	return PreUpgradeBackupExecutor(r.installation)
+}
+
+// PreUpgradeBackupExecutor returns the executor of the backups taken before marin3r is upgraded
+func PreUpgradeBackupExecutor(installation *integreatlyv1alpha1.RHMI) backup.BackupExecutor {
+	if installation.Spec.UseClusterStorage != "false" {
 		return backup.NewNoopBackupExecutor()
 	}
 
 	return backup.NewAWSBackupExecutor(
-		r.installation.Namespace,
-		fmt.Sprintf("%s%s", constants.RateLimitRedisPrefix, r.installation.Name),
+		installation.Namespace,
+		fmt.Sprintf("%s%s", constants.RateLimitRedisPrefix, installation.Name),
 		backup.RedisSnapshotType,
 	)
 }
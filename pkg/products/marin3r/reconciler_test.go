@@ -80,7 +80,7 @@ func getBasicConfig() *config.ConfigReadWriterMock {
 				"HOST":      "threescale.openshift-cluster.com",
 			}), nil
 		},
-		WriteConfigFunc: func(config config.ConfigReadable) error {
+		WriteConfigFunc: func(config config.ConfigReadable, writer string) error {
 			return nil
 		},
 	}
@@ -167,6 +167,20 @@ func TestAlertCreation(t *testing.T) {
 			Recorder:     setupRecorder(),
 			want:         integreatlyv1alpha1.PhaseInProgress,
 		},
+		{
+			name: "returns expected alerts without the dashboard link when grafana is disabled",
+			serverClient: func() k8sclient.Client {
+				return utils.NewTestClient(scheme, getRateLimitConfigMap())
+			},
+			installation: func() *integreatlyv1alpha1.RHMI {
+				installation := getBasicInstallation()
+				installation.Spec.DisabledProducts = []integreatlyv1alpha1.ProductName{integreatlyv1alpha1.ProductGrafana}
+				return installation
+			}(),
+			FakeConfig: getBasicConfig(),
+			Recorder:   setupRecorder(),
+			want:       integreatlyv1alpha1.PhaseCompleted,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -10,11 +10,13 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+const rejectedRequestsAlertName = "marin3r-rejected-requests"
+
 const rejectedRequestsAlertExpr = "abs(clamp_min(increase(limited_calls[1m]) - %f, 0) / (sum(increase(authorized_calls[1m])) + sum(increase(limited_calls[1m]))) - (increase(limited_calls[1m]) / (sum(increase(authorized_calls[1m])) + sum(increase(limited_calls[1m]))))) > 0.3"
 
-func (r *Reconciler) newRejectedRequestsAlertsReconciler(logger l.Logger, installType, ns string) (resources.AlertReconciler, error) {
+func (r *Reconciler) newRejectedRequestsAlertsReconciler(logger l.Logger, installType, ns string) (*resources.AlertReconcilerImpl, error) {
 	installationName := resources.InstallationNames[installType]
-	alertName := "marin3r-rejected-requests"
+	alertName := rejectedRequestsAlertName
 
 	limitPerMinute, err := config.ConvertRate(
 		r.RateLimitConfig.Unit,
@@ -39,6 +41,7 @@ func (r *Reconciler) newRejectedRequestsAlertsReconciler(logger l.Logger, instal
 						Alert: "RHOAMApiUsageRejectedRequestsMismatch",
 						Annotations: map[string]string{
 							"message": "The volume of rejected requests doesn't match the expected volume given the incoming requests and the configuration",
+							"sop_url": resources.SopUrlRHOAMApiUsageRejectedRequestsMismatch,
 						},
 						Expr:   intstr.FromString(fmt.Sprintf(rejectedRequestsAlertExpr, limitPerMinute)),
 						Labels: map[string]string{"severity": "info", "product": installationName},
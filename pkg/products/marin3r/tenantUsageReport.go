@@ -0,0 +1,223 @@
+package marin3r
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/integr8ly/integreatly-operator/pkg/metrics"
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	TenantUsageReportConfigMapName = "tenant-usage-report"
+	TenantUsageReportJSONKey       = "report.json"
+	TenantUsageReportCSVKey        = "report.csv"
+	tenantUsageGeneratedAtKey      = "generatedAt"
+	tenantUsageReportInterval      = time.Hour
+	// tenantUsageMetric is exported by ReportRateLimitCounters from the counters of the tenant usage limit
+	tenantUsageMetric = "rhoam_tenant_authorized_requests"
+	tenantUsageLabel  = "tenant"
+
+	UsagePeriodHourly  = "hourly"
+	UsagePeriodDaily   = "daily"
+	UsagePeriodMonthly = "monthly"
+)
+
+// PrometheusQueryAPI is the subset of the prometheus API used to build the tenant usage report
+type PrometheusQueryAPI interface {
+	Query(ctx context.Context, query string, ts time.Time, opts ...prometheusv1.Option) (model.Value, prometheusv1.Warnings, error)
+}
+
+// TenantUsage is the number of requests of a tenant in a reporting period
+type TenantUsage struct {
+	Period             string    `json:"period"`
+	Start              time.Time `json:"start"`
+	End                time.Time `json:"end"`
+	Complete           bool      `json:"complete"`
+	Tenant             string    `json:"tenant"`
+	AuthorizedRequests int64     `json:"authorizedRequests"`
+}
+
+type usagePeriod struct {
+	name string
+	// start returns the start of the period containing t
+	start func(t time.Time) time.Time
+	// previous returns the start of the period before the one starting at t
+	previous func(t time.Time) time.Time
+}
+
+var usagePeriods = []usagePeriod{
+	{
+		name:     UsagePeriodHourly,
+		start:    func(t time.Time) time.Time { return t.Truncate(time.Hour) },
+		previous: func(t time.Time) time.Time { return t.Add(-time.Hour) },
+	},
+	{
+		name:     UsagePeriodDaily,
+		start:    func(t time.Time) time.Time { return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC) },
+		previous: func(t time.Time) time.Time { return t.AddDate(0, 0, -1) },
+	},
+	{
+		name:     UsagePeriodMonthly,
+		start:    func(t time.Time) time.Time { return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC) },
+		previous: func(t time.Time) time.Time { return t.AddDate(0, -1, 0) },
+	},
+}
+
+// reconcileTenantUsageReport aggregates the authorized requests of each tenant
+// into the tenant usage report ConfigMap. Limitador's own metrics only carry
+// the limit namespace, so the requests are read from the counters of the
+// tenant usage limit the operator exports. The report covers the previous
+// and the current hour, day and month and is refreshed hourly
+func (r *Reconciler) reconcileTenantUsageReport(ctx context.Context, client k8sclient.Client, now time.Time) error {
+	now = now.UTC()
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      TenantUsageReportConfigMapName,
+			Namespace: r.installation.Namespace,
+		},
+	}
+
+	err := client.Get(ctx, k8sclient.ObjectKeyFromObject(cm), cm)
+	if err != nil && !k8serr.IsNotFound(err) {
+		return err
+	}
+	if generatedAt, err := time.Parse(time.RFC3339, cm.Data[tenantUsageGeneratedAtKey]); err == nil && now.Sub(generatedAt) < tenantUsageReportInterval {
+		return nil
+	}
+
+	if r.prometheus == nil {
+		prometheus, err := metrics.GetOboPrometheusApiClient(r.installation.Namespace)
+		if err != nil {
+			return fmt.Errorf("failed to create prometheus client: %w", err)
+		}
+		r.prometheus = prometheus
+	}
+
+	usage, err := getTenantUsage(ctx, r.prometheus, now)
+	if err != nil {
+		return err
+	}
+
+	reportJSON, err := json.Marshal(usage)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tenant usage report: %w", err)
+	}
+	reportCSV, err := tenantUsageToCSV(usage)
+	if err != nil {
+		return err
+	}
+
+	_, err = controllerutil.CreateOrUpdate(ctx, client, cm, func() error {
+		if cm.Labels == nil {
+			cm.Labels = map[string]string{}
+		}
+		cm.Labels["integreatly"] = "true"
+		cm.Data = map[string]string{
+			tenantUsageGeneratedAtKey: now.Format(time.RFC3339),
+			TenantUsageReportJSONKey:  string(reportJSON),
+			TenantUsageReportCSVKey:   reportCSV,
+		}
+		return nil
+	})
+	return err
+}
+
+func getTenantUsage(ctx context.Context, prometheus PrometheusQueryAPI, now time.Time) ([]TenantUsage, error) {
+	usage := []TenantUsage{}
+
+	for _, period := range usagePeriods {
+		current := period.start(now)
+		windows := []struct {
+			start, end time.Time
+			complete   bool
+		}{
+			{start: period.previous(current), end: current, complete: true},
+			{start: current, end: now, complete: false},
+		}
+
+		for _, window := range windows {
+			if !window.end.After(window.start) {
+				continue
+			}
+
+			authorized, err := queryTenantIncrease(ctx, prometheus, tenantUsageMetric, window.start, window.end)
+			if err != nil {
+				return nil, err
+			}
+
+			names := make([]string, 0, len(authorized))
+			for tenant := range authorized {
+				names = append(names, tenant)
+			}
+			sort.Strings(names)
+
+			for _, tenant := range names {
+				usage = append(usage, TenantUsage{
+					Period:             period.name,
+					Start:              window.start,
+					End:                window.end,
+					Complete:           window.complete,
+					Tenant:             tenant,
+					AuthorizedRequests: authorized[tenant],
+				})
+			}
+		}
+	}
+
+	return usage, nil
+}
+
+// queryTenantIncrease returns the increase of metric per tenant between start and end
+func queryTenantIncrease(ctx context.Context, prometheus PrometheusQueryAPI, metric string, start, end time.Time) (map[string]int64, error) {
+	query := fmt.Sprintf(`sum by (%s) (increase(%s{%s!=""}[%ds]))`, tenantUsageLabel, metric, tenantUsageLabel, int64(end.Sub(start).Seconds()))
+
+	result, _, err := prometheus.Query(ctx, query, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", metric, err)
+	}
+	vector, ok := result.(model.Vector)
+	if !ok {
+		return nil, fmt.Errorf("unexpected result type %s for query %s", result.Type(), query)
+	}
+
+	increase := map[string]int64{}
+	for _, sample := range vector {
+		increase[string(sample.Metric[tenantUsageLabel])] = int64(math.Round(float64(sample.Value)))
+	}
+	return increase, nil
+}
+
+func tenantUsageToCSV(usage []TenantUsage) (string, error) {
+	buf := &bytes.Buffer{}
+	writer := csv.NewWriter(buf)
+
+	records := [][]string{{"period", "start", "end", "complete", "tenant", "authorized_requests"}}
+	for _, u := range usage {
+		records = append(records, []string{
+			u.Period,
+			u.Start.Format(time.RFC3339),
+			u.End.Format(time.RFC3339),
+			strconv.FormatBool(u.Complete),
+			u.Tenant,
+			strconv.FormatInt(u.AuthorizedRequests, 10),
+		})
+	}
+	if err := writer.WriteAll(records); err != nil {
+		return "", fmt.Errorf("failed to write tenant usage report csv: %w", err)
+	}
+	return buf.String(), nil
+}
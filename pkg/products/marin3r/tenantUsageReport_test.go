@@ -0,0 +1,123 @@
+package marin3r
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/metrics"
+	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
+	"github.com/integr8ly/integreatly-operator/utils"
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type prometheusQueryMock struct {
+	queries []string
+}
+
+func (m *prometheusQueryMock) Query(_ context.Context, query string, _ time.Time, _ ...prometheusv1.Option) (model.Value, prometheusv1.Warnings, error) {
+	m.queries = append(m.queries, query)
+	return model.Vector{
+		{Metric: model.Metric{"tenant": "tenant-a"}, Value: 120.4},
+		{Metric: model.Metric{"tenant": "tenant-b"}, Value: 42},
+	}, nil, nil
+}
+
+func TestReconcileTenantUsageReport(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prometheus := &prometheusQueryMock{}
+	r := &Reconciler{
+		installation: &integreatlyv1alpha1.RHMI{
+			ObjectMeta: metav1.ObjectMeta{Name: "rhoam", Namespace: "redhat-test-operator"},
+		},
+		log:        l.NewLogger(),
+		prometheus: prometheus,
+	}
+	client := utils.NewTestClient(scheme)
+	now := time.Date(2024, time.March, 1, 0, 30, 0, 0, time.UTC)
+
+	if err := r.reconcileTenantUsageReport(context.TODO(), client, now); err != nil {
+		t.Fatal(err)
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := client.Get(context.TODO(), k8sclient.ObjectKey{Name: TenantUsageReportConfigMapName, Namespace: "redhat-test-operator"}, cm); err != nil {
+		t.Fatal(err)
+	}
+
+	var usage []TenantUsage
+	if err := json.Unmarshal([]byte(cm.Data[TenantUsageReportJSONKey]), &usage); err != nil {
+		t.Fatal(err)
+	}
+	// previous and current hour, previous and current day, previous and current month, two tenants each
+	if len(usage) != 12 {
+		t.Fatalf("expected 12 usage entries, got %d", len(usage))
+	}
+	previousMonth := usage[8]
+	if previousMonth.Period != UsagePeriodMonthly || !previousMonth.Complete || previousMonth.Tenant != "tenant-a" ||
+		!previousMonth.Start.Equal(time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)) || !previousMonth.End.Equal(time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected previous month usage %+v", previousMonth)
+	}
+	if previousMonth.AuthorizedRequests != 120 || usage[9].AuthorizedRequests != 42 {
+		t.Errorf("unexpected request counts %+v %+v", previousMonth, usage[9])
+	}
+	if prometheus.queries[0] != `sum by (tenant) (increase(rhoam_tenant_authorized_requests{tenant!=""}[3600s]))` {
+		t.Errorf("unexpected query %s", prometheus.queries[0])
+	}
+
+	lines := strings.Split(strings.TrimSpace(cm.Data[TenantUsageReportCSVKey]), "\n")
+	if len(lines) != 13 || lines[0] != "period,start,end,complete,tenant,authorized_requests" {
+		t.Fatalf("unexpected csv report %s", cm.Data[TenantUsageReportCSVKey])
+	}
+	if lines[1] != "hourly,2024-02-29T23:00:00Z,2024-03-01T00:00:00Z,true,tenant-a,120" {
+		t.Errorf("unexpected csv line %s", lines[1])
+	}
+
+	// the report is not regenerated within the refresh interval
+	queries := len(prometheus.queries)
+	if err := r.reconcileTenantUsageReport(context.TODO(), client, now.Add(30*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if len(prometheus.queries) != queries {
+		t.Fatalf("expected the report not to be regenerated")
+	}
+
+	if err := r.reconcileTenantUsageReport(context.TODO(), client, now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if len(prometheus.queries) == queries {
+		t.Fatalf("expected the report to be regenerated")
+	}
+}
+
+// TestTenantUsageMetric checks the report queries the metric and label the operator exports
+func TestTenantUsageMetric(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(metrics.TenantAuthorizedRequests)
+	metrics.ResetTenantAuthorizedRequests()
+	metrics.SetTenantAuthorizedRequests("tenant-a", 120)
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(families) != 1 || families[0].GetName() != tenantUsageMetric {
+		t.Fatalf("expected the %s metric to be exported, got %v", tenantUsageMetric, families)
+	}
+	labels := families[0].GetMetric()[0].GetLabel()
+	if len(labels) != 1 || labels[0].GetName() != tenantUsageLabel || labels[0].GetValue() != "tenant-a" {
+		t.Errorf("expected the %s label only, got %v", tenantUsageLabel, labels)
+	}
+}
@@ -12,7 +12,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
-func OboAlertsReconciler(logger l.Logger, installation *integreatlyv1alpha1.RHMI) resources.AlertReconciler {
+func OboAlertsReconciler(logger l.Logger, installation *integreatlyv1alpha1.RHMI) *resources.AlertReconcilerImpl {
 	installationName := resources.InstallationNames[installation.Spec.Type]
 	nsPrefix := installation.Spec.NamespacePrefix
 	namespace := config.GetOboNamespace(installation.Namespace)
@@ -245,6 +245,7 @@ func OboAlertsReconciler(logger l.Logger, installation *integreatlyv1alpha1.RHMI
 					Alert: "RHOAMCSVRequirementsNotMet",
 					Annotations: map[string]string{
 						"message": "RequirementsNotMet for CSV '{{$labels.name}}' in namespace '{{$labels.exported_namespace}}'. Phase is not succeeded",
+						"sop_url": resources.SopUrlRHOAMCSVRequirementsNotMet,
 					},
 					Expr:   intstr.FromString(fmt.Sprintf(`csv_succeeded{exported_namespace=~"%s.*"} != 1`, nsPrefix)),
 					For:    resources.DurationPtr("15m"),
@@ -369,6 +370,23 @@ func OboAlertsReconciler(logger l.Logger, installation *integreatlyv1alpha1.RHMI
 				},
 			},
 		},
+		{
+			AlertName: fmt.Sprintf("%s-sts-credentials-alerts", installationName),
+			Namespace: namespace,
+			GroupName: fmt.Sprintf("%s-sts-credentials.rules", installationName),
+			Rules: []monv1.Rule{
+				{
+					Alert: fmt.Sprintf("%sSTSCredentialsUnhealthy", strings.ToUpper(installationName)),
+					Annotations: map[string]string{
+						"sop_url": resources.SopUrlAlertsAndTroubleshooting,
+						"message": "The STS role used to provision the cloud resources can't be assumed with the web identity token. See the STSCredentials condition of the RHMI CR for details.",
+					},
+					Expr:   intstr.FromString("rhoam_sts_credentials_healthy == 0"),
+					For:    resources.DurationPtr("30m"),
+					Labels: map[string]string{"severity": "critical", "product": installationName},
+				},
+			},
+		},
 	}
 
 	if integreatlyv1alpha1.IsRHOAMMultitenant(integreatlyv1alpha1.InstallationType(installation.Spec.Type)) {
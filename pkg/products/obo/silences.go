@@ -0,0 +1,324 @@
+package obo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/resources"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// TransitionSilencesConfigMapName is the ConfigMap in the installation namespace that
+	// overrides the alerts silenced during upgrades and quota changes
+	TransitionSilencesConfigMapName = "transition-silences"
+	transitionSilencesConfigMapKey  = "alerts"
+
+	// silences created by the operator are identified by their creator
+	silenceCreator = "integreatly-operator"
+
+	// silences are created with a bounded duration and renewed while the transition is in
+	// progress, so they expire on their own if the operator stops reconciling
+	silenceDuration    = 2 * time.Hour
+	silenceRenewBefore = time.Hour
+
+	silenceStateActive  = "active"
+	silenceStatePending = "pending"
+
+	alertmanagerRequestTimeout = 10 * time.Second
+	alertmanagerRetries        = 3
+	alertmanagerRetryInterval  = 500 * time.Millisecond
+
+	// the silences are reconciled at once when the transition in progress changes, and
+	// otherwise at most every transitionSilencesInterval, well within silenceRenewBefore
+	transitionSilencesInterval = 5 * time.Minute
+	// transitionSilencesTimeout bounds the time a reconcile of the installation waits on alertmanager
+	transitionSilencesTimeout = 15 * time.Second
+)
+
+// DefaultTransitionSilencedAlerts are the alerts that fire while the pods of the products
+// restart during an upgrade or quota change
+var DefaultTransitionSilencedAlerts = []string{
+	"KubePodNotReady",
+	"ThreeScaleApicastProductionPod",
+	"ThreeScaleApicastStagingPod",
+	"ThreeScaleBackendListenerPod",
+	"ThreeScaleBackendWorkerPod",
+	"ThreeScaleSystemAppPod",
+	"ThreeScaleZyncPodAvailability",
+	"ThreeScaleZyncDatabasePodAvailability",
+	"RHOAMThreeScaleApicastProductionServiceEndpointDown",
+	"RHOAMThreeScaleApicastStagingServiceEndpointDown",
+	"RHOAMThreeScaleBackendListenerServiceEndpointDown",
+	"RHOAMThreeScaleSystemDeveloperServiceEndpointDown",
+	"RHOAMThreeScaleSystemMasterServiceEndpointDown",
+	"RHOAMThreeScaleSystemProviderServiceEndpointDown",
+	"RHOAMThreeScaleZyncServiceEndpointDown",
+	"RHOAMThreeScaleZyncDatabaseServiceEndpointDown",
+	"Marin3rEnvoyApicastProductionContainerDown",
+	"Marin3rEnvoyApicastStagingContainerDown",
+	"Marin3rRateLimitPod",
+	"Marin3rRateLimitServiceEndpointDown",
+	"Marin3rDiscoveryServiceEndpointDown",
+	"RHOAMRhssoKeycloakServiceEndpointDown",
+	"RHOAMRhssoKeycloakDiscoveryServiceEndpointDown",
+	"RHOAMUserRhssoKeycloakServiceEndpointDown",
+	"RHOAMUserRhssoKeycloakDiscoveryServiceEndpointDown",
+	"GrafanaServicePod",
+	"GrafanaServiceEndpointDown",
+}
+
+type AlertmanagerClientInterface interface {
+	ListSilences(ctx context.Context) ([]Silence, error)
+	CreateSilence(ctx context.Context, silence Silence) (string, error)
+	ExpireSilence(ctx context.Context, id string) error
+}
+
+// SilenceMatcher matches the label of an alert, as defined by the alertmanager v2 API
+type SilenceMatcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual bool   `json:"isEqual"`
+}
+
+type SilenceStatus struct {
+	State string `json:"state"`
+}
+
+// Silence is an alertmanager silence. Setting the ID of a silence when creating it
+// updates the existing silence instead
+type Silence struct {
+	ID        string           `json:"id,omitempty"`
+	Matchers  []SilenceMatcher `json:"matchers"`
+	StartsAt  time.Time        `json:"startsAt"`
+	EndsAt    time.Time        `json:"endsAt"`
+	CreatedBy string           `json:"createdBy"`
+	Comment   string           `json:"comment"`
+	Status    *SilenceStatus   `json:"status,omitempty"`
+}
+
+// IsActive returns true for silences that have not expired
+func (s Silence) IsActive() bool {
+	return s.Status == nil || s.Status.State == silenceStateActive || s.Status.State == silenceStatePending
+}
+
+// AlertmanagerClient talks to the alertmanager v2 API of the OBO monitoring stack
+type AlertmanagerClient struct {
+	*resources.RetryingAPIClient
+}
+
+var _ AlertmanagerClientInterface = &AlertmanagerClient{}
+
+func NewAlertmanagerClient(installationNamespace string) *AlertmanagerClient {
+	return &AlertmanagerClient{
+		RetryingAPIClient: resources.NewRetryingAPIClient("alertmanager API", config.GetOboAlertmanagerURL(installationNamespace), alertmanagerRequestTimeout, alertmanagerRetries, alertmanagerRetryInterval),
+	}
+}
+
+func (a *AlertmanagerClient) ListSilences(ctx context.Context) ([]Silence, error) {
+	silences := []Silence{}
+	if err := a.Do(ctx, http.MethodGet, "api/v2/silences", nil, &silences); err != nil {
+		return nil, err
+	}
+	return silences, nil
+}
+
+func (a *AlertmanagerClient) CreateSilence(ctx context.Context, silence Silence) (string, error) {
+	body, err := json.Marshal(silence)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode silence: %w", err)
+	}
+
+	created := struct {
+		SilenceID string `json:"silenceID"`
+	}{}
+	if err := a.Do(ctx, http.MethodPost, "api/v2/silences", body, &created); err != nil {
+		return "", err
+	}
+	return created.SilenceID, nil
+}
+
+func (a *AlertmanagerClient) ExpireSilence(ctx context.Context, id string) error {
+	return a.Do(ctx, http.MethodDelete, "api/v2/silence/"+url.PathEscape(id), nil, nil)
+}
+
+// GetTransitionSilencedAlerts returns the alerts to silence during upgrades and quota
+// changes, the defaults are used when the installation does not override them
+func GetTransitionSilencedAlerts(ctx context.Context, client k8sclient.Client, namespace string) ([]string, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := client.Get(ctx, types.NamespacedName{Name: TransitionSilencesConfigMapName, Namespace: namespace}, configMap); err != nil {
+		if k8serr.IsNotFound(err) {
+			return DefaultTransitionSilencedAlerts, nil
+		}
+		return nil, fmt.Errorf("failed to get %s config map: %w", TransitionSilencesConfigMapName, err)
+	}
+
+	value, ok := configMap.Data[transitionSilencesConfigMapKey]
+	if !ok {
+		return DefaultTransitionSilencedAlerts, nil
+	}
+	alerts := []string{}
+	if err := json.Unmarshal([]byte(value), &alerts); err != nil {
+		return nil, fmt.Errorf("failed to parse %s key of %s config map: %w", transitionSilencesConfigMapKey, TransitionSilencesConfigMapName, err)
+	}
+	return alerts, nil
+}
+
+// transitionComment describes the upgrade and quota change in progress, it is empty when
+// the installation is not changing
+func transitionComment(installation *integreatlyv1alpha1.RHMI) string {
+	transitions := []string{}
+	if installation.Status.ToVersion != "" {
+		transitions = append(transitions, fmt.Sprintf("upgrade to %s", installation.Status.ToVersion))
+	}
+	if installation.Status.ToQuota != "" {
+		transitions = append(transitions, fmt.Sprintf("quota change to %s", installation.Status.ToQuota))
+	}
+	if len(transitions) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Silenced by %s during the %s", silenceCreator, strings.Join(transitions, " and "))
+}
+
+func transitionMatchers(installation *integreatlyv1alpha1.RHMI, alerts []string) []SilenceMatcher {
+	names := make([]string, 0, len(alerts))
+	for _, alert := range alerts {
+		names = append(names, regexp.QuoteMeta(alert))
+	}
+	sort.Strings(names)
+
+	return []SilenceMatcher{
+		{Name: "alertname", Value: strings.Join(names, "|"), IsRegex: true, IsEqual: true},
+		{Name: "product", Value: resources.InstallationNames[installation.Spec.Type], IsEqual: true},
+	}
+}
+
+func matchersEqual(a, b []SilenceMatcher) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// silenceThrottle remembers when the silences of each installation were last reconciled and for
+// which transition. It outlives the reconciles of the installation
+type silenceThrottle struct {
+	mu       sync.Mutex
+	interval time.Duration
+	last     map[string]silenceAttempt
+}
+
+type silenceAttempt struct {
+	comment string
+	at      time.Time
+}
+
+var transitionSilences = &silenceThrottle{interval: transitionSilencesInterval, last: map[string]silenceAttempt{}}
+
+// attempt returns true and records the attempt when the transition changed since the last
+// attempt or the interval passed
+func (t *silenceThrottle) attempt(namespace, comment string, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	last, ok := t.last[namespace]
+	if ok && last.comment == comment && now.Sub(last.at) < t.interval {
+		return false
+	}
+	t.last[namespace] = silenceAttempt{comment: comment, at: now}
+	return true
+}
+
+// ReconcileTransitionSilences silences the configured alerts while an upgrade or quota change
+// is in progress and removes the silences once it completes. Every silence created, renewed or
+// removed is recorded as an event on the installation. Failed attempts are throttled too, so an
+// unreachable alertmanager doesn't hold up every reconcile with its retries
+func ReconcileTransitionSilences(ctx context.Context, serverClient k8sclient.Client, installation *integreatlyv1alpha1.RHMI, amClient AlertmanagerClientInterface, recorder record.EventRecorder, now time.Time) error {
+	comment := transitionComment(installation)
+	if !transitionSilences.attempt(installation.Namespace, comment, now) {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, transitionSilencesTimeout)
+	defer cancel()
+
+	silences, err := amClient.ListSilences(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list alertmanager silences: %w", err)
+	}
+
+	existing := []Silence{}
+	for _, silence := range silences {
+		if silence.CreatedBy == silenceCreator && silence.IsActive() {
+			existing = append(existing, silence)
+		}
+	}
+
+	var desired *Silence
+	if comment != "" {
+		alerts, err := GetTransitionSilencedAlerts(ctx, serverClient, installation.Namespace)
+		if err != nil {
+			return err
+		}
+		if len(alerts) > 0 {
+			desired = &Silence{
+				Matchers:  transitionMatchers(installation, alerts),
+				StartsAt:  now,
+				EndsAt:    now.Add(silenceDuration),
+				CreatedBy: silenceCreator,
+				Comment:   comment,
+			}
+		}
+	}
+
+	for _, silence := range existing {
+		if desired != nil && desired.ID == "" && matchersEqual(silence.Matchers, desired.Matchers) && silence.Comment == desired.Comment {
+			desired.ID = silence.ID
+			if silence.EndsAt.Sub(now) > silenceRenewBefore {
+				desired = nil
+				continue
+			}
+			// keep the original start so the silence is renewed rather than recreated
+			desired.StartsAt = silence.StartsAt
+			continue
+		}
+
+		if err := amClient.ExpireSilence(ctx, silence.ID); err != nil {
+			return fmt.Errorf("failed to expire alertmanager silence %s: %w", silence.ID, err)
+		}
+		recorder.Event(installation, "Normal", integreatlyv1alpha1.EventAlertsUnsilenced, fmt.Sprintf("Removed alertmanager silence %s: %s", silence.ID, silence.Comment))
+	}
+
+	if desired == nil {
+		return nil
+	}
+	renewed := desired.ID != ""
+	id, err := amClient.CreateSilence(ctx, *desired)
+	if err != nil {
+		return fmt.Errorf("failed to create alertmanager silence: %w", err)
+	}
+	if renewed {
+		recorder.Event(installation, "Normal", integreatlyv1alpha1.EventAlertsSilenceRenewed, fmt.Sprintf("Renewed alertmanager silence %s until %s: %s", id, desired.EndsAt.Format(time.RFC3339), desired.Comment))
+		return nil
+	}
+	recorder.Event(installation, "Normal", integreatlyv1alpha1.EventAlertsSilenced, fmt.Sprintf("Created alertmanager silence %s for alerts %s: %s", id, desired.Matchers[0].Value, desired.Comment))
+	return nil
+}
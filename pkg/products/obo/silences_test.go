@@ -0,0 +1,303 @@
+package obo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+)
+
+// fakeAlertmanager keeps silences in memory and serves them through the alertmanager v2 API
+type fakeAlertmanager struct {
+	mu       sync.Mutex
+	silences map[string]Silence
+	nextID   int
+}
+
+func (f *fakeAlertmanager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v2/silences":
+		silences := []Silence{}
+		for _, silence := range f.silences {
+			silences = append(silences, silence)
+		}
+		_ = json.NewEncoder(w).Encode(silences)
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v2/silences":
+		silence := Silence{}
+		if err := json.NewDecoder(r.Body).Decode(&silence); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if silence.ID == "" {
+			f.nextID++
+			silence.ID = fmt.Sprintf("silence-%d", f.nextID)
+		}
+		silence.Status = &SilenceStatus{State: silenceStateActive}
+		f.silences[silence.ID] = silence
+		_ = json.NewEncoder(w).Encode(map[string]string{"silenceID": silence.ID})
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/api/v2/silence/"):
+		id := strings.TrimPrefix(r.URL.Path, "/api/v2/silence/")
+		silence, ok := f.silences[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		silence.Status = &SilenceStatus{State: "expired"}
+		f.silences[id] = silence
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeAlertmanager) active() []Silence {
+	silences := []Silence{}
+	for _, silence := range f.silences {
+		if silence.IsActive() {
+			silences = append(silences, silence)
+		}
+	}
+	return silences
+}
+
+func TestReconcileTransitionSilences(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	installation := func(toVersion, toQuota string) *integreatlyv1alpha1.RHMI {
+		return &integreatlyv1alpha1.RHMI{
+			ObjectMeta: metav1.ObjectMeta{Name: "rhoam", Namespace: "redhat-rhoam-operator"},
+			Spec:       integreatlyv1alpha1.RHMISpec{Type: string(integreatlyv1alpha1.InstallationTypeManagedApi)},
+			Status:     integreatlyv1alpha1.RHMIStatus{ToVersion: toVersion, ToQuota: toQuota},
+		}
+	}
+	upgrade := installation("1.2.0", "")
+	operatorSilence := func(id string, alerts []string, endsAt time.Time) Silence {
+		return Silence{
+			ID:        id,
+			Matchers:  transitionMatchers(upgrade, alerts),
+			StartsAt:  now.Add(-time.Hour),
+			EndsAt:    endsAt,
+			CreatedBy: silenceCreator,
+			Comment:   transitionComment(upgrade),
+			Status:    &SilenceStatus{State: silenceStateActive},
+		}
+	}
+	foreignSilence := Silence{ID: "manual", CreatedBy: "sre", Status: &SilenceStatus{State: silenceStateActive}}
+	alertsConfigMap := func(alerts string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: TransitionSilencesConfigMapName, Namespace: "redhat-rhoam-operator"},
+			Data:       map[string]string{transitionSilencesConfigMapKey: alerts},
+		}
+	}
+
+	scenarios := []struct {
+		Name         string
+		Installation *integreatlyv1alpha1.RHMI
+		Silences     []Silence
+		Objects      []runtime.Object
+		WantEvents   []string
+		Verify       func(active []Silence) error
+	}{
+		{
+			Name:         "default alerts are silenced during an upgrade",
+			Installation: upgrade,
+			WantEvents:   []string{integreatlyv1alpha1.EventAlertsSilenced},
+			Verify: func(active []Silence) error {
+				if len(active) != 1 {
+					return fmt.Errorf("expected one silence, got %v", active)
+				}
+				if !strings.Contains(active[0].Matchers[0].Value, "ThreeScaleSystemAppPod") || active[0].Matchers[1].Value != "rhoam" {
+					return fmt.Errorf("unexpected matchers %v", active[0].Matchers)
+				}
+				if !active[0].EndsAt.Equal(now.Add(silenceDuration)) {
+					return fmt.Errorf("unexpected end %v", active[0].EndsAt)
+				}
+				return nil
+			},
+		},
+		{
+			Name:         "quota changes are described in the silence",
+			Installation: installation("", "200"),
+			WantEvents:   []string{integreatlyv1alpha1.EventAlertsSilenced},
+			Verify: func(active []Silence) error {
+				if len(active) != 1 || !strings.HasSuffix(active[0].Comment, "quota change to 200") {
+					return fmt.Errorf("expected a quota change silence, got %v", active)
+				}
+				return nil
+			},
+		},
+		{
+			Name:         "current silences are kept",
+			Installation: upgrade,
+			Silences:     []Silence{operatorSilence("silence-0", DefaultTransitionSilencedAlerts, now.Add(silenceDuration))},
+			Verify: func(active []Silence) error {
+				if len(active) != 1 || active[0].ID != "silence-0" {
+					return fmt.Errorf("expected the existing silence, got %v", active)
+				}
+				return nil
+			},
+		},
+		{
+			Name:         "expiring silences are renewed",
+			Installation: upgrade,
+			Silences:     []Silence{operatorSilence("silence-0", DefaultTransitionSilencedAlerts, now.Add(time.Minute))},
+			WantEvents:   []string{integreatlyv1alpha1.EventAlertsSilenceRenewed},
+			Verify: func(active []Silence) error {
+				if len(active) != 1 || active[0].ID != "silence-0" || !active[0].EndsAt.Equal(now.Add(silenceDuration)) {
+					return fmt.Errorf("expected the existing silence to be renewed, got %v", active)
+				}
+				return nil
+			},
+		},
+		{
+			Name:         "silences are replaced when the configured alerts change",
+			Installation: upgrade,
+			Silences:     []Silence{operatorSilence("silence-0", DefaultTransitionSilencedAlerts, now.Add(silenceDuration))},
+			Objects:      []runtime.Object{alertsConfigMap(`["ThreeScaleSystemAppPod"]`)},
+			WantEvents:   []string{integreatlyv1alpha1.EventAlertsUnsilenced, integreatlyv1alpha1.EventAlertsSilenced},
+			Verify: func(active []Silence) error {
+				if len(active) != 1 || active[0].ID == "silence-0" || active[0].Matchers[0].Value != "ThreeScaleSystemAppPod" {
+					return fmt.Errorf("expected a silence for the configured alerts, got %v", active)
+				}
+				return nil
+			},
+		},
+		{
+			Name:         "no silence is created without configured alerts",
+			Installation: upgrade,
+			Objects:      []runtime.Object{alertsConfigMap(`[]`)},
+			Verify: func(active []Silence) error {
+				if len(active) != 0 {
+					return fmt.Errorf("expected no silences, got %v", active)
+				}
+				return nil
+			},
+		},
+		{
+			Name:         "silences are removed once the transition completes",
+			Installation: installation("", ""),
+			Silences:     []Silence{operatorSilence("silence-0", DefaultTransitionSilencedAlerts, now.Add(silenceDuration)), foreignSilence},
+			WantEvents:   []string{integreatlyv1alpha1.EventAlertsUnsilenced},
+			Verify: func(active []Silence) error {
+				if len(active) != 1 || active[0].ID != foreignSilence.ID {
+					return fmt.Errorf("expected only the silences of others to remain, got %v", active)
+				}
+				return nil
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			alertmanager := &fakeAlertmanager{silences: map[string]Silence{}}
+			for _, silence := range scenario.Silences {
+				alertmanager.silences[silence.ID] = silence
+			}
+			server := httptest.NewServer(alertmanager)
+			defer server.Close()
+
+			amClient := NewAlertmanagerClient(scenario.Installation.Namespace)
+			amClient.BaseURL = server.URL
+			amClient.Backoff = wait.Backoff{Duration: time.Millisecond, Factor: 1, Steps: 1}
+			recorder := record.NewFakeRecorder(10)
+			transitionSilences = &silenceThrottle{interval: transitionSilencesInterval, last: map[string]silenceAttempt{}}
+
+			err := ReconcileTransitionSilences(context.TODO(), utils.NewTestClient(scheme, scenario.Objects...), scenario.Installation, amClient, recorder, now)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if err := scenario.Verify(alertmanager.active()); err != nil {
+				t.Error(err)
+			}
+
+			close(recorder.Events)
+			events := []string{}
+			for event := range recorder.Events {
+				events = append(events, strings.Fields(event)[1])
+			}
+			if strings.Join(events, ",") != strings.Join(scenario.WantEvents, ",") {
+				t.Errorf("expected events %v, got %v", scenario.WantEvents, events)
+			}
+		})
+	}
+}
+
+func TestReconcileTransitionSilencesThrottle(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	installation := &integreatlyv1alpha1.RHMI{
+		ObjectMeta: metav1.ObjectMeta{Name: "rhoam", Namespace: "redhat-rhoam-operator"},
+		Spec:       integreatlyv1alpha1.RHMISpec{Type: string(integreatlyv1alpha1.InstallationTypeManagedApi)},
+		Status:     integreatlyv1alpha1.RHMIStatus{ToVersion: "1.2.0"},
+	}
+	amClient := NewAlertmanagerClient(installation.Namespace)
+	amClient.BaseURL = server.URL
+	amClient.Backoff = wait.Backoff{Duration: time.Millisecond, Factor: 1, Steps: 1}
+	transitionSilences = &silenceThrottle{interval: transitionSilencesInterval, last: map[string]silenceAttempt{}}
+	now := time.Now()
+
+	reconcile := func(at time.Time) error {
+		return ReconcileTransitionSilences(context.TODO(), utils.NewTestClient(scheme), installation, amClient, record.NewFakeRecorder(10), at)
+	}
+
+	if err := reconcile(now); err == nil {
+		t.Fatal("expected an error for an unreachable alertmanager")
+	}
+	// the failed attempt is not retried on the next reconcile
+	if err := reconcile(now.Add(time.Minute)); err != nil || requests != 1 {
+		t.Fatalf("expected the attempt to be throttled, got %v after %d requests", err, requests)
+	}
+	// a new transition is reconciled at once
+	installation.Status.ToQuota = "200"
+	if err := reconcile(now.Add(2 * time.Minute)); err == nil || requests != 2 {
+		t.Fatalf("expected a new attempt for the new transition, got %v after %d requests", err, requests)
+	}
+	// and the same transition again once the interval passed
+	if err := reconcile(now.Add(2*time.Minute + transitionSilencesInterval)); err == nil || requests != 3 {
+		t.Fatalf("expected a new attempt once the interval passed, got %v after %d requests", err, requests)
+	}
+}
+
+func TestGetTransitionSilencedAlertsInvalidConfig(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: TransitionSilencesConfigMapName, Namespace: "redhat-rhoam-operator"},
+		Data:       map[string]string{transitionSilencesConfigMapKey: "ThreeScaleSystemAppPod"},
+	}
+
+	if _, err := GetTransitionSilencedAlerts(context.TODO(), utils.NewTestClient(scheme, configMap), "redhat-rhoam-operator"); err == nil {
+		t.Error("expected an error for an invalid alert list")
+	}
+}
@@ -19,7 +19,9 @@ import (
 	"github.com/integr8ly/integreatly-operator/pkg/products/cloudresources"
 	"github.com/integr8ly/integreatly-operator/pkg/products/grafana"
 	"github.com/integr8ly/integreatly-operator/pkg/products/rhsso"
+	"github.com/integr8ly/integreatly-operator/pkg/products/rhssocommon"
 	"github.com/integr8ly/integreatly-operator/pkg/products/rhssouser"
+	"github.com/integr8ly/integreatly-operator/pkg/resources"
 	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/marketplace"
 
@@ -44,7 +46,8 @@ type Interface interface {
 	//
 	//### ctx
 	// This must be used in all network requests performed by the reconciler, as the integreatly-operator maintains this context
-	// and may kill it if an uninstall is detected.
+	// and cancels it when the reconcile of the product or its stage times out, when the RHMI CR is deleted and when the
+	// operator shuts down. context.Cause returns the reason of the cancellation.
 	//
 	//### installation
 	//This is the CR we are basing the install from, it has values that are occasionally required by reconcilers, for example
@@ -83,7 +86,7 @@ type Interface interface {
 	VerifyVersion(installation *integreatlyv1alpha1.RHMI) bool
 }
 
-func NewReconciler(product integreatlyv1alpha1.ProductName, rc *rest.Config, configManager config.ConfigReadWriter, installation *integreatlyv1alpha1.RHMI, mgr manager.Manager, log l.Logger, productsInstallationLoader marketplace.ProductsInstallationLoader) (reconciler Interface, err error) {
+func NewReconciler(ctx context.Context, product integreatlyv1alpha1.ProductName, rc *rest.Config, configManager config.ConfigReadWriter, installation *integreatlyv1alpha1.RHMI, mgr manager.Manager, log l.Logger, productsInstallationLoader marketplace.ProductsInstallationLoader) (reconciler Interface, err error) {
 	mpm := marketplace.NewManager()
 
 	if installation.Spec.SelfSignedCerts {
@@ -110,7 +113,7 @@ func NewReconciler(product integreatlyv1alpha1.ProductName, rc *rest.Config, con
 		if err != nil {
 			return nil, err
 		}
-		reconciler, err = rhsso.NewReconciler(configManager, installation, oauthv1Client, mpm, recorder, rc.Host, &keycloakCommon.LocalConfigKeycloakFactory{}, log, productDeclaration)
+		reconciler, err = rhsso.NewReconciler(configManager, installation, oauthv1Client, mpm, recorder, rc.Host, rhssocommon.NewContextKeycloakClientFactory(ctx, &keycloakCommon.LocalConfigKeycloakFactory{}), log, productDeclaration)
 		if err != nil {
 			return nil, err
 		}
@@ -120,7 +123,7 @@ func NewReconciler(product integreatlyv1alpha1.ProductName, rc *rest.Config, con
 		if err != nil {
 			return nil, err
 		}
-		reconciler, err = rhssouser.NewReconciler(configManager, installation, oauthv1Client, mpm, recorder, rc.Host, &keycloakCommon.LocalConfigKeycloakFactory{}, log, productDeclaration)
+		reconciler, err = rhssouser.NewReconciler(configManager, installation, oauthv1Client, mpm, recorder, rc.Host, rhssocommon.NewContextKeycloakClientFactory(ctx, &keycloakCommon.LocalConfigKeycloakFactory{}), log, productDeclaration)
 		if err != nil {
 			return nil, err
 		}
@@ -140,11 +143,11 @@ func NewReconciler(product integreatlyv1alpha1.ProductName, rc *rest.Config, con
 		/* #nosec */
 		httpc := &http.Client{
 			Timeout: time.Second * 10,
-			Transport: &http.Transport{
+			Transport: resources.NewContextTransport(ctx, &http.Transport{
 				DisableKeepAlives: true,
 				IdleConnTimeout:   time.Second * 10,
 				TLSClientConfig:   &tls.Config{InsecureSkipVerify: installation.Spec.SelfSignedCerts}, // gosec G402, value is read from CR config
-			},
+			}),
 		}
 
 		if installation.Spec.SelfSignedCerts {
@@ -170,6 +173,36 @@ func NewReconciler(product integreatlyv1alpha1.ProductName, rc *rest.Config, con
 	return reconciler, err
 }
 
+// AlertsProvider is implemented by the product reconcilers to expose the reconcilers of the alerts they
+// create, so that the alerts can be rendered without a cluster. The reconcilers are built by the same
+// functions the product reconciler uses, from the inputs it otherwise reads from the cluster
+type AlertsProvider interface {
+	AlertReconcilers(inputs resources.AlertInputs, productConfig quota.ProductConfig) ([]*resources.AlertReconcilerImpl, error)
+}
+
+// NewAlertsProvider creates the reconciler of a product without any of the clients it needs to install
+// the product. It must only be used to build the alerts of the product
+func NewAlertsProvider(product integreatlyv1alpha1.ProductName, configManager config.ConfigReadWriter, installation *integreatlyv1alpha1.RHMI, log l.Logger) (AlertsProvider, error) {
+	productDeclaration := &marketplace.ProductDeclaration{}
+
+	switch product {
+	case integreatlyv1alpha1.ProductRHSSO:
+		return rhsso.NewReconciler(configManager, installation, nil, nil, nil, "", nil, log, productDeclaration)
+	case integreatlyv1alpha1.ProductRHSSOUser:
+		return rhssouser.NewReconciler(configManager, installation, nil, nil, nil, "", nil, log, productDeclaration)
+	case integreatlyv1alpha1.Product3Scale:
+		return threescale.NewReconciler(configManager, installation, nil, nil, nil, nil, nil, log, productDeclaration)
+	case integreatlyv1alpha1.ProductCloudResources:
+		return cloudresources.NewReconciler(configManager, installation, nil, nil, log, productDeclaration)
+	case integreatlyv1alpha1.ProductMarin3r:
+		return marin3r.NewReconciler(configManager, installation, nil, nil, log, productDeclaration)
+	case integreatlyv1alpha1.ProductGrafana:
+		return grafana.NewReconciler(configManager, installation, nil, nil, log)
+	default:
+		return nil, errors.New("unknown products: " + string(product))
+	}
+}
+
 type NoOp struct {
 }
 
@@ -1,6 +1,7 @@
 package products
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -227,6 +228,7 @@ func TestNewReconciler(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			gotReconciler, err := NewReconciler(
+				context.TODO(),
 				tt.args.product,
 				tt.args.rc,
 				tt.args.configManager,
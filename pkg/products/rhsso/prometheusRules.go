@@ -2,6 +2,9 @@ package rhsso
 
 import (
 	"fmt"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/constants"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
 	"strings"
 
 	"github.com/integr8ly/integreatly-operator/pkg/resources"
@@ -10,7 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
-func (r *Reconciler) newAlertsReconciler(logger l.Logger, installType string, namespace string) resources.AlertReconciler {
+func (r *Reconciler) newAlertsReconciler(logger l.Logger, installType string, namespace string) *resources.AlertReconcilerImpl {
 	installationName := resources.InstallationNames[installType]
 
 	alertName := "rhsso-ksm-endpoint-alerts"
@@ -167,3 +170,12 @@ func (r *Reconciler) newAlertsReconciler(logger l.Logger, installType string, na
 		},
 	}
 }
+
+// AlertReconcilers returns the reconcilers of all the alerts created for RHSSO, including the alerts
+// of its postgres instance, so that they can be rendered without a cluster
+func (r *Reconciler) AlertReconcilers(_ resources.AlertInputs, _ quota.ProductConfig) ([]*resources.AlertReconcilerImpl, error) {
+	return []*resources.AlertReconcilerImpl{
+		r.newAlertsReconciler(r.Log, r.Installation.Spec.Type, config.GetOboNamespace(r.Installation.Namespace)),
+		resources.PostgresAlertsReconciler(r.Installation, constants.RHSSOPostgresPrefix+r.Installation.Name, defaultOperandNamespace, r.Log),
+	}, nil
+}
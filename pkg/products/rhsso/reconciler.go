@@ -29,6 +29,7 @@ import (
 	usersv1 "github.com/openshift/api/user/v1"
 	oauthClient "github.com/openshift/client-go/oauth/clientset/versioned/typed/oauth/v1"
 
+	"github.com/integr8ly/integreatly-operator/pkg/resources/backup"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/constants"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	k8sresource "k8s.io/apimachinery/pkg/api/resource"
@@ -50,6 +51,9 @@ var (
 	postgresResourceName      = "rhsso-postgres-rhmi"
 	routeName                 = "keycloak-edge"
 	lastPodRestart            = time.Now()
+
+	// realm roles every user of the realm keeps when the realm roles are set from the role mappings
+	defaultRealmRoles = []string{"offline_access", "uma_authorization"}
 )
 
 const (
@@ -221,7 +225,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, err
 	}
 
-	err = r.ConfigManager.WriteConfig(r.Config)
+	err = r.ConfigManager.WriteConfig(r.Config, "rhsso.Reconcile")
 	if err != nil {
 		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("error writing to config in rhsso cluster reconciler: %w", err)
 	}
@@ -238,16 +242,18 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, err
 	}
 
-	phase, err = r.newAlertsReconciler(r.Log, r.Installation.Spec.Type, config.GetOboNamespace(r.Installation.Namespace)).ReconcileAlerts(ctx, serverClient)
-	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
-		events.HandleError(r.Recorder, installation, phase, "Failed to reconcile alerts", err)
-		return phase, err
-	}
+	if integreatlyv1alpha1.HasObservability(integreatlyv1alpha1.InstallationType(installation.Spec.Type)) {
+		phase, err = r.newAlertsReconciler(r.Log, r.Installation.Spec.Type, config.GetOboNamespace(r.Installation.Namespace)).ReconcileAlerts(ctx, serverClient)
+		if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+			events.HandleError(r.Recorder, installation, phase, "Failed to reconcile alerts", err)
+			return phase, err
+		}
 
-	phase, err = r.ExportAlerts(ctx, serverClient, string(r.Config.GetProductName()), r.Config.GetNamespace())
-	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
-		events.HandleError(r.Recorder, installation, phase, "Failed to export alerts to the observability namespace", err)
-		return phase, err
+		phase, err = r.ExportAlerts(ctx, serverClient, string(r.Config.GetProductName()), r.Config.GetNamespace())
+		if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+			events.HandleError(r.Recorder, installation, phase, "Failed to export alerts to the observability namespace", err)
+			return phase, err
+		}
 	}
 
 	productStatus.Host = r.Config.GetHost()
@@ -291,7 +297,12 @@ func (r *Reconciler) reconcileComponents(ctx context.Context, installation *inte
 		kc.Spec.Profile = RHSSOProfile
 		kc.Spec.PodDisruptionBudget = keycloak.PodDisruptionBudgetConfig{Enabled: true}
 
-		if integreatlyv1alpha1.IsRHOAMMultitenant(integreatlyv1alpha1.InstallationType(installation.Spec.Type)) {
+		if integreatlyv1alpha1.HasMinimalResources(integreatlyv1alpha1.InstallationType(installation.Spec.Type)) {
+			kc.Spec.KeycloakDeploymentSpec.Resources = corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: k8sresource.MustParse("100m"), corev1.ResourceMemory: k8sresource.MustParse("1G")},
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: k8sresource.MustParse("650m"), corev1.ResourceMemory: k8sresource.MustParse("2G")},
+			}
+		} else if integreatlyv1alpha1.IsRHOAMMultitenant(integreatlyv1alpha1.InstallationType(installation.Spec.Type)) {
 			cpu := strconv.Itoa(multiTenantCPU) + "m"
 			kc.Spec.KeycloakDeploymentSpec.Resources = corev1.ResourceRequirements{
 				Requests: corev1.ResourceList{corev1.ResourceCPU: k8sresource.MustParse(cpu), corev1.ResourceMemory: k8sresource.MustParse("2G")},
@@ -419,12 +430,24 @@ func (r *Reconciler) reconcileComponents(ctx context.Context, installation *inte
 	}
 
 	// Create / update the synchronized users
+	auditor := userHelper.NewAuditor(serverClient, r.Recorder, installation)
 	for _, user := range users {
 		or, conflictFound, err := r.createOrUpdateKeycloakUser(ctx, user, serverClient)
 		if err != nil {
+			if or == controllerutil.OperationResultCreated {
+				auditor.Record(ctx, userHelper.AuditSystemRHSSO, userHelper.AuditActionCreated, user.UserName, "new OpenShift user", err)
+			} else {
+				auditor.Record(ctx, userHelper.AuditSystemRHSSO, userHelper.AuditActionUpdated, user.UserName, "synchronised with the OpenShift user", err)
+			}
 			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to create/update the customer admin user: %w", err)
 		}
 		r.Log.Infof("Operation result", l.Fields{"keycloakuser": user.UserName, "result": or})
+		switch or {
+		case controllerutil.OperationResultCreated:
+			auditor.Record(ctx, userHelper.AuditSystemRHSSO, userHelper.AuditActionCreated, user.UserName, "new OpenShift user", nil)
+		case controllerutil.OperationResultUpdated:
+			auditor.Record(ctx, userHelper.AuditSystemRHSSO, userHelper.AuditActionUpdated, user.UserName, "synchronised with the OpenShift user", nil)
+		}
 
 		if conflictFound && integreatlyv1alpha1.IsRHOAMMultitenant(integreatlyv1alpha1.InstallationType(installation.Spec.Type)) {
 			r.Log.Infof("Conflict error found", l.Fields{"keycloak-user": user.UserName})
@@ -432,6 +455,7 @@ func (r *Reconciler) reconcileComponents(ctx context.Context, installation *inte
 			if err != nil {
 				r.Log.Error(fmt.Sprintf("failed to delete keycloak-user %s using the keycloak authenticated client", user.UserName), nil, err)
 			}
+			auditor.Record(ctx, userHelper.AuditSystemRHSSO, userHelper.AuditActionDeleted, user.UserName, "conflicting user in the keycloak realm", err)
 		}
 	}
 
@@ -688,13 +712,45 @@ func syncronizeWithOpenshiftUsers(ctx context.Context, keycloakUsers []keycloak.
 	if err != nil && !k8serr.IsNotFound(err) {
 		return nil, err
 	}
+	roleMappings, err := userHelper.GetRoleMappings(ctx, serverClient, installation.Namespace)
+	if err != nil {
+		return nil, err
+	}
 	for index := range keycloakUsers {
 		keycloakUsers[index].ClientRoles = getKeycloakRoles(integreatlyv1alpha1.InstallationType(installation.Spec.Type))
+		keycloakUsers[index] = setMappedRealmRoles(keycloakUsers[index], roleMappings.KeycloakRealmRoles(keycloakUsers[index].UserName, groups))
 	}
 
 	return keycloakUsers, nil
 }
 
+// setMappedRealmRoles grants the user the realm roles its groups are mapped to and removes the realm roles the
+// mappings granted before but no longer grant. The realm roles granted otherwise are kept
+func setMappedRealmRoles(user keycloak.KeycloakAPIUser, mapped []string) keycloak.KeycloakAPIUser {
+	previouslyMapped := user.Attributes[userHelper.MappedRealmRolesAttribute]
+	if len(mapped) == 0 && len(previouslyMapped) == 0 {
+		return user
+	}
+
+	realmRoles := user.RealmRoles
+	if len(realmRoles) == 0 {
+		realmRoles = defaultRealmRoles
+	}
+	user.RealmRoles = userHelper.MergeMapped(realmRoles, previouslyMapped, mapped)
+
+	attributes := map[string][]string{}
+	for key, value := range user.Attributes {
+		attributes[key] = value
+	}
+	if len(mapped) > 0 {
+		attributes[userHelper.MappedRealmRolesAttribute] = mapped
+	} else {
+		delete(attributes, userHelper.MappedRealmRolesAttribute)
+	}
+	user.Attributes = attributes
+	return user
+}
+
 func kcContainsOsUser(kcUsers []keycloak.KeycloakAPIUser, osUser usersv1.User) bool {
 	for _, kcu := range kcUsers {
 		if kcu.UserName == osUser.Name {
@@ -705,6 +761,7 @@ func kcContainsOsUser(kcUsers []keycloak.KeycloakAPIUser, osUser usersv1.User) b
 	return false
 }
 
+// createOrUpdateKeycloakUser returns the operation that failed when the user CR could not be created or updated
 func (r *Reconciler) createOrUpdateKeycloakUser(ctx context.Context, user keycloak.KeycloakAPIUser, serverClient k8sclient.Client) (controllerutil.OperationResult, bool, error) {
 	conflictFound := false
 	kcUser := &keycloak.KeycloakUser{
@@ -729,6 +786,10 @@ func (r *Reconciler) createOrUpdateKeycloakUser(ctx context.Context, user keyclo
 		}
 		return nil
 	})
+	if err != nil && kcUser.ResourceVersion == "" {
+		// the user did not exist, report the failed create
+		op = controllerutil.OperationResultCreated
+	}
 
 	return op, conflictFound, err
 }
@@ -829,3 +890,8 @@ func GetInstanceLabels() map[string]string {
 		SSOLabelKey: SSOLabelValue,
 	}
 }
+
+// PreUpgradeBackupExecutor returns the executor of the backups taken before RHSSO is upgraded
+func PreUpgradeBackupExecutor(installation *integreatlyv1alpha1.RHMI) backup.BackupExecutor {
+	return rhssocommon.NewPreUpgradeBackupsExecutor(installation, postgresResourceName)
+}
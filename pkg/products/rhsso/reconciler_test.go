@@ -34,6 +34,7 @@ import (
 	"github.com/integr8ly/integreatly-operator/pkg/resources"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/marketplace"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/owner"
+	userHelper "github.com/integr8ly/integreatly-operator/pkg/resources/user"
 
 	fakeoauthClient "github.com/openshift/client-go/oauth/clientset/versioned/fake"
 	oauthClient "github.com/openshift/client-go/oauth/clientset/versioned/typed/oauth/v1"
@@ -47,6 +48,7 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 var (
@@ -67,7 +69,7 @@ func basicConfigMock() *config.ConfigReadWriterMock {
 				"HOST":      "edge/route",
 			}), nil
 		},
-		WriteConfigFunc: func(config config.ConfigReadable) error {
+		WriteConfigFunc: func(config config.ConfigReadable, writer string) error {
 			return nil
 		},
 		GetOauthClientsSecretNameFunc: func() string {
@@ -1129,3 +1131,100 @@ func assertRhssoOperatorMetricsServiceReconciled(t *testing.T, ctx context.Conte
 		t.Errorf("annotation %s: got %q, want %q", owner.IntegreatlyOwnerNamespace, svc.Annotations[owner.IntegreatlyOwnerNamespace], installation.Namespace)
 	}
 }
+
+func TestSetMappedRealmRoles(t *testing.T) {
+	scenarios := []struct {
+		Name           string
+		User           keycloak.KeycloakAPIUser
+		Mapped         []string
+		WantRealmRoles []string
+		WantMapped     []string
+	}{
+		{
+			Name:           "mapped roles are granted on top of the default roles",
+			User:           keycloak.KeycloakAPIUser{UserName: "dev"},
+			Mapped:         []string{"developer"},
+			WantRealmRoles: []string{"offline_access", "uma_authorization", "developer"},
+			WantMapped:     []string{"developer"},
+		},
+		{
+			Name: "roles the mappings no longer grant are removed and manual grants are kept",
+			User: keycloak.KeycloakAPIUser{
+				UserName:   "dev",
+				RealmRoles: []string{"offline_access", "uma_authorization", "developer", "auditor"},
+				Attributes: map[string][]string{userHelper.MappedRealmRolesAttribute: {"developer"}},
+			},
+			WantRealmRoles: []string{"offline_access", "uma_authorization", "auditor"},
+		},
+		{
+			Name:           "users without mapped roles are left unchanged",
+			User:           keycloak.KeycloakAPIUser{UserName: "dev", RealmRoles: []string{"auditor"}},
+			WantRealmRoles: []string{"auditor"},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			user := setMappedRealmRoles(scenario.User, scenario.Mapped)
+			if fmt.Sprint(user.RealmRoles) != fmt.Sprint(scenario.WantRealmRoles) {
+				t.Errorf("expected realm roles %v, got %v", scenario.WantRealmRoles, user.RealmRoles)
+			}
+			if fmt.Sprint(user.Attributes[userHelper.MappedRealmRolesAttribute]) != fmt.Sprint(scenario.WantMapped) {
+				t.Errorf("expected mapped realm roles %v, got %v", scenario.WantMapped, user.Attributes)
+			}
+		})
+	}
+}
+
+func TestCreateOrUpdateKeycloakUserFailure(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := keycloak.KeycloakAPIUser{UserName: "dev"}
+	existing := &keycloak.KeycloakUser{
+		ObjectMeta: metav1.ObjectMeta{Name: userHelper.GetValidGeneratedUserName(user), Namespace: "rhsso"},
+	}
+
+	scenarios := []struct {
+		Name   string
+		Client k8sclient.Client
+		WantOp controllerutil.OperationResult
+	}{
+		{
+			Name:   "a failed create is reported as a create",
+			Client: utils.NewTestClient(scheme),
+			WantOp: controllerutil.OperationResultCreated,
+		},
+		{
+			Name:   "a failed update is not reported as a create",
+			Client: utils.NewTestClient(scheme, existing),
+			WantOp: controllerutil.OperationResultNone,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			serverClient := &moqclient.SigsClientInterfaceMock{
+				GetFunc: func(ctx context.Context, key types.NamespacedName, obj k8sclient.Object, opts ...k8sclient.GetOption) error {
+					return scenario.Client.Get(ctx, key, obj, opts...)
+				},
+				CreateFunc: func(ctx context.Context, obj k8sclient.Object, opts ...k8sclient.CreateOption) error {
+					return errors.New("generic error")
+				},
+				UpdateFunc: func(ctx context.Context, obj k8sclient.Object, opts ...k8sclient.UpdateOption) error {
+					return errors.New("generic error")
+				},
+			}
+			r := &Reconciler{Config: config.NewRHSSO(config.ProductConfig{"NAMESPACE": "rhsso"})}
+
+			op, _, err := r.createOrUpdateKeycloakUser(context.TODO(), user, serverClient)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if op != scenario.WantOp {
+				t.Errorf("expected operation %q, got %q", scenario.WantOp, op)
+			}
+		})
+	}
+}
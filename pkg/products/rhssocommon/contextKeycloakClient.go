@@ -0,0 +1,495 @@
+package rhssocommon
+
+import (
+	"context"
+	"fmt"
+
+	keycloak "github.com/integr8ly/keycloak-client/apis/keycloak/v1alpha1"
+	keycloakCommon "github.com/integr8ly/keycloak-client/pkg/common"
+)
+
+// contextKeycloakClient fails the requests to keycloak with the cause of the cancellation of the context of the
+// reconcile instead of sending them. The keycloak client doesn't take a context or a transport, a request already
+// sent is bounded by the timeout of the client
+type contextKeycloakClient struct {
+	ctx    context.Context
+	client keycloakCommon.KeycloakInterface
+}
+
+var _ keycloakCommon.KeycloakInterface = &contextKeycloakClient{}
+
+func (c *contextKeycloakClient) err() error {
+	if c.ctx.Err() != nil {
+		return fmt.Errorf("keycloak request not sent: %w", context.Cause(c.ctx))
+	}
+	return nil
+}
+
+func (c *contextKeycloakClient) Ping() error {
+	if err := c.err(); err != nil {
+		return err
+	}
+	return c.client.Ping()
+}
+
+func (c *contextKeycloakClient) CreateRealm(realm *keycloak.KeycloakRealm) (string, error) {
+	if err := c.err(); err != nil {
+		return "", err
+	}
+	return c.client.CreateRealm(realm)
+}
+
+func (c *contextKeycloakClient) GetRealm(realmName string) (*keycloak.KeycloakRealm, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.GetRealm(realmName)
+}
+
+func (c *contextKeycloakClient) UpdateRealm(specRealm *keycloak.KeycloakRealm) error {
+	if err := c.err(); err != nil {
+		return err
+	}
+	return c.client.UpdateRealm(specRealm)
+}
+
+func (c *contextKeycloakClient) DeleteRealm(realmName string) error {
+	if err := c.err(); err != nil {
+		return err
+	}
+	return c.client.DeleteRealm(realmName)
+}
+
+func (c *contextKeycloakClient) ListRealms() ([]*keycloak.KeycloakAPIRealm, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.ListRealms()
+}
+
+func (c *contextKeycloakClient) CreateClient(client *keycloak.KeycloakAPIClient, realmName string) (string, error) {
+	if err := c.err(); err != nil {
+		return "", err
+	}
+	return c.client.CreateClient(client, realmName)
+}
+
+func (c *contextKeycloakClient) GetClient(clientID, realmName string) (*keycloak.KeycloakAPIClient, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.GetClient(clientID, realmName)
+}
+
+func (c *contextKeycloakClient) GetClientSecret(clientID, realmName string) (string, error) {
+	if err := c.err(); err != nil {
+		return "", err
+	}
+	return c.client.GetClientSecret(clientID, realmName)
+}
+
+func (c *contextKeycloakClient) GetClientInstall(clientID, realmName string) ([]byte, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.GetClientInstall(clientID, realmName)
+}
+
+func (c *contextKeycloakClient) UpdateClient(specClient *keycloak.KeycloakAPIClient, realmName string) error {
+	if err := c.err(); err != nil {
+		return err
+	}
+	return c.client.UpdateClient(specClient, realmName)
+}
+
+func (c *contextKeycloakClient) DeleteClient(clientID, realmName string) error {
+	if err := c.err(); err != nil {
+		return err
+	}
+	return c.client.DeleteClient(clientID, realmName)
+}
+
+func (c *contextKeycloakClient) ListClients(realmName string) ([]*keycloak.KeycloakAPIClient, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.ListClients(realmName)
+}
+
+func (c *contextKeycloakClient) CreateUser(user *keycloak.KeycloakAPIUser, realmName string) (string, error) {
+	if err := c.err(); err != nil {
+		return "", err
+	}
+	return c.client.CreateUser(user, realmName)
+}
+
+func (c *contextKeycloakClient) CreateFederatedIdentity(fid keycloak.FederatedIdentity, userID string, realmName string) (string, error) {
+	if err := c.err(); err != nil {
+		return "", err
+	}
+	return c.client.CreateFederatedIdentity(fid, userID, realmName)
+}
+
+func (c *contextKeycloakClient) RemoveFederatedIdentity(fid keycloak.FederatedIdentity, userID string, realmName string) error {
+	if err := c.err(); err != nil {
+		return err
+	}
+	return c.client.RemoveFederatedIdentity(fid, userID, realmName)
+}
+
+func (c *contextKeycloakClient) GetUserFederatedIdentities(userName string, realmName string) ([]keycloak.FederatedIdentity, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.GetUserFederatedIdentities(userName, realmName)
+}
+
+func (c *contextKeycloakClient) UpdatePassword(user *keycloak.KeycloakAPIUser, realmName, newPass string) error {
+	if err := c.err(); err != nil {
+		return err
+	}
+	return c.client.UpdatePassword(user, realmName, newPass)
+}
+
+func (c *contextKeycloakClient) FindUserByEmail(email, realm string) (*keycloak.KeycloakAPIUser, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.FindUserByEmail(email, realm)
+}
+
+func (c *contextKeycloakClient) FindUserByUsername(name, realm string) (*keycloak.KeycloakAPIUser, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.FindUserByUsername(name, realm)
+}
+
+func (c *contextKeycloakClient) GetUser(userID, realmName string) (*keycloak.KeycloakAPIUser, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.GetUser(userID, realmName)
+}
+
+func (c *contextKeycloakClient) UpdateUser(specUser *keycloak.KeycloakAPIUser, realmName string) error {
+	if err := c.err(); err != nil {
+		return err
+	}
+	return c.client.UpdateUser(specUser, realmName)
+}
+
+func (c *contextKeycloakClient) DeleteUser(userID, realmName string) error {
+	if err := c.err(); err != nil {
+		return err
+	}
+	return c.client.DeleteUser(userID, realmName)
+}
+
+func (c *contextKeycloakClient) ListUsers(realmName string) ([]*keycloak.KeycloakAPIUser, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.ListUsers(realmName)
+}
+
+func (c *contextKeycloakClient) ListUsersInGroup(realmName, groupID string) ([]*keycloak.KeycloakAPIUser, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.ListUsersInGroup(realmName, groupID)
+}
+
+func (c *contextKeycloakClient) AddUserToGroup(realmName, userID, groupID string) error {
+	if err := c.err(); err != nil {
+		return err
+	}
+	return c.client.AddUserToGroup(realmName, userID, groupID)
+}
+
+func (c *contextKeycloakClient) DeleteUserFromGroup(realmName, userID, groupID string) error {
+	if err := c.err(); err != nil {
+		return err
+	}
+	return c.client.DeleteUserFromGroup(realmName, userID, groupID)
+}
+
+func (c *contextKeycloakClient) FindGroupByName(groupName string, realmName string) (*keycloakCommon.Group, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.FindGroupByName(groupName, realmName)
+}
+
+func (c *contextKeycloakClient) FindGroupByPath(groupPath, realmName string) (*keycloakCommon.Group, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.FindGroupByPath(groupPath, realmName)
+}
+
+func (c *contextKeycloakClient) CreateGroup(group string, realmName string) (string, error) {
+	if err := c.err(); err != nil {
+		return "", err
+	}
+	return c.client.CreateGroup(group, realmName)
+}
+
+func (c *contextKeycloakClient) MakeGroupDefault(groupID string, realmName string) error {
+	if err := c.err(); err != nil {
+		return err
+	}
+	return c.client.MakeGroupDefault(groupID, realmName)
+}
+
+func (c *contextKeycloakClient) ListDefaultGroups(realmName string) ([]*keycloakCommon.Group, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.ListDefaultGroups(realmName)
+}
+
+func (c *contextKeycloakClient) SetGroupChild(groupID, realmName string, childGroup *keycloakCommon.Group) error {
+	if err := c.err(); err != nil {
+		return err
+	}
+	return c.client.SetGroupChild(groupID, realmName, childGroup)
+}
+
+func (c *contextKeycloakClient) CreateGroupClientRole(role *keycloak.KeycloakUserRole, realmName, clientID, groupID string) (string, error) {
+	if err := c.err(); err != nil {
+		return "", err
+	}
+	return c.client.CreateGroupClientRole(role, realmName, clientID, groupID)
+}
+
+func (c *contextKeycloakClient) ListGroupClientRoles(realmName, clientID, groupID string) ([]*keycloak.KeycloakUserRole, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.ListGroupClientRoles(realmName, clientID, groupID)
+}
+
+func (c *contextKeycloakClient) FindGroupClientRole(realmName, clientID, groupID string, predicate func(*keycloak.KeycloakUserRole) bool) (*keycloak.KeycloakUserRole, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.FindGroupClientRole(realmName, clientID, groupID, predicate)
+}
+
+func (c *contextKeycloakClient) ListAvailableGroupClientRoles(realmName, clientID, groupID string) ([]*keycloak.KeycloakUserRole, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.ListAvailableGroupClientRoles(realmName, clientID, groupID)
+}
+
+func (c *contextKeycloakClient) FindAvailableGroupClientRole(realmName, clientID, groupID string, predicate func(*keycloak.KeycloakUserRole) bool) (*keycloak.KeycloakUserRole, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.FindAvailableGroupClientRole(realmName, clientID, groupID, predicate)
+}
+
+func (c *contextKeycloakClient) CreateGroupRealmRole(role *keycloak.KeycloakUserRole, realmName, groupID string) (string, error) {
+	if err := c.err(); err != nil {
+		return "", err
+	}
+	return c.client.CreateGroupRealmRole(role, realmName, groupID)
+}
+
+func (c *contextKeycloakClient) ListGroupRealmRoles(realmName, groupID string) ([]*keycloak.KeycloakUserRole, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.ListGroupRealmRoles(realmName, groupID)
+}
+
+func (c *contextKeycloakClient) ListAvailableGroupRealmRoles(realmName, groupID string) ([]*keycloak.KeycloakUserRole, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.ListAvailableGroupRealmRoles(realmName, groupID)
+}
+
+func (c *contextKeycloakClient) CreateIdentityProvider(identityProvider *keycloak.KeycloakIdentityProvider, realmName string) (string, error) {
+	if err := c.err(); err != nil {
+		return "", err
+	}
+	return c.client.CreateIdentityProvider(identityProvider, realmName)
+}
+
+func (c *contextKeycloakClient) GetIdentityProvider(alias, realmName string) (*keycloak.KeycloakIdentityProvider, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.GetIdentityProvider(alias, realmName)
+}
+
+func (c *contextKeycloakClient) UpdateIdentityProvider(specIdentityProvider *keycloak.KeycloakIdentityProvider, realmName string) error {
+	if err := c.err(); err != nil {
+		return err
+	}
+	return c.client.UpdateIdentityProvider(specIdentityProvider, realmName)
+}
+
+func (c *contextKeycloakClient) DeleteIdentityProvider(alias, realmName string) error {
+	if err := c.err(); err != nil {
+		return err
+	}
+	return c.client.DeleteIdentityProvider(alias, realmName)
+}
+
+func (c *contextKeycloakClient) ListIdentityProviders(realmName string) ([]*keycloak.KeycloakIdentityProvider, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.ListIdentityProviders(realmName)
+}
+
+func (c *contextKeycloakClient) CreateUserClientRole(role *keycloak.KeycloakUserRole, realmName, clientID, userID string) (string, error) {
+	if err := c.err(); err != nil {
+		return "", err
+	}
+	return c.client.CreateUserClientRole(role, realmName, clientID, userID)
+}
+
+func (c *contextKeycloakClient) ListUserClientRoles(realmName, clientID, userID string) ([]*keycloak.KeycloakUserRole, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.ListUserClientRoles(realmName, clientID, userID)
+}
+
+func (c *contextKeycloakClient) ListAvailableUserClientRoles(realmName, clientID, userID string) ([]*keycloak.KeycloakUserRole, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.ListAvailableUserClientRoles(realmName, clientID, userID)
+}
+
+func (c *contextKeycloakClient) DeleteUserClientRole(role *keycloak.KeycloakUserRole, realmName, clientID, userID string) error {
+	if err := c.err(); err != nil {
+		return err
+	}
+	return c.client.DeleteUserClientRole(role, realmName, clientID, userID)
+}
+
+func (c *contextKeycloakClient) CreateUserRealmRole(role *keycloak.KeycloakUserRole, realmName, userID string) (string, error) {
+	if err := c.err(); err != nil {
+		return "", err
+	}
+	return c.client.CreateUserRealmRole(role, realmName, userID)
+}
+
+func (c *contextKeycloakClient) ListUserRealmRoles(realmName, userID string) ([]*keycloak.KeycloakUserRole, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.ListUserRealmRoles(realmName, userID)
+}
+
+func (c *contextKeycloakClient) ListAvailableUserRealmRoles(realmName, userID string) ([]*keycloak.KeycloakUserRole, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.ListAvailableUserRealmRoles(realmName, userID)
+}
+
+func (c *contextKeycloakClient) DeleteUserRealmRole(role *keycloak.KeycloakUserRole, realmName, userID string) error {
+	if err := c.err(); err != nil {
+		return err
+	}
+	return c.client.DeleteUserRealmRole(role, realmName, userID)
+}
+
+func (c *contextKeycloakClient) CreateAuthenticationFlow(authFlow keycloakCommon.AuthenticationFlow, realmName string) (string, error) {
+	if err := c.err(); err != nil {
+		return "", err
+	}
+	return c.client.CreateAuthenticationFlow(authFlow, realmName)
+}
+
+func (c *contextKeycloakClient) ListAuthenticationFlows(realmName string) ([]*keycloakCommon.AuthenticationFlow, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.ListAuthenticationFlows(realmName)
+}
+
+func (c *contextKeycloakClient) FindAuthenticationFlowByAlias(flowAlias, realmName string) (*keycloakCommon.AuthenticationFlow, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.FindAuthenticationFlowByAlias(flowAlias, realmName)
+}
+
+func (c *contextKeycloakClient) AddExecutionToAuthenticatonFlow(flowAlias, realmName string, providerID string, requirement keycloakCommon.Requirement) error {
+	if err := c.err(); err != nil {
+		return err
+	}
+	return c.client.AddExecutionToAuthenticatonFlow(flowAlias, realmName, providerID, requirement)
+}
+
+func (c *contextKeycloakClient) ListAuthenticationExecutionsForFlow(flowAlias, realmName string) ([]*keycloak.AuthenticationExecutionInfo, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.ListAuthenticationExecutionsForFlow(flowAlias, realmName)
+}
+
+func (c *contextKeycloakClient) FindAuthenticationExecutionForFlow(flowAlias, realmName string, predicate func(*keycloak.AuthenticationExecutionInfo) bool) (*keycloak.AuthenticationExecutionInfo, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.FindAuthenticationExecutionForFlow(flowAlias, realmName, predicate)
+}
+
+func (c *contextKeycloakClient) UpdateAuthenticationExecutionForFlow(flowAlias, realmName string, execution *keycloak.AuthenticationExecutionInfo) error {
+	if err := c.err(); err != nil {
+		return err
+	}
+	return c.client.UpdateAuthenticationExecutionForFlow(flowAlias, realmName, execution)
+}
+
+func (c *contextKeycloakClient) CreateAuthenticatorConfig(authenticatorConfig *keycloak.AuthenticatorConfig, realmName, executionID string) (string, error) {
+	if err := c.err(); err != nil {
+		return "", err
+	}
+	return c.client.CreateAuthenticatorConfig(authenticatorConfig, realmName, executionID)
+}
+
+func (c *contextKeycloakClient) GetAuthenticatorConfig(configID, realmName string) (*keycloak.AuthenticatorConfig, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.GetAuthenticatorConfig(configID, realmName)
+}
+
+func (c *contextKeycloakClient) UpdateAuthenticatorConfig(authenticatorConfig *keycloak.AuthenticatorConfig, realmName string) error {
+	if err := c.err(); err != nil {
+		return err
+	}
+	return c.client.UpdateAuthenticatorConfig(authenticatorConfig, realmName)
+}
+
+func (c *contextKeycloakClient) DeleteAuthenticatorConfig(configID, realmName string) error {
+	if err := c.err(); err != nil {
+		return err
+	}
+	return c.client.DeleteAuthenticatorConfig(configID, realmName)
+}
+
+func (c *contextKeycloakClient) ListOfActivesUsersPerRealm(realmName, dateFrom string, max int) ([]keycloakCommon.Users, error) {
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return c.client.ListOfActivesUsersPerRealm(realmName, dateFrom, max)
+}
+
+func (c *contextKeycloakClient) UpdateEventsConfig(realmName string, enabledEventTypes, eventsListeners []string) error {
+	if err := c.err(); err != nil {
+		return err
+	}
+	return c.client.UpdateEventsConfig(realmName, enabledEventTypes, eventsListeners)
+}
@@ -0,0 +1,33 @@
+package rhssocommon
+
+import (
+	"context"
+	"fmt"
+
+	keycloak "github.com/integr8ly/keycloak-client/apis/keycloak/v1alpha1"
+	keycloakCommon "github.com/integr8ly/keycloak-client/pkg/common"
+)
+
+// contextKeycloakClientFactory stops creating keycloak clients once the context of the reconcile is done, and the
+// clients it creates stop sending requests then
+type contextKeycloakClientFactory struct {
+	ctx     context.Context
+	factory keycloakCommon.KeycloakClientFactory
+}
+
+// NewContextKeycloakClientFactory creates a KeycloakClientFactory that fails with the cause of the cancellation
+// of ctx instead of logging in to keycloak with factory or sending requests with the clients it created
+func NewContextKeycloakClientFactory(ctx context.Context, factory keycloakCommon.KeycloakClientFactory) keycloakCommon.KeycloakClientFactory {
+	return &contextKeycloakClientFactory{ctx: ctx, factory: factory}
+}
+
+func (f *contextKeycloakClientFactory) AuthenticatedClient(kc keycloak.Keycloak) (keycloakCommon.KeycloakInterface, error) {
+	if f.ctx.Err() != nil {
+		return nil, fmt.Errorf("keycloak client not created: %w", context.Cause(f.ctx))
+	}
+	client, err := f.factory.AuthenticatedClient(kc)
+	if err != nil {
+		return nil, err
+	}
+	return &contextKeycloakClient{ctx: f.ctx, client: client}, nil
+}
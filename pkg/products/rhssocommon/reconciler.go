@@ -254,7 +254,7 @@ func (r *Reconciler) CreateKeycloakRoute(ctx context.Context, serverClient k8scl
 	// Override the keycloak host to the host of the edge route (instead of the
 	// operator generated route)
 	ssoCommon.SetHost(fmt.Sprintf("https://%v", keycloakRoute.Spec.Host))
-	err = r.ConfigManager.WriteConfig(config)
+	err = r.ConfigManager.WriteConfig(config, "rhssocommon.CreateKeycloakRoute")
 	if err != nil {
 		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("error writing to config in rhsso reconciler: %w", err)
 	}
@@ -439,12 +439,18 @@ func (r *Reconciler) ReconcileCloudResources(dbPRefix string, defaultNamespace s
 }
 
 func (r *Reconciler) PreUpgradeBackupsExecutor(resourceName string) backup.BackupExecutor {
-	if r.Installation.Spec.UseClusterStorage != "false" {
+	return NewPreUpgradeBackupsExecutor(r.Installation, resourceName)
+}
+
+// NewPreUpgradeBackupsExecutor returns the executor of the backups of the postgres of an RHSSO taken before
+// it is upgraded
+func NewPreUpgradeBackupsExecutor(installation *integreatlyv1alpha1.RHMI, resourceName string) backup.BackupExecutor {
+	if installation.Spec.UseClusterStorage != "false" {
 		return backup.NewNoopBackupExecutor()
 	}
 
 	return backup.NewAWSBackupExecutor(
-		r.Installation.Namespace,
+		installation.Namespace,
 		resourceName,
 		backup.PostgresSnapshotType,
 	)
@@ -558,7 +564,7 @@ func (r *Reconciler) HandleProgressPhase(ctx context.Context, serverClient k8scl
 	ssoCommon.SetProductVersion(rhssoVersion)
 	// The Keycloak Operator doesn't currently set the operator version
 	ssoCommon.SetOperatorVersion(operatorVersion)
-	err = r.ConfigManager.WriteConfig(config)
+	err = r.ConfigManager.WriteConfig(config, "rhssocommon.HandleProgressPhase")
 	if err != nil {
 		return integreatlyv1alpha1.PhaseFailed, err
 	}
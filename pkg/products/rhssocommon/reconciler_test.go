@@ -299,7 +299,7 @@ func TestReconciler_handleProgress(t *testing.T) {
 						"URL":       "rhsso.openshift-cluster.com",
 					}), nil
 				},
-				WriteConfigFunc: func(config config.ConfigReadable) error {
+				WriteConfigFunc: func(config config.ConfigReadable, writer string) error {
 					return errors.New("error writing config")
 				},
 			},
@@ -385,7 +385,7 @@ func basicConfigMock() *config.ConfigReadWriterMock {
 				"HOST":      "edge/route",
 			}), nil
 		},
-		WriteConfigFunc: func(config config.ConfigReadable) error {
+		WriteConfigFunc: func(config config.ConfigReadable, writer string) error {
 			return nil
 		},
 		GetOauthClientsSecretNameFunc: func() string {
@@ -1557,3 +1557,38 @@ func retrievePdb(client k8sclient.Client) (*policyv1.PodDisruptionBudget, error)
 
 	return pdb, nil
 }
+
+func TestContextKeycloakClientFactory(t *testing.T) {
+	requests := 0
+	factory := &keycloakCommon.KeycloakClientFactoryMock{
+		AuthenticatedClientFunc: func(_ keycloak.Keycloak) (keycloakCommon.KeycloakInterface, error) {
+			return &keycloakCommon.KeycloakInterfaceMock{
+				ListRealmsFunc: func() ([]*keycloak.KeycloakAPIRealm, error) {
+					requests++
+					return nil, nil
+				},
+			}, nil
+		},
+	}
+	cause := errors.New("reconcile timed out")
+	ctx, cancel := context.WithCancelCause(context.TODO())
+
+	client, err := NewContextKeycloakClientFactory(ctx, factory).AuthenticatedClient(keycloak.Keycloak{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ListRealms(); err != nil {
+		t.Fatal(err)
+	}
+
+	cancel(cause)
+	if _, err := client.ListRealms(); !errors.Is(err, cause) {
+		t.Errorf("expected the request to fail with the cancellation cause, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected no request to keycloak once the context is done, got %d requests", requests)
+	}
+	if _, err := NewContextKeycloakClientFactory(ctx, factory).AuthenticatedClient(keycloak.Keycloak{}); !errors.Is(err, cause) {
+		t.Errorf("expected the client creation to fail with the cancellation cause, got %v", err)
+	}
+}
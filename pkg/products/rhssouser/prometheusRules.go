@@ -1,7 +1,12 @@
 package rhssouser
 
 import (
+	"context"
 	"fmt"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/constants"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"strings"
 
 	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
@@ -11,7 +16,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
-func (r *Reconciler) newAlertsReconciler(logger l.Logger, installType string, namespace string) resources.AlertReconciler {
+func (r *Reconciler) newAlertsReconciler(logger l.Logger, installType string, namespace string) *resources.AlertReconcilerImpl {
 	installationName := resources.InstallationNames[installType]
 
 	alertName := "user-sso-ksm-endpoint-alerts"
@@ -169,3 +174,27 @@ func (r *Reconciler) newAlertsReconciler(logger l.Logger, installType string, na
 		},
 	}
 }
+
+// AlertReconcilers returns the reconcilers of all the alerts created for user SSO, including the alerts
+// of its postgres instance, so that they can be rendered without a cluster
+func (r *Reconciler) AlertReconcilers(_ resources.AlertInputs, _ quota.ProductConfig) ([]*resources.AlertReconcilerImpl, error) {
+	return []*resources.AlertReconcilerImpl{
+		r.newAlertsReconciler(r.Log, r.Installation.Spec.Type, config.GetOboNamespace(r.Installation.Namespace)),
+		resources.PostgresAlertsReconciler(r.Installation, constants.RHSSOUserProstgresPrefix+r.Installation.Name, defaultNamespace, r.Log),
+	}, nil
+}
+
+// deleteAlerts deletes the alerts of user SSO from the observability namespace, which is kept when user SSO is
+// disabled on the installation. The keycloak alerts exported to the namespace are named after the product
+func (r *Reconciler) deleteAlerts(ctx context.Context, client k8sclient.Client) error {
+	namespace := config.GetOboNamespace(r.Installation.Namespace)
+
+	alerts := r.newAlertsReconciler(r.Log, r.Installation.Spec.Type, namespace).Alerts
+	alerts = append(alerts, resources.AlertConfiguration{
+		AlertName: string(r.Config.GetProductName()),
+		Namespace: namespace,
+		Rules:     []monv1.Rule{},
+	})
+
+	return resources.DeleteAlerts(ctx, client, alerts)
+}
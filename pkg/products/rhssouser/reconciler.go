@@ -3,6 +3,7 @@ package rhssouser
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -32,6 +33,7 @@ import (
 
 	oauthClient "github.com/openshift/client-go/oauth/clientset/versioned/typed/oauth/v1"
 
+	"github.com/integr8ly/integreatly-operator/pkg/resources/backup"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/constants"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -54,7 +56,7 @@ var (
 const (
 	masterRealmLabelKey         = "sso"
 	masterRealmLabelValue       = "master"
-	developersGroupName         = "rhmi-developers"
+	developersGroupName         = userHelper.DevelopersGroupName
 	dedicatedAdminsGroupName    = "dedicated-admins"
 	realmManagersGroupName      = "realm-managers"
 	fullRealmManagersGroupPath  = dedicatedAdminsGroupName + "/" + realmManagersGroupName
@@ -69,6 +71,18 @@ const (
 	userSSOIcon = "data:image/svg+xml;base64,PD94bWwgdmVyc2lvbj0iMS4wIiBlbmNvZGluZz0idXRmLTgiPz4KPCEtLSBHZW5lcmF0b3I6IEFkb2JlIElsbHVzdHJhdG9yIDI1LjIuMCwgU1ZHIEV4cG9ydCBQbHVnLUluIC4gU1ZHIFZlcnNpb246IDYuMDAgQnVpbGQgMCkgIC0tPgo8c3ZnIHZlcnNpb249IjEuMSIgaWQ9IkxheWVyXzEiIHhtbG5zPSJodHRwOi8vd3d3LnczLm9yZy8yMDAwL3N2ZyIgeG1sbnM6eGxpbms9Imh0dHA6Ly93d3cudzMub3JnLzE5OTkveGxpbmsiIHg9IjBweCIgeT0iMHB4IgoJIHZpZXdCb3g9IjAgMCAzNyAzNyIgc3R5bGU9ImVuYWJsZS1iYWNrZ3JvdW5kOm5ldyAwIDAgMzcgMzc7IiB4bWw6c3BhY2U9InByZXNlcnZlIj4KPHN0eWxlIHR5cGU9InRleHQvY3NzIj4KCS5zdDB7ZmlsbDojRUUwMDAwO30KCS5zdDF7ZmlsbDojRkZGRkZGO30KPC9zdHlsZT4KPGc+Cgk8cGF0aCBkPSJNMjcuNSwwLjVoLTE4Yy00Ljk3LDAtOSw0LjAzLTksOXYxOGMwLDQuOTcsNC4wMyw5LDksOWgxOGM0Ljk3LDAsOS00LjAzLDktOXYtMThDMzYuNSw0LjUzLDMyLjQ3LDAuNSwyNy41LDAuNUwyNy41LDAuNXoiCgkJLz4KCTxnPgoJCTxwYXRoIGNsYXNzPSJzdDAiIGQ9Ik0yNSwyMi4zN2MtMC45NSwwLTEuNzUsMC42My0yLjAyLDEuNWgtMS44NVYyMS41YzAtMC4zNS0wLjI4LTAuNjItMC42Mi0wLjYycy0wLjYyLDAuMjgtMC42MiwwLjYydjMKCQkJYzAsMC4zNSwwLjI4LDAuNjIsMC42MiwwLjYyaDIuNDhjMC4yNywwLjg3LDEuMDcsMS41LDIuMDIsMS41YzEuMTcsMCwyLjEyLTAuOTUsMi4xMi0yLjEyUzI2LjE3LDIyLjM3LDI1LDIyLjM3eiBNMjUsMjUuMzcKCQkJYy0wLjQ4LDAtMC44OC0wLjM5LTAuODgtMC44OHMwLjM5LTAuODgsMC44OC0wLjg4czAuODgsMC4zOSwwLjg4LDAuODhTMjUuNDgsMjUuMzcsMjUsMjUuMzd6Ii8+CgkJPHBhdGggY2xhc3M9InN0MCIgZD0iTTIwLjUsMTYuMTJjMC4zNCwwLDAuNjItMC4yOCwwLjYyLTAuNjJ2LTIuMzhoMS45MWMwLjMyLDAuNzcsMS4wOCwxLjMxLDEuOTYsMS4zMQoJCQljMS4xNywwLDIuMTItMC45NSwyLjEyLTIuMTJzLTAuOTUtMi4xMi0yLjEyLTIuMTJjLTEuMDIsMC0xLjg4LDAuNzMtMi4wOCwxLjY5SDIwLjVjLTAuMzQsMC0wLjYyLDAuMjgtMC42MiwwLjYydjMKCQkJQzE5Ljg3LDE1Ljg1LDIwLjE2LDE2LjEyLDIwLjUsMTYuMTJ6IE0yNSwxMS40M2MwLjQ4LDAsMC44OCwwLjM5LDAuODgsMC44OHMtMC4zOSwwLjg4LTAuODgsMC44OHMtMC44OC0wLjM5LTAuODgtMC44OAoJCQlTMjQuNTIsMTEuNDMsMjUsMTEuNDN6Ii8+CgkJPHBhdGggY2xhc3M9InN0MCIgZD0iTTEyLjEyLDE5Ljk2di0wLjg0aDIuMzhjMC4zNCwwLDAuNjItMC4yOCwwLjYyLTAuNjJzLTAuMjgtMC42Mi0wLjYyLTAuNjJoLTIuMzh2LTAuOTEKCQkJYzAtMC4zNS0wLjI4LTAuNjItMC42Mi0wLjYyaC0zYy0wLjM0LDAtMC42MiwwLjI4LTAuNjIsMC42MnYzYzAsMC4zNSwwLjI4LDAuNjIsMC42MiwwLjYyaDNDMTEuODQsMjAuNTksMTIuMTIsMjAuMzEsMTIuMTIsMTkuOTYKCQkJeiBNMTAuODcsMTkuMzRIOS4xMnYtMS43NWgxLjc1VjE5LjM0eiIvPgoJCTxwYXRoIGNsYXNzPSJzdDAiIGQ9Ik0yOC41LDE2LjM0aC0zYy0wLjM0LDAtMC42MiwwLjI4LTAuNjIsMC42MnYwLjkxSDIyLjVjLTAuMzQsMC0wLjYyLDAuMjgtMC42MiwwLjYyczAuMjgsMC42MiwwLjYyLDAuNjJoMi4zOAoJCQl2MC44NGMwLDAuMzUsMC4yOCwwLjYyLDAuNjIsMC42MmgzYzAuMzQsMCwwLjYyLTAuMjgsMC42Mi0wLjYydi0zQzI5LjEyLDE2LjYyLDI4Ljg0LDE2LjM0LDI4LjUsMTYuMzR6IE0yNy44NywxOS4zNGgtMS43NXYtMS43NQoJCQloMS43NVYxOS4zNHoiLz4KCQk8cGF0aCBjbGFzcz0ic3QwIiBkPSJNMTYuNSwyMC44N2MtMC4zNCwwLTAuNjMsMC4yOC0wLjYzLDAuNjJ2Mi4zOGgtMS44NWMtMC4yNy0wLjg3LTEuMDctMS41LTIuMDItMS41CgkJCWMtMS4xNywwLTIuMTIsMC45NS0yLjEyLDIuMTJzMC45NSwyLjEyLDIuMTIsMi4xMmMwLjk1LDAsMS43NS0wLjYzLDIuMDItMS41aDIuNDhjMC4zNCwwLDAuNjItMC4yOCwwLjYyLTAuNjJ2LTMKCQkJQzE3LjEyLDIxLjE1LDE2Ljg0LDIwLjg3LDE2LjUsMjAuODd6IE0xMiwyNS4zN2MtMC40OCwwLTAuODgtMC4zOS0wLjg4LTAuODhzMC4zOS0wLjg4LDAuODgtMC44OHMwLjg4LDAuMzksMC44OCwwLjg4CgkJCVMxMi40OCwyNS4zNywxMiwyNS4zN3oiLz4KCQk8cGF0aCBjbGFzcz0ic3QwIiBkPSJNMTYuNSwxMS44N2gtMi40MmMtMC4yLTAuOTctMS4wNi0xLjY5LTIuMDgtMS42OWMtMS4xNywwLTIuMTIsMC45NS0yLjEyLDIuMTJzMC45NSwyLjEyLDIuMTIsMi4xMgoJCQljMC44OCwwLDEuNjQtMC41NCwxLjk2LTEuMzFoMS45MXYyLjM4YzAsMC4zNSwwLjI4LDAuNjIsMC42MywwLjYyczAuNjItMC4yOCwwLjYyLTAuNjJ2LTNDMTcuMTIsMTIuMTUsMTYuODQsMTEuODcsMTYuNSwxMS44N3oKCQkJIE0xMiwxMy4xOGMtMC40OCwwLTAuODgtMC4zOS0wLjg4LTAuODhzMC4zOS0wLjg4LDAuODgtMC44OHMwLjg4LDAuMzksMC44OCwwLjg4UzEyLjQ4LDEzLjE4LDEyLDEzLjE4eiIvPgoJPC9nPgoJPHBhdGggY2xhc3M9InN0MSIgZD0iTTE4LjUsMjIuNjJjLTIuMjcsMC00LjEzLTEuODUtNC4xMy00LjEyczEuODUtNC4xMiw0LjEzLTQuMTJzNC4xMiwxLjg1LDQuMTIsNC4xMlMyMC43NywyMi42MiwxOC41LDIyLjYyegoJCSBNMTguNSwxNS42MmMtMS41OCwwLTIuODgsMS4yOS0yLjg4LDIuODhzMS4yOSwyLjg4LDIuODgsMi44OHMyLjg4LTEuMjksMi44OC0yLjg4UzIwLjA4LDE1LjYyLDE4LjUsMTUuNjJ6Ii8+CjwvZz4KPC9zdmc+Cg=="
 )
 
+// the roles and groups the members of the groups mapped to the user SSO admins are granted, the client roles
+// are recorded as client/role
+var (
+	adminRealmRoles  = []string{createRealmRoleName}
+	adminClientRoles = []string{
+		masterRealmClientName + "/view-clients",
+		masterRealmClientName + "/" + viewRealmRoleName,
+		masterRealmClientName + "/" + manageUsersRoleName,
+	}
+	adminGroups = []string{dedicatedAdminsGroupName, fullRealmManagersGroupPath}
+)
+
 var realmManagersClientRoles = []string{
 	"create-client",
 	"manage-authorization",
@@ -158,6 +172,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 			return integreatlyv1alpha1.PhaseFailed, err
 		}
 
+		if err := r.deleteAlerts(ctx, serverClient); err != nil {
+			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to delete user sso alerts: %v", err)
+		}
+
 		return integreatlyv1alpha1.PhaseCompleted, nil
 	}, r.Log)
 	if err != nil || phase == integreatlyv1alpha1.PhaseFailed {
@@ -260,7 +278,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, err
 	}
 
-	err = r.ConfigManager.WriteConfig(r.Config)
+	err = r.ConfigManager.WriteConfig(r.Config, "rhssouser.Reconcile")
 	if err != nil {
 		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("error writing to config in rhssouser reconciler: %w", err)
 	}
@@ -450,7 +468,7 @@ func (r *Reconciler) reconcileGroups(ctx context.Context, serverClient k8sclient
 		}
 
 		r.Config.SetDevelopersGroupConfigured(true)
-		err = r.ConfigManager.WriteConfig(r.Config)
+		err = r.ConfigManager.WriteConfig(r.Config, "rhssouser.reconcileGroups")
 		if err != nil {
 			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("could not update keycloak config for user-sso: %w", err)
 		}
@@ -466,7 +484,8 @@ func (r *Reconciler) reconcileGroups(ctx context.Context, serverClient k8sclient
 func (r *Reconciler) reconcileAdminUsers(ctx context.Context, serverClient k8sclient.Client, kcClient keycloakCommon.KeycloakInterface, keycloakUsers []keycloak.KeycloakAPIUser) (integreatlyv1alpha1.StatusPhase, error) {
 
 	// Sync keycloak with openshift users
-	users, err := syncAdminUsersInMasterRealm(keycloakUsers, ctx, serverClient, r.Config.GetNamespace())
+	auditor := userHelper.NewAuditor(serverClient, r.Recorder, r.Installation)
+	users, err := syncAdminUsersInMasterRealm(keycloakUsers, ctx, serverClient, r.Config.GetNamespace(), r.Installation.Namespace, auditor)
 	if err != nil {
 		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to synchronize the users: %w", err)
 	}
@@ -631,10 +650,14 @@ func getMasterLabels() map[string]string {
 	}
 }
 
-func syncAdminUsersInMasterRealm(keycloakUsers []keycloak.KeycloakAPIUser, ctx context.Context, serverClient k8sclient.Client, ns string) ([]keycloak.KeycloakAPIUser, error) {
+func syncAdminUsersInMasterRealm(keycloakUsers []keycloak.KeycloakAPIUser, ctx context.Context, serverClient k8sclient.Client, ns, installationNamespace string, auditor *userHelper.Auditor) ([]keycloak.KeycloakAPIUser, error) {
+	roleMappings, err := userHelper.GetRoleMappings(ctx, serverClient, installationNamespace)
+	if err != nil {
+		return nil, err
+	}
 
 	openshiftUsers := &usersv1.UserList{}
-	err := serverClient.List(ctx, openshiftUsers)
+	err = serverClient.List(ctx, openshiftUsers)
 	if err != nil {
 		return nil, err
 	}
@@ -644,22 +667,41 @@ func syncAdminUsersInMasterRealm(keycloakUsers []keycloak.KeycloakAPIUser, ctx c
 		return nil, err
 	}
 
-	dedicatedAdminUsers := getDedicatedAdmins(*openshiftUsers, *openshiftGroups)
+	dedicatedAdminUsers := getDedicatedAdmins(*openshiftUsers, *openshiftGroups, roleMappings)
 
-	// added => Newly added to dedicated-admins group and OS
+	// added => Newly added to a group mapped to the user SSO admins and OS
 	// deleted => No longer exists in OS, remove from SSO
-	// promoted => existing KC user, added to dedicated-admins group, promote KC privileges
-	// demoted => existing KC user, removed from dedicated-admins group, demote KC privileges
-	added, deleted, promoted, demoted := getUserDiff(keycloakUsers, openshiftUsers.Items, dedicatedAdminUsers)
+	added, deleted := getUserDiff(keycloakUsers, openshiftUsers.Items, dedicatedAdminUsers)
 
 	keycloakUsers, err = rhssocommon.DeleteKeycloakUsers(keycloakUsers, deleted, ns, ctx, serverClient)
+	for _, user := range deleted {
+		auditor.Record(ctx, userHelper.AuditSystemRHSSOUser, userHelper.AuditActionDeleted, user.UserName, "no longer an OpenShift user", err)
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	keycloakUsers = addKeycloakUsers(keycloakUsers, added)
-	keycloakUsers = promoteKeycloakUsers(keycloakUsers, promoted)
-	keycloakUsers = demoteKeycloakUsers(keycloakUsers, demoted)
+	for _, user := range added {
+		auditor.Record(ctx, userHelper.AuditSystemRHSSOUser, userHelper.AuditActionCreated, user.Name, "member of a group mapped to the user SSO admins", nil)
+	}
+
+	// promote the members of the groups mapped to the user SSO admins and demote the users that left them,
+	// the roles and groups granted to the users otherwise are left in place
+	for i := range keycloakUsers {
+		if getOpenShiftUser(keycloakUsers[i], openshiftUsers.Items) == nil {
+			continue
+		}
+		wasAdmin := hasAdminPrivileges(&keycloakUsers[i])
+		admin := kcUserInDedicatedAdmins(keycloakUsers[i], dedicatedAdminUsers)
+		keycloakUsers[i] = setMappedAdminGrants(keycloakUsers[i], admin)
+
+		if admin && !wasAdmin {
+			auditor.Record(ctx, userHelper.AuditSystemRHSSOUser, userHelper.AuditActionPromoted, keycloakUsers[i].UserName, "member of a group mapped to the user SSO admins", nil)
+		} else if !admin && wasAdmin && !hasAdminPrivileges(&keycloakUsers[i]) {
+			auditor.Record(ctx, userHelper.AuditSystemRHSSOUser, userHelper.AuditActionDemoted, keycloakUsers[i].UserName, "no longer a member of a group mapped to the user SSO admins", nil)
+		}
+	}
 
 	return keycloakUsers, nil
 }
@@ -668,7 +710,7 @@ func addKeycloakUsers(keycloakUsers []keycloak.KeycloakAPIUser, added []usersv1.
 
 	for _, osUser := range added {
 
-		keycloakUsers = append(keycloakUsers, keycloak.KeycloakAPIUser{
+		keycloakUsers = append(keycloakUsers, setMappedAdminGrants(keycloak.KeycloakAPIUser{
 			Enabled:       true,
 			UserName:      osUser.Name,
 			EmailVerified: true,
@@ -679,172 +721,117 @@ func addKeycloakUsers(keycloakUsers []keycloak.KeycloakAPIUser, added []usersv1.
 					UserName:         osUser.Name,
 				},
 			},
-			RealmRoles: []string{"offline_access", "uma_authorization", "create-realm"},
+			RealmRoles: []string{"offline_access", "uma_authorization"},
 			ClientRoles: map[string][]string{
 				"account": {
 					"manage-account",
 					"view-profile",
 				},
-				"master-realm": {
-					"view-clients",
-					"view-realm",
-					"manage-users",
-				},
 			},
-			Groups: []string{dedicatedAdminsGroupName, fullRealmManagersGroupPath},
-		})
+		}, true))
 	}
 	return keycloakUsers
 }
 
-func promoteKeycloakUsers(allUsers []keycloak.KeycloakAPIUser, promoted []keycloak.KeycloakAPIUser) []keycloak.KeycloakAPIUser {
+// setMappedAdminGrants grants the user the admin roles and groups when it is a member of a group mapped to the
+// user SSO admins, and removes the ones the mappings granted before otherwise. The roles and groups granted to
+// the user otherwise are kept
+func setMappedAdminGrants(user keycloak.KeycloakAPIUser, admin bool) keycloak.KeycloakAPIUser {
+	previousRealmRoles := user.Attributes[userHelper.MappedRealmRolesAttribute]
+	previousClientRoles := user.Attributes[userHelper.MappedClientRolesAttribute]
+	previousGroups := user.Attributes[userHelper.MappedGroupsAttribute]
+	if _, ok := user.Attributes[userHelper.MappedGroupsAttribute]; !ok && hasAdminPrivileges(&user) {
+		// the admins promoted before the grants were recorded
+		previousRealmRoles, previousClientRoles, previousGroups = adminRealmRoles, adminClientRoles, adminGroups
+	}
+
+	realmRoles, clientRoles, groups := []string{}, []string{}, []string{}
+	if admin {
+		realmRoles, clientRoles, groups = adminRealmRoles, adminClientRoles, adminGroups
+	}
+	user.RealmRoles = userHelper.MergeMapped(user.RealmRoles, previousRealmRoles, realmRoles)
+	user.ClientRoles = toClientRoles(userHelper.MergeMapped(fromClientRoles(user.ClientRoles), previousClientRoles, clientRoles))
+	user.Groups = userHelper.MergeMapped(user.Groups, previousGroups, groups)
+
+	attributes := map[string][]string{}
+	for key, value := range user.Attributes {
+		attributes[key] = value
+	}
+	attributes[userHelper.MappedRealmRolesAttribute] = realmRoles
+	attributes[userHelper.MappedClientRolesAttribute] = clientRoles
+	attributes[userHelper.MappedGroupsAttribute] = groups
+	user.Attributes = attributes
+	return user
+}
 
-	for _, promotedUser := range promoted {
-		for i, user := range allUsers {
-			// ID is not populated, have to use UserName. Should be unique on master Realm
-			if promotedUser.UserName == user.UserName {
-				allUsers[i].ClientRoles = map[string][]string{
-					"account": {
-						"manage-account",
-						"view-profile",
-					},
-					"master-realm": {
-						"view-clients",
-						"view-realm",
-						"manage-users",
-					}}
-				allUsers[i].RealmRoles = []string{"offline_access", "uma_authorization", "create-realm"}
-
-				// Add the "dedicated-admins" group if it's not there
-				hasDedicatedAdminGroup := false
-				hasRealmManagerGroup := false
-				for _, group := range allUsers[i].Groups {
-					if group == dedicatedAdminsGroupName {
-						hasDedicatedAdminGroup = true
-					}
-					if group == fullRealmManagersGroupPath {
-						hasRealmManagerGroup = true
-					}
-				}
-				if !hasDedicatedAdminGroup {
-					allUsers[i].Groups = append(allUsers[i].Groups, dedicatedAdminsGroupName)
-				}
-				if !hasRealmManagerGroup {
-					allUsers[i].Groups = append(allUsers[i].Groups, fullRealmManagersGroupPath)
-				}
+// fromClientRoles lists the client roles as client/role
+func fromClientRoles(clientRoles map[string][]string) []string {
+	clients := make([]string, 0, len(clientRoles))
+	for client := range clientRoles {
+		clients = append(clients, client)
+	}
+	sort.Strings(clients)
 
-				break
-			}
+	roles := []string{}
+	for _, client := range clients {
+		for _, role := range clientRoles[client] {
+			roles = append(roles, client+"/"+role)
 		}
 	}
-
-	return allUsers
+	return roles
 }
 
-func demoteKeycloakUsers(allUsers []keycloak.KeycloakAPIUser, demoted []keycloak.KeycloakAPIUser) []keycloak.KeycloakAPIUser {
-
-	for _, demotedUser := range demoted {
-		for i, user := range allUsers {
-			// ID is not populated, have to use UserName. Should be unique on master Realm
-			if demotedUser.UserName == user.UserName { // ID is not set but UserName is
-				allUsers[i].ClientRoles = map[string][]string{
-					"account": {
-						"manage-account",
-						"manage-account-links",
-						"view-profile",
-					}}
-				allUsers[i].RealmRoles = []string{"offline_access", "uma_authorization"}
-				// Remove the dedicated-admins group from the user groups list
-				groups := []string{}
-				for _, group := range allUsers[i].Groups {
-					if (group != dedicatedAdminsGroupName) && (group != fullRealmManagersGroupPath) {
-						groups = append(groups, group)
-					}
-				}
-				allUsers[i].Groups = groups
-				break
-			}
+func toClientRoles(roles []string) map[string][]string {
+	clientRoles := map[string][]string{}
+	for _, role := range roles {
+		if client, name, ok := strings.Cut(role, "/"); ok {
+			clientRoles[client] = append(clientRoles[client], name)
 		}
 	}
-
-	return allUsers
+	return clientRoles
 }
 
 // NOTE: The users type has a Groups field on it but it does not seem to get populated
 // hence the need to check by name which is not ideal. However, this is the only field
 // available on the Group type
-func getDedicatedAdmins(osUsers usersv1.UserList, groups usersv1.GroupList) (dedicatedAdmins []usersv1.User) {
-
-	var osUsersInGroup = getOsUsersInAdminsGroup(groups)
-
+func getDedicatedAdmins(osUsers usersv1.UserList, groups usersv1.GroupList, roleMappings userHelper.RoleMappings) (dedicatedAdmins []usersv1.User) {
 	for _, osUser := range osUsers.Items {
-		if contains(osUsersInGroup, osUser.Name) {
+		if roleMappings.IsUserSSOAdmin(osUser.Name, &groups) {
 			dedicatedAdmins = append(dedicatedAdmins, osUser)
 		}
 	}
 	return dedicatedAdmins
 }
 
-func getOsUsersInAdminsGroup(groups usersv1.GroupList) (users []string) {
-
-	for _, group := range groups.Items {
-		if group.Name == "dedicated-admins" {
-			if group.Users != nil {
-				users = group.Users
-			}
-			break
-		}
-	}
-
-	return users
-}
-
 // There are 3 conceptual user types
 // 1. OpenShift User. 2. Keycloak User created by CR 3. Keycloak User created by customer
 // The distinction is important as we want to try avoid managing users created by the customer apart from certain
 // scenarios such as removing a user if they do not exist in OpenShift. This needs further consideration
 
 // This function should return
-// 1. Users in dedicated-admins group but not keycloak master realm => Added
+// 1. Users in a group mapped to the user SSO admins but not keycloak master realm => Added
 // return osUser list
-// 2. Users in dedicated-admins group, in keycloak master realm, but not have privledges => Promoted
-// return keylcoak user list
-// 3. Users in OS, Users in keycloak master realm, represented by a Keycloak CR, but not dedicated-admins group => Demote
+// 2. Users not in OpenShift but in Keycloak Master Realm, represented by a Keycloak CR 			=> Delete
 // return keylcoak user list
-// 4. Users not in OpenShift but in Keycloak Master Realm, represented by a Keycloak CR 			=> Delete
-// return keylcoak user list
-func getUserDiff(keycloakUsers []keycloak.KeycloakAPIUser, openshiftUsers []usersv1.User, dedicatedAdmins []usersv1.User) ([]usersv1.User, []keycloak.KeycloakAPIUser, []keycloak.KeycloakAPIUser, []keycloak.KeycloakAPIUser) {
+func getUserDiff(keycloakUsers []keycloak.KeycloakAPIUser, openshiftUsers []usersv1.User, dedicatedAdmins []usersv1.User) ([]usersv1.User, []keycloak.KeycloakAPIUser) {
 	var added []usersv1.User
 	var deleted []keycloak.KeycloakAPIUser
-	var promoted []keycloak.KeycloakAPIUser
-	var demoted []keycloak.KeycloakAPIUser
 
 	for _, admin := range dedicatedAdmins {
-		keycloakUser := getKeyCloakUser(admin, keycloakUsers)
-		if keycloakUser == nil {
-			// User in dedicated-admins group but not keycloak master realm
+		if getKeyCloakUser(admin, keycloakUsers) == nil {
+			// User in a group mapped to the user SSO admins but not keycloak master realm
 			added = append(added, admin)
-		} else {
-			if !hasAdminPrivileges(keycloakUser) {
-				// User in dedicated-admins group, in keycloak master realm, but does not have privledges
-				promoted = append(promoted, *keycloakUser)
-			}
 		}
 	}
 
 	for i := range keycloakUsers {
-		kcUser := keycloakUsers[i]
-		osUser := getOpenShiftUser(kcUser, openshiftUsers)
-		if osUser != nil && !kcUserInDedicatedAdmins(kcUser, dedicatedAdmins) && hasAdminPrivileges(&kcUser) {
-			// User in OS and keycloak master realm, represented by a Keycloak CR, but not dedicated-admins group
-			demoted = append(demoted, kcUser)
-		} else if osUser == nil {
+		if getOpenShiftUser(keycloakUsers[i], openshiftUsers) == nil {
 			// User not in OpenShift but is in Keycloak Master Realm, represented by a Keycloak CR
-			deleted = append(deleted, kcUser)
+			deleted = append(deleted, keycloakUsers[i])
 		}
 	}
 
-	return added, deleted, promoted, demoted
+	return added, deleted
 }
 
 func kcUserInDedicatedAdmins(kcUser keycloak.KeycloakAPIUser, admins []usersv1.User) bool {
@@ -1378,3 +1365,8 @@ func (r *Reconciler) deleteConsoleLink(ctx context.Context, serverClient k8sclie
 
 	return nil
 }
+
+// PreUpgradeBackupExecutor returns the executor of the backups taken before user SSO is upgraded
+func PreUpgradeBackupExecutor(installation *integreatlyv1alpha1.RHMI) backup.BackupExecutor {
+	return rhssocommon.NewPreUpgradeBackupsExecutor(installation, postgresResourceName)
+}
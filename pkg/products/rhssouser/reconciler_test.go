@@ -71,7 +71,7 @@ func basicConfigMock() *config.ConfigReadWriterMock {
 				"HOST":      "edge/route",
 			}), nil
 		},
-		WriteConfigFunc: func(config config.ConfigReadable) error {
+		WriteConfigFunc: func(config config.ConfigReadable, writer string) error {
 			return nil
 		},
 		GetOauthClientsSecretNameFunc: func() string {
@@ -1629,3 +1629,63 @@ func assertRhssoOperatorMetricsServiceReconciled(t *testing.T, ctx context.Conte
 		t.Errorf("annotation %s: got %q, want %q", owner.IntegreatlyOwnerNamespace, svc.Annotations[owner.IntegreatlyOwnerNamespace], installation.Namespace)
 	}
 }
+
+func TestSetMappedAdminGrants(t *testing.T) {
+	admin := setMappedAdminGrants(keycloak.KeycloakAPIUser{
+		UserName:    "admin",
+		RealmRoles:  []string{"offline_access", "auditor"},
+		ClientRoles: map[string][]string{"account": {"view-profile"}},
+		Groups:      []string{"auditors"},
+	}, true)
+	if !hasAdminPrivileges(&admin) {
+		t.Fatalf("expected the admin privileges to be granted, got %v and %v", admin.RealmRoles, admin.ClientRoles)
+	}
+	if fmt.Sprint(admin.Groups) != fmt.Sprint([]string{"auditors", dedicatedAdminsGroupName, fullRealmManagersGroupPath}) {
+		t.Fatalf("expected the admin groups to be added, got %v", admin.Groups)
+	}
+
+	scenarios := []struct {
+		Name string
+		User keycloak.KeycloakAPIUser
+	}{
+		{
+			Name: "the grants recorded by the mappings are removed",
+			User: admin,
+		},
+		{
+			Name: "the admins promoted before the grants were recorded are demoted",
+			User: keycloak.KeycloakAPIUser{
+				UserName:    "admin",
+				RealmRoles:  []string{"offline_access", "auditor", createRealmRoleName},
+				ClientRoles: map[string][]string{"account": {"view-profile"}, masterRealmClientName: {"view-clients", viewRealmRoleName, manageUsersRoleName}},
+				Groups:      []string{"auditors", dedicatedAdminsGroupName, fullRealmManagersGroupPath},
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			demoted := setMappedAdminGrants(scenario.User, false)
+			if hasAdminPrivileges(&demoted) {
+				t.Fatalf("expected the admin privileges to be removed, got %v and %v", demoted.RealmRoles, demoted.ClientRoles)
+			}
+			if fmt.Sprint(demoted.RealmRoles) != fmt.Sprint([]string{"offline_access", "auditor"}) {
+				t.Errorf("expected the other realm roles to be kept, got %v", demoted.RealmRoles)
+			}
+			if fmt.Sprint(demoted.ClientRoles) != fmt.Sprint(map[string][]string{"account": {"view-profile"}}) {
+				t.Errorf("expected the other client roles to be kept, got %v", demoted.ClientRoles)
+			}
+			if fmt.Sprint(demoted.Groups) != fmt.Sprint([]string{"auditors"}) {
+				t.Errorf("expected the other groups to be kept, got %v", demoted.Groups)
+			}
+		})
+	}
+
+	manual := setMappedAdminGrants(setMappedAdminGrants(keycloak.KeycloakAPIUser{
+		UserName:   "manual",
+		RealmRoles: []string{createRealmRoleName},
+	}, false), false)
+	if fmt.Sprint(manual.RealmRoles) != fmt.Sprint([]string{createRealmRoleName}) {
+		t.Errorf("expected the realm roles granted outside of the mappings to be kept, got %v", manual.RealmRoles)
+	}
+}
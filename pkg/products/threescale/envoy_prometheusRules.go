@@ -8,9 +8,12 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
-func (r *Reconciler) newEnvoyAlertReconciler(logger l.Logger, installType string, namespace string) resources.AlertReconciler {
+// envoyAlertsName is the PrometheusRule of the alerts on the envoy proxy sidecars of the 3scale components
+const envoyAlertsName = "3scale-ksm-marin3r-alerts"
+
+func (r *Reconciler) newEnvoyAlertReconciler(logger l.Logger, installType string, namespace string) *resources.AlertReconcilerImpl {
 	installationName := resources.InstallationNames[installType]
-	alertName := "3scale-ksm-marin3r-alerts"
+	alertName := envoyAlertsName
 
 	return &resources.AlertReconcilerImpl{
 		Installation: r.installation,
@@ -2,6 +2,10 @@ package threescale
 
 import (
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
 	envoycorev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoylistenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	envoyratelimitconfigv3 "github.com/envoyproxy/go-control-plane/envoy/config/ratelimit/v3"
@@ -14,6 +18,7 @@ import (
 	"github.com/golang/protobuf/ptypes/duration"
 	"github.com/golang/protobuf/ptypes/wrappers"
 	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	marin3rconfig "github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/ratelimit"
 	"google.golang.org/protobuf/types/known/anypb"
 )
@@ -34,9 +39,7 @@ const (
 	BackendListenerName      = "http"
 	BackendNodeID            = "backend-listener-ratelimit"
 	BackendServiceName       = "backend-listener-proxy"
-	headerName               = "host"
 	tenantHeaderName         = "tenant"
-	safeRegex                = ".*apicast.*"
 	multitenantDescriptorKey = "per-mt-limit"
 )
 
@@ -65,12 +68,12 @@ var tsRatelimitDescriptor = envoyroutev3.RateLimit{
 	                descriptor_value: per-mt-limit
 	                headers:
 	                - name: tenant
-	                safe_regex_match:
-	                    google_re2: {}
-	                    regex: ".*apicast.*"
+	                  present_match: true
 	                - request_headers:
 	                    header_name: tenant
 	                    descriptor_key: tenant
+
+The tenant header is only set by the lua filter for requests it could identify a tenant for
 */
 var multiTenantRatelimitDescriptor = envoyroutev3.RateLimit{
 	Stage: &wrappers.UInt32Value{Value: 0},
@@ -81,12 +84,9 @@ var multiTenantRatelimitDescriptor = envoyroutev3.RateLimit{
 					DescriptorValue: multitenantDescriptorKey,
 					Headers: []*envoyroutev3.HeaderMatcher{
 						{
-							Name: headerName,
-							HeaderMatchSpecifier: &envoyroutev3.HeaderMatcher_SafeRegexMatch{
-								SafeRegexMatch: &matcher.RegexMatcher{
-									EngineType: &matcher.RegexMatcher_GoogleRe2{},
-									Regex:      safeRegex,
-								},
+							Name: tenantHeaderName,
+							HeaderMatchSpecifier: &envoyroutev3.HeaderMatcher_PresentMatch{
+								PresentMatch: true,
 							},
 						},
 					},
@@ -179,23 +179,25 @@ func getAPICastHTTPFilters() ([]*hcm.HttpFilter, error) {
 }
 
 /*
+Sets the tenant header, replacing any sent by the client, to the tenant identified according to the tenant identification config, e.g. for the
+default host pattern:
+
+local tenant_pattern = "^(.-)%-apicast"
 function envoy_on_request(request_handle)
-host = request_handle:headers():get('Host')
 local headers = request_handle:headers()
-split_string = Split(host, "-apicast")
-headers:add('tenant',split_string[1])
+local host = headers:get('Host') or ”
+local tenant = string.match(host, tenant_pattern)
+headers:remove('tenant')
+if tenant ~= nil and tenant ~= ” then
+headers:add('tenant', tenant)
 end
-function Split(s, delimiter)
-result = {};
-for match in (s..delimiter):gmatch("(.-)"..delimiter) do
-table.insert(result, match);
-end
-return result;
 end
 */
-func getMultitenantAPICastHTTPFilters() ([]*hcm.HttpFilter, error) {
-
-	luaFunctionToAddTSHeaders := "function envoy_on_request(request_handle) host = request_handle:headers():get('Host') local headers = request_handle:headers() split_string = Split(host, '-apicast') headers:add('tenant', split_string[1]) end function Split(s, delimiter) result = {}; for match in (s..delimiter):gmatch('(.-)'..delimiter) do table.insert(result, match); end return result; end"
+func getMultitenantAPICastHTTPFilters(identification marin3rconfig.TenantIdentificationConfig, tenantHosts map[string]string) ([]*hcm.HttpFilter, error) {
+	luaFunctionToAddTSHeaders, err := getTenantIdentificationLua(identification, tenantHosts)
+	if err != nil {
+		return nil, err
+	}
 
 	luaFilter := &lua.Lua{
 		InlineCode: luaFunctionToAddTSHeaders,
@@ -224,6 +226,83 @@ func getMultitenantAPICastHTTPFilters() ([]*hcm.HttpFilter, error) {
 	return httpFilters, nil
 }
 
+// getTenantIdentificationLua generates the lua filter code setting the tenant header
+func getTenantIdentificationLua(identification marin3rconfig.TenantIdentificationConfig, tenantHosts map[string]string) (string, error) {
+	var declarations, tenant string
+
+	switch identification.Mode {
+	case marin3rconfig.TenantIdentificationHostPattern:
+		declarations = fmt.Sprintf("local tenant_pattern = %s ", luaString(identification.HostPattern))
+		tenant = "string.match(host, tenant_pattern)"
+	case marin3rconfig.TenantIdentificationHeader:
+		tenant = fmt.Sprintf("headers:get(%s)", luaString(identification.Header))
+	case marin3rconfig.TenantIdentificationSNI:
+		tenant = "request_handle:streamInfo():requestedServerName()"
+	case marin3rconfig.TenantIdentificationHostLookup:
+		hosts := map[string]string{}
+		for host, name := range tenantHosts {
+			hosts[strings.ToLower(host)] = name
+		}
+		// explicitly configured hosts take precedence over the generated ones
+		for host, name := range identification.Hosts {
+			hosts[strings.ToLower(host)] = name
+		}
+		entries := make([]string, 0, len(hosts))
+		for _, host := range slices.Sorted(maps.Keys(hosts)) {
+			entries = append(entries, fmt.Sprintf("[%s] = %s", luaString(host), luaString(hosts[host])))
+		}
+		declarations = fmt.Sprintf("local tenant_hosts = { %s } ", strings.Join(entries, ", "))
+		// the port is not part of the lookup key
+		tenant = "tenant_hosts[string.lower(string.match(host, '^[^:]*'))]"
+	default:
+		return "", fmt.Errorf("unexpected tenant identification mode %q", identification.Mode)
+	}
+
+	return declarations +
+		"function envoy_on_request(request_handle) " +
+		"local headers = request_handle:headers() " +
+		"local host = headers:get('Host') or '' " +
+		fmt.Sprintf("local tenant = %s ", tenant) +
+		// a tenant header sent by the client must never reach the rate limit, it is dropped even when no tenant is found
+		fmt.Sprintf("headers:remove('%s') ", tenantHeaderName) +
+		fmt.Sprintf("if tenant ~= nil and tenant ~= '' then headers:add('%s', tenant) end ", tenantHeaderName) +
+		"end", nil
+}
+
+// getTenantApicastHosts maps the default apicast hosts of the approved tenant accounts to the tenant
+func getTenantApicastHosts(accounts []AccountDetail, routingSubdomain string) map[string]string {
+	hosts := map[string]string{}
+	for _, account := range accounts {
+		if account.State != "approved" {
+			continue
+		}
+		for _, env := range []string{"production", "staging"} {
+			hosts[fmt.Sprintf("%s-apicast-%s.%s", account.OrgName, env, routingSubdomain)] = account.OrgName
+		}
+	}
+	return hosts
+}
+
+// luaString quotes s as a lua string literal. Bytes outside printable ASCII use lua's decimal \ddd escape,
+// the only numeric escape supported by every lua version
+func luaString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x20 || c >= 0x7f:
+			fmt.Fprintf(&b, `\%03d`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
 /*
 *
 
@@ -304,7 +383,7 @@ virtualHosts:
     descriptorValue: slowpath
     stage: 0
 */
-func getAPICastVirtualHosts(installation *integreatlyv1alpha1.RHMI, clusterName string) []*envoyroutev3.VirtualHost {
+func getAPICastVirtualHosts(installation *integreatlyv1alpha1.RHMI, clusterName string, descriptors []marin3rconfig.RateLimitDescriptorConfig) []*envoyroutev3.VirtualHost {
 	virtualHost := envoyroutev3.VirtualHost{
 		Name:    clusterName,
 		Domains: []string{"*"},
@@ -324,7 +403,7 @@ func getAPICastVirtualHosts(installation *integreatlyv1alpha1.RHMI, clusterName
 						Timeout: &duration.Duration{
 							Seconds: 75,
 						},
-						RateLimits: getRateLimitsPerInstallType(installation),
+						RateLimits: getRateLimitsPerInstallType(installation, descriptors),
 					},
 				},
 			},
@@ -333,18 +412,118 @@ func getAPICastVirtualHosts(installation *integreatlyv1alpha1.RHMI, clusterName
 	return []*envoyroutev3.VirtualHost{&virtualHost}
 }
 
-func getRateLimitsPerInstallType(installation *integreatlyv1alpha1.RHMI) []*envoyroutev3.RateLimit {
+func getRateLimitsPerInstallType(installation *integreatlyv1alpha1.RHMI, descriptors []marin3rconfig.RateLimitDescriptorConfig) []*envoyroutev3.RateLimit {
 	var routes []*envoyroutev3.RateLimit
 
-	if !integreatlyv1alpha1.IsRHOAMMultitenant(integreatlyv1alpha1.InstallationType(installation.Spec.Type)) {
-		routes = []*envoyroutev3.RateLimit{&tsRatelimitDescriptor}
-	} else {
-		routes = []*envoyroutev3.RateLimit{&tsRatelimitDescriptor, &multiTenantRatelimitDescriptor}
+	// the per API descriptors follow the SKU wide one, at the positions limitador expects them, see marin3rconfig.DescriptorIndex
+	routes = append([]*envoyroutev3.RateLimit{&tsRatelimitDescriptor}, getDescriptorRateLimits(descriptors)...)
+	if integreatlyv1alpha1.IsRHOAMMultitenant(integreatlyv1alpha1.InstallationType(installation.Spec.Type)) {
+		routes = append(routes, &multiTenantRatelimitDescriptor)
 	}
 
 	return routes
 }
 
+/*
+Defines a pair of rate limits for each configured descriptor, exactly one of them
+produces a descriptor for any request
+
+  - actions:
+  - header_value_match:
+    descriptor_value: <name>
+    headers:
+  - name: :path
+    string_match:
+    prefix: <pathPrefix>
+  - name: :method
+    string_match:
+    exact: <method>
+  - name: :authority
+    string_match:
+    exact: <host>
+  - request_headers:
+    header_name: <credentialHeader>
+    descriptor_key: <credentialHeader>
+    skip_if_absent: true
+  - actions:
+  - header_value_match:
+    descriptor_value: unmatched:<name>
+    expect_match: false
+    headers: <same headers>
+*/
+func getDescriptorRateLimits(descriptors []marin3rconfig.RateLimitDescriptorConfig) []*envoyroutev3.RateLimit {
+	rateLimits := make([]*envoyroutev3.RateLimit, 0, 2*len(descriptors))
+
+	for _, descriptor := range descriptors {
+		var headers []*envoyroutev3.HeaderMatcher
+		if descriptor.PathPrefix != "" {
+			headers = append(headers, stringHeaderMatcher(":path", &matcher.StringMatcher{MatchPattern: &matcher.StringMatcher_Prefix{Prefix: descriptor.PathPrefix}}))
+		}
+		if descriptor.Method != "" {
+			headers = append(headers, stringHeaderMatcher(":method", &matcher.StringMatcher{MatchPattern: &matcher.StringMatcher_Exact{Exact: descriptor.Method}}))
+		}
+		if descriptor.Host != "" {
+			headers = append(headers, stringHeaderMatcher(":authority", &matcher.StringMatcher{MatchPattern: &matcher.StringMatcher_Exact{Exact: descriptor.Host}}))
+		}
+
+		actions := []*envoyroutev3.RateLimit_Action{
+			{
+				ActionSpecifier: &envoyroutev3.RateLimit_Action_HeaderValueMatch_{
+					HeaderValueMatch: &envoyroutev3.RateLimit_Action_HeaderValueMatch{
+						DescriptorKey:   marin3rconfig.DescriptorMatchKey,
+						DescriptorValue: descriptor.Name,
+						Headers:         headers,
+					},
+				},
+			},
+		}
+		// a missing credential header only drops its entry, the descriptor is still sent so the
+		// positions of the descriptors after it don't depend on the request
+		if descriptor.CredentialHeader != "" {
+			actions = append(actions, &envoyroutev3.RateLimit_Action{
+				ActionSpecifier: &envoyroutev3.RateLimit_Action_RequestHeaders_{
+					RequestHeaders: &envoyroutev3.RateLimit_Action_RequestHeaders{
+						HeaderName:    descriptor.CredentialHeader,
+						DescriptorKey: descriptor.CredentialHeader,
+						SkipIfAbsent:  true,
+					},
+				},
+			})
+		}
+
+		rateLimits = append(rateLimits,
+			&envoyroutev3.RateLimit{
+				Stage:   &wrappers.UInt32Value{Value: 0},
+				Actions: actions,
+			},
+			&envoyroutev3.RateLimit{
+				Stage: &wrappers.UInt32Value{Value: 0},
+				Actions: []*envoyroutev3.RateLimit_Action{
+					{
+						ActionSpecifier: &envoyroutev3.RateLimit_Action_HeaderValueMatch_{
+							HeaderValueMatch: &envoyroutev3.RateLimit_Action_HeaderValueMatch{
+								DescriptorKey:   marin3rconfig.DescriptorMatchKey,
+								DescriptorValue: descriptor.UnmatchedValue(),
+								ExpectMatch:     &wrappers.BoolValue{Value: false},
+								Headers:         headers,
+							},
+						},
+					},
+				},
+			},
+		)
+	}
+
+	return rateLimits
+}
+
+func stringHeaderMatcher(name string, pattern *matcher.StringMatcher) *envoyroutev3.HeaderMatcher {
+	return &envoyroutev3.HeaderMatcher{
+		Name:                 name,
+		HeaderMatchSpecifier: &envoyroutev3.HeaderMatcher_StringMatch{StringMatch: pattern},
+	}
+}
+
 /*
 *
 virtual_hosts:
@@ -0,0 +1,291 @@
+package threescale
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	envoyroutev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	marin3rconfig "github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/ratelimit"
+)
+
+func TestGetRateLimitsPerInstallType(t *testing.T) {
+	descriptors := []marin3rconfig.RateLimitDescriptorConfig{
+		{Name: "orders", PathPrefix: "/orders", Method: "POST", Unit: "minute", RequestsPerUnit: 100},
+		{Name: "payments", Host: "payments.example.com", CredentialHeader: "user_key", Unit: "hour", RequestsPerUnit: 5},
+	}
+
+	tests := []struct {
+		name             string
+		installationType integreatlyv1alpha1.InstallationType
+		descriptors      []marin3rconfig.RateLimitDescriptorConfig
+		want             int
+	}{
+		{
+			name:             "managed api without descriptors",
+			installationType: integreatlyv1alpha1.InstallationTypeManagedApi,
+			want:             1,
+		},
+		{
+			name:             "multitenant managed api without descriptors",
+			installationType: integreatlyv1alpha1.InstallationTypeMultitenantManagedApi,
+			want:             2,
+		},
+		{
+			name:             "managed api with descriptors",
+			installationType: integreatlyv1alpha1.InstallationTypeManagedApi,
+			descriptors:      descriptors,
+			want:             5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installation := &integreatlyv1alpha1.RHMI{Spec: integreatlyv1alpha1.RHMISpec{Type: string(tt.installationType)}}
+			rateLimits := getRateLimitsPerInstallType(installation, tt.descriptors)
+			if len(rateLimits) != tt.want {
+				t.Fatalf("expected %d rate limits, got %d", tt.want, len(rateLimits))
+			}
+			if rateLimits[0] != &tsRatelimitDescriptor {
+				t.Errorf("expected the SKU wide descriptor to be kept first")
+			}
+		})
+	}
+}
+
+// envoyRateLimitDescriptors returns the descriptors envoy sends to the rate limit service for a request
+// with the given pseudo and regular headers. A rate limit is skipped when any of its actions doesn't apply
+func envoyRateLimitDescriptors(t *testing.T, rateLimits []*envoyroutev3.RateLimit, headers map[string]string) []map[string]string {
+	var descriptors []map[string]string
+	for _, rateLimit := range rateLimits {
+		descriptor := map[string]string{}
+		applies := true
+		for _, action := range rateLimit.Actions {
+			switch specifier := action.ActionSpecifier.(type) {
+			case *envoyroutev3.RateLimit_Action_GenericKey_:
+				key := specifier.GenericKey.DescriptorKey
+				if key == "" {
+					key = "generic_key"
+				}
+				descriptor[key] = specifier.GenericKey.DescriptorValue
+			case *envoyroutev3.RateLimit_Action_HeaderValueMatch_:
+				match := specifier.HeaderValueMatch
+				matched := true
+				for _, header := range match.Headers {
+					value, present := headers[header.Name]
+					switch {
+					case header.GetStringMatch().GetPrefix() != "":
+						matched = matched && present && strings.HasPrefix(value, header.GetStringMatch().GetPrefix())
+					case header.GetStringMatch().GetExact() != "":
+						matched = matched && present && value == header.GetStringMatch().GetExact()
+					default:
+						matched = matched && present == header.GetPresentMatch()
+					}
+				}
+				if match.ExpectMatch != nil && !match.ExpectMatch.Value {
+					matched = !matched
+				}
+				if !matched {
+					applies = false
+					continue
+				}
+				key := match.DescriptorKey
+				if key == "" {
+					key = "header_match"
+				}
+				descriptor[key] = match.DescriptorValue
+			case *envoyroutev3.RateLimit_Action_RequestHeaders_:
+				value, present := headers[specifier.RequestHeaders.HeaderName]
+				if !present {
+					applies = applies && specifier.RequestHeaders.SkipIfAbsent
+					continue
+				}
+				descriptor[specifier.RequestHeaders.DescriptorKey] = value
+			default:
+				t.Fatalf("unexpected rate limit action %T", specifier)
+			}
+		}
+		if applies {
+			descriptors = append(descriptors, descriptor)
+		}
+	}
+	return descriptors
+}
+
+func TestDescriptorRateLimitsKeepTheirIndex(t *testing.T) {
+	descriptors := []marin3rconfig.RateLimitDescriptorConfig{
+		{Name: "orders", PathPrefix: "/orders", Method: "POST", Unit: "minute", RequestsPerUnit: 100},
+		{Name: "payments", Host: "payments.example.com", CredentialHeader: "user_key", Unit: "hour", RequestsPerUnit: 5},
+	}
+
+	requests := []struct {
+		name    string
+		headers map[string]string
+		want    []map[string]string
+	}{
+		{
+			name:    "request matching the first descriptor",
+			headers: map[string]string{":path": "/orders/1", ":method": "POST", ":authority": "shop.example.com"},
+			want:    []map[string]string{{"header_match": "orders"}, {"header_match": "unmatched:payments"}},
+		},
+		{
+			name:    "request matching the second descriptor with a credential",
+			headers: map[string]string{":path": "/pay", ":method": "POST", ":authority": "payments.example.com", "user_key": "key-a"},
+			want:    []map[string]string{{"header_match": "unmatched:orders"}, {"header_match": "payments", "user_key": "key-a"}},
+		},
+		{
+			name:    "request matching the second descriptor without a credential",
+			headers: map[string]string{":path": "/orders", ":method": "GET", ":authority": "payments.example.com"},
+			want:    []map[string]string{{"header_match": "unmatched:orders"}, {"header_match": "payments"}},
+		},
+		{
+			name:    "request matching both descriptors",
+			headers: map[string]string{":path": "/orders", ":method": "POST", ":authority": "payments.example.com", "user_key": "key-b"},
+			want:    []map[string]string{{"header_match": "orders"}, {"header_match": "payments", "user_key": "key-b"}},
+		},
+	}
+
+	for _, installationType := range []integreatlyv1alpha1.InstallationType{integreatlyv1alpha1.InstallationTypeManagedApi, integreatlyv1alpha1.InstallationTypeMultitenantManagedApi} {
+		installation := &integreatlyv1alpha1.RHMI{Spec: integreatlyv1alpha1.RHMISpec{Type: string(installationType)}}
+		rateLimits := getRateLimitsPerInstallType(installation, descriptors)
+
+		for _, tenant := range []string{"", "acme"} {
+			for _, request := range requests {
+				t.Run(fmt.Sprintf("%s %s tenant %q", installationType, request.name, tenant), func(t *testing.T) {
+					headers := map[string]string{}
+					for name, value := range request.headers {
+						headers[name] = value
+					}
+					if tenant != "" {
+						headers[tenantHeaderName] = tenant
+					}
+
+					got := envoyRateLimitDescriptors(t, rateLimits, headers)
+					if len(got) <= marin3rconfig.DescriptorIndex(len(descriptors)-1) {
+						t.Fatalf("expected a descriptor for every API, got %v", got)
+					}
+					if got[0]["generic_key"] != ratelimit.RateLimitDescriptorValue {
+						t.Errorf("expected the SKU wide descriptor first, got %v", got[0])
+					}
+					for i := range descriptors {
+						if descriptor := got[marin3rconfig.DescriptorIndex(i)]; !reflect.DeepEqual(descriptor, request.want[i]) {
+							t.Errorf("expected descriptor %v for %s, got %v", request.want[i], descriptors[i].Name, descriptor)
+						}
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestGetTenantIdentificationLua(t *testing.T) {
+	accounts := []AccountDetail{
+		{Id: 3, OrgName: "acme", State: "approved"},
+		{Id: 4, OrgName: "globex", State: "scheduled_for_deletion"},
+	}
+	tenantHosts := getTenantApicastHosts(accounts, "apps.example.com")
+
+	tests := []struct {
+		name           string
+		identification marin3rconfig.TenantIdentificationConfig
+		wantContains   []string
+		wantMissing    []string
+		wantErr        bool
+	}{
+		{
+			name:           "default host pattern",
+			identification: marin3rconfig.TenantIdentificationConfig{Mode: marin3rconfig.TenantIdentificationHostPattern, HostPattern: marin3rconfig.DefaultTenantHostPattern},
+			wantContains:   []string{`local tenant_pattern = "^(.-)%-apicast"`, "local tenant = string.match(host, tenant_pattern)"},
+		},
+		{
+			name:           "custom domain host pattern",
+			identification: marin3rconfig.TenantIdentificationConfig{Mode: marin3rconfig.TenantIdentificationHostPattern, HostPattern: "^api%.([^.]+)%.example%.org$"},
+			wantContains:   []string{`local tenant_pattern = "^api%.([^.]+)%.example%.org$"`},
+		},
+		{
+			name:           "explicit header",
+			identification: marin3rconfig.TenantIdentificationConfig{Mode: marin3rconfig.TenantIdentificationHeader, Header: "x-tenant"},
+			wantContains:   []string{`local tenant = headers:get("x-tenant")`},
+		},
+		{
+			name:           "sni",
+			identification: marin3rconfig.TenantIdentificationConfig{Mode: marin3rconfig.TenantIdentificationSNI},
+			wantContains:   []string{"local tenant = request_handle:streamInfo():requestedServerName()"},
+		},
+		{
+			name: "host lookup with custom domains",
+			identification: marin3rconfig.TenantIdentificationConfig{
+				Mode: marin3rconfig.TenantIdentificationHostLookup,
+				Hosts: map[string]string{
+					"API.acme.com":                          "acme",
+					"acme-apicast-staging.apps.example.com": "acme-staging",
+				},
+			},
+			wantContains: []string{
+				`["api.acme.com"] = "acme"`,
+				`["acme-apicast-production.apps.example.com"] = "acme"`,
+				`["acme-apicast-staging.apps.example.com"] = "acme-staging"`,
+				"local tenant = tenant_hosts[string.lower(string.match(host, '^[^:]*'))]",
+			},
+			wantMissing: []string{"globex"},
+		},
+		{
+			name:           "unknown mode",
+			identification: marin3rconfig.TenantIdentificationConfig{Mode: "cookie"},
+			wantErr:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := getTenantIdentificationLua(tt.identification, tenantHosts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getTenantIdentificationLua() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !strings.Contains(code, "headers:remove('tenant') if tenant ~= nil and tenant ~= '' then headers:add('tenant', tenant) end") {
+				t.Errorf("expected the tenant header sent by the client to be replaced, got %s", code)
+			}
+			for _, want := range tt.wantContains {
+				if !strings.Contains(code, want) {
+					t.Errorf("expected %q in %s", want, code)
+				}
+			}
+			for _, missing := range tt.wantMissing {
+				if strings.Contains(code, missing) {
+					t.Errorf("unexpected %q in %s", missing, code)
+				}
+			}
+		})
+	}
+}
+
+func TestLuaString(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "^(.-)%-apicast", want: `"^(.-)%-apicast"`},
+		{in: `say "hi" \ bye`, want: `"say \"hi\" \\ bye"`},
+		{in: "line\nbreak\ttab", want: `"line\010break\009tab"`},
+		{in: "café", want: `"caf\195\169"`},
+		{in: "\x00\x7f", want: `"\000\127"`},
+	}
+	for _, tt := range tests {
+		if got := luaString(tt.in); got != tt.want {
+			t.Errorf("luaString(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMultitenantRatelimitDescriptorMatchesTenantHeader(t *testing.T) {
+	headers := multiTenantRatelimitDescriptor.Actions[0].GetHeaderValueMatch().Headers
+	if len(headers) != 1 || headers[0].Name != tenantHeaderName || !headers[0].GetPresentMatch() {
+		t.Fatalf("expected the multitenant descriptor to match any request with a tenant header, got %v", headers)
+	}
+}
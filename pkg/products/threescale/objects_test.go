@@ -1020,7 +1020,7 @@ func getBasicConfigMoc() *config.ConfigReadWriterMock {
 		GetOperatorNamespaceFunc: func() string {
 			return integreatlyOperatorNamespace
 		},
-		WriteConfigFunc: func(config config.ConfigReadable) error {
+		WriteConfigFunc: func(config config.ConfigReadable, writer string) error {
 			return nil
 		},
 		ReadRHSSOFunc: func() (*config.RHSSO, error) {
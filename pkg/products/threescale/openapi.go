@@ -0,0 +1,595 @@
+package threescale
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// OpenAPILabel marks the ConfigMaps holding an OpenAPI 3 document to onboard into 3scale
+	OpenAPILabel = "integreatly.org/3scale-openapi"
+	// OpenAPIDocumentKey is the ConfigMap data key containing the OpenAPI document, in YAML or JSON
+	OpenAPIDocumentKey = "openapi.yaml"
+
+	// openAPIStatusLabel marks the status ConfigMaps and holds the name of the source ConfigMap, cut to the
+	// length of a label value
+	openAPIStatusLabel  = "integreatly.org/3scale-openapi-source"
+	openAPIStatusSuffix = "-3scale-status"
+
+	// openAPIProductsConfigMapName is the ConfigMap of the 3scale namespace keeping the ids of what was onboarded
+	// from each source ConfigMap, keyed by <namespace>.<name>. The status ConfigMaps are garbage collected with
+	// their source, so the products are removed from these ids
+	openAPIProductsConfigMapName = "3scale-openapi-products"
+
+	openAPIStatusHashKey     = "documentHash"
+	openAPIStatusServiceKey  = "serviceId"
+	openAPIStatusBackendKey  = "backendId"
+	openAPIStatusPhaseKey    = "phase"
+	openAPIStatusMessageKey  = "message"
+	openAPIStatusEndpointKey = "productionEndpoint"
+	openAPIStatusUsageKey    = "backendUsageId"
+	openAPIStatusPlanKey     = "applicationPlanId"
+	openAPIStatusAttemptsKey = "attempts"
+	openAPIStatusLastKey     = "lastAttempt"
+
+	openAPIApplicationPlanName = "default"
+
+	// a failed document is retried after openAPIRetryDelay, doubled on every failed attempt up to openAPIMaxRetryDelay
+	openAPIRetryDelay    = time.Minute
+	openAPIMaxRetryDelay = time.Hour
+)
+
+var (
+	openAPIMethods = map[string]bool{
+		"get": true, "put": true, "post": true, "delete": true, "options": true, "head": true, "patch": true, "trace": true,
+	}
+	invalidSystemNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+)
+
+type openAPIDocument struct {
+	OpenAPI string `json:"openapi"`
+	Info    struct {
+		Title   string `json:"title"`
+		Version string `json:"version"`
+	} `json:"info"`
+	Servers []struct {
+		URL string `json:"url"`
+	} `json:"servers"`
+	// path items may hold fields other than operations, such as parameters, so they are decoded lazily
+	Paths map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+type openAPIOperation struct {
+	OperationID string `json:"operationId"`
+}
+
+// openAPIDefinition is what gets onboarded into 3scale from an OpenAPI document
+type openAPIDefinition struct {
+	Name            string
+	PrivateEndpoint string
+	Operations      []openAPIMappingRule
+}
+
+type openAPIMappingRule struct {
+	Metric  string
+	Method  string
+	Pattern string
+}
+
+// openAPIProduct is what was onboarded from a source ConfigMap, recorded in the products ConfigMap
+type openAPIProduct struct {
+	ServiceID string `json:"serviceId,omitempty"`
+	BackendID int    `json:"backendId,omitempty"`
+}
+
+// openAPIStatus is persisted in the status ConfigMap next to the source ConfigMap
+type openAPIStatus struct {
+	DocumentHash string
+	ServiceID    string
+	BackendID    int
+	// BackendUsageID is the backend last added to the product
+	BackendUsageID     int
+	ApplicationPlanID  string
+	Phase              integreatlyv1alpha1.StatusPhase
+	Message            string
+	ProductionEndpoint string
+	// Attempts counts the consecutive failed attempts to onboard the current document
+	Attempts    int
+	LastAttempt time.Time
+}
+
+// parseOpenAPIDocument derives the backend, metrics and mapping rules from an OpenAPI 3 document.
+// The first server is used as the private endpoint of the backend and every operation gets its own metric
+func parseOpenAPIDocument(name string, document []byte) (*openAPIDefinition, error) {
+	doc := &openAPIDocument{}
+	if err := yaml.Unmarshal(document, doc); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q, only OpenAPI 3 documents are supported", doc.OpenAPI)
+	}
+	if len(doc.Servers) == 0 {
+		return nil, fmt.Errorf("OpenAPI document has no servers, the first server is used as the backend private endpoint")
+	}
+	endpoint, err := url.Parse(doc.Servers[0].URL)
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("OpenAPI server %q must be an absolute URL", doc.Servers[0].URL)
+	}
+
+	definition := &openAPIDefinition{
+		Name:            name,
+		PrivateEndpoint: doc.Servers[0].URL,
+	}
+
+	metrics := map[string]bool{}
+	for path, item := range doc.Paths {
+		for method, raw := range item {
+			if !openAPIMethods[strings.ToLower(method)] {
+				continue
+			}
+			operation := &openAPIOperation{}
+			if err := json.Unmarshal(raw, operation); err != nil {
+				return nil, fmt.Errorf("failed to parse operation %s %s: %w", method, path, err)
+			}
+
+			metric := operation.OperationID
+			if metric == "" {
+				metric = strings.ToLower(method) + path
+			}
+			metric = strings.Trim(invalidSystemNameChars.ReplaceAllString(metric, "_"), "_")
+			if metrics[metric] {
+				return nil, fmt.Errorf("duplicate operation %s in OpenAPI document", metric)
+			}
+			metrics[metric] = true
+
+			definition.Operations = append(definition.Operations, openAPIMappingRule{
+				Metric:  metric,
+				Method:  strings.ToUpper(method),
+				Pattern: path + "$",
+			})
+		}
+	}
+	if len(definition.Operations) == 0 {
+		return nil, fmt.Errorf("OpenAPI document has no operations")
+	}
+
+	sort.Slice(definition.Operations, func(i, j int) bool {
+		return definition.Operations[i].Metric < definition.Operations[j].Metric
+	})
+
+	return definition, nil
+}
+
+// reconcileOpenAPIDocuments onboards the OpenAPI documents found in labelled ConfigMaps of the allowed
+// namespaces into 3scale. A changed document updates the product and backend created from the previous
+// revision and the result is reported in a status ConfigMap next to the source ConfigMap
+func (r *Reconciler) reconcileOpenAPIDocuments(ctx context.Context, serverClient k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
+	var sources []corev1.ConfigMap
+	for _, namespace := range r.openAPINamespaces() {
+		list := &corev1.ConfigMapList{}
+		if err := serverClient.List(ctx, list, k8sclient.InNamespace(namespace), k8sclient.HasLabels{OpenAPILabel}); err != nil {
+			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to list OpenAPI ConfigMaps in %s: %w", namespace, err)
+		}
+		sources = append(sources, list.Items...)
+	}
+	productsCM := &corev1.ConfigMap{}
+	productsKey := k8sclient.ObjectKey{Name: openAPIProductsConfigMapName, Namespace: r.Config.GetNamespace()}
+	if err := serverClient.Get(ctx, productsKey, productsCM); err != nil && !k8serr.IsNotFound(err) {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get OpenAPI products ConfigMap: %w", err)
+	}
+	if len(sources) == 0 && len(productsCM.Data) == 0 {
+		return integreatlyv1alpha1.PhaseCompleted, nil
+	}
+
+	accessToken, err := r.GetAdminToken(ctx, serverClient)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get 3scale admin token: %w", err)
+	}
+
+	existing := map[string]bool{}
+	for i := range sources {
+		source := &sources[i]
+		existing[openAPIProductKey(source)] = true
+		if err := r.reconcileOpenAPIDocument(ctx, serverClient, *accessToken, source); err != nil {
+			return integreatlyv1alpha1.PhaseFailed, err
+		}
+	}
+
+	// remove what was onboarded from ConfigMaps that have since been deleted, or whose namespace is no longer allowed
+	for key, value := range productsCM.Data {
+		if existing[key] {
+			continue
+		}
+		product := openAPIProduct{}
+		if err := json.Unmarshal([]byte(value), &product); err != nil {
+			r.log.Warningf("Dropping invalid OpenAPI product record", l.Fields{"source": key, "error": err})
+		} else {
+			r.log.Infof("Removing 3scale product of deleted OpenAPI ConfigMap", l.Fields{"source": key})
+			if err := r.deleteOpenAPIProduct(*accessToken, product); err != nil {
+				return integreatlyv1alpha1.PhaseFailed, err
+			}
+		}
+		if err := r.recordOpenAPIProduct(ctx, serverClient, key, nil); err != nil {
+			return integreatlyv1alpha1.PhaseFailed, err
+		}
+	}
+
+	return integreatlyv1alpha1.PhaseCompleted, nil
+}
+
+// openAPINamespaces are the namespaces OpenAPI documents are onboarded from, the 3scale namespace unless
+// they are configured
+func (r *Reconciler) openAPINamespaces() []string {
+	if namespaces := r.Config.GetOpenAPINamespaces(); len(namespaces) > 0 {
+		return namespaces
+	}
+	return []string{r.Config.GetNamespace()}
+}
+
+// recordOpenAPIProduct sets what was onboarded from a source ConfigMap in the products ConfigMap, or removes
+// the source from it when product is nil
+func (r *Reconciler) recordOpenAPIProduct(ctx context.Context, serverClient k8sclient.Client, key string, product *openAPIProduct) error {
+	productsCM := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      openAPIProductsConfigMapName,
+			Namespace: r.Config.GetNamespace(),
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, serverClient, productsCM, func() error {
+		if product == nil {
+			delete(productsCM.Data, key)
+			return nil
+		}
+		value, err := json.Marshal(product)
+		if err != nil {
+			return err
+		}
+		if productsCM.Data == nil {
+			productsCM.Data = map[string]string{}
+		}
+		productsCM.Data[key] = string(value)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to update OpenAPI products ConfigMap for %s: %w", key, err)
+	}
+	return nil
+}
+
+// reconcileOpenAPIDocument onboards a single document. Errors in the document or from the 3scale API
+// are reported in the status ConfigMap, only failures to read or write the status are returned
+func (r *Reconciler) reconcileOpenAPIDocument(ctx context.Context, serverClient k8sclient.Client, accessToken string, source *corev1.ConfigMap) error {
+	statusCM := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      source.Name + openAPIStatusSuffix,
+			Namespace: source.Namespace,
+		},
+	}
+	if err := serverClient.Get(ctx, k8sclient.ObjectKeyFromObject(statusCM), statusCM); err != nil && !k8serr.IsNotFound(err) {
+		return fmt.Errorf("failed to get OpenAPI status ConfigMap %s: %w", statusCM.Name, err)
+	}
+
+	document := source.Data[OpenAPIDocumentKey]
+	hash := sha256.Sum256([]byte(document))
+	documentHash := hex.EncodeToString(hash[:])
+
+	now := time.Now()
+	previous := getOpenAPIStatus(statusCM)
+	if previous.DocumentHash == documentHash {
+		if previous.Phase == integreatlyv1alpha1.PhaseCompleted {
+			return nil
+		}
+		// a failed document is retried with a backoff, or straight away once it changes
+		if now.Before(previous.nextAttempt()) {
+			return nil
+		}
+	}
+
+	status := r.onboardOpenAPIDocument(accessToken, source, document, previous)
+	status.DocumentHash = documentHash
+	status.LastAttempt = now
+	status.Attempts = 0
+
+	if status.Phase == integreatlyv1alpha1.PhaseCompleted {
+		r.log.Infof("Onboarded OpenAPI document into 3scale", l.Fields{"ns": source.Namespace, "name": source.Name, "serviceId": status.ServiceID})
+		r.recorder.Eventf(r.installation, "Normal", integreatlyv1alpha1.EventOpenAPIOnboarded, "Onboarded OpenAPI document %s/%s into 3scale", source.Namespace, source.Name)
+	} else {
+		status.Attempts = 1
+		if previous.DocumentHash == documentHash {
+			status.Attempts = previous.Attempts + 1
+		}
+		r.log.Warningf("Failed to onboard OpenAPI document into 3scale", l.Fields{"ns": source.Namespace, "name": source.Name, "error": status.Message, "attempts": status.Attempts})
+		r.recorder.Eventf(r.installation, "Warning", integreatlyv1alpha1.EventOpenAPIFailed, "Failed to onboard OpenAPI document %s/%s into 3scale: %s", source.Namespace, source.Name, status.Message)
+	}
+
+	// the ids are recorded before the status, which is deleted with the source
+	var product *openAPIProduct
+	if status.ServiceID != "" || status.BackendID != 0 {
+		product = &openAPIProduct{ServiceID: status.ServiceID, BackendID: status.BackendID}
+	}
+	if err := r.recordOpenAPIProduct(ctx, serverClient, openAPIProductKey(source), product); err != nil {
+		return err
+	}
+
+	if _, err := controllerutil.CreateOrUpdate(ctx, serverClient, statusCM, func() error {
+		if statusCM.Labels == nil {
+			statusCM.Labels = map[string]string{}
+		}
+		statusCM.Labels[openAPIStatusLabel] = openAPIStatusLabelValue(source.Name)
+		statusCM.OwnerReferences = []metav1.OwnerReference{
+			{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+				Name:       source.Name,
+				UID:        source.UID,
+			},
+		}
+		statusCM.Data = status.toData()
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to update OpenAPI status ConfigMap %s: %w", statusCM.Name, err)
+	}
+
+	return nil
+}
+
+// onboardOpenAPIDocument brings the product and backend created for the document in line with it. They are
+// updated in place so the product keeps its id, plans and applications across revisions of the document.
+// The ids are kept as soon as something is created so a failed attempt carries on where it stopped
+func (r *Reconciler) onboardOpenAPIDocument(accessToken string, source *corev1.ConfigMap, document string, previous openAPIStatus) openAPIStatus {
+	status := previous
+	status.Phase = integreatlyv1alpha1.PhaseFailed
+
+	definition, err := parseOpenAPIDocument(openAPISystemName(source), []byte(document))
+	if err != nil {
+		status.Message = err.Error()
+		return status
+	}
+
+	if err := r.reconcileOpenAPIBackend(accessToken, definition, &status); err != nil {
+		status.Message = err.Error()
+		return status
+	}
+	if err := r.reconcileOpenAPIProduct(accessToken, definition, &status); err != nil {
+		status.Message = err.Error()
+		return status
+	}
+
+	if err := r.tsClient.DeployProxy(accessToken, status.ServiceID); err != nil {
+		if tsIsNotFoundError(err) {
+			// the product was removed from 3scale, it is created again on the next attempt
+			status.ServiceID = ""
+			status.BackendUsageID = 0
+			status.ApplicationPlanID = ""
+		}
+		status.Message = fmt.Sprintf("failed to deploy proxy: %v", err)
+		return status
+	}
+	endpoint, err := r.tsClient.PromoteProxy(accessToken, status.ServiceID, "sandbox", "production")
+	if err != nil {
+		status.Message = fmt.Sprintf("failed to promote proxy: %v", err)
+		return status
+	}
+
+	status.Phase = integreatlyv1alpha1.PhaseCompleted
+	status.Message = fmt.Sprintf("onboarded %d operations", len(definition.Operations))
+	status.ProductionEndpoint = endpoint
+	return status
+}
+
+// reconcileOpenAPIBackend creates the backend, or updates its private endpoint, and syncs its metrics and
+// mapping rules with the operations of the document
+func (r *Reconciler) reconcileOpenAPIBackend(accessToken string, definition *openAPIDefinition, status *openAPIStatus) error {
+	if status.BackendID != 0 {
+		err := r.tsClient.UpdateBackend(accessToken, status.BackendID, definition.PrivateEndpoint)
+		if tsIsNotFoundError(err) {
+			status.BackendID = 0
+		} else if err != nil {
+			return fmt.Errorf("failed to update backend %d: %w", status.BackendID, err)
+		}
+	}
+	if status.BackendID == 0 {
+		backendID, err := r.tsClient.CreateBackend(accessToken, definition.Name, definition.PrivateEndpoint)
+		if err != nil {
+			return fmt.Errorf("failed to create backend: %w", err)
+		}
+		status.BackendID = backendID
+	}
+
+	metrics, err := r.tsClient.ListBackendMetrics(accessToken, status.BackendID)
+	if err != nil {
+		return fmt.Errorf("failed to list metrics of backend %d: %w", status.BackendID, err)
+	}
+	metricIDs := map[string]int{}
+	for _, metric := range metrics {
+		metricIDs[metric.FriendlyName] = metric.ID
+	}
+
+	wantedRules := map[string]bool{}
+	wantedMetrics := map[int]bool{}
+	for _, operation := range definition.Operations {
+		metricID, ok := metricIDs[operation.Metric]
+		if !ok {
+			metricID, err = r.tsClient.CreateMetric(accessToken, status.BackendID, operation.Metric, "hits")
+			if err != nil {
+				return fmt.Errorf("failed to create metric %s: %w", operation.Metric, err)
+			}
+			metricIDs[operation.Metric] = metricID
+		}
+		wantedMetrics[metricID] = true
+		wantedRules[openAPIMappingRuleKey(metricID, operation.Method, operation.Pattern)] = true
+	}
+
+	mappingRules, err := r.tsClient.ListBackendMappingRules(accessToken, status.BackendID)
+	if err != nil {
+		return fmt.Errorf("failed to list mapping rules of backend %d: %w", status.BackendID, err)
+	}
+	existingRules := map[string]bool{}
+	for _, mappingRule := range mappingRules {
+		key := openAPIMappingRuleKey(mappingRule.MetricID, mappingRule.HTTPMethod, mappingRule.Pattern)
+		if wantedRules[key] && !existingRules[key] {
+			existingRules[key] = true
+			continue
+		}
+		if err := r.tsClient.DeleteBackendMappingRule(accessToken, status.BackendID, mappingRule.ID); err != nil && !tsIsNotFoundError(err) {
+			return fmt.Errorf("failed to delete mapping rule %s %s: %w", mappingRule.HTTPMethod, mappingRule.Pattern, err)
+		}
+	}
+	for _, operation := range definition.Operations {
+		metricID := metricIDs[operation.Metric]
+		if existingRules[openAPIMappingRuleKey(metricID, operation.Method, operation.Pattern)] {
+			continue
+		}
+		if err := r.tsClient.CreateBackendMappingRule(accessToken, status.BackendID, metricID, operation.Method, operation.Pattern, 1); err != nil {
+			return fmt.Errorf("failed to create mapping rule %s %s: %w", operation.Method, operation.Pattern, err)
+		}
+	}
+
+	// metrics of operations removed from the document, the built in hits metric is kept
+	for _, metric := range metrics {
+		if wantedMetrics[metric.ID] || metric.SystemName == "hits" || strings.HasPrefix(metric.SystemName, "hits.") {
+			continue
+		}
+		if err := r.tsClient.DeleteBackendMetric(accessToken, status.BackendID, metric.ID); err != nil && !tsIsNotFoundError(err) {
+			return fmt.Errorf("failed to delete metric %s: %w", metric.FriendlyName, err)
+		}
+	}
+
+	return nil
+}
+
+// reconcileOpenAPIProduct creates the product the first time the document is onboarded and adds the backend
+// to it whenever the backend had to be created
+func (r *Reconciler) reconcileOpenAPIProduct(accessToken string, definition *openAPIDefinition, status *openAPIStatus) error {
+	if status.ServiceID == "" {
+		serviceID, err := r.tsClient.CreateService(accessToken, definition.Name, definition.Name)
+		if err != nil {
+			return fmt.Errorf("failed to create product: %w", err)
+		}
+		status.ServiceID = serviceID
+		status.BackendUsageID = 0
+		status.ApplicationPlanID = ""
+	}
+	if status.BackendUsageID != status.BackendID {
+		if err := r.tsClient.CreateBackendUsage(accessToken, status.ServiceID, status.BackendID, "/"); err != nil {
+			return fmt.Errorf("failed to add backend to product: %w", err)
+		}
+		status.BackendUsageID = status.BackendID
+	}
+	if status.ApplicationPlanID == "" {
+		planID, err := r.tsClient.CreateApplicationPlan(accessToken, status.ServiceID, openAPIApplicationPlanName)
+		if err != nil {
+			return fmt.Errorf("failed to create application plan: %w", err)
+		}
+		status.ApplicationPlanID = planID
+	}
+	return nil
+}
+
+func (r *Reconciler) deleteOpenAPIProduct(accessToken string, product openAPIProduct) error {
+	if product.ServiceID != "" {
+		if err := r.tsClient.DeleteService(accessToken, product.ServiceID); err != nil && !tsIsNotFoundError(err) {
+			return fmt.Errorf("failed to delete product %s: %w", product.ServiceID, err)
+		}
+	}
+	if product.BackendID != 0 {
+		if err := r.tsClient.DeleteBackend(accessToken, product.BackendID); err != nil && !tsIsNotFoundError(err) {
+			return fmt.Errorf("failed to delete backend %d: %w", product.BackendID, err)
+		}
+	}
+	return nil
+}
+
+func openAPIMappingRuleKey(metricID int, method, pattern string) string {
+	return fmt.Sprintf("%d %s %s", metricID, strings.ToUpper(method), pattern)
+}
+
+// openAPIProductKey is the key of a source ConfigMap in the products ConfigMap. Keys can't hold a slash and
+// namespaces can't hold a dot
+func openAPIProductKey(source *corev1.ConfigMap) string {
+	return source.Namespace + "." + source.Name
+}
+
+func openAPIStatusLabelValue(name string) string {
+	if len(name) <= validation.LabelValueMaxLength {
+		return name
+	}
+	// label values must end with an alphanumeric character
+	return strings.TrimRight(name[:validation.LabelValueMaxLength], "-_.")
+}
+
+func openAPISystemName(source *corev1.ConfigMap) string {
+	return invalidSystemNameChars.ReplaceAllString(fmt.Sprintf("%s-%s", source.Namespace, source.Name), "_")
+}
+
+func getOpenAPIStatus(statusCM *corev1.ConfigMap) openAPIStatus {
+	backendID, _ := strconv.Atoi(statusCM.Data[openAPIStatusBackendKey])
+	backendUsageID, _ := strconv.Atoi(statusCM.Data[openAPIStatusUsageKey])
+	attempts, _ := strconv.Atoi(statusCM.Data[openAPIStatusAttemptsKey])
+	lastAttempt, _ := time.Parse(time.RFC3339, statusCM.Data[openAPIStatusLastKey])
+	return openAPIStatus{
+		DocumentHash:       statusCM.Data[openAPIStatusHashKey],
+		ServiceID:          statusCM.Data[openAPIStatusServiceKey],
+		BackendID:          backendID,
+		BackendUsageID:     backendUsageID,
+		ApplicationPlanID:  statusCM.Data[openAPIStatusPlanKey],
+		Phase:              integreatlyv1alpha1.StatusPhase(statusCM.Data[openAPIStatusPhaseKey]),
+		Message:            statusCM.Data[openAPIStatusMessageKey],
+		ProductionEndpoint: statusCM.Data[openAPIStatusEndpointKey],
+		Attempts:           attempts,
+		LastAttempt:        lastAttempt,
+	}
+}
+
+// nextAttempt is when a failed document is onboarded again
+func (s openAPIStatus) nextAttempt() time.Time {
+	delay := openAPIRetryDelay
+	for i := 1; i < s.Attempts && delay < openAPIMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > openAPIMaxRetryDelay {
+		delay = openAPIMaxRetryDelay
+	}
+	return s.LastAttempt.Add(delay)
+}
+
+func (s openAPIStatus) toData() map[string]string {
+	data := map[string]string{
+		openAPIStatusHashKey:     s.DocumentHash,
+		openAPIStatusServiceKey:  s.ServiceID,
+		openAPIStatusPhaseKey:    string(s.Phase),
+		openAPIStatusMessageKey:  s.Message,
+		openAPIStatusEndpointKey: s.ProductionEndpoint,
+		openAPIStatusPlanKey:     s.ApplicationPlanID,
+		openAPIStatusLastKey:     s.LastAttempt.Format(time.RFC3339),
+	}
+	if s.BackendID != 0 {
+		data[openAPIStatusBackendKey] = strconv.Itoa(s.BackendID)
+	}
+	if s.BackendUsageID != 0 {
+		data[openAPIStatusUsageKey] = strconv.Itoa(s.BackendUsageID)
+	}
+	if s.Attempts != 0 {
+		data[openAPIStatusAttemptsKey] = strconv.Itoa(s.Attempts)
+	}
+	return data
+}
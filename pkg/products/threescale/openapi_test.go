@@ -0,0 +1,412 @@
+package threescale
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const petstoreDocument = `
+openapi: 3.0.0
+info:
+  title: Petstore
+  version: 1.0.0
+servers:
+  - url: http://petstore.team-a.svc:8080
+paths:
+  /pets:
+    get:
+      operationId: listPets
+    post:
+      operationId: createPet
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        required: true
+    get:
+      summary: no operation id
+`
+
+func TestParseOpenAPIDocument(t *testing.T) {
+	tests := []struct {
+		name      string
+		document  string
+		wantRules []openAPIMappingRule
+		wantErr   bool
+	}{
+		{
+			name:     "operations are mapped to metrics and mapping rules",
+			document: petstoreDocument,
+			wantRules: []openAPIMappingRule{
+				{Metric: "createPet", Method: "POST", Pattern: "/pets$"},
+				{Metric: "get_pets_petId", Method: "GET", Pattern: "/pets/{petId}$"},
+				{Metric: "listPets", Method: "GET", Pattern: "/pets$"},
+			},
+		},
+		{
+			name:     "swagger 2 documents are rejected",
+			document: "swagger: \"2.0\"\npaths: {}",
+			wantErr:  true,
+		},
+		{
+			name:     "documents without servers are rejected",
+			document: "openapi: 3.0.0\npaths:\n  /pets:\n    get: {}",
+			wantErr:  true,
+		},
+		{
+			name:     "documents without operations are rejected",
+			document: "openapi: 3.0.0\nservers:\n  - url: http://petstore:8080\npaths: {}",
+			wantErr:  true,
+		},
+		{
+			name:     "duplicate operation ids are rejected",
+			document: "openapi: 3.0.0\nservers:\n  - url: http://petstore:8080\npaths:\n  /a:\n    get:\n      operationId: op\n  /b:\n    get:\n      operationId: op",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			definition, err := parseOpenAPIDocument("petstore", []byte(tt.document))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseOpenAPIDocument() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if definition.PrivateEndpoint != "http://petstore.team-a.svc:8080" {
+				t.Errorf("unexpected private endpoint %s", definition.PrivateEndpoint)
+			}
+			if fmt.Sprint(definition.Operations) != fmt.Sprint(tt.wantRules) {
+				t.Errorf("expected mapping rules %v, got %v", tt.wantRules, definition.Operations)
+			}
+		})
+	}
+}
+
+// fakeOpenAPIThreeScale keeps the backends, metrics and mapping rules created through the mock
+type fakeOpenAPIThreeScale struct {
+	nextID       int
+	backends     map[int]string
+	metrics      map[int][]BackendMetric
+	mappingRules map[int][]BackendMappingRule
+	services     map[string][]int
+	plans        map[string]int
+}
+
+func newFakeOpenAPIThreeScale() *fakeOpenAPIThreeScale {
+	return &fakeOpenAPIThreeScale{
+		backends:     map[int]string{},
+		metrics:      map[int][]BackendMetric{},
+		mappingRules: map[int][]BackendMappingRule{},
+		services:     map[string][]int{},
+		plans:        map[string]int{},
+	}
+}
+
+func (f *fakeOpenAPIThreeScale) id() int {
+	f.nextID++
+	return f.nextID
+}
+
+func (f *fakeOpenAPIThreeScale) mock() *ThreeScaleInterfaceMock {
+	notFound := &tsError{message: "unexpected status code: 404. Body: ", StatusCode: http.StatusNotFound}
+	return &ThreeScaleInterfaceMock{
+		CreateBackendFunc: func(accessToken, name, privateEndpoint string) (int, error) {
+			backendID := f.id()
+			f.backends[backendID] = privateEndpoint
+			f.metrics[backendID] = []BackendMetric{{ID: f.id(), FriendlyName: "Hits", SystemName: fmt.Sprintf("hits.%d", backendID)}}
+			return backendID, nil
+		},
+		UpdateBackendFunc: func(accessToken string, backendID int, privateEndpoint string) error {
+			if _, ok := f.backends[backendID]; !ok {
+				return notFound
+			}
+			f.backends[backendID] = privateEndpoint
+			return nil
+		},
+		ListBackendMetricsFunc: func(accessToken string, backendID int) ([]BackendMetric, error) {
+			return append([]BackendMetric{}, f.metrics[backendID]...), nil
+		},
+		CreateMetricFunc: func(accessToken string, backendID int, friendlyName, unit string) (int, error) {
+			metricID := f.id()
+			f.metrics[backendID] = append(f.metrics[backendID], BackendMetric{ID: metricID, FriendlyName: friendlyName, SystemName: fmt.Sprintf("%s.%d", friendlyName, backendID)})
+			return metricID, nil
+		},
+		DeleteBackendMetricFunc: func(accessToken string, backendID, metricID int) error {
+			metrics := f.metrics[backendID][:0]
+			for _, metric := range f.metrics[backendID] {
+				if metric.ID != metricID {
+					metrics = append(metrics, metric)
+				}
+			}
+			f.metrics[backendID] = metrics
+			return nil
+		},
+		ListBackendMappingRulesFunc: func(accessToken string, backendID int) ([]BackendMappingRule, error) {
+			return append([]BackendMappingRule{}, f.mappingRules[backendID]...), nil
+		},
+		CreateBackendMappingRuleFunc: func(accessToken string, backendID, metricID int, httpMethod, pattern string, delta int) error {
+			f.mappingRules[backendID] = append(f.mappingRules[backendID], BackendMappingRule{ID: f.id(), MetricID: metricID, HTTPMethod: httpMethod, Pattern: pattern, Delta: delta})
+			return nil
+		},
+		DeleteBackendMappingRuleFunc: func(accessToken string, backendID, mappingRuleID int) error {
+			mappingRules := f.mappingRules[backendID][:0]
+			for _, mappingRule := range f.mappingRules[backendID] {
+				if mappingRule.ID != mappingRuleID {
+					mappingRules = append(mappingRules, mappingRule)
+				}
+			}
+			f.mappingRules[backendID] = mappingRules
+			return nil
+		},
+		CreateServiceFunc: func(accessToken, name, systemName string) (string, error) {
+			serviceID := fmt.Sprint(f.id())
+			f.services[serviceID] = nil
+			return serviceID, nil
+		},
+		CreateBackendUsageFunc: func(accessToken, serviceID string, backendID int, path string) error {
+			f.services[serviceID] = append(f.services[serviceID], backendID)
+			return nil
+		},
+		CreateApplicationPlanFunc: func(accessToken, serviceID, name string) (string, error) {
+			f.plans[serviceID]++
+			return fmt.Sprint(f.id()), nil
+		},
+		DeployProxyFunc: func(accessToken, serviceID string) error {
+			if _, ok := f.services[serviceID]; !ok {
+				return notFound
+			}
+			return nil
+		},
+		PromoteProxyFunc: func(accessToken, serviceID, env, to string) (string, error) {
+			return "https://petstore.apps.example.com", nil
+		},
+		DeleteServiceFunc: func(accessToken, serviceID string) error {
+			if _, ok := f.services[serviceID]; !ok {
+				return notFound
+			}
+			delete(f.services, serviceID)
+			return nil
+		},
+		DeleteBackendFunc: func(accessToken string, backendID int) error {
+			if _, ok := f.backends[backendID]; !ok {
+				return notFound
+			}
+			delete(f.backends, backendID)
+			delete(f.metrics, backendID)
+			delete(f.mappingRules, backendID)
+			return nil
+		},
+	}
+}
+
+// operations lists the mapping rules of a backend by metric name
+func (f *fakeOpenAPIThreeScale) operations(backendID int) []string {
+	names := map[int]string{}
+	for _, metric := range f.metrics[backendID] {
+		names[metric.ID] = metric.FriendlyName
+	}
+	var operations []string
+	for _, mappingRule := range f.mappingRules[backendID] {
+		operations = append(operations, fmt.Sprintf("%s %s %s", names[mappingRule.MetricID], mappingRule.HTTPMethod, mappingRule.Pattern))
+	}
+	sort.Strings(operations)
+	return operations
+}
+
+func TestReconcileOpenAPIDocuments(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seed := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "system-seed", Namespace: "3scale"},
+		Data:       map[string][]byte{"ADMIN_ACCESS_TOKEN": []byte("token")},
+	}
+	source := func(document string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "petstore", Namespace: "team-a", Labels: map[string]string{OpenAPILabel: "true"}},
+			Data:       map[string]string{OpenAPIDocumentKey: document},
+		}
+	}
+
+	threeScale := newFakeOpenAPIThreeScale()
+	tsClient := threeScale.mock()
+	// documents of namespaces that are not allowed are ignored
+	otherSource := source(petstoreDocument)
+	otherSource.Namespace = "team-b"
+	serverClient := utils.NewTestClient(scheme, seed, source(petstoreDocument), otherSource)
+	r := &Reconciler{
+		Config:       config.NewThreeScale(config.ProductConfig{"NAMESPACE": "3scale", "OPENAPI_NAMESPACES": "team-a, team-c"}),
+		log:          getLogger(),
+		installation: getTestInstallation("managed"),
+		tsClient:     tsClient,
+		recorder:     record.NewFakeRecorder(20),
+	}
+
+	statusKey := k8sclient.ObjectKey{Name: "petstore" + openAPIStatusSuffix, Namespace: "team-a"}
+	getStatus := func() openAPIStatus {
+		statusCM := &corev1.ConfigMap{}
+		if err := serverClient.Get(context.TODO(), statusKey, statusCM); err != nil {
+			t.Fatal(err)
+		}
+		return getOpenAPIStatus(statusCM)
+	}
+	reconcile := func() {
+		phase, err := r.reconcileOpenAPIDocuments(context.TODO(), serverClient)
+		if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+			t.Fatalf("unexpected reconcile result: phase %s, error %v", phase, err)
+		}
+	}
+	update := func(document string) {
+		if err := serverClient.Update(context.TODO(), source(document)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reconcile()
+	status := getStatus()
+	if status.Phase != integreatlyv1alpha1.PhaseCompleted || status.ServiceID == "" || status.ProductionEndpoint == "" {
+		t.Fatalf("expected the document to be onboarded, got %+v", status)
+	}
+	serviceID, backendID := status.ServiceID, status.BackendID
+	if len(threeScale.services) != 1 {
+		t.Fatalf("expected only the document of the allowed namespace to be onboarded, got products %v", threeScale.services)
+	}
+	statusCM := &corev1.ConfigMap{}
+	if err := serverClient.Get(context.TODO(), statusKey, statusCM); err != nil {
+		t.Fatal(err)
+	}
+	if len(statusCM.OwnerReferences) != 1 || statusCM.OwnerReferences[0].Kind != "ConfigMap" || statusCM.OwnerReferences[0].Name != "petstore" {
+		t.Fatalf("expected the status ConfigMap to be owned by its source, got %+v", statusCM.OwnerReferences)
+	}
+	wantOperations := []string{"createPet POST /pets$", "get_pets_petId GET /pets/{petId}$", "listPets GET /pets$"}
+	if got := threeScale.operations(backendID); fmt.Sprint(got) != fmt.Sprint(wantOperations) {
+		t.Fatalf("expected operations %v, got %v", wantOperations, got)
+	}
+	if fmt.Sprint(threeScale.services[serviceID]) != fmt.Sprint([]int{backendID}) || threeScale.plans[serviceID] != 1 {
+		t.Fatalf("expected the backend and a plan to be added to the product, got backends %v and %d plans", threeScale.services[serviceID], threeScale.plans[serviceID])
+	}
+
+	// an unchanged document is not onboarded again
+	reconcile()
+	if len(tsClient.CreateServiceCalls()) != 1 || len(tsClient.DeployProxyCalls()) != 1 {
+		t.Fatalf("expected the document to be onboarded once, got %d deploys", len(tsClient.DeployProxyCalls()))
+	}
+
+	// an invalid update is reported without touching the existing product and is retried with a backoff
+	update("openapi: 2.0")
+	reconcile()
+	status = getStatus()
+	if status.Phase != integreatlyv1alpha1.PhaseFailed || status.ServiceID != serviceID || status.Attempts != 1 || len(threeScale.services) != 1 {
+		t.Fatalf("expected the invalid document to be reported, got %+v", status)
+	}
+	reconcile()
+	if getStatus().Attempts != 1 {
+		t.Fatalf("expected the failed document not to be retried before the backoff expires")
+	}
+	if err := serverClient.Get(context.TODO(), statusKey, statusCM); err != nil {
+		t.Fatal(err)
+	}
+	statusCM.Data[openAPIStatusLastKey] = time.Now().Add(-openAPIRetryDelay).Format(time.RFC3339)
+	if err := serverClient.Update(context.TODO(), statusCM); err != nil {
+		t.Fatal(err)
+	}
+	reconcile()
+	if getStatus().Attempts != 2 {
+		t.Fatalf("expected the failed document to be retried once the backoff expired")
+	}
+
+	// a valid update is applied to the existing product and backend
+	update(strings.Replace(strings.Replace(petstoreDocument, "petstore.team-a.svc", "petstore-v2.team-a.svc", 1), "operationId: createPet", "operationId: addPet", 1))
+	reconcile()
+	status = getStatus()
+	if status.Phase != integreatlyv1alpha1.PhaseCompleted || status.ServiceID != serviceID || status.BackendID != backendID || status.Attempts != 0 {
+		t.Fatalf("expected the product to be updated in place, got %+v", status)
+	}
+	wantOperations = []string{"addPet POST /pets$", "get_pets_petId GET /pets/{petId}$", "listPets GET /pets$"}
+	if got := threeScale.operations(backendID); fmt.Sprint(got) != fmt.Sprint(wantOperations) {
+		t.Fatalf("expected operations %v, got %v", wantOperations, got)
+	}
+	if len(threeScale.metrics[backendID]) != 4 {
+		t.Fatalf("expected the metric of the removed operation to be deleted, got %v", threeScale.metrics[backendID])
+	}
+	if threeScale.backends[backendID] != "http://petstore-v2.team-a.svc:8080" {
+		t.Fatalf("expected the backend private endpoint to be updated, got %s", threeScale.backends[backendID])
+	}
+	if len(tsClient.CreateServiceCalls()) != 1 || len(tsClient.DeleteServiceCalls()) != 0 || threeScale.plans[serviceID] != 1 {
+		t.Fatalf("expected the product and its plan to be kept")
+	}
+
+	// a backend removed from 3scale is created again and added to the existing product
+	delete(threeScale.backends, backendID)
+	update(petstoreDocument)
+	reconcile()
+	status = getStatus()
+	if status.Phase != integreatlyv1alpha1.PhaseCompleted || status.ServiceID != serviceID || status.BackendID == backendID {
+		t.Fatalf("expected the backend to be created again, got %+v", status)
+	}
+	if fmt.Sprint(threeScale.services[serviceID]) != fmt.Sprint([]int{backendID, status.BackendID}) {
+		t.Fatalf("expected the new backend to be added to the product, got %v", threeScale.services[serviceID])
+	}
+
+	// deleting the source ConfigMap removes the product, the status is garbage collected with the source
+	if err := serverClient.Delete(context.TODO(), source("")); err != nil {
+		t.Fatal(err)
+	}
+	reconcile()
+	if len(threeScale.services) != 0 || len(threeScale.backends) != 0 {
+		t.Fatalf("expected the product to be removed, got products %v and backends %v", threeScale.services, threeScale.backends)
+	}
+	productsCM := &corev1.ConfigMap{}
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: openAPIProductsConfigMapName, Namespace: "3scale"}, productsCM); err != nil {
+		t.Fatal(err)
+	}
+	if len(productsCM.Data) != 0 {
+		t.Fatalf("expected the removed product not to be recorded, got %v", productsCM.Data)
+	}
+}
+
+func TestOpenAPIStatusLabelValue(t *testing.T) {
+	if got := openAPIStatusLabelValue("petstore"); got != "petstore" {
+		t.Errorf("expected a short name to be kept, got %s", got)
+	}
+	long := strings.Repeat("a", 62) + "-" + strings.Repeat("b", 10)
+	if got := openAPIStatusLabelValue(long); got != strings.Repeat("a", 62) {
+		t.Errorf("expected a long name to be cut to a valid label value, got %s", got)
+	}
+}
+
+func TestOpenAPIStatusNextAttempt(t *testing.T) {
+	lastAttempt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		attempts  int
+		wantDelay time.Duration
+	}{
+		{attempts: 0, wantDelay: openAPIRetryDelay},
+		{attempts: 1, wantDelay: openAPIRetryDelay},
+		{attempts: 3, wantDelay: 4 * openAPIRetryDelay},
+		{attempts: 20, wantDelay: openAPIMaxRetryDelay},
+	}
+	for _, tt := range tests {
+		status := openAPIStatus{Attempts: tt.attempts, LastAttempt: lastAttempt}
+		if got := status.nextAttempt().Sub(lastAttempt); got != tt.wantDelay {
+			t.Errorf("expected a delay of %s after %d attempts, got %s", tt.wantDelay, tt.attempts, got)
+		}
+	}
+}
@@ -1,31 +1,27 @@
 package threescale
 
 import (
-	"context"
 	"fmt"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/constants"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
 	"net/http"
 	"strings"
 
 	customDomain "github.com/integr8ly/integreatly-operator/pkg/resources/custom-domain"
 
-	"github.com/integr8ly/integreatly-operator/pkg/metrics"
 	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
 
 	"github.com/integr8ly/integreatly-operator/pkg/resources"
 	monv1 "github.com/rhobs/obo-prometheus-operator/pkg/apis/monitoring/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
-	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func (r *Reconciler) newAlertReconciler(logger l.Logger, installType string, ctx context.Context, serverClient k8sclient.Client, namespace string) (resources.AlertReconciler, error) {
+// newAlertReconciler returns the reconciler of the 3scale alerts. The container CPU metric depends on the cluster
+// version, see metrics.GetContainerCPUMetric
+func (r *Reconciler) newAlertReconciler(logger l.Logger, installType string, containerCpuMetric string, namespace string) *resources.AlertReconcilerImpl {
 	installationName := resources.InstallationNames[installType]
 
-	//clusterVersion
-	containerCpuMetric, err := metrics.GetContainerCPUMetric(ctx, serverClient, logger)
-	if err != nil {
-		return nil, err
-	}
-
 	alertNamePrefix := "3scale-"
 	operatorAlertNamePrefix := "3scale-operator-"
 
@@ -182,6 +178,7 @@ func (r *Reconciler) newAlertReconciler(logger l.Logger, installType string, ctx
 						Alert: "ThreeScaleUserCreationFailed",
 						Annotations: map[string]string{
 							"message": "3Scale user creation failed for user {{  $labels.username  }}",
+							"sop_url": resources.SopUrlThreeScaleUserActionFailed,
 						},
 						Expr:   intstr.FromString(fmt.Sprintf("threescale_user_action{action='%s'} != %d", http.MethodPost, http.StatusCreated)),
 						Labels: map[string]string{"severity": "warning"},
@@ -190,6 +187,7 @@ func (r *Reconciler) newAlertReconciler(logger l.Logger, installType string, ctx
 						Alert: "ThreeScaleUserDeletionFailed",
 						Annotations: map[string]string{
 							"message": "3Scale user deletion failed for user {{  $labels.username  }}",
+							"sop_url": resources.SopUrlThreeScaleUserActionFailed,
 						},
 						Expr:   intstr.FromString(fmt.Sprintf("threescale_user_action{action='%s'} != %d", http.MethodDelete, http.StatusOK)),
 						Labels: map[string]string{"severity": "warning"},
@@ -392,18 +390,33 @@ func (r *Reconciler) newAlertReconciler(logger l.Logger, installType string, ctx
 				},
 			},
 		},
-	}, nil
+	}
 }
 
 func alertThreeScaleContainerHighMemory(installationName string, namespace string) monv1.Rule {
 	return monv1.Rule{
 		Alert: "ThreeScaleContainerHighMemory",
 		Annotations: map[string]string{
-			"sop_url": resources.SopUrlAlertsAndTroubleshooting,
-			"message": "The {{  $labels.container  }} Container in the {{  $labels.pod  }} Pod has been using {{  $value  }}% of available memory for longer than 15 minutes.",
+			"sop_url":                     resources.SopUrlAlertsAndTroubleshooting,
+			"message":                     "The {{  $labels.container  }} Container in the {{  $labels.pod  }} Pod has been using {{  $value  }}% of available memory for longer than 15 minutes.",
+			resources.ThresholdAnnotation: "90",
 		},
-		Expr:   intstr.FromString(fmt.Sprintf("sum by(container, pod) (container_memory_usage_bytes{container!='', container!='system-provider', namespace='%[1]v'}) / sum by(container, pod) (kube_pod_container_resource_limits{namespace='%[1]v',resource='memory'}) * 100 > 90", namespace)),
+		Expr:   intstr.FromString(fmt.Sprintf("sum by(container, pod) (container_memory_usage_bytes{container!='', container!='system-provider', namespace='%[1]v'}) / sum by(container, pod) (kube_pod_container_resource_limits{namespace='%[1]v',resource='memory'}) * 100 > %[2]v", namespace, resources.ThresholdPlaceholder)),
 		For:    resources.DurationPtr("15m"),
 		Labels: map[string]string{"severity": "info", "product": installationName},
 	}
 }
+
+// AlertReconcilers returns the reconcilers of all the alerts created for 3scale, including the alerts
+// of its postgres and redis instances, so that they can be rendered without a cluster
+func (r *Reconciler) AlertReconcilers(inputs resources.AlertInputs, _ quota.ProductConfig) ([]*resources.AlertReconcilerImpl, error) {
+	namespace := config.GetOboNamespace(r.installation.Namespace)
+
+	return []*resources.AlertReconcilerImpl{
+		r.newAlertReconciler(r.log, r.installation.Spec.Type, inputs.ContainerCPUMetric, namespace),
+		r.newEnvoyAlertReconciler(r.log, r.installation.Spec.Type, namespace),
+		resources.RedisAlertsReconciler(r.installation, constants.ThreeScaleBackendRedisPrefix+r.installation.Name, defaultInstallationNamespace, r.log),
+		resources.RedisAlertsReconciler(r.installation, constants.ThreeScaleSystemRedisPrefix+r.installation.Name, defaultInstallationNamespace, r.log),
+		resources.PostgresAlertsReconciler(r.installation, constants.ThreeScalePostgresPrefix+r.installation.Name, defaultInstallationNamespace, r.log),
+	}, nil
+}
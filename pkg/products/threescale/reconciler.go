@@ -5,10 +5,13 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"maps"
 	"net"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	configv1 "github.com/openshift/api/config/v1"
@@ -26,6 +29,7 @@ import (
 	"github.com/integr8ly/integreatly-operator/pkg/resources/quota"
 	"github.com/integr8ly/integreatly-operator/version"
 	prometheus "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monv1 "github.com/rhobs/obo-prometheus-operator/pkg/apis/monitoring/v1"
 
 	"github.com/integr8ly/integreatly-operator/pkg/metrics"
 
@@ -38,6 +42,7 @@ import (
 	oauthv1 "github.com/openshift/api/oauth/v1"
 
 	"github.com/integr8ly/integreatly-operator/pkg/resources/events"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/images"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/ratelimit"
 
 	"github.com/integr8ly/integreatly-operator/pkg/resources/backup"
@@ -56,6 +61,7 @@ import (
 	keycloak "github.com/integr8ly/keycloak-client/apis/keycloak/v1alpha1"
 
 	"github.com/integr8ly/integreatly-operator/pkg/config"
+	marin3rconfig "github.com/integr8ly/integreatly-operator/pkg/products/marin3r/config"
 	"github.com/integr8ly/integreatly-operator/pkg/products/rhsso"
 	"github.com/integr8ly/integreatly-operator/pkg/resources"
 	"github.com/integr8ly/integreatly-operator/pkg/resources/marketplace"
@@ -98,11 +104,15 @@ const (
 	registrySecretName              = "threescale-registry-auth"
 	threeScaleIcon                  = "data:image/svg+xml;base64,PD94bWwgdmVyc2lvbj0iMS4wIiBlbmNvZGluZz0idXRmLTgiPz4KPCEtLSBHZW5lcmF0b3I6IEFkb2JlIElsbHVzdHJhdG9yIDI1LjIuMCwgU1ZHIEV4cG9ydCBQbHVnLUluIC4gU1ZHIFZlcnNpb246IDYuMDAgQnVpbGQgMCkgIC0tPgo8c3ZnIHZlcnNpb249IjEuMSIgaWQ9IkxheWVyXzEiIHhtbG5zPSJodHRwOi8vd3d3LnczLm9yZy8yMDAwL3N2ZyIgeG1sbnM6eGxpbms9Imh0dHA6Ly93d3cudzMub3JnLzE5OTkveGxpbmsiIHg9IjBweCIgeT0iMHB4IgoJIHZpZXdCb3g9IjAgMCAzNyAzNyIgc3R5bGU9ImVuYWJsZS1iYWNrZ3JvdW5kOm5ldyAwIDAgMzcgMzc7IiB4bWw6c3BhY2U9InByZXNlcnZlIj4KPHN0eWxlIHR5cGU9InRleHQvY3NzIj4KCS5zdDB7ZmlsbDojRUUwMDAwO30KCS5zdDF7ZmlsbDojRkZGRkZGO30KPC9zdHlsZT4KPGc+Cgk8cGF0aCBkPSJNMjcuNSwwLjVoLTE4Yy00Ljk3LDAtOSw0LjAzLTksOXYxOGMwLDQuOTcsNC4wMyw5LDksOWgxOGM0Ljk3LDAsOS00LjAzLDktOXYtMThDMzYuNSw0LjUzLDMyLjQ3LDAuNSwyNy41LDAuNUwyNy41LDAuNXoiCgkJLz4KCTxnPgoJCTxwYXRoIGNsYXNzPSJzdDAiIGQ9Ik0yNSwyMi4zN2MtMC45NSwwLTEuNzUsMC42My0yLjAyLDEuNWgtMS44NVYyMS41YzAtMC4zNS0wLjI4LTAuNjItMC42Mi0wLjYycy0wLjYyLDAuMjgtMC42MiwwLjYydjMKCQkJYzAsMC4zNSwwLjI4LDAuNjIsMC42MiwwLjYyaDIuNDhjMC4yNywwLjg3LDEuMDcsMS41LDIuMDIsMS41YzEuMTcsMCwyLjEyLTAuOTUsMi4xMi0yLjEyUzI2LjE3LDIyLjM3LDI1LDIyLjM3eiBNMjUsMjUuMzcKCQkJYy0wLjQ4LDAtMC44OC0wLjM5LTAuODgtMC44OHMwLjM5LTAuODgsMC44OC0wLjg4czAuODgsMC4zOSwwLjg4LDAuODhTMjUuNDgsMjUuMzcsMjUsMjUuMzd6Ii8+CgkJPHBhdGggY2xhc3M9InN0MCIgZD0iTTIwLjUsMTYuMTJjMC4zNCwwLDAuNjItMC4yOCwwLjYyLTAuNjJ2LTIuMzhoMS45MWMwLjMyLDAuNzcsMS4wOCwxLjMxLDEuOTYsMS4zMQoJCQljMS4xNywwLDIuMTItMC45NSwyLjEyLTIuMTJzLTAuOTUtMi4xMi0yLjEyLTIuMTJjLTEuMDIsMC0xLjg4LDAuNzMtMi4wOCwxLjY5SDIwLjVjLTAuMzQsMC0wLjYyLDAuMjgtMC42MiwwLjYydjMKCQkJQzE5Ljg3LDE1Ljg1LDIwLjE2LDE2LjEyLDIwLjUsMTYuMTJ6IE0yNSwxMS40M2MwLjQ4LDAsMC44OCwwLjM5LDAuODgsMC44OHMtMC4zOSwwLjg4LTAuODgsMC44OHMtMC44OC0wLjM5LTAuODgtMC44OAoJCQlTMjQuNTIsMTEuNDMsMjUsMTEuNDN6Ii8+CgkJPHBhdGggY2xhc3M9InN0MCIgZD0iTTEyLjEyLDE5Ljk2di0wLjg0aDIuMzhjMC4zNCwwLDAuNjItMC4yOCwwLjYyLTAuNjJzLTAuMjgtMC42Mi0wLjYyLTAuNjJoLTIuMzh2LTAuOTEKCQkJYzAtMC4zNS0wLjI4LTAuNjItMC42Mi0wLjYyaC0zYy0wLjM0LDAtMC42MiwwLjI4LTAuNjIsMC42MnYzYzAsMC4zNSwwLjI4LDAuNjIsMC42MiwwLjYyaDNDMTEuODQsMjAuNTksMTIuMTIsMjAuMzEsMTIuMTIsMTkuOTYKCQkJeiBNMTAuODcsMTkuMzRIOS4xMnYtMS43NWgxLjc1VjE5LjM0eiIvPgoJCTxwYXRoIGNsYXNzPSJzdDAiIGQ9Ik0yOC41LDE2LjM0aC0zYy0wLjM0LDAtMC42MiwwLjI4LTAuNjIsMC42MnYwLjkxSDIyLjVjLTAuMzQsMC0wLjYyLDAuMjgtMC42MiwwLjYyczAuMjgsMC42MiwwLjYyLDAuNjJoMi4zOAoJCQl2MC44NGMwLDAuMzUsMC4yOCwwLjYyLDAuNjIsMC42MmgzYzAuMzQsMCwwLjYyLTAuMjgsMC42Mi0wLjYydi0zQzI5LjEyLDE2LjYyLDI4Ljg0LDE2LjM0LDI4LjUsMTYuMzR6IE0yNy44NywxOS4zNGgtMS43NXYtMS43NQoJCQloMS43NVYxOS4zNHoiLz4KCQk8cGF0aCBjbGFzcz0ic3QwIiBkPSJNMTYuNSwyMC44N2MtMC4zNCwwLTAuNjMsMC4yOC0wLjYzLDAuNjJ2Mi4zOGgtMS44NWMtMC4yNy0wLjg3LTEuMDctMS41LTIuMDItMS41CgkJCWMtMS4xNywwLTIuMTIsMC45NS0yLjEyLDIuMTJzMC45NSwyLjEyLDIuMTIsMi4xMmMwLjk1LDAsMS43NS0wLjYzLDIuMDItMS41aDIuNDhjMC4zNCwwLDAuNjItMC4yOCwwLjYyLTAuNjJ2LTMKCQkJQzE3LjEyLDIxLjE1LDE2Ljg0LDIwLjg3LDE2LjUsMjAuODd6IE0xMiwyNS4zN2MtMC40OCwwLTAuODgtMC4zOS0wLjg4LTAuODhzMC4zOS0wLjg4LDAuODgtMC44OHMwLjg4LDAuMzksMC44OCwwLjg4CgkJCVMxMi40OCwyNS4zNywxMiwyNS4zN3oiLz4KCQk8cGF0aCBjbGFzcz0ic3QwIiBkPSJNMTYuNSwxMS44N2gtMi40MmMtMC4yLTAuOTctMS4wNi0xLjY5LTIuMDgtMS42OWMtMS4xNywwLTIuMTIsMC45NS0yLjEyLDIuMTJzMC45NSwyLjEyLDIuMTIsMi4xMgoJCQljMC44OCwwLDEuNjQtMC41NCwxLjk2LTEuMzFoMS45MXYyLjM4YzAsMC4zNSwwLjI4LDAuNjIsMC42MywwLjYyczAuNjItMC4yOCwwLjYyLTAuNjJ2LTNDMTcuMTIsMTIuMTUsMTYuODQsMTEuODcsMTYuNSwxMS44N3oKCQkJIE0xMiwxMy4xOGMtMC40OCwwLTAuODgtMC4zOS0wLjg4LTAuODhzMC4zOS0wLjg4LDAuODgtMC44OHMwLjg4LDAuMzksMC44OCwwLjg4UzEyLjQ4LDEzLjE4LDEyLDEzLjE4eiIvPgoJPC9nPgoJPHBhdGggY2xhc3M9InN0MSIgZD0iTTE4LjUsMjIuNjJjLTIuMjcsMC00LjEzLTEuODUtNC4xMy00LjEyczEuODUtNC4xMiw0LjEzLTQuMTJzNC4xMiwxLjg1LDQuMTIsNC4xMlMyMC43NywyMi42MiwxOC41LDIyLjYyegoJCSBNMTguNSwxNS42MmMtMS41OCwwLTIuODgsMS4yOS0yLjg4LDIuODhzMS4yOSwyLjg4LDIuODgsMi44OHMyLjg4LTEuMjksMi44OC0yLjg4UzIwLjA4LDE1LjYyLDE4LjUsMTUuNjJ6Ii8+CjwvZz4KPC9zdmc+Cg=="
 	user3ScaleID                    = "3scale_user_id"
+	user3ScaleManagedRole           = "3scale_managed_role"
 
 	labelRouteToSystemMaster    = "system-master"
 	labelRouteToSystemDeveloper = "system-developer"
 	labelRouteToSystemProvider  = "system-provider"
 
+	// stops the 3scale operator from reverting the apicast services that forward to the envoy proxy sidecars
+	disableApicastServiceReconcilerAnnotation = "apps.3scale.net/disable-apicast-service-reconciler"
+
 	// STS
 	stsS3CredentialsSecretName  = "sts-s3-credentials"                              // #nosec G101 -- This is a false positive
 	stsWebIdentityTokenFilePath = "/var/run/secrets/openshift/serviceaccount/token" // #nosec G101 -- This is a false positive
@@ -145,7 +155,7 @@ func NewReconciler(configManager config.ConfigReadWriter, installation *integrea
 	}
 	threescaleConfig.SetBlackboxTargetPathForAdminUI("/p/login/")
 
-	if err := configManager.WriteConfig(threescaleConfig); err != nil {
+	if err := configManager.WriteConfig(threescaleConfig, "threescale.NewReconciler"); err != nil {
 		return nil, fmt.Errorf("error writing threescale config : %w", err)
 	}
 
@@ -269,13 +279,17 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, nil
 	}
 
-	alertsReconciler, err := r.newAlertReconciler(r.log, r.installation.Spec.Type, ctx, serverClient, config.GetOboNamespace(r.installation.Namespace))
-	if err != nil {
-		return integreatlyv1alpha1.PhaseFailed, err
-	}
-	if phase, err = alertsReconciler.ReconcileAlerts(ctx, serverClient); err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
-		events.HandleError(r.recorder, installation, phase, "Failed to reconcile threescale alerts", err)
-		return phase, err
+	installType := integreatlyv1alpha1.InstallationType(installation.Spec.Type)
+	if integreatlyv1alpha1.HasObservability(installType) {
+		containerCpuMetric, err := metrics.GetContainerCPUMetric(ctx, serverClient, r.log)
+		if err != nil {
+			return integreatlyv1alpha1.PhaseFailed, err
+		}
+		alertsReconciler := r.newAlertReconciler(r.log, r.installation.Spec.Type, containerCpuMetric, config.GetOboNamespace(r.installation.Namespace))
+		if phase, err = alertsReconciler.ReconcileAlerts(ctx, serverClient); err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+			events.HandleError(r.recorder, installation, phase, "Failed to reconcile threescale alerts", err)
+			return phase, err
+		}
 	}
 
 	if customDomainActive {
@@ -490,6 +504,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, err
 	}
 
+	phase, err = r.reconcileOpenAPIDocuments(ctx, serverClient)
+	r.log.Infof("reconcileOpenAPIDocuments", l.Fields{"phase": phase})
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		events.HandleError(r.recorder, installation, phase, "Failed to reconcile OpenAPI documents", err)
+		return phase, err
+	}
+
 	phase, err = r.backupSystemSecrets(ctx, serverClient, installation)
 	r.log.Infof("backupSystemSecrets", l.Fields{"phase": phase})
 	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
@@ -504,30 +525,41 @@ func (r *Reconciler) Reconcile(ctx context.Context, installation *integreatlyv1a
 		return phase, err
 	}
 
-	// Ensure ratelimit annotation is ready before returning phase complete
-	phase, err = r.reconcileRatelimitPortAnnotation(ctx, serverClient)
-	r.log.Infof("reconcileRatelimitPortAnnotation", l.Fields{"phase": phase})
-	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
-		events.HandleError(r.recorder, installation, phase, "Failed to reconcile ratelimit service port annotation", err)
-		return phase, err
-	}
+	if integreatlyv1alpha1.HasRateLimiting(installType) {
+		// the rate limiting is provided by marin3r, it is removed from the 3scale components when marin3r is disabled
+		if installation.IsProductDisabled(integreatlyv1alpha1.ProductMarin3r) {
+			phase, err = r.removeRatelimitingFrom3scaleComponents(ctx, serverClient)
+			if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+				events.HandleError(r.recorder, installation, phase, "Failed to remove rate limiting from 3scale components", err)
+				return phase, err
+			}
+		} else {
+			// Ensure ratelimit annotation is ready before returning phase complete
+			phase, err = r.reconcileRatelimitPortAnnotation(ctx, serverClient)
+			r.log.Infof("reconcileRatelimitPortAnnotation", l.Fields{"phase": phase})
+			if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+				events.HandleError(r.recorder, installation, phase, "Failed to reconcile ratelimit service port annotation", err)
+				return phase, err
+			}
 
-	phase, err = r.reconcileRatelimitingTo3scaleComponents(ctx, serverClient, r.installation)
-	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
-		events.HandleError(r.recorder, installation, phase, "Failed to reconcile rate limiting to 3scale components", err)
-		return phase, err
-	}
+			phase, err = r.reconcileRatelimitingTo3scaleComponents(ctx, serverClient, r.installation)
+			if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+				events.HandleError(r.recorder, installation, phase, "Failed to reconcile rate limiting to 3scale components", err)
+				return phase, err
+			}
 
-	alertsReconciler = r.newEnvoyAlertReconciler(r.log, r.installation.Spec.Type, config.GetOboNamespace(installation.Namespace))
-	if phase, err := alertsReconciler.ReconcileAlerts(ctx, serverClient); err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
-		events.HandleError(r.recorder, installation, phase, "Failed to reconcile threescale alerts", err)
-		return phase, err
-	}
+			alertsReconciler := r.newEnvoyAlertReconciler(r.log, r.installation.Spec.Type, config.GetOboNamespace(installation.Namespace))
+			if phase, err := alertsReconciler.ReconcileAlerts(ctx, serverClient); err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+				events.HandleError(r.recorder, installation, phase, "Failed to reconcile threescale alerts", err)
+				return phase, err
+			}
+		}
 
-	phase, err = r.reconcileServiceMonitor(ctx, serverClient, productNamespace)
-	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
-		events.HandleError(r.recorder, installation, phase, "Failed to reconcile 3scale service monitor", err)
-		return phase, err
+		phase, err = r.reconcileServiceMonitor(ctx, serverClient, productNamespace)
+		if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+			events.HandleError(r.recorder, installation, phase, "Failed to reconcile 3scale service monitor", err)
+			return phase, err
+		}
 	}
 
 	if !integreatlyv1alpha1.IsRHOAMMultitenant(integreatlyv1alpha1.InstallationType(installation.Spec.Type)) {
@@ -723,7 +755,8 @@ func (r *Reconciler) reconcileComponents(ctx context.Context, serverClient k8scl
 	}
 
 	ExternalComponentsTrue := true
-	resourceRequirements := true
+	// the components the quota doesn't size run without resource requests on installations with minimal resources
+	resourceRequirements := !integreatlyv1alpha1.HasMinimalResources(integreatlyv1alpha1.InstallationType(r.installation.Spec.Type))
 	replicas := r.Config.GetReplicasConfig(r.installation)
 	systemAppReplicas := replicas["systemApp"]
 	systemSidekiqReplicas := replicas["systemSidekiq"]
@@ -882,7 +915,7 @@ func (r *Reconciler) reconcileComponents(ctx context.Context, serverClient k8scl
 		})
 		if threescaleRoute != nil {
 			r.Config.SetHost("https://" + threescaleRoute.Spec.Host)
-			err = r.ConfigManager.WriteConfig(r.Config)
+			err = r.ConfigManager.WriteConfig(r.Config, "threescale.reconcileComponents")
 			if err != nil {
 				return integreatlyv1alpha1.PhaseFailed, err
 			}
@@ -1203,12 +1236,6 @@ func (r *Reconciler) reconcileExternalDatasources(ctx context.Context, serverCli
 	if phase != integreatlyv1alpha1.PhaseCompleted {
 		return phase, nil
 	}
-
-	// create Redis Cpu Usage High alert
-	err = resources.CreateRedisCpuUsageAlerts(ctx, serverClient, r.installation, backendRedis, r.log)
-	if err != nil {
-		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to create backend redis prometheus Cpu usage high alerts for threescale: %s", err)
-	}
 	// wait for the backend redis cr to reconcile
 	if backendRedis.Status.Phase != types.PhaseComplete {
 		return integreatlyv1alpha1.PhaseAwaitingComponents, nil
@@ -1494,7 +1521,7 @@ func (r *Reconciler) getOAuthClientName() string {
 	return r.installation.Spec.NamespacePrefix + string(r.Config.GetProductName())
 }
 
-func (r *Reconciler) reconcileOpenshiftUsers(ctx context.Context, _ *integreatlyv1alpha1.RHMI, serverClient k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
+func (r *Reconciler) reconcileOpenshiftUsers(ctx context.Context, installation *integreatlyv1alpha1.RHMI, serverClient k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
 	r.log.Info("Reconciling openshift users to 3scale")
 
 	rhssoConfig, err := r.ConfigManager.ReadRHSSO()
@@ -1528,6 +1555,7 @@ func (r *Reconciler) reconcileOpenshiftUsers(ctx context.Context, _ *integreatly
 	// reset the user action metric before we re-reconcile
 	// in order to get up to date metrics on user creation
 	metrics.ResetThreeScaleUserAction()
+	auditor := userHelper.NewAuditor(serverClient, r.recorder, installation)
 	// the deleted entries are addressed first
 	// a common use case is where one idp is added to give early access to the cluster
 	// later that idp is removed and a more permanent one is added
@@ -1550,6 +1578,10 @@ func (r *Reconciler) reconcileOpenshiftUsers(ctx context.Context, _ *integreatly
 			if statusCode != http.StatusOK {
 				r.log.Error("msg", nil, errors.New("error on http request"))
 			}
+			if err == nil && statusCode != http.StatusOK {
+				err = fmt.Errorf("status code %d", statusCode)
+			}
+			auditor.Record(ctx, userHelper.AuditSystemThreeScale, userHelper.AuditActionDeleted, tsUser.UserDetails.Username, "no longer a keycloak user", err)
 		}
 	}
 
@@ -1597,6 +1629,10 @@ func (r *Reconciler) reconcileOpenshiftUsers(ctx context.Context, _ *integreatly
 			if statusCode != http.StatusCreated {
 				r.log.Infof("3scale user creation rejected", l.Fields{"username": kcUser.UserName, "httpStatusCode": statusCode})
 			}
+			if err == nil && statusCode != http.StatusCreated {
+				err = fmt.Errorf("status code %d", statusCode)
+			}
+			auditor.Record(ctx, userHelper.AuditSystemThreeScale, userHelper.AuditActionCreated, strings.ToLower(kcUser.UserName), "new keycloak user", err)
 		}
 	}
 
@@ -1606,10 +1642,14 @@ func (r *Reconciler) reconcileOpenshiftUsers(ctx context.Context, _ *integreatly
 		return phase, err
 	}
 
-	openshiftAdminGroup := &usersv1.Group{}
-	err = serverClient.Get(ctx, k8sclient.ObjectKey{Name: "dedicated-admins"}, openshiftAdminGroup)
-	if err != nil && !k8serr.IsNotFound(err) {
-		r.log.Info("Failed to retrieve dedicated admins: " + err.Error())
+	roleMappings, err := userHelper.GetRoleMappings(ctx, serverClient, installation.Namespace)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
+	openshiftGroups := &usersv1.GroupList{}
+	err = serverClient.List(ctx, openshiftGroups)
+	if err != nil {
+		r.log.Info("Failed to list openshift groups: " + err.Error())
 		return integreatlyv1alpha1.PhaseInProgress, err
 	}
 	newTsUsers, err := r.tsClient.GetUsers(*accessToken)
@@ -1618,15 +1658,66 @@ func (r *Reconciler) reconcileOpenshiftUsers(ctx context.Context, _ *integreatly
 		return integreatlyv1alpha1.PhaseInProgress, err
 	}
 
-	err = syncOpenshiftAdminMembership(openshiftAdminGroup, newTsUsers, *systemAdminUsername, r.tsClient, *accessToken)
+	// the keycloak users are read again as their attributes were updated with the 3scale user IDs
+	kcu, err = rhsso.GetKeycloakUsers(ctx, serverClient, rhssoConfig.GetNamespace())
 	if err != nil {
-		r.log.Info("Failed to sync openshift admin membership: " + err.Error())
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
+	managedAdmins, err := syncOpenshiftRoleMappings(ctx, auditor, roleMappings, openshiftGroups, newTsUsers, getManagedAdmins(kcu), *systemAdminUsername, r.tsClient, *accessToken)
+	if err != nil {
+		r.log.Info("Failed to sync openshift group role mappings: " + err.Error())
+		return integreatlyv1alpha1.PhaseInProgress, err
+	}
+	if err := r.setManagedAdmins(ctx, serverClient, rhssoConfig.GetNamespace(), kcu, managedAdmins); err != nil {
 		return integreatlyv1alpha1.PhaseInProgress, err
 	}
 
 	return integreatlyv1alpha1.PhaseCompleted, nil
 }
 
+// getManagedAdmins returns the lower case names of the users that are admins in 3scale because of a role mapping
+func getManagedAdmins(kcUsers []keycloak.KeycloakAPIUser) map[string]bool {
+	managedAdmins := map[string]bool{}
+	for _, kcUser := range kcUsers {
+		if roles := kcUser.Attributes[user3ScaleManagedRole]; len(roles) == 1 && roles[0] == adminRole {
+			managedAdmins[strings.ToLower(kcUser.UserName)] = true
+		}
+	}
+	return managedAdmins
+}
+
+// setManagedAdmins records on the keycloak users which 3scale admins are managed by a role mapping, so that
+// they are demoted once they leave the mapped groups
+func (r *Reconciler) setManagedAdmins(ctx context.Context, serverClient k8sclient.Client, ns string, kcUsers []keycloak.KeycloakAPIUser, managedAdmins map[string]bool) error {
+	current := getManagedAdmins(kcUsers)
+	for _, user := range kcUsers {
+		username := strings.ToLower(user.UserName)
+		if current[username] == managedAdmins[username] {
+			continue
+		}
+
+		kcUser := &keycloak.KeycloakUser{}
+		if err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: userHelper.GetValidGeneratedUserName(user), Namespace: ns}, kcUser); err != nil {
+			if k8serr.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to get KeycloakUser %s: %w", user.UserName, err)
+		}
+		if kcUser.Spec.User.Attributes == nil {
+			kcUser.Spec.User.Attributes = map[string][]string{}
+		}
+		if managedAdmins[username] {
+			kcUser.Spec.User.Attributes[user3ScaleManagedRole] = []string{adminRole}
+		} else {
+			delete(kcUser.Spec.User.Attributes, user3ScaleManagedRole)
+		}
+		if err := serverClient.Update(ctx, kcUser); err != nil {
+			return fmt.Errorf("failed to update KeycloakUser CR with %s attribute: %w", user3ScaleManagedRole, err)
+		}
+	}
+	return nil
+}
+
 func (r *Reconciler) updateKeycloakUsersAttributeWith3ScaleUserId(ctx context.Context, serverClient k8sclient.Client, kcu []keycloak.KeycloakAPIUser, accessToken *string) (integreatlyv1alpha1.StatusPhase, error) {
 	rhssoConfig, err := r.ConfigManager.ReadRHSSO()
 	if err != nil {
@@ -1698,6 +1789,7 @@ func (r *Reconciler) reconcile3scaleMultiTenancy(ctx context.Context, serverClie
 	// adding an extra page in case of accounts set to be deleted
 	totalPages++
 
+	provisioner := newTenantProvisioner()
 	var allAccounts []AccountDetail
 	for page := 1; page <= totalPages; page++ {
 		// list 3scale tenant accounts
@@ -1705,6 +1797,9 @@ func (r *Reconciler) reconcile3scaleMultiTenancy(ctx context.Context, serverClie
 		r.log.Infof("Retrieving list of MT accounts available ",
 			l.Fields{"Page": page},
 		)
+		if err := provisioner.wait(ctx); err != nil {
+			return integreatlyv1alpha1.PhaseFailed, err
+		}
 		accounts, err := r.tsClient.ListTenantAccounts(*accessToken, page, func(ac AccountDetail) bool {
 			return ac.Id != 1 && ac.Id != 2
 		})
@@ -1737,190 +1832,71 @@ func (r *Reconciler) reconcile3scaleMultiTenancy(ctx context.Context, serverClie
 		return integreatlyv1alpha1.PhaseFailed, err
 	}
 
-	// looping through the accounts to reconcile default config back
-	for index, account := range allAccounts {
-		r.log.Infof("Checking 3scale account", l.Fields{"tenantAccountName": account.OrgName})
+	failedTenants := map[string]error{}
 
-		state, created := tenantsCreated.Data[account.OrgName]
-		if created && state == "true" {
+	// split the accounts that are not ready yet between the approved accounts, which get their default
+	// config reconciled back, and the broken accounts, which are deleted so they can be recreated
+	accountsToReconcile := map[string]AccountDetail{}
+	brokenAccounts := map[string]AccountDetail{}
+	var remainingAccounts []AccountDetail
+	for _, account := range allAccounts {
+		if state, created := tenantsCreated.Data[account.OrgName]; created && state == "true" {
+			remainingAccounts = append(remainingAccounts, account)
 			continue
 		}
 
-		if account.State == "approved" {
-			r.log.Infof("3scale account is approved", l.Fields{"tenantAccountName": account.OrgName})
-			breakout := false
-			for _, user := range account.Users.User {
-				if user.State == "pending" {
-					r.log.Infof("Activating user access to new tenant account",
-						l.Fields{
-							"userName":           user.Username,
-							"tenantAccountName":  account.OrgName,
-							"tenantAccountState": account.State,
-						},
-					)
-
-					err = r.tsClient.ActivateUser(*accessToken, account.Id, user.Id)
-					if err != nil {
-						r.log.Error("Error activating user access to new tenant account",
-							l.Fields{
-								"userName":          user.Username,
-								"tenantAccountName": account.OrgName,
-							},
-							err,
-						)
-						breakout = true
-					}
-				}
-			}
-			if breakout {
-				continue
-			}
-
-			val, ok := signUpAccountsSecret.Data[string(account.OrgName)]
-			if !ok || string(val) == "" {
-				r.log.Infof("Tenant account does not have access token created",
-					l.Fields{
-						"tenantAccountId":    account.Id,
-						"tenantAccountName":  account.OrgName,
-						"tenantAccountState": account.State,
-					},
-					//TODO: delete account?
-				)
-				continue
-			}
-
-			signUpAccount := SignUpAccount{
-				AccountDetail: account,
-				AccountAccessToken: AccountAccessToken{
-					Value: string(val),
-				},
-			}
-
-			r.log.Infof("Adding authentication provider to tenant account",
-				l.Fields{
-					"tenantAccountId":    account.Id,
-					"tenantAccountName":  account.OrgName,
-					"tenantAccountState": account.State,
-				},
-			)
-
-			// verify if the account have the auth provider already
-			err = r.addAuthProviderToMTAccount(ctx, serverClient, signUpAccount)
-			if err != nil {
-				r.log.Error("Error adding authentication provider to tenant account",
-					l.Fields{
-						"tenantAccountId":    account.Id,
-						"tenantAccountName":  account.OrgName,
-						"tenantAccountState": account.State,
-					},
-					err,
-				)
-				continue
-			}
-
-			// Get the account's corresponding KeycloakUser for later verification
-			kcUser, err := r.getKeycloakUserFromAccount(serverClient, account.OrgName)
-			if err != nil {
-				r.log.Error("Failed to get KeycloakUser for tenant account",
-					l.Fields{
-						"tenantAccountId":    account.Id,
-						"tenantAccountName":  account.OrgName,
-						"tenantAccountState": account.State,
-					},
-					err,
-				)
-				continue
-			}
-
-			// Get the account's corresponding KeycloakClient for later verification
-			kcClient, err := r.getKeycloakClientFromAccount(serverClient, account.OrgName)
-			if err != nil {
-				r.log.Error("Failed to get KeycloakClient for tenant account",
-					l.Fields{
-						"tenantAccountId":    account.Id,
-						"tenantAccountName":  account.OrgName,
-						"tenantAccountState": account.State,
-					},
-					err,
-				)
-				continue
-			}
-
-			r.log.Infof("Checking Keycloak state...", l.Fields{"tenantAccountName": account.OrgName, "kcUser.Status.Phase": kcUser.Status.Phase, "kcClient.Status.Ready": kcClient.Status.Ready})
-
-			// Only add the ssoReady annotation if the tenant account's corresponding KeycloakUser and KeycloakClient CR's are ready.
-			// If not, continue to next account.
-			if kcUser.Status.Phase == keycloak.UserPhaseReconciled && kcClient.Status.Ready {
-
-				r.log.Infof("Adding SSO on 3scale account ", l.Fields{"tenantAccountName": account.OrgName})
-
-				// Add ssoReady annotation to the user CR associated with the tenantAccount's OrgName
-				// This is required by the apimanagementtenant_controller so it can finish reconciling the APIManagementTenant CR
-				err = r.addSSOReadyAnnotationToUser(ctx, serverClient, account.OrgName)
-				if err != nil {
-					r.log.Error("Error adding ssoReady annotation for the user associated with the tenant account org",
-						l.Fields{
-							"tenantAccountOrgName": account.OrgName,
-						},
-						err,
-					)
-					continue
-				}
-
-				r.log.Infof("Reconciling Dashboard link for ", l.Fields{"tenantAccountName": account.OrgName})
+		switch account.State {
+		case "approved":
+			accountsToReconcile[account.OrgName] = account
+			remainingAccounts = append(remainingAccounts, account)
+		case "scheduled_for_deletion":
+			remainingAccounts = append(remainingAccounts, account)
+		default:
+			brokenAccounts[account.OrgName] = account
+		}
+	}
 
-				// Only add the dashboard link when account fully ready
-				err = r.reconcileDashboardLink(ctx, serverClient, account.OrgName, account.AdminBaseURL)
-				if err != nil {
-					r.log.Error("Error reconciling console link for the tenant account",
-						l.Fields{
-							"tenantAccountId":   account.Id,
-							"tenantAccountName": account.OrgName,
-						},
-						err,
-					)
-					continue
-				}
+	var (
+		readyTenantsMutex sync.Mutex
+		readyTenants      []string
+	)
+	for tenant, err := range provisioner.run(ctx, slices.Sorted(maps.Keys(accountsToReconcile)), func(ctx context.Context, tenant string) error {
+		ready, err := r.reconcileTenantAccount(ctx, serverClient, provisioner, *accessToken, accountsToReconcile[tenant], signUpAccountsSecret)
+		if ready {
+			readyTenantsMutex.Lock()
+			readyTenants = append(readyTenants, tenant)
+			readyTenantsMutex.Unlock()
+		}
+		return err
+	}) {
+		failedTenants[tenant] = err
+	}
 
-				r.log.Infof("Setting account created in config map to true", l.Fields{"tenantAccountName": account.OrgName})
-				if _, err := controllerutil.CreateOrUpdate(ctx, serverClient, tenantsCreated, func() error {
-					tenantsCreated.Data[account.OrgName] = "true"
-					tenantsCreated.ObjectMeta.ResourceVersion = ""
-					return nil
-				}); err != nil {
-					r.log.Error("Error setting account created in config map to true", l.Fields{"tenantAccountName": account.OrgName}, err)
-					return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("error creating/updating tenant created CM: %w", err)
-				}
+	if len(readyTenants) > 0 {
+		r.log.Infof("Setting accounts created in config map to true", l.Fields{"tenantAccountNames": readyTenants})
+		if _, err := controllerutil.CreateOrUpdate(ctx, serverClient, tenantsCreated, func() error {
+			if tenantsCreated.Data == nil {
+				tenantsCreated.Data = map[string]string{}
 			}
-		} else if account.State != "scheduled_for_deletion" {
-			r.log.Infof("Deleting broke account for recreation",
-				l.Fields{
-					"tenantAccountId":    account.Id,
-					"tenantAccountName":  account.OrgName,
-					"tenantAccountState": account.State,
-				},
-			)
-
-			err = r.tsClient.DeleteTenant(*accessToken, account.Id)
-			if err != nil {
-				r.log.Error("Error deleting broken account",
-					l.Fields{
-						"tenantAccountId":    account.Id,
-						"tenantAccountName":  account.OrgName,
-						"tenantAccountState": account.State,
-					},
-					err,
-				)
+			for _, tenant := range readyTenants {
+				tenantsCreated.Data[tenant] = "true"
 			}
-
-			//remove account from the list of accounts so it can be recreated
-			r.log.Infof("Account removed to be recreated",
-				l.Fields{"tenantAccountRemoved": allAccounts[index]},
-			)
-			allAccounts = append(allAccounts[:index], allAccounts[index+1:]...)
+			tenantsCreated.ObjectMeta.ResourceVersion = ""
+			return nil
+		}); err != nil {
+			r.log.Error("Error setting accounts created in config map to true", l.Fields{"tenantAccountNames": readyTenants}, err)
+			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("error creating/updating tenant created CM: %w", err)
 		}
 	}
 
+	for tenant, err := range provisioner.run(ctx, slices.Sorted(maps.Keys(brokenAccounts)), func(ctx context.Context, tenant string) error {
+		return r.deleteBrokenTenantAccount(ctx, provisioner, *accessToken, brokenAccounts[tenant])
+	}) {
+		failedTenants[tenant] = err
+	}
+	// broken accounts are removed from the list of accounts so they can be recreated
+	allAccounts = remainingAccounts
+
 	r.log.Info("creating new MT accounts in 3scale")
 
 	// creating new MT accounts in 3scale
@@ -1935,49 +1911,53 @@ func (r *Reconciler) reconcile3scaleMultiTenancy(ctx context.Context, serverClie
 		},
 	)
 
+	// the passwords are kept in a single secret so they are generated before the accounts are created in parallel
+	newAccounts := map[string]newTenantAccount{}
 	for idx, account := range accountsToBeCreated {
-
-		r.log.Info("Accounts to be created loop")
-
 		pw, err := r.getTenantAccountPassword(ctx, serverClient, account)
 		if err != nil {
 			r.log.Error("Failed to get account tenant password:", nil, err)
 			return integreatlyv1alpha1.PhaseFailed, err
 		}
+		newAccounts[account.OrgName] = newTenantAccount{account: account, password: pw, email: emailAddrs[idx]}
+	}
 
-		// Create 3scale account
-		newSignupAccount, err := r.tsClient.CreateTenant(*accessToken, account, pw, emailAddrs[idx])
+	var (
+		accessTokensMutex sync.Mutex
+		accessTokens      = map[string]string{}
+	)
+	for tenant, err := range provisioner.run(ctx, slices.Sorted(maps.Keys(newAccounts)), func(ctx context.Context, tenant string) error {
+		tenantAccessToken, err := r.createTenantAccount(ctx, provisioner, *accessToken, newAccounts[tenant])
 		if err != nil {
-			r.log.Error("Error creating tenant account",
-				l.Fields{"tenantAccountName": account.OrgName},
-				err,
-			)
-
-			// Attempt a delete of Tenant to force re-entry !!!
-
-			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("error creating tenant account: %s, Error=[%v]", account.OrgName, err)
+			return err
 		}
+		accessTokensMutex.Lock()
+		accessTokens[tenant] = tenantAccessToken
+		accessTokensMutex.Unlock()
+		return nil
+	}) {
+		failedTenants[tenant] = err
+	}
 
-		r.log.Infof("New tenant account created",
-			l.Fields{
-				"tenantAccountId":    newSignupAccount.AccountDetail.Id,
-				"tenantAccountName":  newSignupAccount.AccountDetail.OrgName,
-				"tenantAccountState": newSignupAccount.AccountDetail.State,
-			},
-		)
-
+	if len(accessTokens) > 0 {
 		if _, err := controllerutil.CreateOrUpdate(ctx, serverClient, signUpAccountsSecret, func() error {
 			r.log.Info("Creating/updating signUpAccountsSecret " + signUpAccountsSecret.Name + " " + signUpAccountsSecret.Namespace)
-			signUpAccountsSecret.Data[account.OrgName] = []byte(newSignupAccount.AccountAccessToken.Value)
+			if signUpAccountsSecret.Data == nil {
+				signUpAccountsSecret.Data = map[string][]byte{}
+			}
+			for tenant, tenantAccessToken := range accessTokens {
+				signUpAccountsSecret.Data[tenant] = []byte(tenantAccessToken)
+			}
 			signUpAccountsSecret.ObjectMeta.ResourceVersion = ""
 			return nil
 		}); err != nil {
 			r.log.Error("Error creating access token secret ", nil, err)
 			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("error creating access token secret: %w", err)
 		}
-		r.log.Info("After signUpAccountsSecret " + signUpAccountsSecret.Name + " " + signUpAccountsSecret.Namespace)
 	}
 
+	metrics.SetThreeScaleTenantProvisioning(getTenantProvisioningStates(mtUserIdentities, allAccounts, slices.Sorted(maps.Keys(accessTokens)), tenantsCreated, failedTenants))
+
 	// deleting MT accounts in 3scale
 	accountsToBeDeleted := getMTAccountsToBeDeleted(mtUserIdentities, allAccounts)
 	r.log.Infof(
@@ -1987,6 +1967,9 @@ func (r *Reconciler) reconcile3scaleMultiTenancy(ctx context.Context, serverClie
 			"totalAccounts":       len(accountsToBeDeleted),
 		},
 	)
+	if err := provisioner.wait(ctx); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
 	err = r.tsClient.DeleteTenants(*accessToken, accountsToBeDeleted)
 	if err != nil {
 		r.log.Error("error deleting tenant accounts:", nil, err)
@@ -2011,6 +1994,13 @@ func (r *Reconciler) reconcile3scaleMultiTenancy(ctx context.Context, serverClie
 		}
 	}
 
+	if len(failedTenants) > 0 {
+		for tenant, err := range failedTenants {
+			r.log.Error("Failed to provision tenant account, it will be retried", l.Fields{"tenantAccountName": tenant}, err)
+		}
+		return integreatlyv1alpha1.PhaseInProgress, nil
+	}
+
 	if len(accountsToBeCreated) > 0 {
 		r.log.Infof("Returning in progress as there were accounts created and users need to be activated",
 			l.Fields{"totalAccountsCreated": len(accountsToBeCreated)},
@@ -2021,6 +2011,146 @@ func (r *Reconciler) reconcile3scaleMultiTenancy(ctx context.Context, serverClie
 	return integreatlyv1alpha1.PhaseCompleted, nil
 }
 
+type newTenantAccount struct {
+	account  AccountDetail
+	password string
+	email    string
+}
+
+// reconcileTenantAccount activates the users of an approved tenant account and reconciles its default config.
+// It returns true once the account is fully ready
+func (r *Reconciler) reconcileTenantAccount(ctx context.Context, serverClient k8sclient.Client, provisioner *tenantProvisioner, accessToken string, account AccountDetail, signUpAccountsSecret *corev1.Secret) (bool, error) {
+	r.log.Infof("3scale account is approved", l.Fields{"tenantAccountName": account.OrgName})
+	for _, user := range account.Users.User {
+		if user.State != "pending" {
+			continue
+		}
+		r.log.Infof("Activating user access to new tenant account",
+			l.Fields{
+				"userName":           user.Username,
+				"tenantAccountName":  account.OrgName,
+				"tenantAccountState": account.State,
+			},
+		)
+
+		if err := provisioner.wait(ctx); err != nil {
+			return false, err
+		}
+		if err := r.tsClient.ActivateUser(accessToken, account.Id, user.Id); err != nil {
+			return false, fmt.Errorf("error activating user %s access to tenant account %s: %w", user.Username, account.OrgName, err)
+		}
+	}
+
+	val, ok := signUpAccountsSecret.Data[account.OrgName]
+	if !ok || string(val) == "" {
+		r.log.Infof("Tenant account does not have access token created",
+			l.Fields{
+				"tenantAccountId":    account.Id,
+				"tenantAccountName":  account.OrgName,
+				"tenantAccountState": account.State,
+			},
+			//TODO: delete account?
+		)
+		return false, nil
+	}
+
+	signUpAccount := SignUpAccount{
+		AccountDetail: account,
+		AccountAccessToken: AccountAccessToken{
+			Value: string(val),
+		},
+	}
+
+	r.log.Infof("Adding authentication provider to tenant account",
+		l.Fields{
+			"tenantAccountId":    account.Id,
+			"tenantAccountName":  account.OrgName,
+			"tenantAccountState": account.State,
+		},
+	)
+
+	// verify if the account have the auth provider already
+	if err := r.addAuthProviderToMTAccount(ctx, serverClient, signUpAccount); err != nil {
+		return false, fmt.Errorf("error adding authentication provider to tenant account %s: %w", account.OrgName, err)
+	}
+
+	// Get the account's corresponding KeycloakUser for later verification
+	kcUser, err := r.getKeycloakUserFromAccount(serverClient, account.OrgName)
+	if err != nil {
+		return false, fmt.Errorf("failed to get KeycloakUser for tenant account %s: %w", account.OrgName, err)
+	}
+
+	// Get the account's corresponding KeycloakClient for later verification
+	kcClient, err := r.getKeycloakClientFromAccount(serverClient, account.OrgName)
+	if err != nil {
+		return false, fmt.Errorf("failed to get KeycloakClient for tenant account %s: %w", account.OrgName, err)
+	}
+
+	r.log.Infof("Checking Keycloak state...", l.Fields{"tenantAccountName": account.OrgName, "kcUser.Status.Phase": kcUser.Status.Phase, "kcClient.Status.Ready": kcClient.Status.Ready})
+
+	// Only add the ssoReady annotation if the tenant account's corresponding KeycloakUser and KeycloakClient CR's are ready.
+	if kcUser.Status.Phase != keycloak.UserPhaseReconciled || !kcClient.Status.Ready {
+		return false, nil
+	}
+
+	r.log.Infof("Adding SSO on 3scale account ", l.Fields{"tenantAccountName": account.OrgName})
+
+	// Add ssoReady annotation to the user CR associated with the tenantAccount's OrgName
+	// This is required by the apimanagementtenant_controller so it can finish reconciling the APIManagementTenant CR
+	if err := r.addSSOReadyAnnotationToUser(ctx, serverClient, account.OrgName); err != nil {
+		return false, fmt.Errorf("error adding ssoReady annotation for the user associated with the tenant account org %s: %w", account.OrgName, err)
+	}
+
+	r.log.Infof("Reconciling Dashboard link for ", l.Fields{"tenantAccountName": account.OrgName})
+
+	// Only add the dashboard link when account fully ready
+	if err := r.reconcileDashboardLink(ctx, serverClient, account.OrgName, account.AdminBaseURL); err != nil {
+		return false, fmt.Errorf("error reconciling console link for the tenant account %s: %w", account.OrgName, err)
+	}
+
+	return true, nil
+}
+
+func (r *Reconciler) deleteBrokenTenantAccount(ctx context.Context, provisioner *tenantProvisioner, accessToken string, account AccountDetail) error {
+	r.log.Infof("Deleting broke account for recreation",
+		l.Fields{
+			"tenantAccountId":    account.Id,
+			"tenantAccountName":  account.OrgName,
+			"tenantAccountState": account.State,
+		},
+	)
+
+	if err := provisioner.wait(ctx); err != nil {
+		return err
+	}
+	if err := r.tsClient.DeleteTenant(accessToken, account.Id); err != nil {
+		return fmt.Errorf("error deleting broken tenant account %s: %w", account.OrgName, err)
+	}
+	return nil
+}
+
+// createTenantAccount creates the 3scale tenant account and returns its access token
+func (r *Reconciler) createTenantAccount(ctx context.Context, provisioner *tenantProvisioner, accessToken string, newAccount newTenantAccount) (string, error) {
+	if err := provisioner.wait(ctx); err != nil {
+		return "", err
+	}
+
+	newSignupAccount, err := r.tsClient.CreateTenant(accessToken, newAccount.account, newAccount.password, newAccount.email)
+	if err != nil {
+		return "", fmt.Errorf("error creating tenant account: %s, Error=[%v]", newAccount.account.OrgName, err)
+	}
+
+	r.log.Infof("New tenant account created",
+		l.Fields{
+			"tenantAccountId":    newSignupAccount.AccountDetail.Id,
+			"tenantAccountName":  newSignupAccount.AccountDetail.OrgName,
+			"tenantAccountState": newSignupAccount.AccountDetail.State,
+		},
+	)
+
+	return newSignupAccount.AccountAccessToken.Value, nil
+}
+
 func setTenantMetrics(users []userHelper.MultiTenantUser, accounts []AccountDetail) {
 	metrics.ResetNoActivated3ScaleTenantAccount()
 
@@ -2311,60 +2441,85 @@ func getMTAccountsToBeDeleted(usersIdentity []userHelper.MultiTenantUser, accoun
 }
 
 func (r *Reconciler) preUpgradeBackupExecutor() backup.BackupExecutor {
-	if r.installation.Spec.UseClusterStorage != "false" {
+	return PreUpgradeBackupExecutor(r.installation)
+}
+
+// PreUpgradeBackupExecutor returns the executor of the backups taken before 3scale is upgraded
+func PreUpgradeBackupExecutor(installation *integreatlyv1alpha1.RHMI) backup.BackupExecutor {
+	if installation.Spec.UseClusterStorage != "false" {
 		return backup.NewNoopBackupExecutor()
 	}
 
 	return backup.NewConcurrentBackupExecutor(
 		backup.NewAWSBackupExecutor(
-			r.installation.Namespace,
+			installation.Namespace,
 			"threescale-postgres-rhmi",
 			backup.PostgresSnapshotType,
 		),
 		backup.NewAWSBackupExecutor(
-			r.installation.Namespace,
+			installation.Namespace,
 			"threescale-backend-redis-rhmi",
 			backup.RedisSnapshotType,
 		),
 		backup.NewAWSBackupExecutor(
-			r.installation.Namespace,
+			installation.Namespace,
 			"threescale-redis-rhmi",
 			backup.RedisSnapshotType,
 		),
 	)
 }
 
-func syncOpenshiftAdminMembership(openshiftAdminGroup *usersv1.Group, newTsUsers *Users, systemAdminUsername string, tsClient ThreeScaleInterface, accessToken string) error {
+// syncOpenshiftRoleMappings sets the role of the 3scale users to the role their OpenShift groups are mapped to.
+// Admins are only demoted when they were promoted by a mapping, or when a group of the user is mapped to the
+// member role, so that admins promoted in 3scale are left unchanged. It returns the admins managed by the mappings
+func syncOpenshiftRoleMappings(ctx context.Context, auditor *userHelper.Auditor, roleMappings userHelper.RoleMappings, openshiftGroups *usersv1.GroupList, newTsUsers *Users, managedAdmins map[string]bool, systemAdminUsername string, tsClient ThreeScaleInterface, accessToken string) (map[string]bool, error) {
+	newManagedAdmins := map[string]bool{}
 	for _, tsUser := range newTsUsers.Users {
 		// skip if ts user is the system user admin
 		if tsUser.UserDetails.Username == systemAdminUsername {
 			continue
 		}
+		username := strings.ToLower(tsUser.UserDetails.Username)
 
-		// In workshop mode, developer users also get admin permissions in 3scale
-		if (userIsOpenshiftAdmin(tsUser, openshiftAdminGroup)) && tsUser.UserDetails.Role != adminRole {
-			res, err := tsClient.SetUserAsAdmin(tsUser.UserDetails.Id, accessToken)
-			if err != nil || res.StatusCode != http.StatusOK {
-				return err
+		switch role := roleMappings.ThreeScaleRole(tsUser.UserDetails.Username, openshiftGroups); {
+		case role == adminRole:
+			newManagedAdmins[username] = true
+			if tsUser.UserDetails.Role != adminRole {
+				res, err := tsClient.SetUserAsAdmin(tsUser.UserDetails.Id, accessToken)
+				if err == nil && res.StatusCode != http.StatusOK {
+					err = fmt.Errorf("failed to promote 3scale user %s, status code %d", tsUser.UserDetails.Username, res.StatusCode)
+				}
+				auditor.Record(ctx, userHelper.AuditSystemThreeScale, userHelper.AuditActionPromoted, tsUser.UserDetails.Username, "member of a group mapped to the admin role", err)
+				if err != nil {
+					return nil, err
+				}
+			}
+		case tsUser.UserDetails.Role == adminRole && (role == memberRole || managedAdmins[username]):
+			reason := "no longer a member of a group mapped to the admin role"
+			if role == memberRole {
+				reason = "member of a group mapped to the member role"
+			}
+			res, err := tsClient.SetUserAsMember(tsUser.UserDetails.Id, accessToken)
+			if err == nil && res.StatusCode != http.StatusOK {
+				err = fmt.Errorf("failed to demote 3scale user %s, status code %d", tsUser.UserDetails.Username, res.StatusCode)
+			}
+			auditor.Record(ctx, userHelper.AuditSystemThreeScale, userHelper.AuditActionDemoted, tsUser.UserDetails.Username, reason, err)
+			if err != nil {
+				return nil, err
 			}
 		}
 	}
 
-	return nil
+	return newManagedAdmins, nil
 }
 
 func (r *Reconciler) reconcileServiceDiscovery(ctx context.Context, serverClient k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
 
-	if string(r.Config.GetProductVersion()) != string(integreatlyv1alpha1.Version3Scale) {
+	if string(r.Config.GetProductVersion()) != string(integreatlyv1alpha1.Version3Scale) ||
+		string(r.Config.GetOperatorVersion()) != string(integreatlyv1alpha1.OperatorVersion3Scale) {
 		r.Config.SetProductVersion(string(integreatlyv1alpha1.Version3Scale))
-		if err := r.ConfigManager.WriteConfig(r.Config); err != nil {
-			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("error writing threescale config : %w", err)
-		}
-	}
-
-	if string(r.Config.GetOperatorVersion()) != string(integreatlyv1alpha1.OperatorVersion3Scale) {
 		r.Config.SetOperatorVersion(string(integreatlyv1alpha1.OperatorVersion3Scale))
-		if err := r.ConfigManager.WriteConfig(r.Config); err != nil {
+		if err := r.ConfigManager.WriteConfig(r.Config, "threescale.reconcileServiceDiscovery"); err != nil {
 			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("error writing threescale config : %w", err)
 		}
 	}
@@ -2642,16 +2797,6 @@ func tsContainsKc(tsusers []*User, kcUser keycloak.KeycloakAPIUser) bool {
 	return false
 }
 
-func userIsOpenshiftAdmin(tsUser *User, adminGroup *usersv1.Group) bool {
-	for _, userName := range adminGroup.Users {
-		if strings.EqualFold(tsUser.UserDetails.Username, userName) {
-			return true
-		}
-	}
-
-	return false
-}
-
 func (r *Reconciler) getKeycloakClientSpec(id, clientSecret string) keycloak.KeycloakClientSpec {
 	fullScopeAllowed := true
 
@@ -2963,9 +3108,13 @@ func (r *Reconciler) reconcileRatelimitingTo3scaleComponents(ctx context.Context
 
 	r.log.Info("Reconciling rate limiting settings to 3scale components")
 
-	proxyServer := ratelimit.NewEnvoyProxyServer(ctx, serverClient, r.log)
+	envoyImage, err := images.Resolve(ctx, serverClient, installation.Namespace, images.EnvoyProxy)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to resolve the envoy proxy image: %w", err)
+	}
+	proxyServer := ratelimit.NewEnvoyProxyServer(ctx, serverClient, r.log, envoyImage)
 
-	err := r.createBackendListenerProxyService(ctx, serverClient)
+	err = r.createBackendListenerProxyService(ctx, serverClient)
 	if err != nil {
 		return integreatlyv1alpha1.PhaseInProgress, err
 	}
@@ -3067,16 +3216,37 @@ func (r *Reconciler) reconcileRatelimitingTo3scaleComponents(ctx context.Context
 			return integreatlyv1alpha1.PhaseFailed, err
 		}
 	} else {
-		apicastHTTPFilters, err = getMultitenantAPICastHTTPFilters()
+		identification, err := marin3rconfig.GetTenantIdentificationConfig(ctx, serverClient, installation.Namespace)
+		if err != nil {
+			r.log.Error("Failed to read tenant identification config", l.Fields{"ConfigMap": marin3rconfig.TenantIdentificationConfigMapName}, err)
+			return integreatlyv1alpha1.PhaseFailed, err
+		}
+
+		var tenantHosts map[string]string
+		if identification.Mode == marin3rconfig.TenantIdentificationHostLookup {
+			accounts, err := r.listAllTenantAccounts(ctx, serverClient)
+			if err != nil {
+				return integreatlyv1alpha1.PhaseFailed, err
+			}
+			tenantHosts = getTenantApicastHosts(accounts, installation.Spec.RoutingSubdomain)
+		}
+
+		apicastHTTPFilters, err = getMultitenantAPICastHTTPFilters(*identification, tenantHosts)
 		if err != nil {
 			r.log.Error("Failed to create envoyconfig filters for multitenant RHOAM", l.Fields{"APICast": ApicastClusterName}, err)
 			return integreatlyv1alpha1.PhaseFailed, err
 		}
 	}
 
+	rateLimitDescriptors, err := marin3rconfig.GetRateLimitDescriptorConfig(ctx, serverClient, installation.Namespace)
+	if err != nil {
+		r.log.Error("Failed to read rate limit descriptors", l.Fields{"ConfigMap": marin3rconfig.DescriptorConfigMapName}, err)
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
+
 	// apicast listener
 	apiCastFilters, err := getListenerResourceFilters(
-		getAPICastVirtualHosts(installation, ApicastClusterName),
+		getAPICastVirtualHosts(installation, ApicastClusterName, rateLimitDescriptors),
 		apicastHTTPFilters,
 	)
 	if err != nil {
@@ -3141,6 +3311,28 @@ func (r *Reconciler) reconcileRatelimitingTo3scaleComponents(ctx context.Context
 	return integreatlyv1alpha1.PhaseCompleted, nil
 }
 
+// listAllTenantAccounts returns the 3scale tenant accounts, excluding the master and default accounts
+func (r *Reconciler) listAllTenantAccounts(ctx context.Context, serverClient k8sclient.Client) ([]AccountDetail, error) {
+	accessToken, err := r.GetMasterToken(ctx, serverClient)
+	if err != nil {
+		return nil, err
+	}
+
+	var allAccounts []AccountDetail
+	for page := 1; ; page++ {
+		accounts, err := r.tsClient.ListTenantAccounts(*accessToken, page, func(ac AccountDetail) bool {
+			return ac.Id != 1 && ac.Id != 2
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list 3scale tenant accounts: %w", err)
+		}
+		if len(accounts) == 0 {
+			return allAccounts, nil
+		}
+		allAccounts = append(allAccounts, accounts...)
+	}
+}
+
 func (r *Reconciler) getRateLimitServiceCR(ctx context.Context, serverClient k8sclient.Client) (*corev1.Service, error) {
 	rateLimitService := &corev1.Service{}
 	marin3rConfig, err := r.ConfigManager.ReadMarin3r()
@@ -3213,6 +3405,64 @@ func (r *Reconciler) createBackendListenerProxyService(ctx context.Context, serv
 	return nil
 }
 
+// removeRatelimitingFrom3scaleComponents reverts reconcileRatelimitingTo3scaleComponents: the envoy proxy sidecars
+// are removed, the backend-listener route goes back to the backend-listener service and the 3scale operator
+// reconciles the apicast services again
+func (r *Reconciler) removeRatelimitingFrom3scaleComponents(ctx context.Context, serverClient k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
+	r.log.Info("Removing rate limiting settings from 3scale components")
+
+	for _, deploymentName := range []string{apicastStagingDeploymentName, apicastProductionDeploymentName, backendListenerDeploymentName} {
+		phase, err := ratelimit.RemoveEnvoyProxyContainer(ctx, serverClient, deploymentName, r.Config.GetNamespace())
+		if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+			return phase, err
+		}
+	}
+
+	backendRoute, err := r.getBackendListenerRoute(ctx, serverClient)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
+	if backendRoute.Spec.To.Name != backendListenerDeploymentName {
+		backendRoute.Spec.To.Name = backendListenerDeploymentName
+		if err := serverClient.Update(ctx, backendRoute); err != nil {
+			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("error updating the backend-listener external route to the backend-listener service: %v", err)
+		}
+	}
+
+	backendListenerProxyService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      BackendServiceName,
+			Namespace: r.Config.GetNamespace(),
+		},
+	}
+	if err := serverClient.Delete(ctx, backendListenerProxyService); err != nil && !k8serr.IsNotFound(err) {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to delete the backend-listener proxy service: %v", err)
+	}
+
+	apim := &threescalev1.APIManager{}
+	if err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: apiManagerName, Namespace: r.Config.GetNamespace()}, apim); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get the APIManager: %v", err)
+	}
+	if _, ok := apim.Annotations[disableApicastServiceReconcilerAnnotation]; ok {
+		delete(apim.Annotations, disableApicastServiceReconcilerAnnotation)
+		if err := serverClient.Update(ctx, apim); err != nil {
+			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to remove the %s annotation from the APIManager: %v", disableApicastServiceReconcilerAnnotation, err)
+		}
+	}
+
+	envoyAlerts := &monv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      envoyAlertsName,
+			Namespace: config.GetOboNamespace(r.installation.Namespace),
+		},
+	}
+	if err := serverClient.Delete(ctx, envoyAlerts); err != nil && !k8serr.IsNotFound(err) {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to delete the %s alerts: %v", envoyAlertsName, err)
+	}
+
+	return integreatlyv1alpha1.PhaseCompleted, nil
+}
+
 func (r *Reconciler) getBackendListenerRoute(ctx context.Context, serverClient k8sclient.Client) (*routev1.Route, error) {
 	backendRoute := &routev1.Route{}
 	err := serverClient.Get(ctx, k8sclient.ObjectKey{
@@ -3225,7 +3475,7 @@ func (r *Reconciler) getBackendListenerRoute(ctx context.Context, serverClient k
 	return backendRoute, nil
 }
 
-func (r *Reconciler) addSSOReadyAnnotationToUser(_ context.Context, client k8sclient.Client, name string) error {
+func (r *Reconciler) addSSOReadyAnnotationToUser(ctx context.Context, client k8sclient.Client, name string) error {
 	// Get the User CR to annotate
 	userToAnnotate := &usersv1.User{
 		ObjectMeta: metav1.ObjectMeta{
@@ -3233,13 +3483,13 @@ func (r *Reconciler) addSSOReadyAnnotationToUser(_ context.Context, client k8scl
 		},
 	}
 	key := k8sclient.ObjectKeyFromObject(userToAnnotate)
-	err := client.Get(context.TODO(), key, userToAnnotate)
+	err := client.Get(ctx, key, userToAnnotate)
 	if err != nil {
 		return fmt.Errorf("error getting user %s: %v", name, err)
 	}
 
 	// Add the annotation `ssoReady: 'yes'` to the User CR
-	_, err = controllerutil.CreateOrUpdate(context.TODO(), client, userToAnnotate, func() error {
+	_, err = controllerutil.CreateOrUpdate(ctx, client, userToAnnotate, func() error {
 		if userToAnnotate.Annotations == nil {
 			userToAnnotate.Annotations = map[string]string{}
 		}
@@ -3266,7 +3516,7 @@ func (r *Reconciler) reconcileRatelimitPortAnnotation(ctx context.Context, clien
 		if annotations == nil {
 			annotations = map[string]string{}
 		}
-		annotations["apps.3scale.net/disable-apicast-service-reconciler"] = "true"
+		annotations[disableApicastServiceReconcilerAnnotation] = "true"
 		return nil
 	}); err != nil {
 		return integreatlyv1alpha1.PhaseFailed, err
@@ -3383,7 +3633,11 @@ func (r *Reconciler) ping3scalePortals(ctx context.Context, serverClient k8sclie
 			Timeout: 10 * time.Second,
 		}
 		url := fmt.Sprintf("https://%s", portal.Host)
-		res, err := customHTTPClient.Get(url)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to create the ping request of %v 3scale portal (%v): %v", portal.PortalName, portal.Host, err)
+		}
+		res, err := customHTTPClient.Do(req)
 		if err != nil {
 			return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to ping %v 3scale portal (%v): %v", portal.PortalName, portal.Host, err)
 		}
@@ -3652,11 +3906,11 @@ func (r *Reconciler) reconcileTenantOutgoingEmailAddress(ctx context.Context, se
 	/* #nosec */
 	httpc := &http.Client{
 		Timeout: time.Second * 10,
-		Transport: &http.Transport{
+		Transport: resources.NewContextTransport(ctx, &http.Transport{
 			DisableKeepAlives: true,
 			IdleConnTimeout:   time.Second * 10,
 			TLSClientConfig:   &tls.Config{InsecureSkipVerify: r.installation.Spec.SelfSignedCerts}, //#nosec G402 -- value is read from CR config
-		},
+		}),
 	}
 
 	pc := portaClient.NewThreeScale(adminPortal, *masterAccessToken, httpc)
@@ -48,6 +48,7 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 
 	"github.com/integr8ly/integreatly-operator/pkg/resources/sts"
+	userHelper "github.com/integr8ly/integreatly-operator/pkg/resources/user"
 	cloudcredentialv1 "github.com/openshift/api/operator/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -864,10 +865,15 @@ func TestReconciler_syncOpenshiftAdmimMembership(t *testing.T) {
 		},
 	}
 
-	openshiftAdminGroup := &usersv1.Group{
-		Users: usersv1.OptionalNames{
-			"user1",
-			"user2",
+	openshiftGroups := &usersv1.GroupList{
+		Items: []usersv1.Group{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: userHelper.DedicatedAdminsGroupName},
+				Users: usersv1.OptionalNames{
+					"user1",
+					"user2",
+				},
+			},
 		},
 	}
 
@@ -902,7 +908,13 @@ func TestReconciler_syncOpenshiftAdmimMembership(t *testing.T) {
 		},
 	}
 
-	err := syncOpenshiftAdminMembership(openshiftAdminGroup, newTsUsers, "", &tsClientMock, "")
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	auditor := userHelper.NewAuditor(utils.NewTestClient(scheme), setupRecorder(), getValidInstallation(integreatlyv1alpha1.InstallationTypeManagedApi))
+
+	managedAdmins, err := syncOpenshiftRoleMappings(context.TODO(), auditor, userHelper.DefaultRoleMappings(), openshiftGroups, newTsUsers, map[string]bool{}, "", &tsClientMock, "")
 
 	if err != nil {
 		t.Fatalf("Unexpected error when reconcilling openshift admin membership: %s", err)
@@ -911,6 +923,70 @@ func TestReconciler_syncOpenshiftAdmimMembership(t *testing.T) {
 	if !calledSetUserAsAdmin {
 		t.Fatal("Expected user with ID 1 to be promoted as admin, but no promotion was invoked")
 	}
+
+	if !managedAdmins["user1"] || !managedAdmins["user2"] || managedAdmins["user3"] {
+		t.Fatalf("Expected users 1 and 2 to be managed admins, got %v", managedAdmins)
+	}
+}
+
+func TestReconciler_syncOpenshiftRoleMappingsDemotion(t *testing.T) {
+	openshiftGroups := &usersv1.GroupList{
+		Items: []usersv1.Group{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "api-viewers"},
+				Users:      usersv1.OptionalNames{"user2"},
+			},
+		},
+	}
+	roleMappings := userHelper.RoleMappings{
+		{Group: userHelper.DedicatedAdminsGroupName, ThreeScaleRole: userHelper.ThreeScaleAdminRole},
+		{Group: "api-viewers", ThreeScaleRole: userHelper.ThreeScaleMemberRole},
+	}
+
+	newTsUsers := &Users{
+		Users: []*User{
+			// left the admin group after being promoted by the operator. Should be demoted
+			{UserDetails: UserDetails{Id: 1, Role: adminRole, Username: "User1"}},
+			// mapped to the member role. Should be demoted
+			{UserDetails: UserDetails{Id: 2, Role: adminRole, Username: "User2"}},
+			// promoted in 3scale. Should NOT be demoted
+			{UserDetails: UserDetails{Id: 3, Role: adminRole, Username: "User3"}},
+		},
+	}
+
+	demoted := map[int]bool{}
+	tsClientMock := ThreeScaleInterfaceMock{
+		SetUserAsAdminFunc: func(userID int, accessToken string) (*http.Response, error) {
+			t.Fatalf("Unexpected call to `SetUserAsAdmin`. Called with userID %d", userID)
+			return &http.Response{StatusCode: 200}, nil
+		},
+		SetUserAsMemberFunc: func(userID int, accessToken string) (*http.Response, error) {
+			demoted[userID] = true
+			return &http.Response{StatusCode: 200}, nil
+		},
+	}
+
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := record.NewFakeRecorder(10)
+	auditor := userHelper.NewAuditor(utils.NewTestClient(scheme), recorder, getValidInstallation(integreatlyv1alpha1.InstallationTypeManagedApi))
+
+	managedAdmins, err := syncOpenshiftRoleMappings(context.TODO(), auditor, roleMappings, openshiftGroups, newTsUsers, map[string]bool{"user1": true}, "", &tsClientMock, "")
+	if err != nil {
+		t.Fatalf("Unexpected error when syncing openshift role mappings: %s", err)
+	}
+	if len(recorder.Events) != 2 {
+		t.Fatalf("Expected the demotions to be audited, got %d events", len(recorder.Events))
+	}
+
+	if !demoted[1] || !demoted[2] || demoted[3] {
+		t.Fatalf("Expected users 1 and 2 to be demoted, got %v", demoted)
+	}
+	if len(managedAdmins) != 0 {
+		t.Fatalf("Expected no managed admins, got %v", managedAdmins)
+	}
 }
 
 func TestReconciler_ensureDeploymentsReady(t *testing.T) {
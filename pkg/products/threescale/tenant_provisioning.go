@@ -0,0 +1,128 @@
+package threescale
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	userHelper "github.com/integr8ly/integreatly-operator/pkg/resources/user"
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+const (
+	tenantProvisioningWorkers = 10
+
+	// client side limits for the requests made against the 3scale master API while provisioning tenants
+	masterAPIQPS   = 5
+	masterAPIBurst = 10
+
+	tenantRetryInitialBackoff = 30 * time.Second
+	tenantRetryMaxBackoff     = 15 * time.Minute
+
+	TenantStatePending  = "pending"
+	TenantStateCreating = "creating"
+	TenantStateReady    = "ready"
+	TenantStateFailed   = "failed"
+)
+
+var (
+	// The rate limiter and the per tenant backoff are shared between reconciles
+	// as a new Reconciler is built on every reconcile of the installation
+	masterAPILimiter = flowcontrol.NewTokenBucketRateLimiter(masterAPIQPS, masterAPIBurst)
+	tenantBackoff    = flowcontrol.NewBackOff(tenantRetryInitialBackoff, tenantRetryMaxBackoff)
+)
+
+// tenantProvisioner runs the provisioning of 3scale tenant accounts in a bounded worker pool,
+// throttling the requests against the master API and backing off tenants that failed
+type tenantProvisioner struct {
+	workers int
+	limiter flowcontrol.RateLimiter
+	backoff *flowcontrol.Backoff
+}
+
+func newTenantProvisioner() *tenantProvisioner {
+	return &tenantProvisioner{
+		workers: tenantProvisioningWorkers,
+		limiter: masterAPILimiter,
+		backoff: tenantBackoff,
+	}
+}
+
+// wait blocks until a request against the 3scale master API is allowed
+func (p *tenantProvisioner) wait(ctx context.Context) error {
+	return p.limiter.Wait(ctx)
+}
+
+// run calls provision for every tenant with at most p.workers running in parallel.
+// Tenants are retried independently: a tenant that failed is skipped until its backoff
+// expires. It returns the errors of the tenants that failed or are still backing off
+func (p *tenantProvisioner) run(ctx context.Context, tenants []string, provision func(ctx context.Context, tenant string) error) map[string]error {
+	var (
+		g      errgroup.Group
+		mu     sync.Mutex
+		failed = map[string]error{}
+	)
+	g.SetLimit(p.workers)
+	p.backoff.GC()
+
+	for _, tenant := range tenants {
+		if p.backoff.IsInBackOffSinceUpdate(tenant, time.Now()) {
+			mu.Lock()
+			failed[tenant] = fmt.Errorf("tenant %s is backing off after a failed attempt, retrying within %s", tenant, p.backoff.Get(tenant))
+			mu.Unlock()
+			continue
+		}
+
+		g.Go(func() error {
+			if err := provision(ctx, tenant); err != nil {
+				p.backoff.Next(tenant, time.Now())
+				mu.Lock()
+				failed[tenant] = err
+				mu.Unlock()
+				return nil
+			}
+			p.backoff.Reset(tenant)
+			return nil
+		})
+	}
+
+	// errors are collected per tenant, the group never returns one
+	_ = g.Wait()
+	return failed
+}
+
+// getTenantProvisioningStates counts the multi tenant users per provisioning state of their 3scale tenant account
+func getTenantProvisioningStates(users []userHelper.MultiTenantUser, accounts []AccountDetail, created []string, tenantsCreated *corev1.ConfigMap, failed map[string]error) map[string]int {
+	states := map[string]int{
+		TenantStatePending:  0,
+		TenantStateCreating: 0,
+		TenantStateReady:    0,
+		TenantStateFailed:   0,
+	}
+
+	existing := map[string]bool{}
+	for _, account := range accounts {
+		existing[account.OrgName] = true
+	}
+	for _, tenant := range created {
+		existing[tenant] = true
+	}
+
+	for _, user := range users {
+		switch {
+		case failed[user.TenantName] != nil:
+			states[TenantStateFailed]++
+		case tenantsCreated.Data[user.TenantName] == "true":
+			states[TenantStateReady]++
+		case existing[user.TenantName]:
+			states[TenantStateCreating]++
+		default:
+			states[TenantStatePending]++
+		}
+	}
+
+	return states
+}
@@ -0,0 +1,103 @@
+package threescale
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	userHelper "github.com/integr8ly/integreatly-operator/pkg/resources/user"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+func TestTenantProvisionerRun(t *testing.T) {
+	provisioner := &tenantProvisioner{
+		workers: 2,
+		limiter: flowcontrol.NewFakeAlwaysRateLimiter(),
+		backoff: flowcontrol.NewBackOff(time.Hour, 2*time.Hour),
+	}
+
+	var running, maxRunning, calls int32
+	provision := func(failing string) func(ctx context.Context, tenant string) error {
+		return func(ctx context.Context, tenant string) error {
+			atomic.AddInt32(&calls, 1)
+			current := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				seen := atomic.LoadInt32(&maxRunning)
+				if current <= seen || atomic.CompareAndSwapInt32(&maxRunning, seen, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			if tenant == failing {
+				return fmt.Errorf("failed to create %s", tenant)
+			}
+			return nil
+		}
+	}
+
+	tenants := []string{"tenant-a", "tenant-b", "tenant-c", "tenant-d"}
+
+	failed := provisioner.run(context.TODO(), tenants, provision("tenant-b"))
+	if len(failed) != 1 || failed["tenant-b"] == nil {
+		t.Fatalf("expected only tenant-b to fail, got %v", failed)
+	}
+	if calls != 4 {
+		t.Fatalf("expected 4 provisioning calls, got %d", calls)
+	}
+	if maxRunning > 2 {
+		t.Fatalf("expected at most 2 tenants provisioned in parallel, got %d", maxRunning)
+	}
+
+	// the failed tenant is backing off and is not retried straight away
+	calls = 0
+	failed = provisioner.run(context.TODO(), tenants, provision(""))
+	if len(failed) != 1 || failed["tenant-b"] == nil {
+		t.Fatalf("expected tenant-b to still be reported as failed while backing off, got %v", failed)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 provisioning calls, got %d", calls)
+	}
+
+	// once the backoff is cleared the tenant is retried
+	provisioner.backoff.Reset("tenant-b")
+	calls = 0
+	failed = provisioner.run(context.TODO(), []string{"tenant-b"}, provision(""))
+	if len(failed) != 0 || calls != 1 {
+		t.Fatalf("expected tenant-b to be retried successfully, got failures %v after %d calls", failed, calls)
+	}
+}
+
+func TestGetTenantProvisioningStates(t *testing.T) {
+	users := []userHelper.MultiTenantUser{
+		{Username: "ready", TenantName: "ready"},
+		{Username: "activating", TenantName: "activating"},
+		{Username: "created", TenantName: "created"},
+		{Username: "failed", TenantName: "failed"},
+		{Username: "pending", TenantName: "pending"},
+	}
+	accounts := []AccountDetail{
+		{OrgName: "ready", State: "approved"},
+		{OrgName: "activating", State: "approved"},
+		{OrgName: "failed", State: "approved"},
+	}
+	tenantsCreated := &corev1.ConfigMap{Data: map[string]string{"ready": "true"}}
+	failed := map[string]error{"failed": fmt.Errorf("failed to activate user")}
+
+	states := getTenantProvisioningStates(users, accounts, []string{"created"}, tenantsCreated, failed)
+
+	expected := map[string]int{
+		TenantStatePending:  1,
+		TenantStateCreating: 2,
+		TenantStateReady:    1,
+		TenantStateFailed:   1,
+	}
+	for state, count := range expected {
+		if states[state] != count {
+			t.Errorf("expected %d tenants in state %s, got %d", count, state, states[state])
+		}
+	}
+}
@@ -35,6 +35,11 @@ type ThreeScaleInterface interface {
 	CreateBackend(accessToken, name, privateEndpoint string) (int, error)
 	CreateMetric(accessToken string, backendID int, friendlyName, unit string) (int, error)
 	CreateBackendMappingRule(accessToken string, backendID, metricID int, httpMethod, pattern string, delta int) error
+	UpdateBackend(accessToken string, backendID int, privateEndpoint string) error
+	ListBackendMetrics(accessToken string, backendID int) ([]BackendMetric, error)
+	ListBackendMappingRules(accessToken string, backendID int) ([]BackendMappingRule, error)
+	DeleteBackendMetric(accessToken string, backendID, metricID int) error
+	DeleteBackendMappingRule(accessToken string, backendID, mappingRuleID int) error
 	CreateService(accessToken, name, systemName string) (string, error)
 	CreateBackendUsage(accessToken, serviceID string, backendID int, path string) error
 	CreateApplicationPlan(accessToken, serviceID, name string) (string, error)
@@ -60,6 +65,9 @@ type ThreeScaleInterface interface {
 const (
 	adminRole  = "admin"
 	memberRole = "member"
+
+	// backendListPageSize is the largest page the 3scale API returns for the metrics and mapping rules of a backend
+	backendListPageSize = 500
 )
 
 type threeScaleClient struct {
@@ -412,6 +420,109 @@ func (tsc *threeScaleClient) CreateBackendMappingRule(accessToken string, backen
 	return assertStatusCode(http.StatusCreated, res)
 }
 
+func (tsc *threeScaleClient) UpdateBackend(accessToken string, backendID int, privateEndpoint string) error {
+	res, err := tsc.makeRequest(
+		"PUT",
+		fmt.Sprintf("backend_apis/%d.json", backendID),
+		withAccessToken(accessToken, map[string]interface{}{
+			"private_endpoint": privateEndpoint,
+		}),
+	)
+	if err != nil {
+		return err
+	}
+
+	return assertStatusCode(http.StatusOK, res)
+}
+
+func (tsc *threeScaleClient) ListBackendMetrics(accessToken string, backendID int) ([]BackendMetric, error) {
+	res, err := tsc.makeRequest(
+		"GET",
+		fmt.Sprintf("backend_apis/%d/metrics.json", backendID),
+		withAccessToken(accessToken, map[string]interface{}{
+			"per_page": backendListPageSize,
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := assertStatusCode(http.StatusOK, res); err != nil {
+		return nil, err
+	}
+
+	responseBody := &struct {
+		Metrics []struct {
+			Metric BackendMetric `json:"metric"`
+		} `json:"metrics"`
+	}{}
+	if err := jsonFromResponse(res, responseBody); err != nil {
+		return nil, err
+	}
+
+	metrics := make([]BackendMetric, 0, len(responseBody.Metrics))
+	for _, metric := range responseBody.Metrics {
+		metrics = append(metrics, metric.Metric)
+	}
+	return metrics, nil
+}
+
+func (tsc *threeScaleClient) ListBackendMappingRules(accessToken string, backendID int) ([]BackendMappingRule, error) {
+	res, err := tsc.makeRequest(
+		"GET",
+		fmt.Sprintf("backend_apis/%d/mapping_rules.json", backendID),
+		withAccessToken(accessToken, map[string]interface{}{
+			"per_page": backendListPageSize,
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := assertStatusCode(http.StatusOK, res); err != nil {
+		return nil, err
+	}
+
+	responseBody := &struct {
+		MappingRules []struct {
+			MappingRule BackendMappingRule `json:"mapping_rule"`
+		} `json:"mapping_rules"`
+	}{}
+	if err := jsonFromResponse(res, responseBody); err != nil {
+		return nil, err
+	}
+
+	mappingRules := make([]BackendMappingRule, 0, len(responseBody.MappingRules))
+	for _, mappingRule := range responseBody.MappingRules {
+		mappingRules = append(mappingRules, mappingRule.MappingRule)
+	}
+	return mappingRules, nil
+}
+
+func (tsc *threeScaleClient) DeleteBackendMetric(accessToken string, backendID, metricID int) error {
+	res, err := tsc.makeRequest(
+		"DELETE",
+		fmt.Sprintf("backend_apis/%d/metrics/%d.json", backendID, metricID),
+		onlyAccessToken(accessToken),
+	)
+	if err != nil {
+		return err
+	}
+
+	return assertStatusCode(http.StatusOK, res)
+}
+
+func (tsc *threeScaleClient) DeleteBackendMappingRule(accessToken string, backendID, mappingRuleID int) error {
+	res, err := tsc.makeRequest(
+		"DELETE",
+		fmt.Sprintf("backend_apis/%d/mapping_rules/%d.json", backendID, mappingRuleID),
+		onlyAccessToken(accessToken),
+	)
+	if err != nil {
+		return err
+	}
+
+	return assertStatusCode(http.StatusOK, res)
+}
+
 func (tsc *threeScaleClient) CreateService(accessToken, name, systemName string) (string, error) {
 	res, err := tsc.makeRequest(
 		"POST",
@@ -857,5 +968,5 @@ func assertStatusCode(expected int, res *http.Response) error {
 		return err
 	}
 
-	return fmt.Errorf("unexpected status code: %d. Body: %s", res.StatusCode, string(body))
+	return &tsError{message: fmt.Sprintf("unexpected status code: %d. Body: %s", res.StatusCode, string(body)), StatusCode: res.StatusCode}
 }
@@ -64,6 +64,12 @@ var _ ThreeScaleInterface = &ThreeScaleInterfaceMock{}
 //			DeleteBackendFunc: func(accessToken string, backendID int) error {
 //				panic("mock out the DeleteBackend method")
 //			},
+//			DeleteBackendMappingRuleFunc: func(accessToken string, backendID int, mappingRuleID int) error {
+//				panic("mock out the DeleteBackendMappingRule method")
+//			},
+//			DeleteBackendMetricFunc: func(accessToken string, backendID int, metricID int) error {
+//				panic("mock out the DeleteBackendMetric method")
+//			},
 //			DeleteServiceFunc: func(accessToken string, serviceID string) error {
 //				panic("mock out the DeleteService method")
 //			},
@@ -97,6 +103,12 @@ var _ ThreeScaleInterface = &ThreeScaleInterfaceMock{}
 //			IsAuthProviderAddedFunc: func(accessToken string, authProviderName string, account AccountDetail) (bool, error) {
 //				panic("mock out the IsAuthProviderAdded method")
 //			},
+//			ListBackendMappingRulesFunc: func(accessToken string, backendID int) ([]BackendMappingRule, error) {
+//				panic("mock out the ListBackendMappingRules method")
+//			},
+//			ListBackendMetricsFunc: func(accessToken string, backendID int) ([]BackendMetric, error) {
+//				panic("mock out the ListBackendMetrics method")
+//			},
 //			ListTenantAccountsFunc: func(accessToken string, page int, filterFn func(ac AccountDetail) bool) ([]AccountDetail, error) {
 //				panic("mock out the ListTenantAccounts method")
 //			},
@@ -115,6 +127,9 @@ var _ ThreeScaleInterface = &ThreeScaleInterfaceMock{}
 //			SetUserAsMemberFunc: func(userID int, accessToken string) (*http.Response, error) {
 //				panic("mock out the SetUserAsMember method")
 //			},
+//			UpdateBackendFunc: func(accessToken string, backendID int, privateEndpoint string) error {
+//				panic("mock out the UpdateBackend method")
+//			},
 //			UpdateTenantFunc: func(id int64, params portaClient.Params, portaClientMoqParam *portaClient.ThreeScaleClient) error {
 //				panic("mock out the UpdateTenant method")
 //			},
@@ -173,6 +188,12 @@ type ThreeScaleInterfaceMock struct {
 	// DeleteBackendFunc mocks the DeleteBackend method.
 	DeleteBackendFunc func(accessToken string, backendID int) error
 
+	// DeleteBackendMappingRuleFunc mocks the DeleteBackendMappingRule method.
+	DeleteBackendMappingRuleFunc func(accessToken string, backendID int, mappingRuleID int) error
+
+	// DeleteBackendMetricFunc mocks the DeleteBackendMetric method.
+	DeleteBackendMetricFunc func(accessToken string, backendID int, metricID int) error
+
 	// DeleteServiceFunc mocks the DeleteService method.
 	DeleteServiceFunc func(accessToken string, serviceID string) error
 
@@ -206,6 +227,12 @@ type ThreeScaleInterfaceMock struct {
 	// IsAuthProviderAddedFunc mocks the IsAuthProviderAdded method.
 	IsAuthProviderAddedFunc func(accessToken string, authProviderName string, account AccountDetail) (bool, error)
 
+	// ListBackendMappingRulesFunc mocks the ListBackendMappingRules method.
+	ListBackendMappingRulesFunc func(accessToken string, backendID int) ([]BackendMappingRule, error)
+
+	// ListBackendMetricsFunc mocks the ListBackendMetrics method.
+	ListBackendMetricsFunc func(accessToken string, backendID int) ([]BackendMetric, error)
+
 	// ListTenantAccountsFunc mocks the ListTenantAccounts method.
 	ListTenantAccountsFunc func(accessToken string, page int, filterFn func(ac AccountDetail) bool) ([]AccountDetail, error)
 
@@ -224,6 +251,9 @@ type ThreeScaleInterfaceMock struct {
 	// SetUserAsMemberFunc mocks the SetUserAsMember method.
 	SetUserAsMemberFunc func(userID int, accessToken string) (*http.Response, error)
 
+	// UpdateBackendFunc mocks the UpdateBackend method.
+	UpdateBackendFunc func(accessToken string, backendID int, privateEndpoint string) error
+
 	// UpdateTenantFunc mocks the UpdateTenant method.
 	UpdateTenantFunc func(id int64, params portaClient.Params, portaClientMoqParam *portaClient.ThreeScaleClient) error
 
@@ -379,6 +409,24 @@ type ThreeScaleInterfaceMock struct {
 			// BackendID is the backendID argument value.
 			BackendID int
 		}
+		// DeleteBackendMappingRule holds details about calls to the DeleteBackendMappingRule method.
+		DeleteBackendMappingRule []struct {
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+			// BackendID is the backendID argument value.
+			BackendID int
+			// MappingRuleID is the mappingRuleID argument value.
+			MappingRuleID int
+		}
+		// DeleteBackendMetric holds details about calls to the DeleteBackendMetric method.
+		DeleteBackendMetric []struct {
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+			// BackendID is the backendID argument value.
+			BackendID int
+			// MetricID is the metricID argument value.
+			MetricID int
+		}
 		// DeleteService holds details about calls to the DeleteService method.
 		DeleteService []struct {
 			// AccessToken is the accessToken argument value.
@@ -454,6 +502,20 @@ type ThreeScaleInterfaceMock struct {
 			// Account is the account argument value.
 			Account AccountDetail
 		}
+		// ListBackendMappingRules holds details about calls to the ListBackendMappingRules method.
+		ListBackendMappingRules []struct {
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+			// BackendID is the backendID argument value.
+			BackendID int
+		}
+		// ListBackendMetrics holds details about calls to the ListBackendMetrics method.
+		ListBackendMetrics []struct {
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+			// BackendID is the backendID argument value.
+			BackendID int
+		}
 		// ListTenantAccounts holds details about calls to the ListTenantAccounts method.
 		ListTenantAccounts []struct {
 			// AccessToken is the accessToken argument value.
@@ -500,6 +562,15 @@ type ThreeScaleInterfaceMock struct {
 			// AccessToken is the accessToken argument value.
 			AccessToken string
 		}
+		// UpdateBackend holds details about calls to the UpdateBackend method.
+		UpdateBackend []struct {
+			// AccessToken is the accessToken argument value.
+			AccessToken string
+			// BackendID is the backendID argument value.
+			BackendID int
+			// PrivateEndpoint is the privateEndpoint argument value.
+			PrivateEndpoint string
+		}
 		// UpdateTenant holds details about calls to the UpdateTenant method.
 		UpdateTenant []struct {
 			// ID is the id argument value.
@@ -536,6 +607,8 @@ type ThreeScaleInterfaceMock struct {
 	lockCreateTenant                    sync.RWMutex
 	lockDeleteAccount                   sync.RWMutex
 	lockDeleteBackend                   sync.RWMutex
+	lockDeleteBackendMappingRule        sync.RWMutex
+	lockDeleteBackendMetric             sync.RWMutex
 	lockDeleteService                   sync.RWMutex
 	lockDeleteTenant                    sync.RWMutex
 	lockDeleteTenants                   sync.RWMutex
@@ -547,12 +620,15 @@ type ThreeScaleInterfaceMock struct {
 	lockGetUser                         sync.RWMutex
 	lockGetUsers                        sync.RWMutex
 	lockIsAuthProviderAdded             sync.RWMutex
+	lockListBackendMappingRules         sync.RWMutex
+	lockListBackendMetrics              sync.RWMutex
 	lockListTenantAccounts              sync.RWMutex
 	lockPromoteProxy                    sync.RWMutex
 	lockSetFromEmailAddress             sync.RWMutex
 	lockSetNamespace                    sync.RWMutex
 	lockSetUserAsAdmin                  sync.RWMutex
 	lockSetUserAsMember                 sync.RWMutex
+	lockUpdateBackend                   sync.RWMutex
 	lockUpdateTenant                    sync.RWMutex
 	lockUpdateUser                      sync.RWMutex
 }
@@ -1181,6 +1257,86 @@ func (mock *ThreeScaleInterfaceMock) DeleteBackendCalls() []struct {
 	return calls
 }
 
+// DeleteBackendMappingRule calls DeleteBackendMappingRuleFunc.
+func (mock *ThreeScaleInterfaceMock) DeleteBackendMappingRule(accessToken string, backendID int, mappingRuleID int) error {
+	if mock.DeleteBackendMappingRuleFunc == nil {
+		panic("ThreeScaleInterfaceMock.DeleteBackendMappingRuleFunc: method is nil but ThreeScaleInterface.DeleteBackendMappingRule was just called")
+	}
+	callInfo := struct {
+		AccessToken   string
+		BackendID     int
+		MappingRuleID int
+	}{
+		AccessToken:   accessToken,
+		BackendID:     backendID,
+		MappingRuleID: mappingRuleID,
+	}
+	mock.lockDeleteBackendMappingRule.Lock()
+	mock.calls.DeleteBackendMappingRule = append(mock.calls.DeleteBackendMappingRule, callInfo)
+	mock.lockDeleteBackendMappingRule.Unlock()
+	return mock.DeleteBackendMappingRuleFunc(accessToken, backendID, mappingRuleID)
+}
+
+// DeleteBackendMappingRuleCalls gets all the calls that were made to DeleteBackendMappingRule.
+// Check the length with:
+//
+//	len(mockedThreeScaleInterface.DeleteBackendMappingRuleCalls())
+func (mock *ThreeScaleInterfaceMock) DeleteBackendMappingRuleCalls() []struct {
+	AccessToken   string
+	BackendID     int
+	MappingRuleID int
+} {
+	var calls []struct {
+		AccessToken   string
+		BackendID     int
+		MappingRuleID int
+	}
+	mock.lockDeleteBackendMappingRule.RLock()
+	calls = mock.calls.DeleteBackendMappingRule
+	mock.lockDeleteBackendMappingRule.RUnlock()
+	return calls
+}
+
+// DeleteBackendMetric calls DeleteBackendMetricFunc.
+func (mock *ThreeScaleInterfaceMock) DeleteBackendMetric(accessToken string, backendID int, metricID int) error {
+	if mock.DeleteBackendMetricFunc == nil {
+		panic("ThreeScaleInterfaceMock.DeleteBackendMetricFunc: method is nil but ThreeScaleInterface.DeleteBackendMetric was just called")
+	}
+	callInfo := struct {
+		AccessToken string
+		BackendID   int
+		MetricID    int
+	}{
+		AccessToken: accessToken,
+		BackendID:   backendID,
+		MetricID:    metricID,
+	}
+	mock.lockDeleteBackendMetric.Lock()
+	mock.calls.DeleteBackendMetric = append(mock.calls.DeleteBackendMetric, callInfo)
+	mock.lockDeleteBackendMetric.Unlock()
+	return mock.DeleteBackendMetricFunc(accessToken, backendID, metricID)
+}
+
+// DeleteBackendMetricCalls gets all the calls that were made to DeleteBackendMetric.
+// Check the length with:
+//
+//	len(mockedThreeScaleInterface.DeleteBackendMetricCalls())
+func (mock *ThreeScaleInterfaceMock) DeleteBackendMetricCalls() []struct {
+	AccessToken string
+	BackendID   int
+	MetricID    int
+} {
+	var calls []struct {
+		AccessToken string
+		BackendID   int
+		MetricID    int
+	}
+	mock.lockDeleteBackendMetric.RLock()
+	calls = mock.calls.DeleteBackendMetric
+	mock.lockDeleteBackendMetric.RUnlock()
+	return calls
+}
+
 // DeleteService calls DeleteServiceFunc.
 func (mock *ThreeScaleInterfaceMock) DeleteService(accessToken string, serviceID string) error {
 	if mock.DeleteServiceFunc == nil {
@@ -1573,6 +1729,78 @@ func (mock *ThreeScaleInterfaceMock) IsAuthProviderAddedCalls() []struct {
 	return calls
 }
 
+// ListBackendMappingRules calls ListBackendMappingRulesFunc.
+func (mock *ThreeScaleInterfaceMock) ListBackendMappingRules(accessToken string, backendID int) ([]BackendMappingRule, error) {
+	if mock.ListBackendMappingRulesFunc == nil {
+		panic("ThreeScaleInterfaceMock.ListBackendMappingRulesFunc: method is nil but ThreeScaleInterface.ListBackendMappingRules was just called")
+	}
+	callInfo := struct {
+		AccessToken string
+		BackendID   int
+	}{
+		AccessToken: accessToken,
+		BackendID:   backendID,
+	}
+	mock.lockListBackendMappingRules.Lock()
+	mock.calls.ListBackendMappingRules = append(mock.calls.ListBackendMappingRules, callInfo)
+	mock.lockListBackendMappingRules.Unlock()
+	return mock.ListBackendMappingRulesFunc(accessToken, backendID)
+}
+
+// ListBackendMappingRulesCalls gets all the calls that were made to ListBackendMappingRules.
+// Check the length with:
+//
+//	len(mockedThreeScaleInterface.ListBackendMappingRulesCalls())
+func (mock *ThreeScaleInterfaceMock) ListBackendMappingRulesCalls() []struct {
+	AccessToken string
+	BackendID   int
+} {
+	var calls []struct {
+		AccessToken string
+		BackendID   int
+	}
+	mock.lockListBackendMappingRules.RLock()
+	calls = mock.calls.ListBackendMappingRules
+	mock.lockListBackendMappingRules.RUnlock()
+	return calls
+}
+
+// ListBackendMetrics calls ListBackendMetricsFunc.
+func (mock *ThreeScaleInterfaceMock) ListBackendMetrics(accessToken string, backendID int) ([]BackendMetric, error) {
+	if mock.ListBackendMetricsFunc == nil {
+		panic("ThreeScaleInterfaceMock.ListBackendMetricsFunc: method is nil but ThreeScaleInterface.ListBackendMetrics was just called")
+	}
+	callInfo := struct {
+		AccessToken string
+		BackendID   int
+	}{
+		AccessToken: accessToken,
+		BackendID:   backendID,
+	}
+	mock.lockListBackendMetrics.Lock()
+	mock.calls.ListBackendMetrics = append(mock.calls.ListBackendMetrics, callInfo)
+	mock.lockListBackendMetrics.Unlock()
+	return mock.ListBackendMetricsFunc(accessToken, backendID)
+}
+
+// ListBackendMetricsCalls gets all the calls that were made to ListBackendMetrics.
+// Check the length with:
+//
+//	len(mockedThreeScaleInterface.ListBackendMetricsCalls())
+func (mock *ThreeScaleInterfaceMock) ListBackendMetricsCalls() []struct {
+	AccessToken string
+	BackendID   int
+} {
+	var calls []struct {
+		AccessToken string
+		BackendID   int
+	}
+	mock.lockListBackendMetrics.RLock()
+	calls = mock.calls.ListBackendMetrics
+	mock.lockListBackendMetrics.RUnlock()
+	return calls
+}
+
 // ListTenantAccounts calls ListTenantAccountsFunc.
 func (mock *ThreeScaleInterfaceMock) ListTenantAccounts(accessToken string, page int, filterFn func(ac AccountDetail) bool) ([]AccountDetail, error) {
 	if mock.ListTenantAccountsFunc == nil {
@@ -1797,6 +2025,46 @@ func (mock *ThreeScaleInterfaceMock) SetUserAsMemberCalls() []struct {
 	return calls
 }
 
+// UpdateBackend calls UpdateBackendFunc.
+func (mock *ThreeScaleInterfaceMock) UpdateBackend(accessToken string, backendID int, privateEndpoint string) error {
+	if mock.UpdateBackendFunc == nil {
+		panic("ThreeScaleInterfaceMock.UpdateBackendFunc: method is nil but ThreeScaleInterface.UpdateBackend was just called")
+	}
+	callInfo := struct {
+		AccessToken     string
+		BackendID       int
+		PrivateEndpoint string
+	}{
+		AccessToken:     accessToken,
+		BackendID:       backendID,
+		PrivateEndpoint: privateEndpoint,
+	}
+	mock.lockUpdateBackend.Lock()
+	mock.calls.UpdateBackend = append(mock.calls.UpdateBackend, callInfo)
+	mock.lockUpdateBackend.Unlock()
+	return mock.UpdateBackendFunc(accessToken, backendID, privateEndpoint)
+}
+
+// UpdateBackendCalls gets all the calls that were made to UpdateBackend.
+// Check the length with:
+//
+//	len(mockedThreeScaleInterface.UpdateBackendCalls())
+func (mock *ThreeScaleInterfaceMock) UpdateBackendCalls() []struct {
+	AccessToken     string
+	BackendID       int
+	PrivateEndpoint string
+} {
+	var calls []struct {
+		AccessToken     string
+		BackendID       int
+		PrivateEndpoint string
+	}
+	mock.lockUpdateBackend.RLock()
+	calls = mock.calls.UpdateBackend
+	mock.lockUpdateBackend.RUnlock()
+	return calls
+}
+
 // UpdateTenant calls UpdateTenantFunc.
 func (mock *ThreeScaleInterfaceMock) UpdateTenant(id int64, params portaClient.Params, portaClientMoqParam *portaClient.ThreeScaleClient) error {
 	if mock.UpdateTenantFunc == nil {
@@ -79,6 +79,20 @@ type AccountAccessToken struct {
 	Value string `xml:"value"`
 }
 
+type BackendMetric struct {
+	ID           int    `json:"id"`
+	FriendlyName string `json:"friendly_name"`
+	SystemName   string `json:"system_name"`
+}
+
+type BackendMappingRule struct {
+	ID         int    `json:"id"`
+	MetricID   int    `json:"metric_id"`
+	HTTPMethod string `json:"http_method"`
+	Pattern    string `json:"pattern"`
+	Delta      int    `json:"delta"`
+}
+
 type XMLAccountList struct {
 	Accounts []AccountDetail `xml:"account"`
 }
@@ -0,0 +1,267 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monv1 "github.com/rhobs/obo-prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// AlertOverridesConfigMapName is the ConfigMap in the installation namespace that overrides the
+	// duration, severity and threshold of alerts by their name
+	AlertOverridesConfigMapName = "alert-overrides"
+	alertOverridesKey           = "alerts"
+
+	// AlertOverridesAnnotation reports the effective values of the overridden alerts of a PrometheusRule
+	AlertOverridesAnnotation = "integreatly.org/alert-overrides"
+
+	// ThresholdAnnotation declares the threshold of an alert. Its expression refers to the threshold with
+	// ThresholdPlaceholder, which is replaced by the declared threshold or the override. Only the alerts
+	// that declare a threshold can have it overridden
+	ThresholdAnnotation  = "threshold"
+	ThresholdPlaceholder = "$threshold"
+
+	severityLabel = "severity"
+)
+
+var alertSeverities = []string{"critical", "warning", "info", "none"}
+
+// AlertOverride replaces the values an alert is defined with. Values that are not set are left unchanged
+type AlertOverride struct {
+	For       string   `json:"for,omitempty"`
+	Severity  string   `json:"severity,omitempty"`
+	Threshold *float64 `json:"threshold,omitempty"`
+
+	// invalid is why the override was skipped, the alert keeps the values it is defined with
+	invalid string
+}
+
+// EffectiveAlertOverride is the result of overriding an alert, the error is set when part of the
+// override could not be applied
+type EffectiveAlertOverride struct {
+	For       string `json:"for,omitempty"`
+	Severity  string `json:"severity,omitempty"`
+	Threshold string `json:"threshold,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// AlertOverrides maps alert names to their override
+type AlertOverrides map[string]AlertOverride
+
+func (o AlertOverride) Validate() error {
+	if o.For != "" {
+		if _, err := time.ParseDuration(o.For); err != nil {
+			return fmt.Errorf("invalid for duration %s: %w", o.For, err)
+		}
+	}
+	if o.Severity != "" && !Contains(alertSeverities, o.Severity) {
+		return fmt.Errorf("invalid severity %s, must be one of %v", o.Severity, alertSeverities)
+	}
+	return nil
+}
+
+// GetAlertOverrides reads the alert overrides of the installation. A missing ConfigMap results in
+// no overrides
+func GetAlertOverrides(ctx context.Context, client k8sclient.Client, namespace string) (AlertOverrides, error) {
+	configMap, err := getAlertSettingsConfigMap(ctx, client, AlertOverridesConfigMapName, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewAlertOverrides(configMap)
+}
+
+// NewAlertOverrides parses the alert overrides from the ConfigMap. A nil ConfigMap results in no
+// overrides. Invalid overrides are kept so they are reported on the alert, but none of their values
+// are applied
+func NewAlertOverrides(configMap *corev1.ConfigMap) (AlertOverrides, error) {
+	overrides := AlertOverrides{}
+	if configMap == nil {
+		return overrides, nil
+	}
+
+	value, ok := configMap.Data[alertOverridesKey]
+	if !ok {
+		return overrides, nil
+	}
+	entries := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(value), &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse %s key of %s config map: %w", alertOverridesKey, AlertOverridesConfigMapName, err)
+	}
+	for alert, entry := range entries {
+		override := AlertOverride{}
+		err := json.Unmarshal(entry, &override)
+		if err == nil {
+			err = override.Validate()
+		}
+		if err != nil {
+			override = AlertOverride{invalid: fmt.Sprintf("invalid override: %v", err)}
+		}
+		overrides[alert] = override
+	}
+
+	return overrides, nil
+}
+
+// Invalid returns the alerts whose override was skipped and why
+func (o AlertOverrides) Invalid() map[string]string {
+	invalid := map[string]string{}
+	for alert, override := range o {
+		if override.invalid != "" {
+			invalid[alert] = override.invalid
+		}
+	}
+	return invalid
+}
+
+// apply returns the duration and labels of the alert with the override applied
+func (o AlertOverride) apply(forDuration string, labels map[string]string) (string, map[string]string, EffectiveAlertOverride) {
+	effective := EffectiveAlertOverride{}
+
+	if o.For != "" {
+		forDuration = o.For
+	}
+	effective.For = forDuration
+
+	overridden := map[string]string{}
+	for key, value := range labels {
+		overridden[key] = value
+	}
+	if o.Severity != "" {
+		overridden[severityLabel] = o.Severity
+	}
+	effective.Severity = overridden[severityLabel]
+
+	return forDuration, overridden, effective
+}
+
+// applyThreshold returns the expression with its threshold placeholder replaced by the threshold the alert
+// declares, or by the override when it is set, and the annotations declaring the threshold used
+func applyThreshold(expr string, annotations map[string]string, override *float64) (string, map[string]string, string, error) {
+	threshold, declared := annotations[ThresholdAnnotation]
+	if !declared {
+		if override != nil {
+			return expr, annotations, "", fmt.Errorf("the alert does not declare a threshold")
+		}
+		return expr, annotations, "", nil
+	}
+
+	if override != nil {
+		threshold = strconv.FormatFloat(*override, 'f', -1, 64)
+		overridden := map[string]string{}
+		for key, value := range annotations {
+			overridden[key] = value
+		}
+		overridden[ThresholdAnnotation] = threshold
+		annotations = overridden
+	}
+	return strings.ReplaceAll(expr, ThresholdPlaceholder, threshold), annotations, threshold, nil
+}
+
+// applyOverrides returns a copy of the rules with the declared thresholds and the overrides of their alerts
+// applied, and the effective values of the overridden alerts
+func (o AlertOverrides) applyOverrides(rules interface{}) (interface{}, map[string]EffectiveAlertOverride) {
+	effective := map[string]EffectiveAlertOverride{}
+	override := func(alert string, expr, forDuration string, labels, annotations map[string]string) (string, string, map[string]string, map[string]string) {
+		alertOverride, ok := o[alert]
+		expr, annotations, threshold, err := applyThreshold(expr, annotations, alertOverride.Threshold)
+		if !ok || alert == "" {
+			return expr, forDuration, labels, annotations
+		}
+
+		forDuration, labels, result := alertOverride.apply(forDuration, labels)
+		result.Threshold = threshold
+		if err != nil {
+			result.Error = err.Error()
+		}
+		if alertOverride.invalid != "" {
+			result.Error = alertOverride.invalid
+		}
+		effective[alert] = result
+		return expr, forDuration, labels, annotations
+	}
+
+	switch alertRules := rules.(type) {
+	case []monv1.Rule:
+		overridden := make([]monv1.Rule, len(alertRules))
+		for i, rule := range alertRules {
+			forDuration := ""
+			if rule.For != nil {
+				forDuration = string(*rule.For)
+			}
+			expr, forDuration, labels, annotations := override(rule.Alert, rule.Expr.String(), forDuration, rule.Labels, rule.Annotations)
+			if expr != rule.Expr.String() {
+				rule.Expr = intstr.FromString(expr)
+			}
+			rule.Labels = labels
+			rule.Annotations = annotations
+			if forDuration != "" {
+				d := monv1.Duration(forDuration)
+				rule.For = &d
+			}
+			overridden[i] = rule
+		}
+		return overridden, effective
+	case []monitoringv1.Rule:
+		overridden := make([]monitoringv1.Rule, len(alertRules))
+		for i, rule := range alertRules {
+			forDuration := ""
+			if rule.For != nil {
+				forDuration = string(*rule.For)
+			}
+			expr, forDuration, labels, annotations := override(rule.Alert, rule.Expr.String(), forDuration, rule.Labels, rule.Annotations)
+			if expr != rule.Expr.String() {
+				rule.Expr = intstr.FromString(expr)
+			}
+			rule.Labels = labels
+			rule.Annotations = annotations
+			if forDuration != "" {
+				d := monitoringv1.Duration(forDuration)
+				rule.For = &d
+			}
+			overridden[i] = rule
+		}
+		return overridden, effective
+	default:
+		return rules, effective
+	}
+}
+
+// setAlertOverridesAnnotation reports the effective values of the overridden alerts on the annotations
+// of their PrometheusRule
+func setAlertOverridesAnnotation(annotations map[string]string, effective map[string]EffectiveAlertOverride) (map[string]string, error) {
+	if len(effective) == 0 {
+		delete(annotations, AlertOverridesAnnotation)
+		return annotations, nil
+	}
+
+	value, err := json.Marshal(effective)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode effective alert overrides: %w", err)
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[AlertOverridesAnnotation] = string(value)
+	return annotations, nil
+}
+
+// alertOverrideErrors returns the alerts whose override could not be fully applied
+func alertOverrideErrors(effective map[string]EffectiveAlertOverride) map[string]string {
+	errs := map[string]string{}
+	for alert, result := range effective {
+		if result.Error != "" {
+			errs[alert] = result.Error
+		}
+	}
+	return errs
+}
@@ -0,0 +1,298 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/utils"
+	monv1 "github.com/rhobs/obo-prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func alertOverridesConfigMap(alerts string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: v1.ObjectMeta{Name: AlertOverridesConfigMapName, Namespace: "testing-namespaces-test"},
+		Data:       map[string]string{alertOverridesKey: alerts},
+	}
+}
+
+func TestGetAlertOverrides(t *testing.T) {
+	scenarios := []struct {
+		Name          string
+		ConfigMap     *corev1.ConfigMap
+		WantOverrides int
+		WantInvalid   int
+		WantErr       bool
+	}{
+		{
+			Name: "no overrides without a config map",
+		},
+		{
+			Name:          "overrides are read by alert name",
+			ConfigMap:     alertOverridesConfigMap(`{"PostgresCPUHigh": {"threshold": 90, "for": "10m", "severity": "warning"}}`),
+			WantOverrides: 1,
+		},
+		{
+			Name:          "invalid durations are skipped",
+			ConfigMap:     alertOverridesConfigMap(`{"PostgresCPUHigh": {"for": "ten minutes"}, "RedisMemoryUsageHigh": {"for": "10m"}}`),
+			WantOverrides: 2,
+			WantInvalid:   1,
+		},
+		{
+			Name:          "unknown severities are skipped",
+			ConfigMap:     alertOverridesConfigMap(`{"PostgresCPUHigh": {"severity": "page"}}`),
+			WantOverrides: 1,
+			WantInvalid:   1,
+		},
+		{
+			Name:          "invalid thresholds are skipped",
+			ConfigMap:     alertOverridesConfigMap(`{"PostgresCPUHigh": {"threshold": "high"}}`),
+			WantOverrides: 1,
+			WantInvalid:   1,
+		},
+		{
+			Name:      "malformed overrides are rejected",
+			ConfigMap: alertOverridesConfigMap(`["PostgresCPUHigh"]`),
+			WantErr:   true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			scheme, err := utils.NewTestScheme()
+			if err != nil {
+				t.Fatal(err)
+			}
+			objects := []runtime.Object{}
+			if scenario.ConfigMap != nil {
+				objects = append(objects, scenario.ConfigMap)
+			}
+
+			overrides, err := GetAlertOverrides(context.TODO(), utils.NewTestClient(scheme, objects...), "testing-namespaces-test")
+			if (err != nil) != scenario.WantErr {
+				t.Fatalf("unexpected error %v, wantErr %v", err, scenario.WantErr)
+			}
+			if len(overrides) != scenario.WantOverrides {
+				t.Errorf("expected %d overrides, got %v", scenario.WantOverrides, overrides)
+			}
+			if invalid := overrides.Invalid(); len(invalid) != scenario.WantInvalid {
+				t.Errorf("expected %d invalid overrides, got %v", scenario.WantInvalid, invalid)
+			}
+		})
+	}
+}
+
+func TestApplyOverrides(t *testing.T) {
+	threshold := func(value float64) *float64 { return &value }
+
+	scenarios := []struct {
+		Name          string
+		Override      *AlertOverride
+		Expr          string
+		Threshold     string
+		WantExpr      string
+		WantFor       string
+		WantThreshold string
+		WantEffective *EffectiveAlertOverride
+	}{
+		{
+			Name:          "declared thresholds are overridden",
+			Override:      &AlertOverride{Threshold: threshold(85.5), For: "30m", Severity: "critical"},
+			Expr:          "cro_postgres_cpu_utilization_average > $threshold",
+			Threshold:     "80",
+			WantExpr:      "cro_postgres_cpu_utilization_average > 85.5",
+			WantFor:       "30m",
+			WantThreshold: "85.5",
+			WantEffective: &EffectiveAlertOverride{For: "30m", Severity: "critical", Threshold: "85.5"},
+		},
+		{
+			Name:          "thresholds are replaced wherever the expression refers to them",
+			Override:      &AlertOverride{Threshold: threshold(10)},
+			Expr:          "(cro_postgres_free_storage_average < ((cro_postgres_current_allocated_storage / 100) * $threshold)) and on (instanceID) (predict_linear(cro_postgres_free_storage_average[1h], 3600) < $threshold)",
+			Threshold:     "20",
+			WantExpr:      "(cro_postgres_free_storage_average < ((cro_postgres_current_allocated_storage / 100) * 10)) and on (instanceID) (predict_linear(cro_postgres_free_storage_average[1h], 3600) < 10)",
+			WantFor:       "5m",
+			WantThreshold: "10",
+			WantEffective: &EffectiveAlertOverride{For: "5m", Severity: "warning", Threshold: "10"},
+		},
+		{
+			Name:          "the declared threshold is reported when it is not overridden",
+			Override:      &AlertOverride{Severity: "info"},
+			Expr:          "cro_redis_memory_usage_percentage_average > $threshold",
+			Threshold:     "90",
+			WantExpr:      "cro_redis_memory_usage_percentage_average > 90",
+			WantFor:       "5m",
+			WantThreshold: "90",
+			WantEffective: &EffectiveAlertOverride{For: "5m", Severity: "info", Threshold: "90"},
+		},
+		{
+			Name:          "invalid overrides are reported without being applied",
+			Override:      &AlertOverride{invalid: "invalid override: invalid severity page"},
+			Expr:          "cro_redis_memory_usage_percentage_average > $threshold",
+			Threshold:     "90",
+			WantExpr:      "cro_redis_memory_usage_percentage_average > 90",
+			WantFor:       "5m",
+			WantThreshold: "90",
+			WantEffective: &EffectiveAlertOverride{For: "5m", Severity: "warning", Threshold: "90", Error: "invalid override: invalid severity page"},
+		},
+		{
+			Name:          "alerts without an override use their declared threshold",
+			Expr:          "cro_redis_memory_usage_percentage_average > $threshold",
+			Threshold:     "90",
+			WantExpr:      "cro_redis_memory_usage_percentage_average > 90",
+			WantFor:       "5m",
+			WantThreshold: "90",
+		},
+		{
+			Name:          "numbers of alerts that don't declare a threshold are left unchanged",
+			Override:      &AlertOverride{Threshold: threshold(1), For: "1m"},
+			Expr:          "sum(kube_pod_status_ready{namespace='test'}) < 3 and absent(up{job='test'}) * 100 > 90",
+			WantExpr:      "sum(kube_pod_status_ready{namespace='test'}) < 3 and absent(up{job='test'}) * 100 > 90",
+			WantFor:       "1m",
+			WantEffective: &EffectiveAlertOverride{For: "1m", Severity: "warning", Error: "the alert does not declare a threshold"},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			annotations := map[string]string{"message": "test"}
+			if scenario.Threshold != "" {
+				annotations[ThresholdAnnotation] = scenario.Threshold
+			}
+			labels := map[string]string{severityLabel: "warning", "product": "rhoam"}
+			rules := []monv1.Rule{{Alert: "TestAlert", Expr: intstr.FromString(scenario.Expr), For: DurationPtr("5m"), Labels: labels, Annotations: annotations}}
+			overrides := AlertOverrides{}
+			if scenario.Override != nil {
+				overrides["TestAlert"] = *scenario.Override
+			}
+
+			result, effective := overrides.applyOverrides(rules)
+			rule := result.([]monv1.Rule)[0]
+			if rule.Expr.StrVal != scenario.WantExpr {
+				t.Errorf("expected expression %q, got %q", scenario.WantExpr, rule.Expr.StrVal)
+			}
+			if string(*rule.For) != scenario.WantFor {
+				t.Errorf("expected for %q, got %q", scenario.WantFor, *rule.For)
+			}
+			if rule.Annotations[ThresholdAnnotation] != scenario.WantThreshold || rule.Annotations["message"] != "test" {
+				t.Errorf("unexpected annotations %v", rule.Annotations)
+			}
+			if got, ok := effective["TestAlert"]; ok != (scenario.WantEffective != nil) || (ok && got != *scenario.WantEffective) {
+				t.Errorf("expected effective values %+v, got %+v", scenario.WantEffective, effective)
+			}
+			if scenario.WantEffective != nil && rule.Labels[severityLabel] != scenario.WantEffective.Severity {
+				t.Errorf("unexpected labels %v", rule.Labels)
+			}
+			if labels[severityLabel] != "warning" || annotations[ThresholdAnnotation] != scenario.Threshold || rules[0].Expr.StrVal != scenario.Expr {
+				t.Error("expected the rules of the alert to be left unchanged")
+			}
+		})
+	}
+}
+
+func TestReconcileAlertsOverrides(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	installation := &integreatlyv1alpha1.RHMI{
+		ObjectMeta: v1.ObjectMeta{Name: "rhoam", Namespace: "testing-namespaces-test"},
+	}
+	serverClient := utils.NewTestClient(scheme, installation, alertOverridesConfigMap(`{"HighUsage": {"threshold": 75, "for": "10m", "severity": "critical"}}`))
+
+	alertReconciler := &AlertReconcilerImpl{
+		ProductName:  "Test",
+		Installation: installation,
+		Log:          getLogger(),
+		Alerts: []AlertConfiguration{
+			{
+				AlertName: "test-alert",
+				GroupName: "test-group",
+				Namespace: "testing-namespaces-test",
+				Rules: []monv1.Rule{
+					{
+						Alert:       "HighUsage",
+						Expr:        intstr.FromString("usage > " + ThresholdPlaceholder),
+						For:         DurationPtr("5m"),
+						Labels:      map[string]string{"severity": "warning"},
+						Annotations: map[string]string{ThresholdAnnotation: "90"},
+					},
+					rules[0],
+				},
+			},
+		},
+	}
+	if _, err := alertReconciler.ReconcileAlerts(context.TODO(), serverClient); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	rule := &monv1.PrometheusRule{}
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: "test-alert", Namespace: "testing-namespaces-test"}, rule); err != nil {
+		t.Fatal(err)
+	}
+	overridden := rule.Spec.Groups[0].Rules[0]
+	if overridden.Expr.String() != "usage > 75" || overridden.Annotations[ThresholdAnnotation] != "75" || *overridden.For != "10m" || overridden.Labels["severity"] != "critical" {
+		t.Errorf("expected the override to be applied, got %+v", overridden)
+	}
+	if rule.Spec.Groups[0].Rules[1].Expr != rules[0].Expr {
+		t.Errorf("expected alerts without an override to be left unchanged, got %+v", rule.Spec.Groups[0].Rules[1])
+	}
+
+	effective := map[string]EffectiveAlertOverride{}
+	if err := json.Unmarshal([]byte(rule.Annotations[AlertOverridesAnnotation]), &effective); err != nil {
+		t.Fatalf("expected the effective values to be reported, got %v: %v", rule.Annotations, err)
+	}
+	if effective["HighUsage"] != (EffectiveAlertOverride{For: "10m", Severity: "critical", Threshold: "75"}) {
+		t.Errorf("unexpected effective values %+v", effective)
+	}
+}
+
+func TestReconcileAlertsInvalidOverrides(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	installation := &integreatlyv1alpha1.RHMI{
+		ObjectMeta: v1.ObjectMeta{Name: "rhoam", Namespace: "testing-namespaces-test"},
+	}
+	reconcile := func(overrides string) *monv1.PrometheusRule {
+		serverClient := utils.NewTestClient(scheme, installation, alertOverridesConfigMap(overrides))
+		alertReconciler := &AlertReconcilerImpl{
+			ProductName:  "Test",
+			Installation: installation,
+			Log:          getLogger(),
+			Alerts:       alerts,
+		}
+		phase, err := alertReconciler.ReconcileAlerts(context.TODO(), serverClient)
+		if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+			t.Fatalf("expected invalid overrides %s not to block the alerts, got phase %s and error %v", overrides, phase, err)
+		}
+		rule := &monv1.PrometheusRule{}
+		if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: "test-alert", Namespace: "testing-namespaces-test"}, rule); err != nil {
+			t.Fatal(err)
+		}
+		if severity := rule.Spec.Groups[0].Rules[0].Labels[severityLabel]; severity != "test" {
+			t.Errorf("expected the default severity with invalid overrides %s, got %s", overrides, severity)
+		}
+		return rule
+	}
+
+	reconcile(`not json`)
+
+	// the skipped override of an alert is reported on its PrometheusRule
+	rule := reconcile(`{"TestRule": {"severity": "page"}}`)
+	effective := map[string]EffectiveAlertOverride{}
+	if err := json.Unmarshal([]byte(rule.Annotations[AlertOverridesAnnotation]), &effective); err != nil {
+		t.Fatalf("expected the skipped override to be reported, got %v: %v", rule.Annotations, err)
+	}
+	if !strings.HasPrefix(effective["TestRule"].Error, "invalid override") {
+		t.Errorf("expected the skipped override to be reported, got %+v", effective)
+	}
+}
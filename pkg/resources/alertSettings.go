@@ -0,0 +1,88 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type alertSettingsKey struct{}
+
+// alertSettings holds the runbook catalogue and the alert overrides of an installation once they
+// are read during a reconcile
+type alertSettings struct {
+	mu        sync.Mutex
+	namespace string
+	runbooks  *RunbookCatalogue
+	overrides AlertOverrides
+}
+
+// WithAlertSettings returns a context in which the alert reconcilers share the runbook catalogue and
+// the alert overrides, so that their ConfigMaps are read once rather than by every alert reconciler.
+// It is set up for each reconcile of the installation, the next reconcile picks up changes to them
+func WithAlertSettings(ctx context.Context) context.Context {
+	return context.WithValue(ctx, alertSettingsKey{}, &alertSettings{})
+}
+
+// getAlertSettings returns the runbook catalogue and the alert overrides of the installation, read
+// from the context when an earlier alert reconciler of the same reconcile read them already
+func getAlertSettings(ctx context.Context, client k8sclient.Client, namespace string, log l.Logger) (*RunbookCatalogue, AlertOverrides, error) {
+	settings, ok := ctx.Value(alertSettingsKey{}).(*alertSettings)
+	if !ok {
+		return readAlertSettings(ctx, client, namespace, log)
+	}
+
+	settings.mu.Lock()
+	defer settings.mu.Unlock()
+	if settings.runbooks != nil && settings.namespace == namespace {
+		return settings.runbooks, settings.overrides, nil
+	}
+	runbooks, overrides, err := readAlertSettings(ctx, client, namespace, log)
+	if err != nil {
+		return nil, nil, err
+	}
+	settings.namespace, settings.runbooks, settings.overrides = namespace, runbooks, overrides
+	return runbooks, overrides, nil
+}
+
+// readAlertSettings reads the runbook catalogue and the alert overrides. A malformed runbook catalogue or
+// overrides ConfigMap must not block the alerts, it is reported and the alerts keep the runbooks and values
+// they are defined with
+func readAlertSettings(ctx context.Context, client k8sclient.Client, namespace string, log l.Logger) (*RunbookCatalogue, AlertOverrides, error) {
+	runbooksConfigMap, err := getAlertSettingsConfigMap(ctx, client, RunbookConfigMapName, namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	runbooks, err := NewRunbookCatalogue(runbooksConfigMap)
+	if err != nil {
+		log.Warningf("Invalid runbook catalogue, using the default runbooks", l.Fields{"ns": namespace, "configMap": RunbookConfigMapName, "error": err})
+		runbooks, _ = NewRunbookCatalogue(nil)
+	}
+	overridesConfigMap, err := getAlertSettingsConfigMap(ctx, client, AlertOverridesConfigMapName, namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	overrides, err := NewAlertOverrides(overridesConfigMap)
+	if err != nil {
+		log.Warningf("Invalid alert overrides, using the default alert values", l.Fields{"ns": namespace, "configMap": AlertOverridesConfigMapName, "error": err})
+		overrides = AlertOverrides{}
+	}
+	return runbooks, overrides, nil
+}
+
+// getAlertSettingsConfigMap returns the ConfigMap of the installation namespace, or nil when it doesn't exist
+func getAlertSettingsConfigMap(ctx context.Context, client k8sclient.Client, name, namespace string) (*corev1.ConfigMap, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := client.Get(ctx, k8sclient.ObjectKey{Name: name, Namespace: namespace}, configMap); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get %s config map: %w", name, err)
+	}
+	return configMap, nil
+}
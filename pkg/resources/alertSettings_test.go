@@ -0,0 +1,78 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/integr8ly/integreatly-operator/pkg/client"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestGetAlertSettings(t *testing.T) {
+	scenarios := []struct {
+		Name     string
+		Ctx      func() context.Context
+		Failures int
+		WantGets int
+	}{
+		{
+			Name:     "the ConfigMaps are read by every alert reconciler without a shared context",
+			Ctx:      context.TODO,
+			WantGets: 6,
+		},
+		{
+			Name:     "the ConfigMaps are read once per reconcile",
+			Ctx:      func() context.Context { return WithAlertSettings(context.TODO()) },
+			WantGets: 2,
+		},
+		{
+			Name:     "failed reads are not kept",
+			Ctx:      func() context.Context { return WithAlertSettings(context.TODO()) },
+			Failures: 1,
+			WantGets: 3,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			gets := 0
+			serverClient := &client.SigsClientInterfaceMock{
+				GetFunc: func(ctx context.Context, key types.NamespacedName, obj k8sclient.Object, opts ...k8sclient.GetOption) error {
+					gets++
+					if gets <= scenario.Failures {
+						return fmt.Errorf("generic error")
+					}
+					if _, ok := obj.(*corev1.ConfigMap); !ok {
+						return fmt.Errorf("unexpected object %T", obj)
+					}
+					return errors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, key.Name)
+				},
+			}
+
+			ctx := scenario.Ctx()
+			for i := 0; i < 3; i++ {
+				runbooks, overrides, err := getAlertSettings(ctx, serverClient, "testing-namespaces-test", getLogger())
+				if i < scenario.Failures {
+					if err == nil {
+						t.Fatal("expected an error")
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				if runbooks == nil || overrides == nil {
+					t.Fatalf("expected the default settings, got %v and %v", runbooks, overrides)
+				}
+			}
+			if gets != scenario.WantGets {
+				t.Errorf("expected %d reads, got %d", scenario.WantGets, gets)
+			}
+		})
+	}
+}
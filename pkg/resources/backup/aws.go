@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
+	"strings"
 	"time"
 
 	"github.com/integr8ly/cloud-resource-operator/api/integreatly/v1alpha1"
 	crotypes "github.com/integr8ly/cloud-resource-operator/api/integreatly/v1alpha1/types"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -22,6 +24,9 @@ type AWSBackupExecutor struct {
 	SnapshotNamespace string          // Namespace where the snapshot CR is created
 	ResourceName      string          // AWS Resource name
 	SnapshotType      AWSSnapshotType // Type of snapshot CR to create
+	Purpose           string          // Why the snapshot is taken, part of the snapshot CR name
+
+	snapshot *Snapshot // Snapshot completed by the last backup
 }
 
 func NewAWSBackupExecutor(snapshotNamespace, resourceName string, snapshotType AWSSnapshotType) BackupExecutor {
@@ -29,55 +34,202 @@ func NewAWSBackupExecutor(snapshotNamespace, resourceName string, snapshotType A
 		SnapshotNamespace: snapshotNamespace,
 		ResourceName:      resourceName,
 		SnapshotType:      snapshotType,
+		Purpose:           preUpgradePurpose,
 	}
 }
 
-// AWSSnapshotType represents the type of snapshot to create
-type AWSSnapshotType string
+// NewAWSExportExecutor creates a snapshot to export the data of the resource before it is deleted by
+// an uninstall
+func NewAWSExportExecutor(snapshotNamespace, resourceName string, snapshotType AWSSnapshotType) BackupExecutor {
+	return &AWSBackupExecutor{
+		SnapshotNamespace: snapshotNamespace,
+		ResourceName:      resourceName,
+		SnapshotType:      snapshotType,
+		Purpose:           uninstallExportPurpose,
+	}
+}
 
-const (
-	// PostgresSnapshotType creates PostgresSnapshot CRs
-	PostgresSnapshotType AWSSnapshotType = "PostgresSnapshot"
-	// RedisSnapshotType creates RedisSnapshot CRs
-	RedisSnapshotType AWSSnapshotType = "RedisSnapshot"
-)
+// Snapshots returns the snapshot completed by the last backup
+func (e *AWSBackupExecutor) Snapshots() []Snapshot {
+	if e.snapshot == nil {
+		return nil
+	}
+	return []Snapshot{*e.snapshot}
+}
 
-// PerformBackup creates a snapshot CR and waits until the status of the CR
-// is `complete`
-func (e *AWSBackupExecutor) PerformBackup(client k8sclient.Client, timeout time.Duration) error {
-	log.Infof("Performing backup on AWS", l.Fields{"snapshotType": e.SnapshotType, "resourceName": e.ResourceName})
+// StaleResources returns the resource of the executor when no snapshot taken for the same purpose completed
+// since the given time
+func (e *AWSBackupExecutor) StaleResources(ctx context.Context, client k8sclient.Client, since time.Time) ([]string, error) {
+	snapshots, err := e.listSnapshots(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	for _, snapshot := range snapshots {
+		if snapshot.status.Phase == crotypes.PhaseComplete && !snapshot.created.Time.Before(since) {
+			return nil, nil
+		}
+	}
+	return []string{e.ResourceName}, nil
+}
 
-	snapshotName := fmt.Sprintf("%s-preupgrade-snapshot-%s", e.ResourceName, time.Now().Format("2006-01-02-150405"))
+// StartBackup creates a snapshot CR without waiting for it, and returns whether the snapshot completed.
+// A snapshot taken for the same purpose since the given time that is in progress or complete is reused,
+// failed snapshots are deleted and a new one is created on the next call
+func (e *AWSBackupExecutor) StartBackup(ctx context.Context, client k8sclient.Client, since time.Time) (bool, error) {
+	snapshots, err := e.listSnapshots(ctx, client)
+	if err != nil {
+		return false, err
+	}
 
-	// Initialize the snapshot CR based on the snapshot type
-	var snapshotCR runtime.Object
+	var latest *snapshotState
+	failed := false
+	for i, snapshot := range snapshots {
+		if snapshot.status.Phase == crotypes.PhaseFailed {
+			log.Warningf("Deleting failed snapshot", l.Fields{"snapshot": snapshot.name, "message": snapshot.status.Message})
+			snapshotCR, err := e.newSnapshotCR(snapshot.name)
+			if err != nil {
+				return false, err
+			}
+			if err := client.Delete(ctx, snapshotCR); err != nil && !k8serr.IsNotFound(err) {
+				return false, fmt.Errorf("failed to delete failed %s %s: %w", e.SnapshotType, snapshot.name, err)
+			}
+			failed = true
+			continue
+		}
+		if snapshot.created.Time.Before(since) {
+			continue
+		}
+		if latest == nil || latest.created.Before(&snapshot.created) {
+			latest = &snapshots[i]
+		}
+	}
+
+	if latest == nil && failed {
+		return false, nil
+	}
+	if latest == nil {
+		snapshotName := e.snapshotNamePrefix() + time.Now().Format("2006-01-02-150405")
+		log.Infof("Starting backup on AWS", l.Fields{"snapshotType": e.SnapshotType, "resourceName": e.ResourceName, "snapshot": snapshotName})
+		snapshotCR, err := e.newSnapshotCR(snapshotName)
+		if err != nil {
+			return false, err
+		}
+		if err := client.Create(ctx, snapshotCR); err != nil {
+			return false, fmt.Errorf("Error creating %s for backup of resource %s: %v", e.SnapshotType, e.ResourceName, err)
+		}
+		return false, nil
+	}
+	if latest.status.Phase != crotypes.PhaseComplete {
+		return false, nil
+	}
+
+	e.snapshot = &Snapshot{
+		Type:         e.SnapshotType,
+		Namespace:    e.SnapshotNamespace,
+		Name:         latest.name,
+		ResourceName: e.ResourceName,
+		SnapshotID:   latest.status.SnapshotID,
+	}
+	return true, nil
+}
+
+// snapshotState is a snapshot CR taken by the executor
+type snapshotState struct {
+	name    string
+	created v1.Time
+	status  crotypes.ResourceTypeSnapshotStatus
+}
+
+// listSnapshots returns the snapshot CRs taken for the resource and purpose of the executor
+func (e *AWSBackupExecutor) listSnapshots(ctx context.Context, client k8sclient.Client) ([]snapshotState, error) {
+	var snapshots []snapshotState
+	switch e.SnapshotType {
+	case PostgresSnapshotType:
+		list := &v1alpha1.PostgresSnapshotList{}
+		if err := client.List(ctx, list, k8sclient.InNamespace(e.SnapshotNamespace)); err != nil {
+			return nil, fmt.Errorf("failed to list %s for %s: %w", e.SnapshotType, e.ResourceName, err)
+		}
+		for _, snapshot := range list.Items {
+			if snapshot.Spec.ResourceName == e.ResourceName && strings.HasPrefix(snapshot.Name, e.snapshotNamePrefix()) {
+				snapshots = append(snapshots, snapshotState{snapshot.Name, snapshot.CreationTimestamp, snapshot.Status})
+			}
+		}
+	case RedisSnapshotType:
+		list := &v1alpha1.RedisSnapshotList{}
+		if err := client.List(ctx, list, k8sclient.InNamespace(e.SnapshotNamespace)); err != nil {
+			return nil, fmt.Errorf("failed to list %s for %s: %w", e.SnapshotType, e.ResourceName, err)
+		}
+		for _, snapshot := range list.Items {
+			if snapshot.Spec.ResourceName == e.ResourceName && strings.HasPrefix(snapshot.Name, e.snapshotNamePrefix()) {
+				snapshots = append(snapshots, snapshotState{snapshot.Name, snapshot.CreationTimestamp, snapshot.Status})
+			}
+		}
+	default:
+		return nil, fmt.Errorf("Unsupported value for AWSShapshotType. Expected %s or %s, got %s",
+			PostgresSnapshotType, RedisSnapshotType, e.SnapshotType)
+	}
+	return snapshots, nil
+}
+
+// newSnapshotCR returns the snapshot CR of the executor type with the given name
+func (e *AWSBackupExecutor) newSnapshotCR(name string) (k8sclient.Object, error) {
 	commonObjectMeta := v1.ObjectMeta{
 		Namespace: e.SnapshotNamespace,
-		Name:      snapshotName,
+		Name:      name,
 	}
 
 	switch e.SnapshotType {
 	case PostgresSnapshotType:
-		snapshotCR = &v1alpha1.PostgresSnapshot{
+		return &v1alpha1.PostgresSnapshot{
 			ObjectMeta: commonObjectMeta,
 			Spec: v1alpha1.PostgresSnapshotSpec{
 				ResourceName: e.ResourceName,
 			},
-		}
+		}, nil
 	case RedisSnapshotType:
-		snapshotCR = &v1alpha1.RedisSnapshot{
+		return &v1alpha1.RedisSnapshot{
 			ObjectMeta: commonObjectMeta,
 			Spec: v1alpha1.RedisSnapshotSpec{
 				ResourceName: e.ResourceName,
 			},
-		}
+		}, nil
 	default:
-		return fmt.Errorf("Unsupported value for AWSShapshotType. Expected %s or %s, got %s",
+		return nil, fmt.Errorf("Unsupported value for AWSShapshotType. Expected %s or %s, got %s",
 			PostgresSnapshotType, RedisSnapshotType, e.SnapshotType)
 	}
+}
+
+func (e *AWSBackupExecutor) snapshotNamePrefix() string {
+	return fmt.Sprintf("%s-%s-snapshot-", e.ResourceName, e.Purpose)
+}
+
+// AWSSnapshotType represents the type of snapshot to create
+type AWSSnapshotType string
+
+const (
+	// PostgresSnapshotType creates PostgresSnapshot CRs
+	PostgresSnapshotType AWSSnapshotType = "PostgresSnapshot"
+	// RedisSnapshotType creates RedisSnapshot CRs
+	RedisSnapshotType AWSSnapshotType = "RedisSnapshot"
+
+	preUpgradePurpose      = "preupgrade"
+	uninstallExportPurpose = "uninstall-export"
+)
+
+// PerformBackup creates a snapshot CR and waits until the status of the CR
+// is `complete`
+func (e *AWSBackupExecutor) PerformBackup(client k8sclient.Client, timeout time.Duration) error {
+	log.Infof("Performing backup on AWS", l.Fields{"snapshotType": e.SnapshotType, "resourceName": e.ResourceName})
+
+	snapshotName := e.snapshotNamePrefix() + time.Now().Format("2006-01-02-150405")
+
+	snapshotCR, err := e.newSnapshotCR(snapshotName)
+	if err != nil {
+		return err
+	}
 
 	// Create the CR
-	err := client.Create(context.TODO(), snapshotCR.(k8sclient.Object))
+	err = client.Create(context.TODO(), snapshotCR)
 	if err != nil {
 		return fmt.Errorf("Error creating %s for backup of resource %s: %v",
 			e.SnapshotType, e.ResourceName, err)
@@ -112,15 +264,18 @@ func (e *AWSBackupExecutor) PerformBackup(client k8sclient.Client, timeout time.
 		// Get the phase
 		var phase crotypes.StatusPhase
 		var message crotypes.StatusMessage
+		var snapshotID string
 		switch e.SnapshotType {
 		case PostgresSnapshotType:
 			typedSnapshotCR := queryCR.(*v1alpha1.PostgresSnapshot)
 			phase = typedSnapshotCR.Status.Phase
 			message = typedSnapshotCR.Status.Message
+			snapshotID = typedSnapshotCR.Status.SnapshotID
 		case RedisSnapshotType:
 			typedSnapshotCR := queryCR.(*v1alpha1.RedisSnapshot)
 			phase = typedSnapshotCR.Status.Phase
 			message = typedSnapshotCR.Status.Message
+			snapshotID = typedSnapshotCR.Status.SnapshotID
 		}
 
 		// If the snapshot failed, return an error with the message
@@ -130,6 +285,13 @@ func (e *AWSBackupExecutor) PerformBackup(client k8sclient.Client, timeout time.
 
 		// If it's complete, break the loop
 		if phase == crotypes.PhaseComplete {
+			e.snapshot = &Snapshot{
+				Type:         e.SnapshotType,
+				Namespace:    e.SnapshotNamespace,
+				Name:         snapshotName,
+				ResourceName: e.ResourceName,
+				SnapshotID:   snapshotID,
+			}
 			break
 		}
 	}
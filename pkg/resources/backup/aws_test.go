@@ -13,6 +13,7 @@ import (
 	"github.com/integr8ly/cloud-resource-operator/api/integreatly/v1alpha1"
 	"github.com/integr8ly/cloud-resource-operator/api/integreatly/v1alpha1/types"
 	moqClient "github.com/integr8ly/integreatly-operator/pkg/client"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -69,6 +70,11 @@ func TestAWSSnapshotPostgres(t *testing.T) {
 	if err != nil {
 		t.Errorf("Unexpected error performing postgres backup: %v", err)
 	}
+
+	snapshots := GetSnapshots(executor)
+	if len(snapshots) != 1 || !strings.HasPrefix(snapshots[0].Name, fmt.Sprintf("%s-preupgrade-snapshot", resourceName)) {
+		t.Errorf("Expected the completed snapshot to be reported, got %+v", snapshots)
+	}
 }
 
 // TestAWSSnapshotRedis tests that the AWSBackupExecutor succesfully creates
@@ -259,3 +265,113 @@ func buildTestRedisSnapshotCR() *v1alpha1.RedisSnapshot {
 		},
 	}
 }
+
+// TestAWSStartBackup tests that an export snapshot is started without waiting for it, that a snapshot in
+// progress is reused and that a failed snapshot is deleted before a new one is started
+func TestAWSStartBackup(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	namespace := "testing-namespaces-operator"
+	resourceName := "test-rhoam-postgres"
+	client := moqClient.NewSigsClientMoqWithSchemeWithStatusSubresource(scheme, buildTestPostgresSnapshotCr())
+	executor := NewAWSExportExecutor(namespace, resourceName, PostgresSnapshotType)
+
+	listSnapshots := func() []v1alpha1.PostgresSnapshot {
+		list := &v1alpha1.PostgresSnapshotList{}
+		if err := client.List(context.TODO(), list, k8sclient.InNamespace(namespace)); err != nil {
+			t.Fatal(err)
+		}
+		return list.Items
+	}
+	setPhase := func(phase types.StatusPhase) {
+		snapshot := listSnapshots()[0]
+		snapshot.Status.Phase = phase
+		snapshot.Status.SnapshotID = "rds:" + snapshot.Name
+		if err := client.Status().Update(context.TODO(), &snapshot); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		done, err := StartBackup(context.TODO(), client, executor, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if done {
+			t.Fatal("expected the snapshot to be in progress")
+		}
+		if snapshots := listSnapshots(); len(snapshots) != 1 {
+			t.Fatalf("expected the snapshot in progress to be reused, got %d snapshots", len(snapshots))
+		}
+	}
+
+	setPhase(types.PhaseFailed)
+	if _, err := StartBackup(context.TODO(), client, executor, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if snapshots := listSnapshots(); len(snapshots) != 0 {
+		t.Fatalf("expected the failed snapshot to be deleted, got %d snapshots", len(snapshots))
+	}
+	if _, err := StartBackup(context.TODO(), client, executor, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+
+	setPhase(types.PhaseComplete)
+	done, err := StartBackup(context.TODO(), client, executor, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshots := listSnapshots()
+	if !done || len(snapshots) != 1 {
+		t.Fatalf("expected the snapshot to complete, got done %v and %d snapshots", done, len(snapshots))
+	}
+	reported := GetSnapshots(executor)
+	if len(reported) != 1 || reported[0].Name != snapshots[0].Name || reported[0].SnapshotID != "rds:"+snapshots[0].Name {
+		t.Errorf("expected the completed snapshot to be reported, got %+v", reported)
+	}
+}
+
+// TestAWSStartBackupSince tests that a complete snapshot taken before the given time is not reused
+func TestAWSStartBackupSince(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	namespace := "testing-namespaces-operator"
+	resourceName := "test-rhoam-postgres"
+	executor := NewAWSExportExecutor(namespace, resourceName, PostgresSnapshotType)
+	oldSnapshot := &v1alpha1.PostgresSnapshot{
+		ObjectMeta: controllerruntime.ObjectMeta{
+			Name:              executor.(*AWSBackupExecutor).snapshotNamePrefix() + "old",
+			Namespace:         namespace,
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-48 * time.Hour)),
+		},
+		Spec: v1alpha1.PostgresSnapshotSpec{
+			ResourceName: resourceName,
+		},
+		Status: types.ResourceTypeSnapshotStatus{
+			Phase:      types.PhaseComplete,
+			SnapshotID: "rds:old",
+		},
+	}
+	client := moqClient.NewSigsClientMoqWithSchemeWithStatusSubresource(scheme, oldSnapshot)
+
+	done, err := StartBackup(context.TODO(), client, executor, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done {
+		t.Fatal("expected the snapshot taken before the given time not to be reused")
+	}
+	list := &v1alpha1.PostgresSnapshotList{}
+	if err := client.List(context.TODO(), list, k8sclient.InNamespace(namespace)); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 2 {
+		t.Errorf("expected a new snapshot to be started, got %d snapshots", len(list.Items))
+	}
+}
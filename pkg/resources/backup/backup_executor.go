@@ -1,6 +1,7 @@
 package backup
 
 import (
+	"context"
 	"fmt"
 	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
 	"golang.org/x/sync/errgroup"
@@ -15,6 +16,61 @@ type BackupExecutor interface {
 	PerformBackup(client k8sclient.Client, timeout time.Duration) error
 }
 
+// Snapshot is a snapshot CR created by a backup, SnapshotID is the ID of the snapshot in the cloud
+// provider
+type Snapshot struct {
+	Type         AWSSnapshotType `json:"type"`
+	Namespace    string          `json:"namespace"`
+	Name         string          `json:"name"`
+	ResourceName string          `json:"resourceName"`
+	SnapshotID   string          `json:"snapshotID,omitempty"`
+}
+
+// SnapshotReporter is implemented by the executors that report the snapshots taken by their last
+// backup
+type SnapshotReporter interface {
+	Snapshots() []Snapshot
+}
+
+// GetSnapshots returns the snapshots taken by the last backup of the executor, if it reports them
+func GetSnapshots(executor BackupExecutor) []Snapshot {
+	if reporter, ok := executor.(SnapshotReporter); ok {
+		return reporter.Snapshots()
+	}
+	return nil
+}
+
+// FreshnessChecker is implemented by the executors that can tell which of their resources have no backup
+// completed since a given time
+type FreshnessChecker interface {
+	StaleResources(ctx context.Context, client k8sclient.Client, since time.Time) ([]string, error)
+}
+
+// GetStaleResources returns the resources of the executor without a backup completed since the given time
+func GetStaleResources(ctx context.Context, client k8sclient.Client, executor BackupExecutor, since time.Time) ([]string, error) {
+	checker, ok := executor.(FreshnessChecker)
+	if !ok {
+		return nil, fmt.Errorf("backup executor %T can't report the age of its backups", executor)
+	}
+	return checker.StaleResources(ctx, client, since)
+}
+
+// BackupStarter is implemented by the executors that can start a backup without waiting for it, so that
+// a reconcile checks its progress on each run instead of blocking until it completes
+type BackupStarter interface {
+	StartBackup(ctx context.Context, client k8sclient.Client, since time.Time) (bool, error)
+}
+
+// StartBackup starts the backup of the executor, or checks the backup started since the given time by an
+// earlier call, and returns whether it completed
+func StartBackup(ctx context.Context, client k8sclient.Client, executor BackupExecutor, since time.Time) (bool, error) {
+	starter, ok := executor.(BackupStarter)
+	if !ok {
+		return false, fmt.Errorf("backup executor %T can't start a backup without waiting for it", executor)
+	}
+	return starter.StartBackup(ctx, client, since)
+}
+
 // NoopBackupExecutor does nothing. For components that do not require backups
 type NoopBackupExecutor struct{}
 
@@ -28,6 +84,16 @@ func (e *NoopBackupExecutor) PerformBackup(client k8sclient.Client, timeout time
 	return nil
 }
 
+// StaleResources returns no resources, there is nothing to back up
+func (e *NoopBackupExecutor) StaleResources(_ context.Context, _ k8sclient.Client, _ time.Time) ([]string, error) {
+	return nil, nil
+}
+
+// StartBackup has nothing to start, the backup is always complete
+func (e *NoopBackupExecutor) StartBackup(_ context.Context, _ k8sclient.Client, _ time.Time) (bool, error) {
+	return true, nil
+}
+
 // ConcurrentBackupExecutor performs backups by delegating the operation into
 // a list of `BackupExecutor` that are performed concurrently in separate
 // goroutines
@@ -41,6 +107,41 @@ func NewConcurrentBackupExecutor(executors ...BackupExecutor) BackupExecutor {
 	}
 }
 
+// Snapshots returns the snapshots taken by the last backup of each executor
+func (e *ConcurrentBackupExecutor) Snapshots() []Snapshot {
+	var snapshots []Snapshot
+	for _, executor := range e.Executors {
+		snapshots = append(snapshots, GetSnapshots(executor)...)
+	}
+	return snapshots
+}
+
+// StaleResources returns the stale resources of each executor
+func (e *ConcurrentBackupExecutor) StaleResources(ctx context.Context, client k8sclient.Client, since time.Time) ([]string, error) {
+	var stale []string
+	for _, executor := range e.Executors {
+		resources, err := GetStaleResources(ctx, client, executor, since)
+		if err != nil {
+			return nil, err
+		}
+		stale = append(stale, resources...)
+	}
+	return stale, nil
+}
+
+// StartBackup starts the backup of each executor and returns whether they all completed
+func (e *ConcurrentBackupExecutor) StartBackup(ctx context.Context, client k8sclient.Client, since time.Time) (bool, error) {
+	complete := true
+	for _, executor := range e.Executors {
+		done, err := StartBackup(ctx, client, executor, since)
+		if err != nil {
+			return false, err
+		}
+		complete = complete && done
+	}
+	return complete, nil
+}
+
 func (e *ConcurrentBackupExecutor) PerformBackup(client k8sclient.Client, timeout time.Duration) error {
 	log.Infof("Concurrently performing backups", l.Fields{"backups": len(e.Executors)})
 
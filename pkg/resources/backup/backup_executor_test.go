@@ -1,11 +1,15 @@
 package backup
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
 
+	"github.com/integr8ly/cloud-resource-operator/api/integreatly/v1alpha1"
+	"github.com/integr8ly/cloud-resource-operator/api/integreatly/v1alpha1/types"
 	"github.com/integr8ly/integreatly-operator/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -42,6 +46,79 @@ func TestConcurrentBackup(t *testing.T) {
 	}
 }
 
+func TestGetStaleResources(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	namespace := "redhat-rhoam-operator"
+	postgresSnapshot := func(name string, age time.Duration, phase types.StatusPhase) *v1alpha1.PostgresSnapshot {
+		return &v1alpha1.PostgresSnapshot{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, CreationTimestamp: metav1.NewTime(time.Now().Add(-age))},
+			Spec:       v1alpha1.PostgresSnapshotSpec{ResourceName: "threescale-postgres-rhoam"},
+			Status:     types.ResourceTypeSnapshotStatus{Phase: phase},
+		}
+	}
+	redisSnapshot := func(name string, age time.Duration) *v1alpha1.RedisSnapshot {
+		return &v1alpha1.RedisSnapshot{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, CreationTimestamp: metav1.NewTime(time.Now().Add(-age))},
+			Spec:       v1alpha1.RedisSnapshotSpec{ResourceName: "threescale-redis-rhoam"},
+			Status:     types.ResourceTypeSnapshotStatus{Phase: types.PhaseComplete},
+		}
+	}
+	executor := NewConcurrentBackupExecutor(
+		NewAWSBackupExecutor(namespace, "threescale-postgres-rhoam", PostgresSnapshotType),
+		NewAWSBackupExecutor(namespace, "threescale-redis-rhoam", RedisSnapshotType),
+		NewNoopBackupExecutor(),
+	)
+
+	scenarios := []struct {
+		Name      string
+		Objects   []runtime.Object
+		Executor  BackupExecutor
+		WantStale []string
+		WantErr   bool
+	}{
+		{
+			Name: "recent pre-upgrade backups are fresh",
+			Objects: []runtime.Object{
+				postgresSnapshot("threescale-postgres-rhoam-preupgrade-snapshot-1", time.Hour, types.PhaseComplete),
+				redisSnapshot("threescale-redis-rhoam-preupgrade-snapshot-1", time.Hour),
+			},
+			Executor: executor,
+		},
+		{
+			Name: "old, failed and other purpose snapshots are stale",
+			Objects: []runtime.Object{
+				postgresSnapshot("threescale-postgres-rhoam-preupgrade-snapshot-1", time.Hour, types.PhaseFailed),
+				postgresSnapshot("threescale-postgres-rhoam-uninstall-export-snapshot-1", time.Hour, types.PhaseComplete),
+				redisSnapshot("threescale-redis-rhoam-preupgrade-snapshot-1", 48*time.Hour),
+			},
+			Executor:  executor,
+			WantStale: []string{"threescale-postgres-rhoam", "threescale-redis-rhoam"},
+		},
+		{
+			Name:     "executors that can't report their backups are an error",
+			Executor: mockBackupExecutor{},
+			WantErr:  true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			client := utils.NewTestClient(scheme, scenario.Objects...)
+			stale, err := GetStaleResources(context.TODO(), client, scenario.Executor, time.Now().Add(-24*time.Hour))
+			if (err != nil) != scenario.WantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fmt.Sprint(stale) != fmt.Sprint(scenario.WantStale) {
+				t.Fatalf("expected stale resources %v, got %v", scenario.WantStale, stale)
+			}
+		})
+	}
+}
+
 type mockBackupExecutor struct {
 	SleepTime time.Duration
 }
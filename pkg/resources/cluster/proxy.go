@@ -0,0 +1,72 @@
+package cluster
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"golang.org/x/net/http/httpproxy"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	clusterProxyName = "cluster"
+	// the trusted CA of the cluster proxy is a config map in this namespace, user-ca-bundle by default
+	trustedCANamespace = "openshift-config"
+	trustedCAKey       = "ca-bundle.crt"
+)
+
+// GetProxyTransport returns a transport for the requests the operator sends out of the cluster. It goes through
+// the cluster-wide proxy and trusts its CA bundle on top of the system roots. Without a cluster proxy, the proxy
+// environment variables of the operator are used
+func GetProxyTransport(ctx context.Context, serverClient k8sclient.Client) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	proxy := &configv1.Proxy{}
+	if err := serverClient.Get(ctx, types.NamespacedName{Name: clusterProxyName}, proxy); err != nil {
+		if k8serr.IsNotFound(err) {
+			return transport, nil
+		}
+		return nil, fmt.Errorf("failed to retrieve cluster proxy: %w", err)
+	}
+
+	// the status has the proxy in effect, with the cluster networks added to noProxy
+	if proxy.Status.HTTPProxy != "" || proxy.Status.HTTPSProxy != "" {
+		proxyFunc := (&httpproxy.Config{
+			HTTPProxy:  proxy.Status.HTTPProxy,
+			HTTPSProxy: proxy.Status.HTTPSProxy,
+			NoProxy:    proxy.Status.NoProxy,
+		}).ProxyFunc()
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}
+	}
+
+	if proxy.Spec.TrustedCA.Name == "" {
+		return transport, nil
+	}
+	trustedCA := &corev1.ConfigMap{}
+	if err := serverClient.Get(ctx, types.NamespacedName{Name: proxy.Spec.TrustedCA.Name, Namespace: trustedCANamespace}, trustedCA); err != nil {
+		return nil, fmt.Errorf("failed to retrieve trusted CA %s of the cluster proxy: %w", proxy.Spec.TrustedCA.Name, err)
+	}
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		roots = x509.NewCertPool()
+	}
+	if !roots.AppendCertsFromPEM([]byte(trustedCA.Data[trustedCAKey])) {
+		return nil, fmt.Errorf("trusted CA %s of the cluster proxy has no certificates in %s", proxy.Spec.TrustedCA.Name, trustedCAKey)
+	}
+	transport.TLSClientConfig = transport.TLSClientConfig.Clone()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	transport.TLSClientConfig.RootCAs = roots
+	return transport, nil
+}
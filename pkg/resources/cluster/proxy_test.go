@@ -0,0 +1,77 @@
+package cluster
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/integr8ly/integreatly-operator/utils"
+	configv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetProxyTransport(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mirror := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mirror.Close()
+
+	proxy := &configv1.Proxy{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec:       configv1.ProxySpec{TrustedCA: configv1.ConfigMapNameReference{Name: "user-ca-bundle"}},
+		Status: configv1.ProxyStatus{
+			HTTPSProxy: "http://proxy.example.com:3128",
+			NoProxy:    "127.0.0.1,.cluster.local",
+		},
+	}
+	trustedCA := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "user-ca-bundle", Namespace: "openshift-config"},
+		Data: map[string]string{
+			"ca-bundle.crt": string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: mirror.Certificate().Raw})),
+		},
+	}
+
+	t.Run("uses the cluster proxy and trusts its CA", func(t *testing.T) {
+		transport, err := GetProxyTransport(context.TODO(), utils.NewTestClient(scheme, proxy, trustedCA))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req, _ := http.NewRequest(http.MethodGet, "https://quay.io/v2/", nil)
+		proxyURL, err := transport.Proxy(req)
+		if err != nil || proxyURL == nil || proxyURL.Host != "proxy.example.com:3128" {
+			t.Errorf("expected the requests to go through the cluster proxy, got %v, %v", proxyURL, err)
+		}
+
+		// the mirror is in noProxy and its certificate is signed by the trusted CA
+		resp, err := (&http.Client{Transport: transport}).Get(mirror.URL)
+		if err != nil {
+			t.Fatalf("expected the mirror to be trusted, got %v", err)
+		}
+		resp.Body.Close()
+	})
+
+	t.Run("fails when the trusted CA is missing", func(t *testing.T) {
+		if _, err := GetProxyTransport(context.TODO(), utils.NewTestClient(scheme, proxy)); err == nil {
+			t.Error("expected an error")
+		}
+	})
+
+	t.Run("uses the default transport without a cluster proxy", func(t *testing.T) {
+		transport, err := GetProxyTransport(context.TODO(), utils.NewTestClient(scheme))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if transport.TLSClientConfig != nil && transport.TLSClientConfig.RootCAs != nil {
+			t.Error("expected the system roots to be used")
+		}
+	})
+}
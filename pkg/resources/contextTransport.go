@@ -0,0 +1,35 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+)
+
+// ContextTransport sends the requests of HTTP clients that don't take a context with the context of the
+// reconcile, so that their requests are cancelled with the reconcile
+type ContextTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+var _ http.RoundTripper = &ContextTransport{}
+
+// NewContextTransport creates a ContextTransport sending the requests with base, or the default transport when
+// base is nil
+func NewContextTransport(ctx context.Context, base http.RoundTripper) *ContextTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &ContextTransport{ctx: ctx, base: base}
+}
+
+func (t *ContextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.ctx.Err() != nil {
+		return nil, context.Cause(t.ctx)
+	}
+	// the requests created with a context keep it
+	if req.Context().Done() == nil {
+		req = req.WithContext(t.ctx)
+	}
+	return t.base.RoundTrip(req)
+}
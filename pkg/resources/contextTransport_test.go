@@ -0,0 +1,46 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestContextTransport(t *testing.T) {
+	cancelled := errors.New("installation is being deleted")
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			select {
+			case <-r.Context().Done():
+			case <-release:
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	client := &http.Client{Transport: NewContextTransport(ctx, server.Client().Transport)}
+
+	res, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = res.Body.Close()
+
+	// a request in progress is cancelled with the context
+	time.AfterFunc(100*time.Millisecond, func() { cancel(cancelled) })
+	if _, err := client.Get(server.URL + "/slow"); err == nil {
+		t.Fatal("expected the request in progress to be cancelled")
+	}
+
+	// the requests after the cancellation fail with its cause
+	if _, err := client.Get(server.URL); !errors.Is(err, cancelled) {
+		t.Fatalf("expected error %v, got %v", cancelled, err)
+	}
+}
@@ -0,0 +1,107 @@
+package images
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// OverridesConfigMapName is the ConfigMap in the installation namespace that maps the logical names of
+	// the operand images, as listed in products/additional-images.yaml, to the images that replace them,
+	// e.g. the same images in a mirrored registry
+	OverridesConfigMapName = "operand-image-overrides"
+
+	// ResolvedImageAnnotationPrefix is followed by the logical name of an image in the annotation that
+	// reports the image a reconciled deployment runs
+	ResolvedImageAnnotationPrefix = "images.integreatly.org/"
+
+	EnvoyProxy        = "3scale-openshift-service-mesh"
+	Limitador         = "marin3r-limitador"
+	Grafana           = "grafana"
+	GrafanaOAuthProxy = "grafana-ose-oauth-proxy"
+)
+
+// defaults are the images the operands are pinned to, they must match products/additional-images.yaml
+var defaults = map[string]string{
+	EnvoyProxy:        "registry.redhat.io/openshift-service-mesh/proxyv2-rhel9:2.6.12-1767872298",
+	Limitador:         "registry.redhat.io/rhcl-1/limitador-rhel9@sha256:aff28d76f9cfeefafd6b652e055bb25e1a2bf250e062e3e9b5e54db873f9eeb7",
+	Grafana:           "registry.redhat.io/rhel9/grafana:9.7-1767725073",
+	GrafanaOAuthProxy: "registry.redhat.io/openshift4/ose-oauth-proxy-rhel9:v4.16.0-202512111314.p2.g565f7ed.assembly.stream.el9",
+}
+
+// Default returns the image the operand is pinned to
+func Default(name string) string {
+	return defaults[name]
+}
+
+// Overrides maps the logical names of operand images to the images that replace them
+type Overrides map[string]string
+
+// GetOverrides reads the operand image overrides from the installation namespace. There are no overrides
+// when the ConfigMap doesn't exist
+func GetOverrides(ctx context.Context, client k8sclient.Client, namespace string) (Overrides, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := client.Get(ctx, k8sclient.ObjectKey{Name: OverridesConfigMapName, Namespace: namespace}, configMap); err != nil {
+		if k8serr.IsNotFound(err) {
+			return Overrides{}, nil
+		}
+		return nil, fmt.Errorf("failed to get %s config map: %w", OverridesConfigMapName, err)
+	}
+
+	overrides := Overrides{}
+	for name, image := range configMap.Data {
+		if _, ok := defaults[name]; !ok {
+			return nil, fmt.Errorf("unknown image %s in %s config map, must be one of %s", name, OverridesConfigMapName, strings.Join(Names(), ", "))
+		}
+		image = strings.TrimSpace(image)
+		if image == "" {
+			return nil, fmt.Errorf("empty override for image %s in %s config map", name, OverridesConfigMapName)
+		}
+		overrides[name] = image
+	}
+	return overrides, nil
+}
+
+// Resolve returns the image the operand runs, the override if there is one or the default image
+func (o Overrides) Resolve(name string) string {
+	if image, ok := o[name]; ok {
+		return image
+	}
+	return defaults[name]
+}
+
+// Resolve returns the image the operand runs with the overrides of the installation namespace
+func Resolve(ctx context.Context, client k8sclient.Client, namespace, name string) (string, error) {
+	overrides, err := GetOverrides(ctx, client, namespace)
+	if err != nil {
+		return "", err
+	}
+	return overrides.Resolve(name), nil
+}
+
+// SetResolvedImage reports on the object the image it runs for the logical image name
+func SetResolvedImage(object metav1.Object, name, image string) {
+	annotations := object.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[ResolvedImageAnnotationPrefix+name] = image
+	object.SetAnnotations(annotations)
+}
+
+// Names returns the logical names of the operand images
+func Names() []string {
+	names := make([]string, 0, len(defaults))
+	for name := range defaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,112 @@
+package images
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/integr8ly/integreatly-operator/utils"
+	"gopkg.in/yaml.v2"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDefaultsMatchAdditionalImages(t *testing.T) {
+	data, err := os.ReadFile("../../../products/additional-images.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	additionalImages := map[string][]struct {
+		Name string `yaml:"name"`
+		URL  string `yaml:"url"`
+	}{}
+	if err := yaml.Unmarshal(data, &additionalImages); err != nil {
+		t.Fatal(err)
+	}
+
+	listed := map[string]string{}
+	for _, components := range additionalImages {
+		for _, component := range components {
+			listed[component.Name] = component.URL
+		}
+	}
+	if len(listed) != len(defaults) {
+		t.Errorf("expected %d images in additional-images.yaml, got %d", len(defaults), len(listed))
+	}
+	for name, image := range defaults {
+		if listed[name] != image {
+			t.Errorf("expected %s to be pinned to %s in additional-images.yaml, got %q", name, image, listed[name])
+		}
+	}
+}
+
+func TestGetOverrides(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		Name      string
+		Data      map[string]string
+		NoConfig  bool
+		WantErr   bool
+		WantImage string
+	}{
+		{
+			Name:      "default image without the config map",
+			NoConfig:  true,
+			WantImage: defaults[Limitador],
+		},
+		{
+			Name:      "default image without an override",
+			Data:      map[string]string{Grafana: "mirror.example.com/rhel9/grafana:9.7"},
+			WantImage: defaults[Limitador],
+		},
+		{
+			Name:      "overridden image",
+			Data:      map[string]string{Limitador: " mirror.example.com/rhcl-1/limitador-rhel9:1.0 "},
+			WantImage: "mirror.example.com/rhcl-1/limitador-rhel9:1.0",
+		},
+		{
+			Name:    "unknown image",
+			Data:    map[string]string{"limitador": "mirror.example.com/rhcl-1/limitador-rhel9:1.0"},
+			WantErr: true,
+		},
+		{
+			Name:    "empty override",
+			Data:    map[string]string{Limitador: ""},
+			WantErr: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			client := utils.NewTestClient(scheme)
+			if !scenario.NoConfig {
+				client = utils.NewTestClient(scheme, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: OverridesConfigMapName, Namespace: "redhat-rhoam-operator"},
+					Data:       scenario.Data,
+				})
+			}
+
+			image, err := Resolve(context.TODO(), client, "redhat-rhoam-operator", Limitador)
+			if (err != nil) != scenario.WantErr {
+				t.Fatalf("expected error %v, got %v", scenario.WantErr, err)
+			}
+			if image != scenario.WantImage {
+				t.Errorf("expected image %q, got %q", scenario.WantImage, image)
+			}
+		})
+	}
+}
+
+func TestSetResolvedImage(t *testing.T) {
+	deployment := &appsv1.Deployment{}
+	SetResolvedImage(deployment, Grafana, "mirror.example.com/rhel9/grafana:9.7")
+
+	if got := deployment.Annotations[ResolvedImageAnnotationPrefix+Grafana]; got != "mirror.example.com/rhel9/grafana:9.7" {
+		t.Errorf("expected the resolved image to be reported, got %q", got)
+	}
+}
@@ -0,0 +1,210 @@
+package images
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	dockerHubRegistry = "docker.io"
+	dockerHubHost     = "registry-1.docker.io"
+)
+
+// the manifest types a registry may serve for the images of the operands
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+var challengeParam = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// CheckPullable checks that the registry of the image serves its manifest with the credentials of the
+// pull secret, which can be nil to pull anonymously
+func CheckPullable(ctx context.Context, httpClient *http.Client, image string, pullSecret *corev1.Secret) error {
+	registry, repository, reference, err := parseReference(image)
+	if err != nil {
+		return err
+	}
+	username, password, err := registryCredentials(pullSecret, registry)
+	if err != nil {
+		return err
+	}
+
+	host := registry
+	if registry == dockerHubRegistry {
+		host = dockerHubHost
+	}
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", host, repository, reference)
+
+	status, challenge, err := headManifest(ctx, httpClient, manifestURL, "")
+	if err != nil {
+		return err
+	}
+	if status == http.StatusUnauthorized {
+		authorization := ""
+		if strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+			token, err := fetchToken(ctx, httpClient, challenge, repository, username, password)
+			if err != nil {
+				return err
+			}
+			authorization = "Bearer " + token
+		} else if username != "" {
+			authorization = "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+		}
+		if authorization != "" {
+			if status, _, err = headManifest(ctx, httpClient, manifestURL, authorization); err != nil {
+				return err
+			}
+		}
+	}
+
+	switch status {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("not authorized to pull %s with the pull secret", image)
+	case http.StatusNotFound:
+		return fmt.Errorf("image %s not found", image)
+	default:
+		return fmt.Errorf("unexpected status %d checking image %s", status, image)
+	}
+}
+
+func headManifest(ctx context.Context, httpClient *http.Client, manifestURL, authorization string) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to reach the registry: %w", err)
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, resp.Header.Get("WWW-Authenticate"), nil
+}
+
+// fetchToken gets a pull token from the token server of the bearer challenge
+func fetchToken(ctx context.Context, httpClient *http.Client, challenge, repository, username, password string) (string, error) {
+	params := map[string]string{}
+	for _, match := range challengeParam.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Host == "" {
+		return "", fmt.Errorf("invalid registry authentication challenge: %s", challenge)
+	}
+	query := realm.Query()
+	if service, ok := params["service"]; ok {
+		query.Set("service", service)
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", repository)
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if username != "" {
+		req.SetBasicAuth(username, password)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach the registry token server: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry token server returned status %d", resp.StatusCode)
+	}
+
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to decode the registry token: %w", err)
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	return token.AccessToken, nil
+}
+
+// parseReference splits an image into its registry, repository and tag or digest
+func parseReference(image string) (string, string, string, error) {
+	name, reference := image, "latest"
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, reference = name[:i], name[i+1:]
+	} else if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, reference = name[:i], name[i+1:]
+	}
+
+	registry, repository := dockerHubRegistry, name
+	if parts := strings.SplitN(name, "/", 2); len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		registry, repository = parts[0], parts[1]
+	} else if !strings.Contains(name, "/") {
+		repository = "library/" + name
+	}
+	if repository == "" || reference == "" {
+		return "", "", "", fmt.Errorf("invalid image %s", image)
+	}
+	return registry, repository, reference, nil
+}
+
+// registryCredentials returns the credentials of the pull secret for the registry, they are empty when the
+// pull secret has none
+func registryCredentials(pullSecret *corev1.Secret, registry string) (string, string, error) {
+	if pullSecret == nil {
+		return "", "", nil
+	}
+	data, ok := pullSecret.Data[corev1.DockerConfigJsonKey]
+	if !ok {
+		return "", "", nil
+	}
+
+	config := struct {
+		Auths map[string]struct {
+			Auth     string `json:"auth"`
+			Username string `json:"username"`
+			Password string `json:"password"`
+		} `json:"auths"`
+	}{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return "", "", fmt.Errorf("failed to parse pull secret %s: %w", pullSecret.Name, err)
+	}
+
+	for key, auth := range config.Auths {
+		host := strings.TrimPrefix(strings.TrimPrefix(key, "https://"), "http://")
+		host = strings.SplitN(host, "/", 2)[0]
+		if host != registry && !(registry == dockerHubRegistry && host == "index.docker.io") {
+			continue
+		}
+		if auth.Auth == "" {
+			return auth.Username, auth.Password, nil
+		}
+		decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid auth for %s in pull secret %s: %w", key, pullSecret.Name, err)
+		}
+		username, password, _ := strings.Cut(string(decoded), ":")
+		return username, password, nil
+	}
+	return "", "", nil
+}
@@ -0,0 +1,111 @@
+package images
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestParseReference(t *testing.T) {
+	scenarios := []struct {
+		Image          string
+		WantRegistry   string
+		WantRepository string
+		WantReference  string
+	}{
+		{Image: "registry.redhat.io/rhel9/grafana:9.7", WantRegistry: "registry.redhat.io", WantRepository: "rhel9/grafana", WantReference: "9.7"},
+		{Image: "mirror.example.com:5000/rhcl-1/limitador-rhel9@sha256:aff2", WantRegistry: "mirror.example.com:5000", WantRepository: "rhcl-1/limitador-rhel9", WantReference: "sha256:aff2"},
+		{Image: "grafana/grafana", WantRegistry: "docker.io", WantRepository: "grafana/grafana", WantReference: "latest"},
+		{Image: "busybox:1.36", WantRegistry: "docker.io", WantRepository: "library/busybox", WantReference: "1.36"},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Image, func(t *testing.T) {
+			registry, repository, reference, err := parseReference(scenario.Image)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if registry != scenario.WantRegistry || repository != scenario.WantRepository || reference != scenario.WantReference {
+				t.Errorf("expected %s %s %s, got %s %s %s", scenario.WantRegistry, scenario.WantRepository, scenario.WantReference, registry, repository, reference)
+			}
+		})
+	}
+}
+
+func TestCheckPullable(t *testing.T) {
+	var registry *httptest.Server
+	registry = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			username, password, ok := r.BasicAuth()
+			if !ok || username != "mirror" || password != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `{"token": "pull-token"}`)
+		case r.Header.Get("Authorization") != "Bearer pull-token":
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="mirror"`, registry.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/v2/rhel9/grafana/manifests/9.7":
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer registry.Close()
+	host := strings.TrimPrefix(registry.URL, "https://")
+
+	pullSecret := func(auth string) *corev1.Secret {
+		return &corev1.Secret{
+			Type: corev1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{
+				corev1.DockerConfigJsonKey: []byte(fmt.Sprintf(`{"auths": {"%s": {"auth": "%s"}}}`, host, base64.StdEncoding.EncodeToString([]byte(auth)))),
+			},
+		}
+	}
+
+	scenarios := []struct {
+		Name       string
+		Image      string
+		PullSecret *corev1.Secret
+		WantErr    bool
+	}{
+		{
+			Name:       "pullable with the pull secret",
+			Image:      host + "/rhel9/grafana:9.7",
+			PullSecret: pullSecret("mirror:secret"),
+		},
+		{
+			Name:       "wrong credentials",
+			Image:      host + "/rhel9/grafana:9.7",
+			PullSecret: pullSecret("mirror:wrong"),
+			WantErr:    true,
+		},
+		{
+			Name:    "no pull secret",
+			Image:   host + "/rhel9/grafana:9.7",
+			WantErr: true,
+		},
+		{
+			Name:       "missing image",
+			Image:      host + "/rhel9/grafana:missing",
+			PullSecret: pullSecret("mirror:secret"),
+			WantErr:    true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			err := CheckPullable(context.TODO(), registry.Client(), scenario.Image, scenario.PullSecret)
+			if (err != nil) != scenario.WantErr {
+				t.Errorf("expected error %v, got %v", scenario.WantErr, err)
+			}
+		})
+	}
+}
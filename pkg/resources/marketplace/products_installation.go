@@ -11,6 +11,10 @@ import (
 // how to install them
 type ProductsInstallation struct {
 	Products ProductsDeclaration `yaml:"products"`
+	// The products whose declaration was overridden at runtime
+	Overridden map[string]bool `yaml:"-"`
+	// InvalidOverrides maps the products whose override was skipped to the reason, they keep their declaration
+	InvalidOverrides map[string]string `yaml:"-"`
 }
 
 type ProductsDeclaration map[string]ProductDeclaration
@@ -0,0 +1,130 @@
+package marketplace
+
+import (
+	"context"
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ProductDeclarationOverridesConfigMapName is the ConfigMap in the operator namespace that overrides the
+// product declarations at runtime. Each key is a product name and each value the YAML of the fields of
+// the declaration to override, e.g. to install a product from a test index image
+const ProductDeclarationOverridesConfigMapName = "product-declaration-overrides"
+
+// ConfigMapProductsInstallationLoader applies the overrides of the product declaration overrides
+// ConfigMap to the ProductsInstallation of another loader. Invalid overrides are skipped and reported in
+// the InvalidOverrides of the ProductsInstallation, so they don't stop the products from being reconciled
+type ConfigMapProductsInstallationLoader struct {
+	loader    ProductsInstallationLoader
+	client    k8sclient.Reader
+	namespace string
+}
+
+var _ ProductsInstallationLoader = &ConfigMapProductsInstallationLoader{}
+
+// NewConfigMapProductsInstallationLoader creates a ProductsInstallationLoader instance that overrides the
+// product declarations of loader with the ConfigMap in namespace
+func NewConfigMapProductsInstallationLoader(loader ProductsInstallationLoader, client k8sclient.Reader, namespace string) ProductsInstallationLoader {
+	return &ConfigMapProductsInstallationLoader{
+		loader:    loader,
+		client:    client,
+		namespace: namespace,
+	}
+}
+
+func (l *ConfigMapProductsInstallationLoader) GetProductsInstallation() (*ProductsInstallation, error) {
+	productsInstallation, err := l.loader.GetProductsInstallation()
+	if err != nil {
+		return nil, err
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := l.client.Get(context.TODO(), k8sclient.ObjectKey{Name: ProductDeclarationOverridesConfigMapName, Namespace: l.namespace}, configMap); err != nil {
+		if k8serr.IsNotFound(err) {
+			return productsInstallation, nil
+		}
+		return nil, fmt.Errorf("failed to get %s config map: %w", ProductDeclarationOverridesConfigMapName, err)
+	}
+
+	for product, data := range configMap.Data {
+		declaration, err := overrideProductDeclaration(productsInstallation, product, data)
+		if err != nil {
+			if productsInstallation.InvalidOverrides == nil {
+				productsInstallation.InvalidOverrides = map[string]string{}
+			}
+			productsInstallation.InvalidOverrides[product] = err.Error()
+			continue
+		}
+
+		productsInstallation.Products[product] = declaration
+		if productsInstallation.Overridden == nil {
+			productsInstallation.Overridden = map[string]bool{}
+		}
+		productsInstallation.Overridden[product] = true
+	}
+
+	return productsInstallation, nil
+}
+
+func overrideProductDeclaration(productsInstallation *ProductsInstallation, product, data string) (ProductDeclaration, error) {
+	declaration, ok := productsInstallation.Products[product]
+	if !ok {
+		return declaration, fmt.Errorf("unknown product %s in %s config map", product, ProductDeclarationOverridesConfigMapName)
+	}
+	override := ProductDeclaration{}
+	if err := yaml.UnmarshalStrict([]byte(data), &override); err != nil {
+		return declaration, fmt.Errorf("invalid override for product %s in %s config map: %w", product, ProductDeclarationOverridesConfigMapName, err)
+	}
+	declaration, err := declaration.Override(override)
+	if err != nil {
+		return declaration, fmt.Errorf("invalid override for product %s in %s config map: %w", product, ProductDeclarationOverridesConfigMapName, err)
+	}
+	return declaration, nil
+}
+
+// Override returns the declaration with the fields set in override replacing the fields of p. The
+// result must be a valid declaration
+func (p ProductDeclaration) Override(override ProductDeclaration) (ProductDeclaration, error) {
+	if override.InstallFrom != "" {
+		p.InstallFrom = override.InstallFrom
+	}
+	if override.ManifestsDir != nil {
+		manifestsDir := *override.ManifestsDir
+		p.ManifestsDir = &manifestsDir
+	}
+	if override.Index != "" {
+		p.Index = override.Index
+	}
+	if override.Channel != "" {
+		p.Channel = override.Channel
+	}
+	if override.Package != "" {
+		p.Package = override.Package
+	}
+
+	return p, p.Validate()
+}
+
+// Validate checks that p declares a supported installation source with the fields it requires
+func (p *ProductDeclaration) Validate() error {
+	switch p.InstallFrom {
+	case ProductInstallationSourceIndex:
+		if p.Index == "" {
+			return fmt.Errorf("installation source %s requires index", p.InstallFrom)
+		}
+	case ProductInstallationSourceLocal:
+		if p.ManifestsDir == nil || *p.ManifestsDir == "" {
+			return fmt.Errorf("installation source %s requires manifestsDir", p.InstallFrom)
+		}
+	case ProductInstallationSourceImplicit:
+	default:
+		return fmt.Errorf("installation source %s not supported, must be one of %s, %s or %s", p.InstallFrom,
+			ProductInstallationSourceLocal, ProductInstallationSourceIndex, ProductInstallationSourceImplicit)
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package marketplace
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/integr8ly/integreatly-operator/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type staticProductsInstallationLoader struct {
+	products ProductsDeclaration
+}
+
+func (l *staticProductsInstallationLoader) GetProductsInstallation() (*ProductsInstallation, error) {
+	products := ProductsDeclaration{}
+	for name, declaration := range l.products {
+		products[name] = declaration
+	}
+	return &ProductsInstallation{Products: products}, nil
+}
+
+func TestConfigMapProductsInstallationLoader(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manifestsDir := "integreatly-3scale"
+	loader := &staticProductsInstallationLoader{products: ProductsDeclaration{
+		"3scale": {InstallFrom: ProductInstallationSourceLocal, ManifestsDir: &manifestsDir, Channel: "rhmi"},
+		"rhsso":  {InstallFrom: ProductInstallationSourceImplicit},
+	}}
+	overrides := func(data map[string]string) runtime.Object {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: ProductDeclarationOverridesConfigMapName, Namespace: "redhat-rhoam-operator"},
+			Data:       data,
+		}
+	}
+
+	scenarios := []struct {
+		Name            string
+		Objects         []runtime.Object
+		WantDeclaration ProductDeclaration
+		WantOverridden  bool
+		WantInvalid     string
+	}{
+		{
+			Name:            "declaration from the loader without the config map",
+			WantDeclaration: ProductDeclaration{InstallFrom: ProductInstallationSourceLocal, ManifestsDir: &manifestsDir, Channel: "rhmi"},
+		},
+		{
+			Name: "overridden index image and channel",
+			Objects: []runtime.Object{overrides(map[string]string{
+				"3scale": "installFrom: index\nindex: quay.io/integreatly/3scale-index:test\nchannel: threescale-2.16\n",
+			})},
+			WantDeclaration: ProductDeclaration{InstallFrom: ProductInstallationSourceIndex, ManifestsDir: &manifestsDir, Index: "quay.io/integreatly/3scale-index:test", Channel: "threescale-2.16"},
+			WantOverridden:  true,
+		},
+		{
+			Name:            "index installation without an index image is skipped",
+			Objects:         []runtime.Object{overrides(map[string]string{"3scale": "installFrom: index\n"})},
+			WantDeclaration: ProductDeclaration{InstallFrom: ProductInstallationSourceLocal, ManifestsDir: &manifestsDir, Channel: "rhmi"},
+			WantInvalid:     "3scale",
+		},
+		{
+			Name:            "unsupported installation source is skipped",
+			Objects:         []runtime.Object{overrides(map[string]string{"3scale": "installFrom: bundle\n"})},
+			WantDeclaration: ProductDeclaration{InstallFrom: ProductInstallationSourceLocal, ManifestsDir: &manifestsDir, Channel: "rhmi"},
+			WantInvalid:     "3scale",
+		},
+		{
+			Name:            "unknown field is skipped",
+			Objects:         []runtime.Object{overrides(map[string]string{"3scale": "indexImage: quay.io/integreatly/3scale-index:test\n"})},
+			WantDeclaration: ProductDeclaration{InstallFrom: ProductInstallationSourceLocal, ManifestsDir: &manifestsDir, Channel: "rhmi"},
+			WantInvalid:     "3scale",
+		},
+		{
+			Name: "unknown product is skipped without affecting the other overrides",
+			Objects: []runtime.Object{overrides(map[string]string{
+				"threescale": "channel: stable\n",
+				"3scale":     "channel: threescale-2.16\n",
+			})},
+			WantDeclaration: ProductDeclaration{InstallFrom: ProductInstallationSourceLocal, ManifestsDir: &manifestsDir, Channel: "threescale-2.16"},
+			WantOverridden:  true,
+			WantInvalid:     "threescale",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			client := utils.NewTestClient(scheme, scenario.Objects...)
+
+			productsInstallation, err := NewConfigMapProductsInstallationLoader(loader, client, "redhat-rhoam-operator").GetProductsInstallation()
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if declaration := productsInstallation.Products["3scale"]; !reflect.DeepEqual(declaration, scenario.WantDeclaration) {
+				t.Errorf("expected declaration %+v, got %+v", scenario.WantDeclaration, declaration)
+			}
+			if productsInstallation.Overridden["3scale"] != scenario.WantOverridden {
+				t.Errorf("expected overridden to be %v", scenario.WantOverridden)
+			}
+			if productsInstallation.Overridden["rhsso"] {
+				t.Error("expected rhsso not to be overridden")
+			}
+			wantInvalid := 0
+			if scenario.WantInvalid != "" {
+				wantInvalid = 1
+			}
+			if len(productsInstallation.InvalidOverrides) != wantInvalid || (wantInvalid == 1 && productsInstallation.InvalidOverrides[scenario.WantInvalid] == "") {
+				t.Errorf("expected the invalid override of %q to be reported, got %v", scenario.WantInvalid, productsInstallation.InvalidOverrides)
+			}
+		})
+	}
+}
@@ -31,10 +31,8 @@ import (
 
 	cro1types "github.com/integr8ly/cloud-resource-operator/api/integreatly/v1alpha1/types"
 
-	"github.com/pkg/errors"
 	monv1 "github.com/rhobs/obo-prometheus-operator/pkg/apis/monitoring/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"github.com/integr8ly/integreatly-operator/api/v1alpha1"
 
@@ -58,116 +56,154 @@ var (
 	caser = cases.Title(language.English)
 )
 
+// ReconcilePostgresAlerts reconciles the alerts of the postgres instance. Completed is returned once the instance
+// is provisioned and all of its alerts are reconciled
 func ReconcilePostgresAlerts(ctx context.Context, client k8sclient.Client, inst *v1alpha1.RHMI, cr *crov1.Postgres, log l.Logger) (v1alpha1.StatusPhase, error) {
-	// create prometheus failed rule
-	_, err := createPostgresResourceStatusPhaseFailedAlert(ctx, client, inst, cr, log, inst.Spec.Type)
-	if err != nil {
-		return v1alpha1.PhaseFailed, fmt.Errorf("failed to create postgres failure alert for %s: %w", cr.Name, err)
+	if !v1alpha1.HasObservability(v1alpha1.InstallationType(inst.Spec.Type)) {
+		return awaitResourcePhase(cr.Status.Phase), nil
 	}
 
-	// create the prometheus deletion rule
-	if _, err = createPostgresResourceDeletionStatusFailedAlert(ctx, client, inst, cr, log, inst.Spec.Type); err != nil {
-		return v1alpha1.PhaseFailed, fmt.Errorf("failed to create postgres deletion prometheus alert for %s: %w", cr.Name, err)
+	alertReconciler := cloudResourceAlertsReconciler(inst, cr.Labels["productName"], PostgresAlerts(inst, cr, log), log)
+	if phase, err := alertReconciler.ReconcileAlerts(ctx, client); err != nil || phase != v1alpha1.PhaseCompleted {
+		return phase, fmt.Errorf("failed to reconcile postgres alerts for %s: %w", cr.Name, err)
 	}
 
-	if cr.Status.Phase != cro1types.PhaseComplete {
-		return v1alpha1.PhaseAwaitingComponents, nil
-	}
+	return awaitResourcePhase(cr.Status.Phase), nil
+}
 
-	// create the prometheus pending rule
-	_, err = createPostgresResourceStatusPhasePendingAlert(ctx, client, inst, cr, log, inst.Spec.Type)
-	if err != nil {
-		return v1alpha1.PhaseFailed, fmt.Errorf("failed to create postgres pending alert for %s: %w", cr.Name, err)
-	}
+// PostgresAlerts returns the alerts of the postgres instance. The alerts on a provisioned instance are only
+// returned once the instance is provisioned
+func PostgresAlerts(inst *v1alpha1.RHMI, cr *crov1.Postgres, log l.Logger) []AlertConfiguration {
+	alerts := &cloudResourceAlerts{}
 
-	// create the prometheus availability rule
-	if _, err = createPostgresAvailabilityAlert(ctx, client, inst, cr, log, inst.Spec.Type); err != nil {
-		return v1alpha1.PhaseFailed, fmt.Errorf("failed to create postgres prometheus alert for %s: %w", cr.Name, err)
-	}
+	// failed provisioning and deletion alerts
+	addPostgresResourceStatusPhaseFailedAlert(alerts, inst, cr, log, inst.Spec.Type)
+	addPostgresResourceDeletionStatusFailedAlert(alerts, inst, cr, log, inst.Spec.Type)
 
-	// create the prometheus connectivity rule
-	if _, err = createPostgresConnectivityAlert(ctx, client, inst, cr, log, inst.Spec.Type); err != nil {
-		return v1alpha1.PhaseFailed, fmt.Errorf("failed to create postgres connectivity prometheus alert for %s: %w", cr.Name, err)
+	if cr.Status.Phase != cro1types.PhaseComplete {
+		return alerts.configurations
 	}
 
-	// create the prometheus deletion rule
-	if _, err = createPostgresResourceDeletionStatusFailedAlert(ctx, client, inst, cr, log, inst.Spec.Type); err != nil {
-		return v1alpha1.PhaseFailed, fmt.Errorf("failed to create postgres deletion prometheus alert for %s: %w", cr.Name, err)
-	}
+	addPostgresResourceStatusPhasePendingAlert(alerts, inst, cr, log, inst.Spec.Type)
+	addPostgresAvailabilityAlert(alerts, inst, cr, log, inst.Spec.Type)
+	addPostgresConnectivityAlert(alerts, inst, cr, log, inst.Spec.Type)
+	addPostgresFreeStorageAlerts(alerts, inst, cr, log, inst.Spec.Type)
+	addPostgresFreeableMemoryAlert(alerts, inst, cr, log, inst.Spec.Type)
+	addPostgresCPUUtilizationAlerts(alerts, inst, cr, log, inst.Spec.Type)
 
-	// create the prometheus free storage alert rules
-	if err = reconcilePostgresFreeStorageAlerts(ctx, client, inst, cr, log, inst.Spec.Type); err != nil {
-		return v1alpha1.PhaseFailed, fmt.Errorf("failed to create postgres free storage prometheus alerts for %s: %w", cr.Name, err)
-	}
+	return alerts.configurations
+}
 
-	if err = reconcilePostgresFreeableMemoryAlert(ctx, client, inst, cr, log, inst.Spec.Type); err != nil {
-		return v1alpha1.PhaseFailed, fmt.Errorf("failed to create postgres freeable memory alert for %s: %w", cr.Name, err)
+// ReconcileRedisAlerts reconciles the alerts of the redis instance. Completed is returned once the instance
+// is provisioned and all of its alerts are reconciled
+func ReconcileRedisAlerts(ctx context.Context, client k8sclient.Client, inst *v1alpha1.RHMI, cr *crov1.Redis, log l.Logger) (v1alpha1.StatusPhase, error) {
+	if !v1alpha1.HasObservability(v1alpha1.InstallationType(inst.Spec.Type)) {
+		return awaitResourcePhase(cr.Status.Phase), nil
 	}
 
-	// create the prometheus high cpu alert rule
-	if err = reconcilePostgresCPUUtilizationAlerts(ctx, client, inst, cr, log, inst.Spec.Type); err != nil {
-		return v1alpha1.PhaseFailed, fmt.Errorf("failed to create postgres cpu utilization prometheus alerts for %s: %w", cr.Name, err)
+	alertReconciler := cloudResourceAlertsReconciler(inst, cr.Labels["productName"], RedisAlerts(inst, cr, log), log)
+	if phase, err := alertReconciler.ReconcileAlerts(ctx, client); err != nil || phase != v1alpha1.PhaseCompleted {
+		return phase, fmt.Errorf("failed to reconcile redis alerts for %s: %w", cr.Name, err)
 	}
 
-	return v1alpha1.PhaseCompleted, nil
+	return awaitResourcePhase(cr.Status.Phase), nil
 }
 
-func ReconcileRedisAlerts(ctx context.Context, client k8sclient.Client, inst *v1alpha1.RHMI, cr *crov1.Redis, log l.Logger) (v1alpha1.StatusPhase, error) {
-
-	// redis cr returning a failed state
-	_, err := createRedisResourceStatusPhaseFailedAlert(ctx, client, inst, cr, log)
-	if err != nil {
-		return v1alpha1.PhaseFailed, fmt.Errorf("failed to create redis failure alert %s: %w", cr.Name, err)
-	}
+// RedisAlerts returns the alerts of the redis instance. The alerts on a provisioned instance are only
+// returned once the instance is provisioned
+func RedisAlerts(inst *v1alpha1.RHMI, cr *crov1.Redis, log l.Logger) []AlertConfiguration {
+	alerts := &cloudResourceAlerts{}
 
-	// redis cr returning a failed state during deletion
-	_, err = createRedisResourceDeletionStatusFailedAlert(ctx, client, inst, cr, log)
-	if err != nil {
-		return v1alpha1.PhaseFailed, fmt.Errorf("failed to create redis deletion failure alert for %s: %w", cr.Name, err)
-	}
+	// failed provisioning and deletion alerts
+	addRedisResourceStatusPhaseFailedAlert(alerts, inst, cr, log)
+	addRedisResourceDeletionStatusFailedAlert(alerts, inst, cr, log)
 
 	if cr.Status.Phase != cro1types.PhaseComplete {
-		return v1alpha1.PhaseAwaitingComponents, nil
+		return alerts.configurations
 	}
 
-	// create prometheus pending rule
-	_, err = createRedisResourceStatusPhasePendingAlert(ctx, client, inst, cr, log)
-	if err != nil {
-		return v1alpha1.PhaseFailed, fmt.Errorf("failed to create redis pending alert %s: %w", cr.Name, err)
-	}
+	addRedisResourceStatusPhasePendingAlert(alerts, inst, cr, log)
+	addRedisAvailabilityAlert(alerts, inst, cr, log)
+	addRedisConnectivityAlert(alerts, inst, cr, log)
+	addRedisMemoryUsageAlerts(alerts, inst, cr, log)
+	addRedisCpuUsageAlerts(alerts, inst, cr, log)
+	addRedisServiceMaintenanceAlerts(alerts, inst, cr, log)
 
-	// create the prometheus availability rule
-	_, err = createRedisAvailabilityAlert(ctx, client, inst, cr, log)
-	if err != nil {
-		return v1alpha1.PhaseFailed, fmt.Errorf("failed to create redis prometheus alert for %s: %w", cr.Name, err)
+	return alerts.configurations
+}
+
+// awaitResourcePhase returns completed once the cloud resource is provisioned
+func awaitResourcePhase(phase cro1types.StatusPhase) v1alpha1.StatusPhase {
+	if phase != cro1types.PhaseComplete {
+		return v1alpha1.PhaseAwaitingComponents
 	}
-	// create backend connectivity alert
-	_, err = createRedisConnectivityAlert(ctx, client, inst, cr, log)
-	if err != nil {
-		return v1alpha1.PhaseFailed, fmt.Errorf("failed to create redis prometheus connectivity alert for %s: %w", cr.Name, err)
+	return v1alpha1.PhaseCompleted
+}
+
+func cloudResourceAlertsReconciler(inst *v1alpha1.RHMI, productName string, alerts []AlertConfiguration, log l.Logger) *AlertReconcilerImpl {
+	return &AlertReconcilerImpl{
+		ProductName:  productName,
+		Log:          log,
+		Installation: inst,
+		Alerts:       alerts,
 	}
+}
 
-	// create Redis Memory Usage High alert
-	if err = createRedisMemoryUsageAlerts(ctx, client, inst, cr, log); err != nil {
-		return v1alpha1.PhaseFailed, fmt.Errorf("failed to create redis prometheus memory usage high alerts for %s: %w", cr.Name, err)
+// PostgresAlertsReconciler returns a reconciler for the alerts of a provisioned postgres instance, without
+// reading the instance from the cluster. It is used to render the alerts of a product offline
+func PostgresAlertsReconciler(inst *v1alpha1.RHMI, name, productName string, log l.Logger) *AlertReconcilerImpl {
+	cr := &crov1.Postgres{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: inst.Namespace,
+			Labels:    map[string]string{"productName": productName},
+		},
+		Status: cro1types.ResourceTypeStatus{Phase: cro1types.PhaseComplete},
 	}
+	return cloudResourceAlertsReconciler(inst, productName, PostgresAlerts(inst, cr, log), log)
+}
 
-	// create Redis Cpu Usage High Alert
-	if err = CreateRedisCpuUsageAlerts(ctx, client, inst, cr, log); err != nil {
-		return v1alpha1.PhaseFailed, fmt.Errorf("failed to create redis prometheus cpu usage high alerts for %s: %w", cr.Name, err)
+// RedisAlertsReconciler returns a reconciler for the alerts of a provisioned redis instance, without
+// reading the instance from the cluster. It is used to render the alerts of a product offline
+func RedisAlertsReconciler(inst *v1alpha1.RHMI, name, productName string, log l.Logger) *AlertReconcilerImpl {
+	cr := &crov1.Redis{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: inst.Namespace,
+			Labels:    map[string]string{"productName": productName},
+		},
+		Status: cro1types.ResourceTypeStatus{Phase: cro1types.PhaseComplete},
 	}
+	return cloudResourceAlertsReconciler(inst, productName, RedisAlerts(inst, cr, log), log)
+}
 
-	// create Redis Service Maintenance Alert
-	if err = CreateRedisServiceMaintenanceAlerts(ctx, client, inst, cr, log); err != nil {
-		return v1alpha1.PhaseFailed, fmt.Errorf("failed to create redis prometheus service maintenance critical alerts for %s: %w", cr.Name, err)
+// InstallationAlertsReconciler returns a reconciler for the alerts on the secrets the installation depends on
+func InstallationAlertsReconciler(log l.Logger, cr *v1alpha1.RHMI, addonParametersSecretName string) *AlertReconcilerImpl {
+	alerts := &cloudResourceAlerts{}
+	addSmtpSecretExistsAlert(alerts, cr)
+	addDeadMansSnitchSecretExistsAlert(alerts, cr)
+	addAddonManagedApiServiceParametersExistsAlert(alerts, cr, addonParametersSecretName)
+
+	return &AlertReconcilerImpl{
+		ProductName:  InstallationNames[cr.Spec.Type],
+		Log:          log,
+		Installation: cr,
+		Alerts:       alerts.configurations,
 	}
+}
 
-	return v1alpha1.PhaseCompleted, nil
+// ReconcileInstallationAlerts reconciles the alerts on the secrets the installation depends on
+func ReconcileInstallationAlerts(ctx context.Context, client k8sclient.Client, log l.Logger, cr *v1alpha1.RHMI) (v1alpha1.StatusPhase, error) {
+	addonParametersSecret, err := addon.GetAddonParametersSecret(ctx, client, cr.Namespace)
+	if err != nil {
+		return v1alpha1.PhaseFailed, fmt.Errorf("failed to get addon parameters secret: %w", err)
+	}
+	return InstallationAlertsReconciler(log, cr, addonParametersSecret.Name).ReconcileAlerts(ctx, client)
 }
 
-// CreateSmtpSecretExists creates a PrometheusRule to alert if the rhoam-smtp-secret is present
+// addSmtpSecretExistsAlert adds a PrometheusRule to alert if the rhoam-smtp-secret is present
 // the ocm sendgrid service creates a secret automatically this is a check for when that service fails
-func CreateSmtpSecretExists(ctx context.Context, client k8sclient.Client, cr *v1alpha1.RHMI) (v1alpha1.StatusPhase, error) {
+func addSmtpSecretExistsAlert(alerts *cloudResourceAlerts, cr *v1alpha1.RHMI) {
 	installationName := InstallationNames[cr.Spec.Type]
 
 	alertName := "SendgridSmtpSecretExists"
@@ -182,42 +218,31 @@ func CreateSmtpSecretExists(ctx context.Context, client k8sclient.Client, cr *v1
 	}
 
 	ruleNs := config.GetOboNamespace(cr.Namespace)
-	_, err := reconcilePrometheusRule(ctx, client, ruleName, ruleNs, alertName, alertDescription, sopUrlSendGridSmtpSecretExists, alertFor10Mins, alertExp, labels)
-	if err != nil {
-		return v1alpha1.PhaseFailed, fmt.Errorf("failed to create sendgrid smtp exists rule err: %s", err)
-	}
-	return v1alpha1.PhaseCompleted, nil
+	alerts.add(ruleName, ruleNs, alertName, alertDescription, sopUrlSendGridSmtpSecretExists, alertFor10Mins, alertExp, labels)
 }
 
-// CreateAddonManagedApiServiceParametersExists creates a PrometheusRule to alert if the addon-managed-api-service-parameters is present
+// addAddonManagedApiServiceParametersExistsAlert adds a PrometheusRule to alert if the addon-managed-api-service-parameters is present
 // Hive creates a secret automatically this is a check for when that service fails or the secret has been removed
-func CreateAddonManagedApiServiceParametersExists(ctx context.Context, client k8sclient.Client, cr *v1alpha1.RHMI) (v1alpha1.StatusPhase, error) {
+func addAddonManagedApiServiceParametersExistsAlert(alerts *cloudResourceAlerts, cr *v1alpha1.RHMI, addonParametersSecretName string) {
 	installationName := InstallationNames[cr.Spec.Type]
-	addonParametersSecret, err := addon.GetAddonParametersSecret(ctx, client, cr.Namespace)
-	if err != nil {
-		return v1alpha1.PhaseFailed, fmt.Errorf("failed to create addon-managed-api-service-parameters secret exists rule err: %s", err)
-	}
+
 	alertName := "AddonManagedApiServiceParametersExists"
 	ruleName := "addon-managed-api-service-parameters-secret-exists-rule"
 	alertExp := intstr.FromString(
-		fmt.Sprintf("absent(kube_secret_info{namespace='%s',secret='%s'} == 1)", cr.Namespace, addonParametersSecret.Name),
+		fmt.Sprintf("absent(kube_secret_info{namespace='%s',secret='%s'} == 1)", cr.Namespace, addonParametersSecretName),
 	)
-	alertDescription := fmt.Sprintf("The %s secret has been removed from the %s namespace, this secret should be generated and managed by Hive", addonParametersSecret.Name, cr.Namespace)
+	alertDescription := fmt.Sprintf("The %s secret has been removed from the %s namespace, this secret should be generated and managed by Hive", addonParametersSecretName, cr.Namespace)
 	labels := map[string]string{
 		"severity": "critical",
 		"product":  installationName,
 	}
 
 	ruleNs := config.GetOboNamespace(cr.Namespace)
-	_, err = reconcilePrometheusRule(ctx, client, ruleName, ruleNs, alertName, alertDescription, sopUrlAddonManagedApiServiceParametersExists, alertFor5Mins, alertExp, labels)
-	if err != nil {
-		return v1alpha1.PhaseFailed, fmt.Errorf("failed to create addon-managed-api-service-parameters secret exists rule err: %s", err)
-	}
-	return v1alpha1.PhaseCompleted, nil
+	alerts.add(ruleName, ruleNs, alertName, alertDescription, sopUrlAddonManagedApiServiceParametersExists, alertFor5Mins, alertExp, labels)
 }
 
-// CreateDeadMansSnitchSecretExists creates a PrometheusRule to alert if the redhat-rhoam-deadmanssnitch is present
-func CreateDeadMansSnitchSecretExists(ctx context.Context, client k8sclient.Client, cr *v1alpha1.RHMI) (v1alpha1.StatusPhase, error) {
+// addDeadMansSnitchSecretExistsAlert adds a PrometheusRule to alert if the redhat-rhoam-deadmanssnitch is present
+func addDeadMansSnitchSecretExistsAlert(alerts *cloudResourceAlerts, cr *v1alpha1.RHMI) {
 	installationName := InstallationNames[cr.Spec.Type]
 
 	alertName := "DeadMansSnitchSecretExists"
@@ -238,21 +263,17 @@ func CreateDeadMansSnitchSecretExists(ctx context.Context, client k8sclient.Clie
 	}
 
 	ruleNs := config.GetOboNamespace(cr.Namespace)
-	_, err := reconcilePrometheusRule(ctx, client, ruleName, ruleNs, alertName, alertDescription, SopUrlDeadMansSnitchSecretExists, alertForXMins, alertExp, labels)
-	if err != nil {
-		return v1alpha1.PhaseFailed, fmt.Errorf("failed to create deadmanssnitch exists rule err: %s", err)
-	}
-	return v1alpha1.PhaseCompleted, nil
+	alerts.add(ruleName, ruleNs, alertName, alertDescription, SopUrlDeadMansSnitchSecretExists, alertForXMins, alertExp, labels)
 }
 
-// createPostgresAvailabilityAlert creates a PrometheusRule alert to watch for the availability
+// addPostgresAvailabilityAlert adds a PrometheusRule alert to watch for the availability
 // of a Postgres instance
-func createPostgresAvailabilityAlert(ctx context.Context, client k8sclient.Client, inst *v1alpha1.RHMI, cr *crov1.Postgres, log l.Logger, installType string) (*monv1.PrometheusRule, error) {
+func addPostgresAvailabilityAlert(alerts *cloudResourceAlerts, inst *v1alpha1.RHMI, cr *crov1.Postgres, log l.Logger, installType string) {
 	installationName := InstallationNames[installType]
 
 	if strings.ToLower(inst.Spec.UseClusterStorage) == "true" {
 		log.Info("skipping postgres alert creation, useClusterStorage is true")
-		return nil, nil
+		return
 	}
 
 	productName := cr.Labels["productName"]
@@ -276,21 +297,17 @@ func createPostgresAvailabilityAlert(ctx context.Context, client k8sclient.Clien
 	}
 
 	ruleNs := config.GetOboNamespace(inst.Namespace)
-	pr, err := reconcilePrometheusRule(ctx, client, ruleName, ruleNs, alertName, alertDescription, sopURL, alertFor5Mins, alertExp, labels)
-	if err != nil {
-		return nil, err
-	}
-	return pr, nil
+	alerts.add(ruleName, ruleNs, alertName, alertDescription, sopURL, alertFor5Mins, alertExp, labels)
 }
 
-// createPostgresConnectivityAlert creates a PrometheusRule alert to watch for the connectivity
+// addPostgresConnectivityAlert adds a PrometheusRule alert to watch for the connectivity
 // of a Postgres instance
-func createPostgresConnectivityAlert(ctx context.Context, client k8sclient.Client, inst *v1alpha1.RHMI, cr *crov1.Postgres, log l.Logger, installType string) (*monv1.PrometheusRule, error) {
+func addPostgresConnectivityAlert(alerts *cloudResourceAlerts, inst *v1alpha1.RHMI, cr *crov1.Postgres, log l.Logger, installType string) {
 	installationName := InstallationNames[installType]
 
 	if strings.ToLower(inst.Spec.UseClusterStorage) == "true" {
 		log.Info("skipping postgres connectivity alert creation, useClusterStorage is true")
-		return nil, nil
+		return
 	}
 	productName := cr.Labels["productName"]
 	postgresCRName := caser.String(strings.Replace(cr.Name, "postgres-example-rhmi", "", -1))
@@ -313,20 +330,16 @@ func createPostgresConnectivityAlert(ctx context.Context, client k8sclient.Clien
 	}
 
 	ruleNs := config.GetOboNamespace(inst.Namespace)
-	pr, err := reconcilePrometheusRule(ctx, client, ruleName, ruleNs, alertName, alertDescription, sopURL, alertFor5Mins, alertExp, labels)
-	if err != nil {
-		return nil, err
-	}
-	return pr, nil
+	alerts.add(ruleName, ruleNs, alertName, alertDescription, sopURL, alertFor5Mins, alertExp, labels)
 }
 
-// createPostgresResourceStatusPhasePendingAlert creates a PrometheusRule alert to watch for Postgres CR state
-func createPostgresResourceStatusPhasePendingAlert(ctx context.Context, client k8sclient.Client, inst *v1alpha1.RHMI, cr *crov1.Postgres, log l.Logger, installType string) (*monv1.PrometheusRule, error) {
+// addPostgresResourceStatusPhasePendingAlert adds a PrometheusRule alert to watch for Postgres CR state
+func addPostgresResourceStatusPhasePendingAlert(alerts *cloudResourceAlerts, inst *v1alpha1.RHMI, cr *crov1.Postgres, log l.Logger, installType string) {
 	installationName := InstallationNames[installType]
 
 	if strings.ToLower(inst.Spec.UseClusterStorage) == "true" {
 		log.Info("skipping postgres state alert creation, useClusterStorage is true")
-		return nil, nil
+		return
 	}
 	productName := cr.Labels["productName"]
 	postgresCRName := caser.String(strings.Replace(cr.Name, "postgres-example-rhmi", "", -1))
@@ -344,20 +357,16 @@ func createPostgresResourceStatusPhasePendingAlert(ctx context.Context, client k
 	}
 
 	ruleNs := config.GetOboNamespace(inst.Namespace)
-	pr, err := reconcilePrometheusRule(ctx, client, ruleName, ruleNs, alertName, alertDescription, sopUrlPostgresResourceStatusPhasePending, alertFor20Mins, alertExp, labels)
-	if err != nil {
-		return nil, err
-	}
-	return pr, nil
+	alerts.add(ruleName, ruleNs, alertName, alertDescription, sopUrlPostgresResourceStatusPhasePending, alertFor20Mins, alertExp, labels)
 }
 
-// createPostgresResourceStatusPhaseFailedAlert creates a PrometheusRule alert to watch for Postgres CR state
-func createPostgresResourceStatusPhaseFailedAlert(ctx context.Context, client k8sclient.Client, inst *v1alpha1.RHMI, cr *crov1.Postgres, log l.Logger, installType string) (*monv1.PrometheusRule, error) {
+// addPostgresResourceStatusPhaseFailedAlert adds a PrometheusRule alert to watch for Postgres CR state
+func addPostgresResourceStatusPhaseFailedAlert(alerts *cloudResourceAlerts, inst *v1alpha1.RHMI, cr *crov1.Postgres, log l.Logger, installType string) {
 	installationName := InstallationNames[installType]
 
 	if strings.ToLower(inst.Spec.UseClusterStorage) == "true" {
 		log.Info("skipping postgres state alert creation, useClusterStorage is true")
-		return nil, nil
+		return
 	}
 	productName := cr.Labels["productName"]
 	postgresCRName := caser.String(strings.Replace(cr.Name, "postgres-example-rhmi", "", -1))
@@ -375,20 +384,16 @@ func createPostgresResourceStatusPhaseFailedAlert(ctx context.Context, client k8
 	}
 
 	ruleNs := config.GetOboNamespace(inst.Namespace)
-	pr, err := reconcilePrometheusRule(ctx, client, ruleName, ruleNs, alertName, alertDescription, sopUrlPostgresResourceStatusPhaseFailed, alertFor5Mins, alertExp, labels)
-	if err != nil {
-		return nil, err
-	}
-	return pr, nil
+	alerts.add(ruleName, ruleNs, alertName, alertDescription, sopUrlPostgresResourceStatusPhaseFailed, alertFor5Mins, alertExp, labels)
 }
 
-// createPostgresResourceDeletionStatusFailedAlert creates a PrometheusRule alert that watches for failed deletions of Postgres CRs
-func createPostgresResourceDeletionStatusFailedAlert(ctx context.Context, client k8sclient.Client, inst *v1alpha1.RHMI, cr *crov1.Postgres, log l.Logger, installType string) (*monv1.PrometheusRule, error) {
+// addPostgresResourceDeletionStatusFailedAlert adds a PrometheusRule alert that watches for failed deletions of Postgres CRs
+func addPostgresResourceDeletionStatusFailedAlert(alerts *cloudResourceAlerts, inst *v1alpha1.RHMI, cr *crov1.Postgres, log l.Logger, installType string) {
 	installationName := InstallationNames[installType]
 
 	if strings.ToLower(inst.Spec.UseClusterStorage) == "true" {
 		log.Info("skipping postgres state alert creation, useClusterStorage is true")
-		return nil, nil
+		return
 	}
 	productName := cr.Labels["productName"]
 	postgresCRName := caser.String(strings.Replace(cr.Name, "postgres-example-rhmi", "", -1))
@@ -405,26 +410,22 @@ func createPostgresResourceDeletionStatusFailedAlert(ctx context.Context, client
 	}
 
 	ruleNs := config.GetOboNamespace(inst.Namespace)
-	pr, err := reconcilePrometheusRule(ctx, client, ruleName, ruleNs, alertName, alertDescription, sopUrlCloudResourceDeletionStatusFailed, alertFor5Mins, alertExp, labels)
-	if err != nil {
-		return nil, err
-	}
-	return pr, nil
+	alerts.add(ruleName, ruleNs, alertName, alertDescription, sopUrlCloudResourceDeletionStatusFailed, alertFor5Mins, alertExp, labels)
 }
 
-// reconcilePostgresFreeStorageAlerts reconciles on both free storage alerts (4 days and 4 hours) and a low storage alert
+// addPostgresFreeStorageAlerts adds both free storage alerts (4 days and 4 hours) and a low storage alert
 // To avoid any false positives when the instances are being deployed for linear projection (4 days and 4 hours)
 // the alert query requires a minimum time of data before it will evaluate if the instance would run out of storage.
 //
 // the low storage alert fires if storage is under 10% of current capacity, with a 30 minute alertOn value to allow for any
 // provider autoscaling to happen, if after 30 minutes the instance will require manual intervention
-func reconcilePostgresFreeStorageAlerts(ctx context.Context, client k8sclient.Client, inst *v1alpha1.RHMI, cr *crov1.Postgres, log l.Logger, installType string) error {
+func addPostgresFreeStorageAlerts(alerts *cloudResourceAlerts, inst *v1alpha1.RHMI, cr *crov1.Postgres, log l.Logger, installType string) {
 	installationName := InstallationNames[installType]
 
 	// don't create the alert if we are using in cluster storage
 	if strings.ToLower(inst.Spec.UseClusterStorage) == "true" {
 		log.Info("skipping postgres free storage alert creation, useClusterStorage is true")
-		return nil
+		return
 	}
 
 	// job to check time that the operator metrics are exposed
@@ -444,13 +445,10 @@ func reconcilePostgresFreeStorageAlerts(ctx context.Context, client k8sclient.Cl
 	//    * [1h] - one hour data points
 	//    * , 5 * 3600 - multiplying data points by 5 hour, to allow 1 hour of pending before firing the alert
 	alertExp := intstr.FromString(
-		fmt.Sprintf("(predict_linear(sum by (instanceID) (cro_postgres_free_storage_average{job='%s'})[1h:1m], 5 * 3600) <= 0 and on (instanceID) (cro_postgres_free_storage_average < ((cro_postgres_current_allocated_storage / 100) * 25)))", job))
+		fmt.Sprintf("(predict_linear(sum by (instanceID) (cro_postgres_free_storage_average{job='%s'})[1h:1m], 5 * 3600) <= 0 and on (instanceID) (cro_postgres_free_storage_average < ((cro_postgres_current_allocated_storage / 100) * %s)))", job, ThresholdPlaceholder))
 
 	ruleNs := config.GetOboNamespace(inst.Namespace)
-	_, err := reconcilePrometheusRule(ctx, client, ruleName, ruleNs, alertName, alertDescription, sopURL, alertFor30Mins, alertExp, labels)
-	if err != nil {
-		return err
-	}
+	alerts.addThreshold(ruleName, ruleNs, alertName, alertDescription, sopURL, alertFor30Mins, "25", alertExp, labels)
 
 	// build and reconcile postgres will fill in 4 days alert
 	alertName = "PostgresStorageWillFillIn4Days"
@@ -465,12 +463,9 @@ func reconcilePostgresFreeStorageAlerts(ctx context.Context, client k8sclient.Cl
 	//    * [2h] - 2 hour data points
 	//    * , 4 * 24 * 3600 - multiplying data points by 4 days
 	alertExp = intstr.FromString(
-		fmt.Sprintf("(predict_linear(sum by (instanceID) (cro_postgres_free_storage_average{job='%s'})[6h:1m], 4 * 24 * 3600) <= 0) and on (instanceID) (cro_postgres_free_storage_average < ((cro_postgres_current_allocated_storage / 100) * 25))", job))
+		fmt.Sprintf("(predict_linear(sum by (instanceID) (cro_postgres_free_storage_average{job='%s'})[6h:1m], 4 * 24 * 3600) <= 0) and on (instanceID) (cro_postgres_free_storage_average < ((cro_postgres_current_allocated_storage / 100) * %s))", job, ThresholdPlaceholder))
 
-	_, err = reconcilePrometheusRule(ctx, client, ruleName, ruleNs, alertName, alertDescription, sopUrlPostgresWillFill, alertFor30Mins, alertExp, labels)
-	if err != nil {
-		return err
-	}
+	alerts.addThreshold(ruleName, ruleNs, alertName, alertDescription, sopUrlPostgresWillFill, alertFor30Mins, "25", alertExp, labels)
 
 	// build and reconcile postgres low storage alert
 	alertName = "PostgresStorageLow"
@@ -482,22 +477,18 @@ func reconcilePostgresFreeStorageAlerts(ctx context.Context, client k8sclient.Cl
 	}
 
 	// checking if the percentage of free storage is less than 10% of the current allocated storage
-	alertExp = intstr.FromString("cro_postgres_free_storage_average < ((cro_postgres_current_allocated_storage / 100 ) * 20)")
+	alertExp = intstr.FromString("cro_postgres_free_storage_average < ((cro_postgres_current_allocated_storage / 100 ) * " + ThresholdPlaceholder + ")")
 
-	_, err = reconcilePrometheusRule(ctx, client, ruleName, ruleNs, alertName, alertDescription, sopUrlPostgresWillFill, alertFor30Mins, alertExp, labels)
-	if err != nil {
-		return err
-	}
-	return nil
+	alerts.addThreshold(ruleName, ruleNs, alertName, alertDescription, sopUrlPostgresWillFill, alertFor30Mins, "20", alertExp, labels)
 }
 
-func reconcilePostgresFreeableMemoryAlert(ctx context.Context, client k8sclient.Client, inst *v1alpha1.RHMI, cr *crov1.Postgres, log l.Logger, installType string) error {
+func addPostgresFreeableMemoryAlert(alerts *cloudResourceAlerts, inst *v1alpha1.RHMI, cr *crov1.Postgres, log l.Logger, installType string) {
 	installationName := InstallationNames[installType]
 
 	// don't create the alert if we are using in cluster storage
 	if strings.ToLower(inst.Spec.UseClusterStorage) == "true" {
 		log.Info("skipping postgres free storage alert creation, useClusterStorage is true")
-		return nil
+		return
 	}
 
 	// build and reconcile postgres low freeable memory alert
@@ -511,23 +502,19 @@ func reconcilePostgresFreeableMemoryAlert(ctx context.Context, client k8sclient.
 
 	// checking if the percentage of freeable memory is less than 5% of the max memory
 	// conversion formula is MiB = bytes / (1024^2)
-	alertExp := intstr.FromString("(cro_postgres_freeable_memory_average) < ((cro_postgres_max_memory / 100 ) * 5)")
+	alertExp := intstr.FromString("(cro_postgres_freeable_memory_average) < ((cro_postgres_max_memory / 100 ) * " + ThresholdPlaceholder + ")")
 
 	ruleNs := config.GetOboNamespace(inst.Namespace)
-	_, err := reconcilePrometheusRule(ctx, client, ruleName, ruleNs, alertName, alertDescription, sopUrlPostgresFreeableMemoryLow, alertFor5Mins, alertExp, labels)
-	if err != nil {
-		return err
-	}
-	return nil
+	alerts.addThreshold(ruleName, ruleNs, alertName, alertDescription, sopUrlPostgresFreeableMemoryLow, alertFor5Mins, "5", alertExp, labels)
 }
 
-func reconcilePostgresCPUUtilizationAlerts(ctx context.Context, client k8sclient.Client, inst *v1alpha1.RHMI, cr *crov1.Postgres, log l.Logger, installType string) error {
+func addPostgresCPUUtilizationAlerts(alerts *cloudResourceAlerts, inst *v1alpha1.RHMI, cr *crov1.Postgres, log l.Logger, installType string) {
 	installationName := InstallationNames[installType]
 
 	// don't create the alert if we are using in cluster storage
 	if strings.ToLower(inst.Spec.UseClusterStorage) == "true" {
 		log.Info("skipping postgres free storage alert creation, useClusterStorage is true")
-		return nil
+		return
 	}
 
 	alertName := "PostgresCPUHigh"
@@ -538,21 +525,17 @@ func reconcilePostgresCPUUtilizationAlerts(ctx context.Context, client k8sclient
 		"product":  installationName,
 	}
 
-	alertExp := intstr.FromString("cro_postgres_cpu_utilization_average > 80")
+	alertExp := intstr.FromString("cro_postgres_cpu_utilization_average > " + ThresholdPlaceholder)
 
 	ruleNs := config.GetOboNamespace(inst.Namespace)
-	_, err := reconcilePrometheusRule(ctx, client, ruleName, ruleNs, alertName, alertDescription, sopUrlPostgresCpuUsageHigh, alertFor30Mins, alertExp, labels)
-	if err != nil {
-		return err
-	}
-	return nil
+	alerts.addThreshold(ruleName, ruleNs, alertName, alertDescription, sopUrlPostgresCpuUsageHigh, alertFor30Mins, "80", alertExp, labels)
 }
 
-// createRedisResourceStatusPhasePendingAlert creates a PrometheusRule alert to watch for Redis CR state
-func createRedisResourceStatusPhasePendingAlert(ctx context.Context, client k8sclient.Client, inst *v1alpha1.RHMI, cr *crov1.Redis, log l.Logger) (*monv1.PrometheusRule, error) {
+// addRedisResourceStatusPhasePendingAlert adds a PrometheusRule alert to watch for Redis CR state
+func addRedisResourceStatusPhasePendingAlert(alerts *cloudResourceAlerts, inst *v1alpha1.RHMI, cr *crov1.Redis, log l.Logger) {
 	if strings.ToLower(inst.Spec.UseClusterStorage) == "true" {
 		log.Info("skipping redis alert creation, useClusterStorage is true")
-		return nil, nil
+		return
 	}
 	productName := cr.Labels["productName"]
 	redisCRName := caser.String(strings.Replace(cr.Name, "redis-example-rhmi", "", -1))
@@ -569,19 +552,15 @@ func createRedisResourceStatusPhasePendingAlert(ctx context.Context, client k8sc
 	}
 
 	ruleNs := config.GetOboNamespace(inst.Namespace)
-	pr, err := reconcilePrometheusRule(ctx, client, ruleName, ruleNs, alertName, alertDescription, sopUrlRedisResourceStatusPhasePending, alertFor20Mins, alertExp, labels)
-	if err != nil {
-		return nil, err
-	}
-	return pr, nil
+	alerts.add(ruleName, ruleNs, alertName, alertDescription, sopUrlRedisResourceStatusPhasePending, alertFor20Mins, alertExp, labels)
 }
 
-// CreateRedisMemoryUsageHighAlert creates a PrometheusRule alert to watch for High Memory usage
+// addRedisMemoryUsageAlerts adds PrometheusRule alerts to watch for High Memory usage
 // of a Redis cache
-func createRedisMemoryUsageAlerts(ctx context.Context, client k8sclient.Client, inst *v1alpha1.RHMI, cr *crov1.Redis, log l.Logger) error {
+func addRedisMemoryUsageAlerts(alerts *cloudResourceAlerts, inst *v1alpha1.RHMI, cr *crov1.Redis, log l.Logger) {
 	if strings.ToLower(inst.Spec.UseClusterStorage) == "true" {
 		log.Info("skipping redis memory usage high alert creation, useClusterStorage is true")
-		return nil
+		return
 	}
 	productName := cr.Labels["productName"]
 
@@ -593,13 +572,10 @@ func createRedisMemoryUsageAlerts(ctx context.Context, client k8sclient.Client,
 		"productName": productName,
 	}
 
-	alertExp := intstr.FromString(fmt.Sprintf("cro_redis_memory_usage_percentage_average > %s", alertPercentage))
+	alertExp := intstr.FromString("cro_redis_memory_usage_percentage_average > " + ThresholdPlaceholder)
 
 	ruleNs := config.GetOboNamespace(inst.Namespace)
-	_, err := reconcilePrometheusRule(ctx, client, ruleName, ruleNs, alertName, alertDescription, sopUrlRedisMemoryUsageHigh, alertFor30Mins, alertExp, labels)
-	if err != nil {
-		return err
-	}
+	alerts.addThreshold(ruleName, ruleNs, alertName, alertDescription, sopUrlRedisMemoryUsageHigh, alertFor30Mins, alertPercentage, alertExp, labels)
 
 	// job to check time that the operator metrics are exposed
 	job := "operator-metrics-service"
@@ -614,12 +590,9 @@ func createRedisMemoryUsageAlerts(ctx context.Context, client k8sclient.Client,
 	// building a predict_linear query using 1 hour of data points to predict a 4 hour projection, and checking if it is less than or equal 0
 	//    * [1h] - one hour data points
 	//    * , 4 * 3600 - multiplying data points by 4 hours
-	alertExp = intstr.FromString(fmt.Sprintf("(predict_linear(sum by (instanceID) (cro_redis_memory_usage_percentage_average{job='%s'})[1h:1m], 5 * 3600) >= 100) and on (instanceID) (cro_redis_memory_usage_percentage_average{job='%s'} > 75)", job, job))
+	alertExp = intstr.FromString(fmt.Sprintf("(predict_linear(sum by (instanceID) (cro_redis_memory_usage_percentage_average{job='%s'})[1h:1m], 5 * 3600) >= 100) and on (instanceID) (cro_redis_memory_usage_percentage_average{job='%s'} > %s)", job, job, ThresholdPlaceholder))
 
-	_, err = reconcilePrometheusRule(ctx, client, ruleName, ruleNs, alertName, alertDescription, sopUrlRedisMemoryUsageHigh, alertFor30Mins, alertExp, labels)
-	if err != nil {
-		return err
-	}
+	alerts.addThreshold(ruleName, ruleNs, alertName, alertDescription, sopUrlRedisMemoryUsageHigh, alertFor30Mins, "75", alertExp, labels)
 
 	alertName = "RedisMemoryUsageMaxIn4Days"
 	ruleName = "redis-memory-usage-max-fill-in-4-days"
@@ -631,21 +604,16 @@ func createRedisMemoryUsageAlerts(ctx context.Context, client k8sclient.Client,
 	// building a predict_linear query using 1 hour of data points to predict a 4 hour projection, and checking if it is less than or equal 0
 	//    * [6h] - six hour data points
 	//    * , 4 * 24 * 3600 - multiplying data points by 4 days
-	alertExp = intstr.FromString(fmt.Sprintf("(predict_linear(sum by (instanceID) (cro_redis_memory_usage_percentage_average{job='%s'})[6h:1m], 4 * 24 * 3600) >= 100) and on (instanceID) (cro_redis_memory_usage_percentage_average{job='%s'} > 75)", job, job))
-
-	_, err = reconcilePrometheusRule(ctx, client, ruleName, ruleNs, alertName, alertDescription, sopUrlRedisMemoryUsageHigh, alertFor30Mins, alertExp, labels)
-	if err != nil {
-		return err
-	}
+	alertExp = intstr.FromString(fmt.Sprintf("(predict_linear(sum by (instanceID) (cro_redis_memory_usage_percentage_average{job='%s'})[6h:1m], 4 * 24 * 3600) >= 100) and on (instanceID) (cro_redis_memory_usage_percentage_average{job='%s'} > %s)", job, job, ThresholdPlaceholder))
 
-	return nil
+	alerts.addThreshold(ruleName, ruleNs, alertName, alertDescription, sopUrlRedisMemoryUsageHigh, alertFor30Mins, "75", alertExp, labels)
 }
 
-// createRedisResourceStatusPhaseFailedAlert creates a PrometheusRule alert to watch for Redis CR state
-func createRedisResourceStatusPhaseFailedAlert(ctx context.Context, client k8sclient.Client, inst *v1alpha1.RHMI, cr *crov1.Redis, log l.Logger) (*monv1.PrometheusRule, error) {
+// addRedisResourceStatusPhaseFailedAlert adds a PrometheusRule alert to watch for Redis CR state
+func addRedisResourceStatusPhaseFailedAlert(alerts *cloudResourceAlerts, inst *v1alpha1.RHMI, cr *crov1.Redis, log l.Logger) {
 	if strings.ToLower(inst.Spec.UseClusterStorage) == "true" {
 		log.Info("skipping redis alert creation, useClusterStorage is true")
-		return nil, nil
+		return
 	}
 	productName := cr.Labels["productName"]
 	redisCRName := caser.String(strings.Replace(cr.Name, "redis-example-rhmi", "", -1))
@@ -662,18 +630,14 @@ func createRedisResourceStatusPhaseFailedAlert(ctx context.Context, client k8scl
 	}
 
 	ruleNs := config.GetOboNamespace(inst.Namespace)
-	pr, err := reconcilePrometheusRule(ctx, client, ruleName, ruleNs, alertName, alertDescription, sopUrlRedisResourceStatusPhaseFailed, alertFor5Mins, alertExp, labels)
-	if err != nil {
-		return nil, err
-	}
-	return pr, nil
+	alerts.add(ruleName, ruleNs, alertName, alertDescription, sopUrlRedisResourceStatusPhaseFailed, alertFor5Mins, alertExp, labels)
 }
 
-// createRedisResourceDeletionStatusFailedAlert creates a PrometheusRule alert that watches for failed deletions of Redis CRs
-func createRedisResourceDeletionStatusFailedAlert(ctx context.Context, client k8sclient.Client, inst *v1alpha1.RHMI, cr *crov1.Redis, log l.Logger) (*monv1.PrometheusRule, error) {
+// addRedisResourceDeletionStatusFailedAlert adds a PrometheusRule alert that watches for failed deletions of Redis CRs
+func addRedisResourceDeletionStatusFailedAlert(alerts *cloudResourceAlerts, inst *v1alpha1.RHMI, cr *crov1.Redis, log l.Logger) {
 	if strings.ToLower(inst.Spec.UseClusterStorage) == "true" {
 		log.Info("skipping redis state alert creation, useClusterStorage is true")
-		return nil, nil
+		return
 	}
 	productName := cr.Labels["productName"]
 	redisCRName := caser.String(strings.Replace(cr.Name, "redis-example-rhmi", "", -1))
@@ -689,19 +653,15 @@ func createRedisResourceDeletionStatusFailedAlert(ctx context.Context, client k8
 	}
 
 	ruleNs := config.GetOboNamespace(inst.Namespace)
-	pr, err := reconcilePrometheusRule(ctx, client, ruleName, ruleNs, alertName, alertDescription, sopUrlCloudResourceDeletionStatusFailed, alertFor5Mins, alertExp, labels)
-	if err != nil {
-		return nil, err
-	}
-	return pr, nil
+	alerts.add(ruleName, ruleNs, alertName, alertDescription, sopUrlCloudResourceDeletionStatusFailed, alertFor5Mins, alertExp, labels)
 }
 
-// createRedisAvailabilityAlert creates a PrometheusRule alert to watch for the availability
+// addRedisAvailabilityAlert adds a PrometheusRule alert to watch for the availability
 // of a Redis cache
-func createRedisAvailabilityAlert(ctx context.Context, client k8sclient.Client, inst *v1alpha1.RHMI, cr *crov1.Redis, log l.Logger) (*monv1.PrometheusRule, error) {
+func addRedisAvailabilityAlert(alerts *cloudResourceAlerts, inst *v1alpha1.RHMI, cr *crov1.Redis, log l.Logger) {
 	if strings.ToLower(inst.Spec.UseClusterStorage) == "true" {
 		log.Info("skipping redis alert creation, useClusterStorage is true")
-		return nil, nil
+		return
 	}
 	productName := cr.Labels["productName"]
 	redisCRName := caser.String(strings.Replace(cr.Name, "redis-example-rhmi", "", -1))
@@ -727,19 +687,15 @@ func createRedisAvailabilityAlert(ctx context.Context, client k8sclient.Client,
 	}
 
 	ruleNs := config.GetOboNamespace(inst.Namespace)
-	pr, err := reconcilePrometheusRule(ctx, client, ruleName, ruleNs, alertName, alertDescription, sopURL, alertFor5Mins, alertExp, labels)
-	if err != nil {
-		return nil, err
-	}
-	return pr, nil
+	alerts.add(ruleName, ruleNs, alertName, alertDescription, sopURL, alertFor5Mins, alertExp, labels)
 }
 
-// createRedisConnectivityAlert creates a PrometheusRule alert to watch for the connectivity
+// addRedisConnectivityAlert adds a PrometheusRule alert to watch for the connectivity
 // of a Redis cache
-func createRedisConnectivityAlert(ctx context.Context, client k8sclient.Client, inst *v1alpha1.RHMI, cr *crov1.Redis, log l.Logger) (*monv1.PrometheusRule, error) {
+func addRedisConnectivityAlert(alerts *cloudResourceAlerts, inst *v1alpha1.RHMI, cr *crov1.Redis, log l.Logger) {
 	if strings.ToLower(inst.Spec.UseClusterStorage) == "true" {
 		log.Info("skipping redis connectivity alert creation, useClusterStorage is true")
-		return nil, nil
+		return
 	}
 	productName := cr.Labels["productName"]
 	redisCRName := caser.String(strings.Replace(cr.Name, "redis-example-rhmi", "", -1))
@@ -765,19 +721,15 @@ func createRedisConnectivityAlert(ctx context.Context, client k8sclient.Client,
 	}
 
 	ruleNs := config.GetOboNamespace(inst.Namespace)
-	pr, err := reconcilePrometheusRule(ctx, client, ruleName, ruleNs, alertName, alertDescription, sopURL, alertFor30Mins, alertExp, labels)
-	if err != nil {
-		return nil, err
-	}
-	return pr, nil
+	alerts.add(ruleName, ruleNs, alertName, alertDescription, sopURL, alertFor30Mins, alertExp, labels)
 }
 
-// CreateRedisCpuUsageAlerts creates a PrometheusRule alerts to watch for High Cpu usage
+// addRedisCpuUsageAlerts adds a PrometheusRule alerts to watch for High Cpu usage
 // of a Redis cache
-func CreateRedisCpuUsageAlerts(ctx context.Context, client k8sclient.Client, inst *v1alpha1.RHMI, cr *crov1.Redis, log l.Logger) error {
+func addRedisCpuUsageAlerts(alerts *cloudResourceAlerts, inst *v1alpha1.RHMI, cr *crov1.Redis, log l.Logger) {
 	if strings.ToLower(inst.Spec.UseClusterStorage) == "true" {
 		log.Info("skipping redis memory usage high alert creation, useClusterStorage is true")
-		return nil
+		return
 	}
 	productName := cr.Labels["productName"]
 	alertName := "RedisCpuUsageHigh"
@@ -788,21 +740,17 @@ func CreateRedisCpuUsageAlerts(ctx context.Context, client k8sclient.Client, ins
 		"productName": productName,
 	}
 
-	alertExp := intstr.FromString(fmt.Sprintf("cro_redis_engine_cpu_utilization_average > %s", alertPercentage))
+	alertExp := intstr.FromString("cro_redis_engine_cpu_utilization_average > " + ThresholdPlaceholder)
 
 	ruleNs := config.GetOboNamespace(inst.Namespace)
-	_, err := reconcilePrometheusRule(ctx, client, ruleName, ruleNs, alertName, alertDescription, sopUrlRedisCpuUsageHigh, alertFor30Mins, alertExp, labels)
-	if err != nil {
-		return err
-	}
-	return nil
+	alerts.addThreshold(ruleName, ruleNs, alertName, alertDescription, sopUrlRedisCpuUsageHigh, alertFor30Mins, alertPercentage, alertExp, labels)
 }
 
-// CreateRedisServiceMaintenanceAlerts creates a PrometheusRule alerts to watch critical security update for Redis cache
-func CreateRedisServiceMaintenanceAlerts(ctx context.Context, client k8sclient.Client, inst *v1alpha1.RHMI, cr *crov1.Redis, log l.Logger) error {
+// addRedisServiceMaintenanceAlerts adds a PrometheusRule alerts to watch critical security update for Redis cache
+func addRedisServiceMaintenanceAlerts(alerts *cloudResourceAlerts, inst *v1alpha1.RHMI, cr *crov1.Redis, log l.Logger) {
 	if strings.ToLower(inst.Spec.UseClusterStorage) == "true" {
 		log.Info("skipping redis service maintenance alert creation, useClusterStorage is true")
-		return nil
+		return
 	}
 	productName := cr.Labels["productName"]
 	alertName := "RedisServiceMaintenanceCritical"
@@ -816,75 +764,41 @@ func CreateRedisServiceMaintenanceAlerts(ctx context.Context, client k8sclient.C
 	alertExp := intstr.FromString("cro_redis_service_maintenance{ServiceUpdateType='security-update',UpdateActionStatus!~'complete|waiting-to-start|in-progress|scheduled|stopping',ServiceUpdateSeverity='critical'}")
 
 	ruleNs := config.GetOboNamespace(inst.Namespace)
-	_, err := reconcilePrometheusRule(ctx, client, ruleName, ruleNs, alertName, alertDescription, sopUrlRedisServiceMaintenanceCritical, alertFor15Mins, alertExp, labels)
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
-// reconcilePrometheusRule will create a PrometheusRule object
-func reconcilePrometheusRule(ctx context.Context, client k8sclient.Client, ruleName, ns, alertName, desc, sopURL, alertFor string, alertExp intstr.IntOrString, labels map[string]string) (*monv1.PrometheusRule, error) {
-	alertGroupName := alertName + "Group"
-	groups := []monv1.RuleGroup{
-		{
-			Name: alertGroupName,
-			Rules: []monv1.Rule{
-				{
-					Alert:  alertName,
-					Expr:   alertExp,
-					For:    DurationPtr(alertFor),
-					Labels: labels,
-					Annotations: map[string]string{
-						"description": desc,
-						"sop_url":     sopURL,
-					},
-				},
-			},
-		},
-	}
+	alerts.add(ruleName, ruleNs, alertName, alertDescription, sopUrlRedisServiceMaintenanceCritical, alertFor15Mins, alertExp, labels)
+}
 
-	rule := &monv1.PrometheusRule{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      ruleName,
-			Namespace: ns,
-			Labels: map[string]string{
-				config.GetOboLabelSelectorKey(): config.GetOboLabelSelector(),
-			},
-		},
-		Spec: monv1.PrometheusRuleSpec{
-			Groups: groups,
-		},
-	}
+// cloudResourceAlerts collects alerts that each have a PrometheusRule of their own
+type cloudResourceAlerts struct {
+	configurations []AlertConfiguration
+}
 
-	// create or update the resource
-	_, err := controllerutil.CreateOrUpdate(ctx, client, rule, func() error {
-		rule.Name = ruleName
-		rule.Namespace = ns
-		rule.Spec.Groups = []monv1.RuleGroup{
+// add adds an alert in a PrometheusRule of its own
+func (a *cloudResourceAlerts) add(ruleName, ns, alertName, desc, sopURL, alertFor string, alertExp intstr.IntOrString, labels map[string]string) {
+	a.configurations = append(a.configurations, AlertConfiguration{
+		AlertName: ruleName,
+		GroupName: alertName + "Group",
+		Namespace: ns,
+		Rules: []monv1.Rule{
 			{
-				Name: alertGroupName,
-				Rules: []monv1.Rule{
-					{
-						Alert:  alertName,
-						Expr:   alertExp,
-						For:    DurationPtr(alertFor),
-						Labels: labels,
-						Annotations: map[string]string{
-							"description": desc,
-							"sop_url":     sopURL,
-						},
-					},
+				Alert:  alertName,
+				Expr:   alertExp,
+				For:    DurationPtr(alertFor),
+				Labels: labels,
+				Annotations: map[string]string{
+					"description":    desc,
+					SOPURLAnnotation: sopURL,
 				},
 			},
-		}
-		return nil
+		},
 	})
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to reconcile prometheus rule request for %s", ruleName)
-	}
+}
 
-	return rule, nil
+// addThreshold adds an alert whose expression refers to its threshold with ThresholdPlaceholder, so that
+// the threshold can be overridden
+func (a *cloudResourceAlerts) addThreshold(ruleName, ns, alertName, desc, sopURL, alertFor, threshold string, alertExp intstr.IntOrString, labels map[string]string) {
+	a.add(ruleName, ns, alertName, desc, sopURL, alertFor, alertExp, labels)
+	rule := a.configurations[len(a.configurations)-1].Rules.([]monv1.Rule)
+	rule[0].Annotations[ThresholdAnnotation] = threshold
 }
 
 func InstallationState(version string, toVersion string) string {
@@ -2,12 +2,15 @@ package resources
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/config"
 	"github.com/integr8ly/integreatly-operator/utils"
 	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monv1 "github.com/rhobs/obo-prometheus-operator/pkg/apis/monitoring/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -74,7 +77,7 @@ func TestInstallationState(t *testing.T) {
 	}
 }
 
-func TestCreateAddonManagedApiServiceParametersExists(t *testing.T) {
+func TestReconcileInstallationAlerts(t *testing.T) {
 	scheme, err := utils.NewTestScheme()
 	if err != nil {
 		t.Fatal(err)
@@ -92,7 +95,7 @@ func TestCreateAddonManagedApiServiceParametersExists(t *testing.T) {
 		wantErr bool
 	}{
 		{
-			name: "test create AddonManagedApiServiceParametersExists alert successful",
+			name: "test reconcile installation alerts successful",
 			args: args{
 				ctx: context.TODO(),
 				client: utils.NewTestClient(scheme, &monitoringv1.PrometheusRule{
@@ -120,7 +123,7 @@ func TestCreateAddonManagedApiServiceParametersExists(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name: "test create AddonManagedApiServiceParametersExists alert failure",
+			name: "test reconcile installation alerts failure when the addon parameters secret is missing",
 			args: args{
 				ctx: context.TODO(),
 				client: utils.NewTestClient(scheme, &corev1.Secret{
@@ -143,54 +146,8 @@ func TestCreateAddonManagedApiServiceParametersExists(t *testing.T) {
 			want:    v1alpha1.PhaseFailed,
 			wantErr: true,
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got, err := CreateAddonManagedApiServiceParametersExists(tt.args.ctx, tt.args.client, tt.args.cr)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("CreateAddonManagedApiServiceParametersExists() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if got != tt.want {
-				t.Errorf("CreateAddonManagedApiServiceParametersExists() got = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
-func TestCreateDeadMansSnitchSecretExists(t *testing.T) {
-	scheme, err := utils.NewTestScheme()
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	type args struct {
-		ctx    context.Context
-		client client.Client
-		cr     *v1alpha1.RHMI
-	}
-	tests := []struct {
-		name    string
-		args    args
-		want    v1alpha1.StatusPhase
-		wantErr bool
-	}{
-		{
-			name: "test create CreateDeadMansSnitchSecretExists alert successful",
-			args: args{
-				ctx: context.TODO(),
-				client: utils.NewTestClient(scheme, &monitoringv1.PrometheusRule{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: "DeadMansSnitchSecretExists",
-					},
-				}, getNamespaces()),
-				cr: getRHMIcr(),
-			},
-			want:    v1alpha1.PhaseCompleted,
-			wantErr: false,
-		},
 		{
-			name: "test create CreateDeadMansSnitchSecretExists alert failure",
+			name: "test reconcile installation alerts failure",
 			args: args{
 				ctx:    context.TODO(),
 				client: utils.NewTestClient(runtime.NewScheme()),
@@ -202,69 +159,74 @@ func TestCreateDeadMansSnitchSecretExists(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := CreateDeadMansSnitchSecretExists(tt.args.ctx, tt.args.client, tt.args.cr)
+			got, err := ReconcileInstallationAlerts(tt.args.ctx, tt.args.client, getLogger(), tt.args.cr)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("CreateDeadMansSnitchSecretExists() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("ReconcileInstallationAlerts() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if got != tt.want {
-				t.Errorf("CreateDeadMansSnitchSecretExists() got = %v, want %v", got, tt.want)
+				t.Errorf("ReconcileInstallationAlerts() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func TestCreateSmtpSecretExists(t *testing.T) {
-	scheme, err := utils.NewTestScheme()
-	if err != nil {
-		t.Fatal(err)
-	}
+func TestInstallationAlertsReconciler(t *testing.T) {
+	freshInstallation := getRHMIcr()
+	freshInstallation.Status.ToVersion = "1.2.0"
 
-	type args struct {
-		ctx    context.Context
-		client client.Client
-		cr     *v1alpha1.RHMI
-	}
 	tests := []struct {
-		name    string
-		args    args
-		want    v1alpha1.StatusPhase
-		wantErr bool
+		name     string
+		cr       *v1alpha1.RHMI
+		wantFor  map[string]string
+		wantExpr map[string]string
 	}{
 		{
-			name: "test create CreateSmtpSecretExists alert successful",
-			args: args{
-				ctx: context.TODO(),
-				client: utils.NewTestClient(scheme, &monitoringv1.PrometheusRule{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: "CreateSmtpSecretExists",
-					},
-				}, getNamespaces()),
-				cr: getRHMIcr(),
+			name: "alerts of an installed installation",
+			cr:   getRHMIcr(),
+			wantFor: map[string]string{
+				"SendgridSmtpSecretExists":               alertFor10Mins,
+				"DeadMansSnitchSecretExists":             alertFor10Mins,
+				"AddonManagedApiServiceParametersExists": alertFor5Mins,
+			},
+			wantExpr: map[string]string{
+				"AddonManagedApiServiceParametersExists": fmt.Sprintf("absent(kube_secret_info{namespace='%s',secret='addon-test-parameters'} == 1)", defaultOperatorNamespace),
 			},
-			want:    v1alpha1.PhaseCompleted,
-			wantErr: false,
 		},
 		{
-			name: "test create CreateSmtpSecretExists alert failure",
-			args: args{
-				ctx:    context.TODO(),
-				client: utils.NewTestClient(runtime.NewScheme()),
-				cr:     getRHMIcr(),
+			name: "DeadMansSnitch secret alert waits longer on fresh installations",
+			cr:   freshInstallation,
+			wantFor: map[string]string{
+				"DeadMansSnitchSecretExists": alertFor60Mins,
 			},
-			want:    v1alpha1.PhaseFailed,
-			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := CreateSmtpSecretExists(tt.args.ctx, tt.args.client, tt.args.cr)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("CreateSmtpSecretExists() error = %v, wantErr %v", err, tt.wantErr)
-				return
+			rules, err := InstallationAlertsReconciler(getLogger(), tt.cr, "addon-test-parameters").PrometheusRules(&RunbookCatalogue{}, AlertOverrides{})
+			if err != nil {
+				t.Fatalf("PrometheusRules() error = %v", err)
 			}
-			if got != tt.want {
-				t.Errorf("CreateSmtpSecretExists() got = %v, want %v", got, tt.want)
+			alerts := map[string]monv1.Rule{}
+			for _, rule := range rules {
+				if rule.Namespace != config.GetOboNamespace(tt.cr.Namespace) {
+					t.Errorf("rule %s created in namespace %s", rule.Name, rule.Namespace)
+				}
+				for _, group := range rule.Spec.Groups {
+					for _, r := range group.Rules {
+						alerts[r.Alert] = r
+					}
+				}
+			}
+			for alert, want := range tt.wantFor {
+				if got := alerts[alert].For; got == nil || string(*got) != want {
+					t.Errorf("alert %s for = %v, want %s", alert, got, want)
+				}
+			}
+			for alert, want := range tt.wantExpr {
+				if got := alerts[alert].Expr.StrVal; got != want {
+					t.Errorf("alert %s expr = %s, want %s", alert, got, want)
+				}
 			}
 		})
 	}
@@ -19,6 +19,7 @@ var (
 	InstallationNames = map[string]string{
 		string(integreatlyv1alpha1.InstallationTypeManagedApi):            "rhoam",
 		string(integreatlyv1alpha1.InstallationTypeMultitenantManagedApi): "rhoam",
+		string(integreatlyv1alpha1.InstallationTypeDeveloper):             "rhoam",
 	}
 )
 
@@ -46,6 +47,17 @@ type AlertConfiguration struct {
 	Rules     interface{}
 }
 
+// AlertInputs are the values read from the cluster that the alerts of the products are built with, so that
+// the alerts can be built without a cluster
+type AlertInputs struct {
+	// ContainerCPUMetric is the recording rule of the container CPU usage, which depends on the cluster version
+	ContainerCPUMetric string
+	// GrafanaConsoleURL is the URL of the customer monitoring Grafana console
+	GrafanaConsoleURL string
+	// RedisNames are the names of the Redis instances of the installation
+	RedisNames []string
+}
+
 func (r *AlertReconcilerImpl) ReconcileAlerts(ctx context.Context, client k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
 	// If the installation was marked for deletion, delete the alerts
 	if r.Installation.DeletionTimestamp != nil {
@@ -57,13 +69,29 @@ func (r *AlertReconcilerImpl) ReconcileAlerts(ctx context.Context, client k8scli
 		return integreatlyv1alpha1.PhaseCompleted, nil
 	}
 
+	runbooks, overrides, err := getAlertSettings(ctx, client, r.Installation.Namespace, r.Log)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, err
+	}
+
+	missingRunbooks := []string{}
 	for _, alert := range r.Alerts {
-		if or, err := r.reconcileRule(ctx, client, alert); err != nil {
+		alert, effective, missing := resolveAlert(alert, runbooks, overrides)
+		missingRunbooks = append(missingRunbooks, missing...)
+
+		if errs := alertOverrideErrors(effective); len(errs) > 0 {
+			r.Log.Warningf("Alert overrides not applied", l.Fields{"productName": r.ProductName, "alertName": alert.AlertName, "errors": errs})
+		}
+
+		if or, err := r.reconcileRule(ctx, client, alert, effective); err != nil {
 			return integreatlyv1alpha1.PhaseFailed, err
 		} else if or != controllerutil.OperationResultNone {
 			r.Log.Infof("Operation result", l.Fields{"productName": r.ProductName, "alertName": alert.AlertName, "result": string(or)})
 		}
 	}
+	if len(missingRunbooks) > 0 {
+		r.Log.Warningf("Alerts without a runbook", l.Fields{"productName": r.ProductName, "alerts": missingRunbooks})
+	}
 
 	if err := r.deleteAlerts(ctx, client, r.RemovedAlerts); err != nil {
 		return integreatlyv1alpha1.PhaseFailed, err
@@ -72,7 +100,7 @@ func (r *AlertReconcilerImpl) ReconcileAlerts(ctx context.Context, client k8scli
 	return integreatlyv1alpha1.PhaseCompleted, nil
 }
 
-func (r *AlertReconcilerImpl) reconcileRule(ctx context.Context, client k8sclient.Client, alert AlertConfiguration) (controllerutil.OperationResult, error) {
+func (r *AlertReconcilerImpl) reconcileRule(ctx context.Context, client k8sclient.Client, alert AlertConfiguration, overrides map[string]EffectiveAlertOverride) (controllerutil.OperationResult, error) {
 
 	var alertRulesType interface{} = alert.Rules
 
@@ -86,25 +114,7 @@ func (r *AlertReconcilerImpl) reconcileRule(ctx context.Context, client k8sclien
 		}
 
 		return controllerutil.CreateOrUpdate(ctx, client, rule, func() error {
-			rule.ObjectMeta.Labels = map[string]string{
-				"integreatly":                   "yes",
-				config.GetOboLabelSelectorKey(): config.GetOboLabelSelector(),
-			}
-			var intervalPtr *monv1.Duration
-			if alert.Interval != "" {
-				intervalPtr = DurationPtr(alert.Interval)
-			}
-			rule.Spec = monv1.PrometheusRuleSpec{
-				Groups: []monv1.RuleGroup{
-					{
-						Name:     alert.GroupName,
-						Rules:    alertRules,
-						Interval: intervalPtr,
-					},
-				},
-			}
-
-			return nil
+			return setPrometheusRule(rule, alert, alertRules, overrides)
 		})
 	case []monitoringv1.Rule:
 		rule := &monitoringv1.PrometheusRule{
@@ -119,6 +129,11 @@ func (r *AlertReconcilerImpl) reconcileRule(ctx context.Context, client k8sclien
 				"integreatly":                   "yes",
 				config.GetOboLabelSelectorKey(): config.GetOboLabelSelector(),
 			}
+			annotations, err := setAlertOverridesAnnotation(rule.ObjectMeta.Annotations, overrides)
+			if err != nil {
+				return err
+			}
+			rule.ObjectMeta.Annotations = annotations
 			var upIntervalPtr *monitoringv1.Duration
 			if alert.Interval != "" {
 				upIntervalPtr = UpDurationPtr(alert.Interval)
@@ -141,10 +156,82 @@ func (r *AlertReconcilerImpl) reconcileRule(ctx context.Context, client k8sclien
 
 }
 
+// setPrometheusRule sets the labels, annotations and rule group of an observability PrometheusRule to the alert
+func setPrometheusRule(rule *monv1.PrometheusRule, alert AlertConfiguration, alertRules []monv1.Rule, overrides map[string]EffectiveAlertOverride) error {
+	rule.ObjectMeta.Labels = map[string]string{
+		"integreatly":                   "yes",
+		config.GetOboLabelSelectorKey(): config.GetOboLabelSelector(),
+	}
+	annotations, err := setAlertOverridesAnnotation(rule.ObjectMeta.Annotations, overrides)
+	if err != nil {
+		return err
+	}
+	rule.ObjectMeta.Annotations = annotations
+	var intervalPtr *monv1.Duration
+	if alert.Interval != "" {
+		intervalPtr = DurationPtr(alert.Interval)
+	}
+	rule.Spec = monv1.PrometheusRuleSpec{
+		Groups: []monv1.RuleGroup{
+			{
+				Name:     alert.GroupName,
+				Rules:    alertRules,
+				Interval: intervalPtr,
+			},
+		},
+	}
+	return nil
+}
+
+// resolveAlert returns the alert with the overrides and runbooks applied, the effective overrides and the
+// names of the alerts left without a runbook
+func resolveAlert(alert AlertConfiguration, runbooks *RunbookCatalogue, overrides AlertOverrides) (AlertConfiguration, map[string]EffectiveAlertOverride, []string) {
+	var missing []string
+	var effective map[string]EffectiveAlertOverride
+	alert.Rules, effective = overrides.applyOverrides(alert.Rules)
+	alert.Rules, missing = runbooks.applyRunbooks(alert.Rules)
+	return alert, effective, missing
+}
+
+// PrometheusRules returns the observability PrometheusRules of the alerts as ReconcileAlerts creates them,
+// with the runbooks and overrides applied. It reads nothing from the cluster, so the alerts can be rendered
+// offline. Alerts of other rule types are left out
+func (r *AlertReconcilerImpl) PrometheusRules(runbooks *RunbookCatalogue, overrides AlertOverrides) ([]monv1.PrometheusRule, error) {
+	rules := []monv1.PrometheusRule{}
+	for _, alert := range r.Alerts {
+		alert, effective, _ := resolveAlert(alert, runbooks, overrides)
+		alertRules, ok := alert.Rules.([]monv1.Rule)
+		if !ok {
+			continue
+		}
+		rule := monv1.PrometheusRule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      alert.AlertName,
+				Namespace: alert.Namespace,
+			},
+		}
+		if err := setPrometheusRule(&rule, alert, alertRules, effective); err != nil {
+			return nil, fmt.Errorf("failed to render alert %s: %w", alert.AlertName, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
 func (r *AlertReconcilerImpl) deleteAlerts(ctx context.Context, client k8sclient.Client, alerts []AlertConfiguration) error {
-	rule := &monitoringv1.PrometheusRule{}
+	return DeleteAlerts(ctx, client, alerts)
+}
 
+// DeleteAlerts deletes the PrometheusRules of the alerts, it is used to remove the alerts of a product that is
+// uninstalled while the observability namespace is kept
+func DeleteAlerts(ctx context.Context, client k8sclient.Client, alerts []AlertConfiguration) error {
 	for _, alert := range alerts {
+		// the rules of the observability namespace are of the observability operator
+		var rule k8sclient.Object = &monitoringv1.PrometheusRule{}
+		if _, ok := alert.Rules.([]monv1.Rule); ok {
+			rule = &monv1.PrometheusRule{}
+		}
+
 		if err := client.Get(ctx, k8sclient.ObjectKey{
 			Name:      alert.AlertName,
 			Namespace: alert.Namespace,
@@ -164,6 +251,15 @@ func (r *AlertReconcilerImpl) deleteAlerts(ctx context.Context, client k8sclient
 	return nil
 }
 
+// AlertReconcilerFunc adapts a function reconciling a set of PrometheusRules to the AlertReconciler interface
+type AlertReconcilerFunc func(ctx context.Context, client k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error)
+
+var _ AlertReconciler = AlertReconcilerFunc(nil)
+
+func (f AlertReconcilerFunc) ReconcileAlerts(ctx context.Context, client k8sclient.Client) (integreatlyv1alpha1.StatusPhase, error) {
+	return f(ctx, client)
+}
+
 type NoopAlertReconciler struct{}
 
 var _ AlertReconciler = &NoopAlertReconciler{}
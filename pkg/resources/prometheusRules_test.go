@@ -329,3 +329,34 @@ var (
 func getLogger() l.Logger {
 	return l.NewLoggerWithContext(l.Fields{})
 }
+
+func TestDeleteAlerts(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oboRule := &monv1.PrometheusRule{
+		ObjectMeta: v1.ObjectMeta{Name: "obo-alert", Namespace: "testing-namespaces-observability"},
+	}
+	rule := &monitoringv1.PrometheusRule{
+		ObjectMeta: v1.ObjectMeta{Name: "test-alert", Namespace: "testing-namespaces-test"},
+	}
+	serverClient := utils.NewTestClient(scheme, oboRule, rule)
+
+	err = DeleteAlerts(context.TODO(), serverClient, []AlertConfiguration{
+		{AlertName: "obo-alert", Namespace: "testing-namespaces-observability", Rules: []monv1.Rule{}},
+		{AlertName: "test-alert", Namespace: "testing-namespaces-test", Rules: []monitoringv1.Rule{}},
+		{AlertName: "missing-alert", Namespace: "testing-namespaces-observability", Rules: []monv1.Rule{}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKeyFromObject(oboRule), &monv1.PrometheusRule{}); !errors.IsNotFound(err) {
+		t.Fatalf("expected obo alert to be deleted, got %v", err)
+	}
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKeyFromObject(rule), &monitoringv1.PrometheusRule{}); !errors.IsNotFound(err) {
+		t.Fatalf("expected alert to be deleted, got %v", err)
+	}
+}
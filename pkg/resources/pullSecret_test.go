@@ -193,7 +193,7 @@ func TestReconcileSecretToRHMIOperatorNamespace(t *testing.T) {
 		GetOperatorNamespaceFunc: func() string {
 			return defaultOperatorNamespace
 		},
-		WriteConfigFunc: func(config config.ConfigReadable) error {
+		WriteConfigFunc: func(config config.ConfigReadable, writer string) error {
 			return nil
 		},
 	}
@@ -261,7 +261,7 @@ func TestReconcileSecretToProductNamespace(t *testing.T) {
 		GetOperatorNamespaceFunc: func() string {
 			return defaultOperatorNamespace
 		},
-		WriteConfigFunc: func(config config.ConfigReadable) error {
+		WriteConfigFunc: func(config config.ConfigReadable, writer string) error {
 			return nil
 		},
 	}
@@ -36,6 +36,7 @@ const (
 	TwentyMillionQuotaName      = "20 Million"
 	FiftyMillionQuotaName       = "50 Million"
 	OneHundredMillionQuotaName  = "100 Million"
+	DeveloperQuotaName          = "developer"
 )
 
 var (
@@ -57,6 +58,27 @@ var (
 			GrafanaName,
 		},
 	}
+
+	// the components of the developer installations run with minimal resource requests
+	developerResource = ResourceConfig{
+		Replicas: 1,
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("50m"),
+				corev1.ResourceMemory: resource.MustParse("100Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("500Mi"),
+			},
+		},
+	}
+	developerResources = map[string]ResourceConfig{
+		BackendListenerName:   developerResource,
+		BackendWorkerName:     developerResource,
+		ApicastProductionName: developerResource,
+		ApicastStagingName:    developerResource,
+	}
 )
 
 type Quota struct {
@@ -115,6 +137,20 @@ func GetQuota(ctx context.Context, c client.Client, quotaParam string, QuotaConf
 		return fmt.Errorf("wasn't able to find a quota in the quota config which matches the '%s' quota parameter", quotaParam)
 	}
 
+	setQuota(retQuota, quotaReceiver)
+	return nil
+}
+
+// SetDeveloperQuota sets the quota of the developer installations, which don't have a quota parameter.
+// It runs a single replica of each component with minimal resource requests and no rate limiting
+func SetDeveloperQuota(retQuota *Quota) {
+	setQuota(retQuota, quotaConfigReceiver{
+		Name:      DeveloperQuotaName,
+		Resources: developerResources,
+	})
+}
+
+func setQuota(retQuota *Quota, quotaReceiver quotaConfigReceiver) {
 	retQuota.name = quotaReceiver.Name
 	retQuota.productConfigs = map[v1alpha1.ProductName]QuotaProductConfig{}
 
@@ -133,7 +169,6 @@ func GetQuota(ctx context.Context, c client.Client, quotaParam string, QuotaConf
 
 	//populate rate limit configuration
 	retQuota.rateLimitConfig = quotaReceiver.RateLimit
-	return nil
 }
 
 func (s *Quota) GetProduct(productName v1alpha1.ProductName) QuotaProductConfig {
@@ -230,6 +230,30 @@ func TestGetQuota(t *testing.T) {
 	}
 }
 
+func TestSetDeveloperQuota(t *testing.T) {
+	quota := &Quota{}
+	SetDeveloperQuota(quota)
+
+	if quota.GetName() != DeveloperQuotaName {
+		t.Errorf("Expected quota name to be '%v' but got '%v'", DeveloperQuotaName, quota.GetName())
+	}
+	for _, name := range []string{ApicastProductionName, ApicastStagingName, BackendListenerName, BackendWorkerName} {
+		if replicas := quota.GetProduct(v1alpha1.Product3Scale).GetReplicas(name); replicas != 1 {
+			t.Errorf("Expected %s replicas to be '1' but got '%v'", name, replicas)
+		}
+		resources, ok := quota.GetProduct(v1alpha1.Product3Scale).GetResourceConfig(name)
+		if !ok {
+			t.Fatalf("Expected resources for %s", name)
+		}
+		if cpu := resources.Requests[corev1.ResourceCPU]; cpu.Cmp(resource.MustParse("50m")) != 0 {
+			t.Errorf("Expected %s cpu request to be '50m' but got '%v'", name, cpu.String())
+		}
+	}
+	if requestsPerUnit := quota.GetRateLimitConfig().RequestsPerUnit; requestsPerUnit != 0 {
+		t.Errorf("Expected no rate limit but got '%v' requests per unit", requestsPerUnit)
+	}
+}
+
 func TestProductConfig_Configure(t *testing.T) {
 	type fields struct {
 		productName     v1alpha1.ProductName
@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/3scale-sre/marin3r/api/envoy"
+	"github.com/integr8ly/integreatly-operator/pkg/resources/images"
 	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
 
 	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
@@ -18,21 +20,23 @@ import (
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-const (
-	EnvoyImage = "registry.redhat.io/openshift-service-mesh/proxyv2-rhel9:2.6.12-1767872298"
-)
+// the prefix of the labels and annotations that marin3r injects the envoy proxy sidecar with
+const marin3rPrefix = "marin3r.3scale.net/"
 
 type envoyProxyServer struct {
 	ctx    context.Context
 	client k8sclient.Client
 	log    l.Logger
+	image  string
 }
 
-func NewEnvoyProxyServer(ctx context.Context, client k8sclient.Client, logger l.Logger) *envoyProxyServer {
+// NewEnvoyProxyServer creates the sidecars with the envoy image, resolved with the operand image overrides
+func NewEnvoyProxyServer(ctx context.Context, client k8sclient.Client, logger l.Logger, image string) *envoyProxyServer {
 	return &envoyProxyServer{
 		ctx:    ctx,
 		client: client,
 		log:    logger,
+		image:  image,
 	}
 }
 
@@ -85,7 +89,7 @@ func (envoyProxy *envoyProxyServer) patchDeployment(deploymentName, namespace, e
 		"adding MARIN3R annotations and labels: ", l.Fields{
 			"marin3r.3scale.net/node-id":           envoyNodeID,
 			"marin3r.3scale.net/ports":             envoyPort,
-			"marin3r.3scale.net/envoy-image":       EnvoyImage,
+			"marin3r.3scale.net/envoy-image":       envoyProxy.image,
 			"marin3r.3scale.net/status":            "enabled",
 			"marin3r.3scale.net/envoy-api-version": envoy.APIv3.String(),
 		})
@@ -94,9 +98,10 @@ func (envoyProxy *envoyProxyServer) patchDeployment(deploymentName, namespace, e
 	deployment.Spec.Template.Annotations["marin3r.3scale.net/node-id"] = envoyNodeID
 	deployment.Spec.Template.Annotations["marin3r.3scale.net/ports"] = envoyPort
 	deployment.Spec.Template.Annotations["marin3r.3scale.net/envoy-api-version"] = envoy.APIv3.String()
-	deployment.Spec.Template.Annotations["marin3r.3scale.net/envoy-image"] = EnvoyImage
+	deployment.Spec.Template.Annotations["marin3r.3scale.net/envoy-image"] = envoyProxy.image
 	deployment.Spec.Template.Annotations["marin3r.3scale.net/resources.requests.cpu"] = "190m"
 	deployment.Spec.Template.Annotations["marin3r.3scale.net/resources.requests.memory"] = "90Mi"
+	images.SetResolvedImage(deployment, images.EnvoyProxy, envoyProxy.image)
 
 	if err := envoyProxy.client.Update(envoyProxy.ctx, deployment); err != nil {
 		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to apply MARIN3R labels to %s deployment: %v", deployment, err)
@@ -138,6 +143,41 @@ func (envoyProxy *envoyProxyServer) patchService(svcName, namespace, portName st
 	return integreatlyv1alpha1.PhaseCompleted, nil
 }
 
+// RemoveEnvoyProxyContainer removes the marin3r labels and annotations from the deployment, so that its pods
+// are recreated without the envoy proxy sidecar
+func RemoveEnvoyProxyContainer(ctx context.Context, client k8sclient.Client, deploymentName, namespace string) (integreatlyv1alpha1.StatusPhase, error) {
+	deployment, phase, err := getDeployment(ctx, client, deploymentName, namespace)
+	if err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to get %s deployment on namespace %s : %w", deploymentName, namespace, err)
+	}
+	if phase == integreatlyv1alpha1.PhaseAwaitingComponents {
+		return integreatlyv1alpha1.PhaseCompleted, nil
+	}
+
+	updated := false
+	for _, metadata := range []map[string]string{deployment.Spec.Template.Labels, deployment.Spec.Template.Annotations} {
+		for key := range metadata {
+			if strings.HasPrefix(key, marin3rPrefix) {
+				delete(metadata, key)
+				updated = true
+			}
+		}
+	}
+	resolvedImageAnnotation := images.ResolvedImageAnnotationPrefix + images.EnvoyProxy
+	if _, ok := deployment.Annotations[resolvedImageAnnotation]; ok {
+		delete(deployment.Annotations, resolvedImageAnnotation)
+		updated = true
+	}
+	if !updated {
+		return integreatlyv1alpha1.PhaseCompleted, nil
+	}
+
+	if err := client.Update(ctx, deployment); err != nil {
+		return integreatlyv1alpha1.PhaseFailed, fmt.Errorf("failed to remove MARIN3R labels from %s deployment: %v", deploymentName, err)
+	}
+	return integreatlyv1alpha1.PhaseCompleted, nil
+}
+
 func getDeployment(ctx context.Context, client k8sclient.Client, deploymentName string, deploymentNamespace string) (*appsv1.Deployment, integreatlyv1alpha1.StatusPhase, error) {
 	apiCastDeployment := &appsv1.Deployment{}
 
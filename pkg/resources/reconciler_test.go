@@ -38,7 +38,7 @@ func basicConfigMock() *config.ConfigReadWriterMock {
 				"URL":       "3scale.openshift-cluster.com",
 			}), nil
 		},
-		WriteConfigFunc: func(config config.ConfigReadable) error {
+		WriteConfigFunc: func(config config.ConfigReadable, writer string) error {
 			return nil
 		},
 	}
@@ -0,0 +1,93 @@
+package resources
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// RetryingAPIClient sends JSON requests to the API of an in-cluster service, retrying on
+// connection errors and server errors
+type RetryingAPIClient struct {
+	// Name describes the API in the errors returned once the retries are exhausted
+	Name       string
+	BaseURL    string
+	HTTPClient *http.Client
+	Backoff    wait.Backoff
+}
+
+// NewRetryingAPIClient creates a RetryingAPIClient that doesn't reuse connections, so that
+// the requests follow the pods of the service when they restart
+func NewRetryingAPIClient(name, baseURL string, timeout time.Duration, retries int, retryInterval time.Duration) *RetryingAPIClient {
+	return &RetryingAPIClient{
+		Name:    name,
+		BaseURL: baseURL,
+		HTTPClient: &http.Client{
+			Transport: &http.Transport{
+				DisableKeepAlives: true,
+				IdleConnTimeout:   timeout,
+			},
+			Timeout: timeout,
+		},
+		Backoff: wait.Backoff{
+			Duration: retryInterval,
+			Factor:   2,
+			Steps:    retries,
+		},
+	}
+}
+
+// Do sends a request to BaseURL/path with body as its JSON content, and decodes the
+// response body into out when it is set
+func (c *RetryingAPIClient) Do(ctx context.Context, method, path string, body []byte, out interface{}) error {
+	endpoint := fmt.Sprintf("%s/%s", c.BaseURL, path)
+
+	var lastErr error
+	err := wait.ExponentialBackoffWithContext(ctx, c.Backoff, func(ctx context.Context) (bool, error) {
+		req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return false, err
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			lastErr = err
+			return false, nil
+		}
+		defer resp.Body.Close()
+
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			lastErr = err
+			return false, nil
+		}
+
+		if resp.StatusCode >= http.StatusInternalServerError {
+			lastErr = fmt.Errorf("%s %s returned %d: %s", method, endpoint, resp.StatusCode, respBody)
+			return false, nil
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return false, fmt.Errorf("%s %s returned %d: %s", method, endpoint, resp.StatusCode, respBody)
+		}
+
+		if out != nil {
+			if err := json.Unmarshal(respBody, out); err != nil {
+				return false, fmt.Errorf("failed to decode response of %s %s: %w", method, endpoint, err)
+			}
+		}
+		return true, nil
+	})
+	if wait.Interrupted(err) && lastErr != nil {
+		return fmt.Errorf("%s request failed after retries: %w", c.Name, lastErr)
+	}
+	return err
+}
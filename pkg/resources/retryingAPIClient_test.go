@@ -0,0 +1,89 @@
+package resources
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestRetryingAPIClient(t *testing.T) {
+	scenarios := []struct {
+		Name      string
+		Statuses  []int
+		Body      []byte
+		WantCalls int
+		WantErr   string
+	}{
+		{
+			Name:      "the response is decoded",
+			Statuses:  []int{http.StatusOK},
+			Body:      []byte(`{"name":"test"}`),
+			WantCalls: 1,
+		},
+		{
+			Name:      "server errors are retried",
+			Statuses:  []int{http.StatusServiceUnavailable, http.StatusOK},
+			WantCalls: 2,
+		},
+		{
+			Name:      "the last server error is returned once the retries are exhausted",
+			Statuses:  []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError},
+			WantCalls: 3,
+			WantErr:   "test API request failed after retries",
+		},
+		{
+			Name:      "client errors are not retried",
+			Statuses:  []int{http.StatusBadRequest},
+			WantCalls: 1,
+			WantErr:   "returned 400",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := scenario.Statuses[calls]
+				calls++
+				if r.URL.Path != "/api/items" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+				if body, _ := io.ReadAll(r.Body); string(body) != string(scenario.Body) {
+					t.Errorf("unexpected body %s", body)
+				}
+				if scenario.Body != nil && r.Header.Get("Content-Type") != "application/json" {
+					t.Errorf("unexpected content type %s", r.Header.Get("Content-Type"))
+				}
+				w.WriteHeader(status)
+				_, _ = w.Write([]byte(`{"name":"test"}`))
+			}))
+			defer server.Close()
+
+			client := NewRetryingAPIClient("test API", server.URL, time.Second, 3, time.Millisecond)
+			client.Backoff = wait.Backoff{Duration: time.Millisecond, Factor: 1, Steps: 3}
+
+			out := struct {
+				Name string `json:"name"`
+			}{}
+			err := client.Do(context.TODO(), http.MethodPost, "api/items", scenario.Body, &out)
+			if scenario.WantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if scenario.WantErr != "" && (err == nil || !strings.Contains(err.Error(), scenario.WantErr)) {
+				t.Fatalf("expected error containing %q, got %v", scenario.WantErr, err)
+			}
+			if calls != scenario.WantCalls {
+				t.Fatalf("expected %d calls, got %d", scenario.WantCalls, calls)
+			}
+			if err == nil && out.Name != "test" {
+				t.Fatalf("expected the response to be decoded, got %v", out)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monv1 "github.com/rhobs/obo-prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// RunbookConfigMapName is the ConfigMap in the installation namespace that points the
+	// sop_url annotation of the alerts at other runbooks
+	RunbookConfigMapName = "alert-runbooks"
+	runbookBaseURLKey    = "baseURL"
+	runbookOverridesKey  = "runbooks"
+
+	SOPURLAnnotation = "sop_url"
+)
+
+// RunbookCatalogue resolves the runbook of each alert. Without a base URL or an override
+// the runbook the alert is defined with is used
+type RunbookCatalogue struct {
+	// BaseURL replaces the location of the default runbooks, keeping their document names
+	BaseURL string
+	// Runbooks maps alert names to their runbook. Runbooks relative to the base URL are
+	// joined with it
+	Runbooks map[string]string
+}
+
+// GetRunbookCatalogue reads the runbook catalogue of the installation. A missing ConfigMap
+// results in the default runbooks
+func GetRunbookCatalogue(ctx context.Context, client k8sclient.Client, namespace string) (*RunbookCatalogue, error) {
+	configMap, err := getAlertSettingsConfigMap(ctx, client, RunbookConfigMapName, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRunbookCatalogue(configMap)
+}
+
+// NewRunbookCatalogue parses the runbook catalogue from the ConfigMap. A nil ConfigMap results
+// in the default runbooks
+func NewRunbookCatalogue(configMap *corev1.ConfigMap) (*RunbookCatalogue, error) {
+	catalogue := &RunbookCatalogue{Runbooks: map[string]string{}}
+	if configMap == nil {
+		return catalogue, nil
+	}
+
+	catalogue.BaseURL = strings.TrimSuffix(configMap.Data[runbookBaseURLKey], "/")
+	if catalogue.BaseURL != "" {
+		if _, err := url.ParseRequestURI(catalogue.BaseURL); err != nil {
+			return nil, fmt.Errorf("invalid %s in %s config map: %w", runbookBaseURLKey, RunbookConfigMapName, err)
+		}
+	}
+	if runbooks, ok := configMap.Data[runbookOverridesKey]; ok {
+		if err := json.Unmarshal([]byte(runbooks), &catalogue.Runbooks); err != nil {
+			return nil, fmt.Errorf("failed to parse %s key of %s config map: %w", runbookOverridesKey, RunbookConfigMapName, err)
+		}
+	}
+	for alert, runbook := range catalogue.Runbooks {
+		if !isAbsoluteURL(runbook) && catalogue.BaseURL == "" {
+			return nil, fmt.Errorf("runbook %s of alert %s is relative but no %s is set", runbook, alert, runbookBaseURLKey)
+		}
+	}
+
+	return catalogue, nil
+}
+
+// URL returns the runbook of the alert, or an empty string if it has none
+func (c *RunbookCatalogue) URL(alertName, defaultURL string) string {
+	if runbook, ok := c.Runbooks[alertName]; ok {
+		if isAbsoluteURL(runbook) {
+			return runbook
+		}
+		return c.BaseURL + "/" + strings.TrimPrefix(runbook, "/")
+	}
+	if defaultURL == "" || c.BaseURL == "" {
+		return defaultURL
+	}
+	return c.BaseURL + "/" + path.Base(defaultURL)
+}
+
+// applyRunbooks returns a copy of the rules with their sop_url annotation resolved by the
+// catalogue, and the names of the alerts left without a runbook
+func (c *RunbookCatalogue) applyRunbooks(rules interface{}) (interface{}, []string) {
+	missing := []string{}
+	resolve := func(alert string, annotations map[string]string) map[string]string {
+		if alert == "" {
+			return annotations
+		}
+		resolved := map[string]string{}
+		for key, value := range annotations {
+			resolved[key] = value
+		}
+		if runbook := c.URL(alert, annotations[SOPURLAnnotation]); runbook != "" {
+			resolved[SOPURLAnnotation] = runbook
+		} else {
+			missing = append(missing, alert)
+		}
+		return resolved
+	}
+
+	switch alertRules := rules.(type) {
+	case []monv1.Rule:
+		resolved := make([]monv1.Rule, len(alertRules))
+		for i, rule := range alertRules {
+			rule.Annotations = resolve(rule.Alert, rule.Annotations)
+			resolved[i] = rule
+		}
+		return resolved, missing
+	case []monitoringv1.Rule:
+		resolved := make([]monitoringv1.Rule, len(alertRules))
+		for i, rule := range alertRules {
+			rule.Annotations = resolve(rule.Alert, rule.Annotations)
+			resolved[i] = rule
+		}
+		return resolved, missing
+	default:
+		return rules, missing
+	}
+}
+
+func isAbsoluteURL(runbook string) bool {
+	u, err := url.Parse(runbook)
+	return err == nil && u.IsAbs()
+}
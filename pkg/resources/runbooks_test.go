@@ -0,0 +1,174 @@
+package resources
+
+import (
+	"context"
+	"testing"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/utils"
+	monv1 "github.com/rhobs/obo-prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func runbookConfigMap(data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: v1.ObjectMeta{Name: RunbookConfigMapName, Namespace: "testing-namespaces-test"},
+		Data:       data,
+	}
+}
+
+func TestRunbookCatalogueURL(t *testing.T) {
+	scenarios := []struct {
+		Name       string
+		ConfigMap  *corev1.ConfigMap
+		Alert      string
+		DefaultURL string
+		WantURL    string
+		WantErr    bool
+	}{
+		{
+			Name:       "default runbooks are used without a catalogue",
+			Alert:      "ThreeScaleSystemAppPod",
+			DefaultURL: SopUrlSystemAppPodsDown,
+			WantURL:    SopUrlSystemAppPodsDown,
+		},
+		{
+			Name:       "default runbooks are moved to the base URL",
+			ConfigMap:  runbookConfigMap(map[string]string{runbookBaseURLKey: "https://runbooks.example.com/rhoam/"}),
+			Alert:      "ThreeScaleSystemAppPod",
+			DefaultURL: SopUrlSystemAppPodsDown,
+			WantURL:    "https://runbooks.example.com/rhoam/ThreeScaleSystemAppPod.md",
+		},
+		{
+			Name: "relative overrides are joined with the base URL",
+			ConfigMap: runbookConfigMap(map[string]string{
+				runbookBaseURLKey:   "https://runbooks.example.com/rhoam",
+				runbookOverridesKey: `{"ThreeScaleSystemAppPod": "3scale/system-app.md"}`,
+			}),
+			Alert:      "ThreeScaleSystemAppPod",
+			DefaultURL: SopUrlSystemAppPodsDown,
+			WantURL:    "https://runbooks.example.com/rhoam/3scale/system-app.md",
+		},
+		{
+			Name:      "absolute overrides are used as they are",
+			ConfigMap: runbookConfigMap(map[string]string{runbookOverridesKey: `{"ThreeScaleUserCreationFailed": "https://wiki.example.com/users"}`}),
+			Alert:     "ThreeScaleUserCreationFailed",
+			WantURL:   "https://wiki.example.com/users",
+		},
+		{
+			Name:      "alerts without a default runbook are not moved to the base URL",
+			ConfigMap: runbookConfigMap(map[string]string{runbookBaseURLKey: "https://runbooks.example.com/rhoam"}),
+			Alert:     "ThreeScaleUserCreationFailed",
+			WantURL:   "",
+		},
+		{
+			Name:      "relative overrides require a base URL",
+			ConfigMap: runbookConfigMap(map[string]string{runbookOverridesKey: `{"ThreeScaleSystemAppPod": "system-app.md"}`}),
+			WantErr:   true,
+		},
+		{
+			Name:      "invalid overrides are rejected",
+			ConfigMap: runbookConfigMap(map[string]string{runbookOverridesKey: `["system-app.md"]`}),
+			WantErr:   true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			scheme, err := utils.NewTestScheme()
+			if err != nil {
+				t.Fatal(err)
+			}
+			objects := []runtime.Object{}
+			if scenario.ConfigMap != nil {
+				objects = append(objects, scenario.ConfigMap)
+			}
+
+			catalogue, err := GetRunbookCatalogue(context.TODO(), utils.NewTestClient(scheme, objects...), "testing-namespaces-test")
+			if (err != nil) != scenario.WantErr {
+				t.Fatalf("unexpected error %v, wantErr %v", err, scenario.WantErr)
+			}
+			if scenario.WantErr {
+				return
+			}
+
+			if got := catalogue.URL(scenario.Alert, scenario.DefaultURL); got != scenario.WantURL {
+				t.Errorf("expected runbook %q, got %q", scenario.WantURL, got)
+			}
+		})
+	}
+}
+
+func TestReconcileAlertsRunbooks(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	installation := &integreatlyv1alpha1.RHMI{
+		ObjectMeta: v1.ObjectMeta{Name: "rhoam", Namespace: "testing-namespaces-test"},
+	}
+	serverClient := utils.NewTestClient(scheme, installation, runbookConfigMap(map[string]string{
+		runbookBaseURLKey:   "https://runbooks.example.com/rhoam",
+		runbookOverridesKey: `{"TestRule": "test-rule.md"}`,
+	}))
+
+	alertReconciler := &AlertReconcilerImpl{
+		ProductName:  "Test",
+		Alerts:       alerts,
+		Installation: installation,
+		Log:          getLogger(),
+	}
+	if _, err := alertReconciler.ReconcileAlerts(context.TODO(), serverClient); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	rule := &monv1.PrometheusRule{}
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: "test-alert", Namespace: "testing-namespaces-test"}, rule); err != nil {
+		t.Fatal(err)
+	}
+	if got := rule.Spec.Groups[0].Rules[0].Annotations[SOPURLAnnotation]; got != "https://runbooks.example.com/rhoam/test-rule.md" {
+		t.Errorf("expected the runbook of the catalogue, got %q", got)
+	}
+	if _, ok := rules[0].Annotations[SOPURLAnnotation]; ok {
+		t.Error("expected the alert configuration to be left unchanged")
+	}
+}
+
+func TestReconcileAlertsInvalidRunbooks(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	installation := &integreatlyv1alpha1.RHMI{
+		ObjectMeta: v1.ObjectMeta{Name: "rhoam", Namespace: "testing-namespaces-test"},
+	}
+	serverClient := utils.NewTestClient(scheme, installation, runbookConfigMap(map[string]string{
+		runbookOverridesKey: `["test-rule.md"]`,
+	}))
+	alertRules := []monv1.Rule{rules[0]}
+	alertRules[0].Annotations = map[string]string{SOPURLAnnotation: SopUrlSystemAppPodsDown}
+
+	alertReconciler := &AlertReconcilerImpl{
+		ProductName: "Test",
+		Alerts: []AlertConfiguration{
+			{AlertName: "test-alert", GroupName: "test-group", Namespace: "testing-namespaces-test", Rules: alertRules},
+		},
+		Installation: installation,
+		Log:          getLogger(),
+	}
+	phase, err := alertReconciler.ReconcileAlerts(context.TODO(), serverClient)
+	if err != nil || phase != integreatlyv1alpha1.PhaseCompleted {
+		t.Fatalf("expected an invalid catalogue not to block the alerts, got phase %s and error %v", phase, err)
+	}
+
+	rule := &monv1.PrometheusRule{}
+	if err := serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: "test-alert", Namespace: "testing-namespaces-test"}, rule); err != nil {
+		t.Fatal(err)
+	}
+	if got := rule.Spec.Groups[0].Rules[0].Annotations[SOPURLAnnotation]; got != SopUrlSystemAppPodsDown {
+		t.Errorf("expected the default runbook, got %q", got)
+	}
+}
@@ -57,4 +57,9 @@ const (
 	SopUrlPersistentVolumeErrors                               = "https://gitlab.cee.redhat.com/rhcloudservices/integreatly-help/blob/master/sops/rhoam/alerts/pvc_storage.asciidoc#persistentvolumeerrors"
 	SopApiManagementTenantCRFailed                             = "https://gitlab.cee.redhat.com/rhcloudservices/integreatly-help/blob/master/sops/rhoam/alerts/ApiManagementTenantCRFailed.asciidoc"
 	SopUrlRHOAMCloudResourceOperatorMetricsMissing             = "https://gitlab.cee.redhat.com/rhcloudservices/integreatly-help/blob/master/sops/rhoam/alerts/RHOAMCloudResourceOperatorMetricsMissing.asciidoc"
+	SopUrlRHOAMApiUsageThresholdExceeded                       = "https://gitlab.cee.redhat.com/rhcloudservices/integreatly-help/blob/master/sops/rhoam/alerts/RHOAMApiUsageThresholdExceeded.asciidoc"
+	SopUrlRHOAMApiUsageOverLimit                               = "https://gitlab.cee.redhat.com/rhcloudservices/integreatly-help/blob/master/sops/rhoam/alerts/RHOAMApiUsageOverLimit.asciidoc"
+	SopUrlRHOAMApiUsageRejectedRequestsMismatch                = "https://gitlab.cee.redhat.com/rhcloudservices/integreatly-help/blob/master/sops/rhoam/alerts/RHOAMApiUsageRejectedRequestsMismatch.asciidoc"
+	SopUrlRHOAMCSVRequirementsNotMet                           = "https://gitlab.cee.redhat.com/rhcloudservices/integreatly-help/blob/master/sops/rhoam/alerts/RHOAMCSVRequirementsNotMet.asciidoc"
+	SopUrlThreeScaleUserActionFailed                           = "https://gitlab.cee.redhat.com/rhcloudservices/integreatly-help/blob/master/sops/rhoam/alerts/ThreeScaleUserActionFailed.asciidoc"
 )
@@ -0,0 +1,105 @@
+package sts
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awssts "github.com/aws/aws-sdk-go-v2/service/sts"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// EndpointEnvKey overrides the STS endpoint the credentials are checked against, e.g. to use a local stub
+	// instead of AWS
+	EndpointEnvKey = "STS_ENDPOINT"
+
+	// CROServiceAccountName is the service account the cloud resources operator assumes the role with
+	CROServiceAccountName = "cloud-resource-operator"
+
+	// webIdentityTokenAudience is the audience of the projected token of the cloud resources operator
+	webIdentityTokenAudience = "openshift"
+	// checkTokenExpiration is the shortest expiration allowed, the token is only used by the check
+	checkTokenExpiration = 600
+
+	// checkSessionDuration is the shortest session allowed, the credentials of the check are not used
+	checkSessionDuration = 900
+)
+
+// CredentialsChecker checks that the STS role can be assumed with a web identity token of the service account of
+// the cloud resources operator, the way the cloud resources operator assumes it
+type CredentialsChecker struct {
+	HTTPClient *http.Client
+	// Endpoint of STS, the AWS endpoint of the partition of the role is used when empty
+	Endpoint string
+}
+
+// NewCredentialsChecker creates a CredentialsChecker using the STS endpoint of the EndpointEnvKey environment
+// variable when set
+func NewCredentialsChecker() *CredentialsChecker {
+	return &CredentialsChecker{
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		Endpoint:   os.Getenv(EndpointEnvKey),
+	}
+}
+
+// RequestServiceAccountToken mints a web identity token for the service account with the TokenRequest API, with
+// the audience of the token projected into the pods of the cloud resources operator
+func RequestServiceAccountToken(ctx context.Context, client k8sclient.Client, namespace, name string) (string, error) {
+	expiration := int64(checkTokenExpiration)
+	serviceAccount := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	tokenRequest := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			Audiences:         []string{webIdentityTokenAudience},
+			ExpirationSeconds: &expiration,
+		},
+	}
+	if err := client.SubResource("token").Create(ctx, serviceAccount, tokenRequest); err != nil {
+		return "", fmt.Errorf("failed to request a token for service account %s/%s: %w", namespace, name, err)
+	}
+	return tokenRequest.Status.Token, nil
+}
+
+// Check returns why the role can't be assumed with the web identity token, or nil when the credentials work
+func (c *CredentialsChecker) Check(ctx context.Context, roleARN, token string) error {
+	if strings.TrimSpace(token) == "" {
+		return fmt.Errorf("web identity token is empty")
+	}
+
+	options := awssts.Options{
+		Region:           regionForRoleARN(roleARN),
+		RetryMaxAttempts: 1,
+	}
+	if c.HTTPClient != nil {
+		options.HTTPClient = c.HTTPClient
+	}
+	if c.Endpoint != "" {
+		options.BaseEndpoint = aws.String(c.Endpoint)
+	}
+
+	_, err := awssts.New(options).AssumeRoleWithWebIdentity(ctx, &awssts.AssumeRoleWithWebIdentityInput{
+		RoleArn:          aws.String(roleARN),
+		RoleSessionName:  aws.String(RoleSessionName),
+		WebIdentityToken: aws.String(strings.TrimSpace(token)),
+		DurationSeconds:  aws.Int32(checkSessionDuration),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to assume role %s with web identity: %w", roleARN, err)
+	}
+	return nil
+}
+
+// regionForRoleARN returns a region of the partition of the role, as the roles are global
+func regionForRoleARN(roleARN string) string {
+	if strings.HasPrefix(roleARN, "arn:aws-us-gov:") {
+		return "us-gov-west-1"
+	}
+	return "us-east-1"
+}
@@ -0,0 +1,123 @@
+package sts
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/integr8ly/integreatly-operator/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	assumeRoleResponse = `<AssumeRoleWithWebIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleWithWebIdentityResult>
+    <Credentials>
+      <AccessKeyId>ASIAEXAMPLE</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>session</SessionToken>
+      <Expiration>2030-01-01T00:00:00Z</Expiration>
+    </Credentials>
+  </AssumeRoleWithWebIdentityResult>
+  <ResponseMetadata><RequestId>request-id</RequestId></ResponseMetadata>
+</AssumeRoleWithWebIdentityResponse>`
+	expiredTokenResponse = `<ErrorResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <Error>
+    <Type>Sender</Type>
+    <Code>ExpiredTokenException</Code>
+    <Message>Token expired</Message>
+  </Error>
+  <RequestId>request-id</RequestId>
+</ErrorResponse>`
+)
+
+func TestCredentialsChecker_Check(t *testing.T) {
+	roleARN := "arn:aws:iam::123456789012:role/rhoam"
+
+	scenarios := []struct {
+		Name       string
+		Token      string
+		StatusCode int
+		Response   string
+		WantErr    string
+	}{
+		{
+			Name:       "role assumed with the token",
+			Token:      "token",
+			StatusCode: http.StatusOK,
+			Response:   assumeRoleResponse,
+		},
+		{
+			Name:    "token empty",
+			Token:   " ",
+			WantErr: "is empty",
+		},
+		{
+			Name:       "role can't be assumed",
+			Token:      "token",
+			StatusCode: http.StatusBadRequest,
+			Response:   expiredTokenResponse,
+			WantErr:    "ExpiredTokenException",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if err := r.ParseForm(); err != nil {
+					t.Errorf("failed to parse request: %v", err)
+				}
+				if r.Form.Get("Action") != "AssumeRoleWithWebIdentity" || r.Form.Get("RoleArn") != roleARN || r.Form.Get("WebIdentityToken") != "token" {
+					t.Errorf("unexpected request %v", r.Form)
+				}
+				w.Header().Set("Content-Type", "text/xml")
+				w.WriteHeader(scenario.StatusCode)
+				_, _ = fmt.Fprint(w, scenario.Response)
+			}))
+			defer server.Close()
+
+			checker := &CredentialsChecker{
+				HTTPClient: server.Client(),
+				Endpoint:   server.URL,
+			}
+			err := checker.Check(context.TODO(), roleARN, scenario.Token)
+			if scenario.WantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if scenario.WantErr != "" && (err == nil || !strings.Contains(err.Error(), scenario.WantErr)) {
+				t.Fatalf("expected error containing %q, got %v", scenario.WantErr, err)
+			}
+			if scenario.StatusCode == 0 && requests != 0 {
+				t.Fatalf("expected no request to STS, got %d", requests)
+			}
+		})
+	}
+}
+
+func TestRequestServiceAccountToken(t *testing.T) {
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	serviceAccount := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: CROServiceAccountName, Namespace: "redhat-rhoam-cloud-resources-operator"},
+	}
+
+	token, err := RequestServiceAccountToken(context.TODO(), utils.NewTestClient(scheme, serviceAccount), serviceAccount.Namespace, serviceAccount.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token == "" {
+		t.Error("expected a token")
+	}
+
+	if _, err := RequestServiceAccountToken(context.TODO(), utils.NewTestClient(scheme), serviceAccount.Namespace, serviceAccount.Name); err == nil {
+		t.Error("expected an error without the service account")
+	}
+}
@@ -0,0 +1,251 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	l "github.com/integr8ly/integreatly-operator/pkg/resources/logger"
+	usersv1 "github.com/openshift/api/user/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// UserAuditTrailConfigMapName is the ConfigMap in the installation namespace that keeps the latest
+	// user provisioning actions. The record is only kept when the ConfigMap is created with maxEntries
+	UserAuditTrailConfigMapName = "user-audit-trail"
+	auditMaxEntriesKey          = "maxEntries"
+	auditEntriesKey             = "entries"
+	// keeps the record well below the size limit of a ConfigMap
+	maxAuditEntries = 1000
+
+	AuditSystemThreeScale = "3scale"
+	AuditSystemRHSSO      = "rhsso"
+	AuditSystemRHSSOUser  = "rhssouser"
+
+	AuditActionCreated  = "created"
+	AuditActionUpdated  = "updated"
+	AuditActionPromoted = "promoted"
+	AuditActionDemoted  = "demoted"
+	AuditActionDeleted  = "deleted"
+)
+
+// auditLog writes the audit entries as JSON, regardless of the format of the operator logs, so that
+// they can be collected and parsed on their own
+var auditLog = &logrus.Logger{
+	Out:       os.Stdout,
+	Formatter: &logrus.JSONFormatter{},
+	Hooks:     make(logrus.LevelHooks),
+	Level:     logrus.InfoLevel,
+}
+
+// AuditEntry records an action taken on a user in one of the products
+type AuditEntry struct {
+	Time     time.Time `json:"time"`
+	System   string    `json:"system"`
+	Action   string    `json:"action"`
+	Username string    `json:"username"`
+	Reason   string    `json:"reason,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Auditor records the user provisioning actions as events on the OpenShift user, as JSON audit logs
+// and, when enabled, in the user audit trail ConfigMap. The OpenShift users and the ConfigMap are read
+// once by each Auditor, so it is created for each reconcile
+type Auditor struct {
+	client       k8sclient.Client
+	recorder     record.EventRecorder
+	installation *integreatlyv1alpha1.RHMI
+	log          l.Logger
+
+	mu sync.Mutex
+	// users are the OpenShift users by name, nil until they are listed
+	users map[string]*usersv1.User
+	// trail is the user audit trail ConfigMap as last read or updated, nil when it does not exist
+	trail     *corev1.ConfigMap
+	trailRead bool
+}
+
+func NewAuditor(client k8sclient.Client, recorder record.EventRecorder, installation *integreatlyv1alpha1.RHMI) *Auditor {
+	return &Auditor{
+		client:       client,
+		recorder:     recorder,
+		installation: installation,
+		log:          l.NewLoggerWithContext(l.Fields{l.ComponentLogContext: "user-audit"}),
+	}
+}
+
+// Record records the action, err is the error the action failed with if any. Failing to keep the
+// audit trail is logged and does not fail the provisioning
+func (a *Auditor) Record(ctx context.Context, system, action, username, reason string, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	entry := AuditEntry{
+		Time:     time.Now().UTC(),
+		System:   system,
+		Action:   action,
+		Username: username,
+		Reason:   reason,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	auditLog.WithFields(logrus.Fields{
+		"audit":    "user-provisioning",
+		"system":   entry.System,
+		"action":   entry.Action,
+		"username": entry.Username,
+		"reason":   entry.Reason,
+		"error":    entry.Error,
+	}).Info("user provisioning action")
+
+	a.recordEvent(ctx, entry)
+
+	if err := a.appendAuditTrail(ctx, entry); err != nil {
+		a.log.Warningf("Failed to update the user audit trail", l.Fields{"username": username, "error": err})
+	}
+}
+
+// recordEvent emits the entry on the OpenShift user, or on the installation when the user no
+// longer exists
+func (a *Auditor) recordEvent(ctx context.Context, entry AuditEntry) {
+	if a.recorder == nil {
+		return
+	}
+
+	var object runtime.Object
+	if user := a.getUser(ctx, entry.Username); user != nil {
+		object = user
+	} else if a.installation != nil {
+		object = a.installation
+	} else {
+		return
+	}
+
+	message := fmt.Sprintf("%s user %s %s", entry.System, entry.Username, entry.Action)
+	if entry.Reason != "" {
+		message = fmt.Sprintf("%s: %s", message, entry.Reason)
+	}
+	if entry.Error != "" {
+		a.recorder.Event(object, corev1.EventTypeWarning, integreatlyv1alpha1.EventUserProvisioningError, fmt.Sprintf("%s failed: %s", message, entry.Error))
+		return
+	}
+	a.recorder.Event(object, corev1.EventTypeNormal, integreatlyv1alpha1.EventUserProvisioned, message)
+}
+
+// getUser returns the OpenShift user, or nil when it no longer exists or the users can't be listed
+func (a *Auditor) getUser(ctx context.Context, username string) *usersv1.User {
+	if a.users == nil {
+		users := &usersv1.UserList{}
+		if err := a.client.List(ctx, users); err != nil {
+			a.log.Warningf("Failed to list the users", l.Fields{"error": err})
+			return nil
+		}
+		a.users = map[string]*usersv1.User{}
+		for i := range users.Items {
+			a.users[users.Items[i].Name] = &users.Items[i]
+		}
+	}
+	return a.users[username]
+}
+
+// appendAuditTrail adds the entry to the user audit trail ConfigMap, keeping the latest maxEntries. The
+// ConfigMap is read again when it was updated since it was last read
+func (a *Auditor) appendAuditTrail(ctx context.Context, entry AuditEntry) error {
+	if a.installation == nil {
+		return nil
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap, err := a.getAuditTrail(ctx)
+		if err != nil || configMap == nil {
+			return err
+		}
+
+		maxEntries, err := getAuditMaxEntries(configMap)
+		if err != nil || maxEntries == 0 {
+			return err
+		}
+
+		entries, err := GetAuditTrail(configMap)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+		if len(entries) > maxEntries {
+			entries = entries[len(entries)-maxEntries:]
+		}
+
+		value, err := json.Marshal(entries)
+		if err != nil {
+			return fmt.Errorf("failed to encode the user audit trail: %w", err)
+		}
+		configMap = configMap.DeepCopy()
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+		configMap.Data[auditEntriesKey] = string(value)
+		if err := a.client.Update(ctx, configMap); err != nil {
+			a.trailRead = false
+			return fmt.Errorf("failed to update %s config map: %w", UserAuditTrailConfigMapName, err)
+		}
+		a.trail = configMap
+		return nil
+	})
+}
+
+// getAuditTrail returns the user audit trail ConfigMap, or nil when it does not exist
+func (a *Auditor) getAuditTrail(ctx context.Context) (*corev1.ConfigMap, error) {
+	if a.trailRead {
+		return a.trail, nil
+	}
+	configMap := &corev1.ConfigMap{}
+	if err := a.client.Get(ctx, k8sclient.ObjectKey{Name: UserAuditTrailConfigMapName, Namespace: a.installation.Namespace}, configMap); err != nil {
+		if !k8serr.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get %s config map: %w", UserAuditTrailConfigMapName, err)
+		}
+		configMap = nil
+	}
+	a.trail, a.trailRead = configMap, true
+	return configMap, nil
+}
+
+// GetAuditTrail returns the entries of the user audit trail ConfigMap, oldest first
+func GetAuditTrail(configMap *corev1.ConfigMap) ([]AuditEntry, error) {
+	entries := []AuditEntry{}
+	value, ok := configMap.Data[auditEntriesKey]
+	if !ok || value == "" {
+		return entries, nil
+	}
+	if err := json.Unmarshal([]byte(value), &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse %s key of %s config map: %w", auditEntriesKey, UserAuditTrailConfigMapName, err)
+	}
+	return entries, nil
+}
+
+func getAuditMaxEntries(configMap *corev1.ConfigMap) (int, error) {
+	value, ok := configMap.Data[auditMaxEntriesKey]
+	if !ok {
+		return 0, nil
+	}
+	maxEntries, err := strconv.Atoi(value)
+	if err != nil || maxEntries < 0 {
+		return 0, fmt.Errorf("invalid %s in %s config map, must be a positive number: %s", auditMaxEntriesKey, UserAuditTrailConfigMapName, value)
+	}
+	if maxEntries > maxAuditEntries {
+		return maxAuditEntries, nil
+	}
+	return maxEntries, nil
+}
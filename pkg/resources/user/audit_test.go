@@ -0,0 +1,177 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	integreatlyv1alpha1 "github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/pkg/client"
+	"github.com/integr8ly/integreatly-operator/utils"
+	userv1 "github.com/openshift/api/user/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestAuditorRecord(t *testing.T) {
+	installation := &integreatlyv1alpha1.RHMI{
+		ObjectMeta: v1.ObjectMeta{Name: "rhoam", Namespace: "testing-namespaces-test"},
+	}
+
+	scenarios := []struct {
+		Name        string
+		ConfigMap   *corev1.ConfigMap
+		WantEntries []string
+	}{
+		{
+			Name: "no record is kept without a config map",
+		},
+		{
+			Name: "the latest entries are kept",
+			ConfigMap: &corev1.ConfigMap{
+				ObjectMeta: v1.ObjectMeta{Name: UserAuditTrailConfigMapName, Namespace: installation.Namespace},
+				Data:       map[string]string{auditMaxEntriesKey: "2"},
+			},
+			WantEntries: []string{"bob", "deleted-user"},
+		},
+		{
+			Name: "no record is kept with an invalid maximum",
+			ConfigMap: &corev1.ConfigMap{
+				ObjectMeta: v1.ObjectMeta{Name: UserAuditTrailConfigMapName, Namespace: installation.Namespace},
+				Data:       map[string]string{auditMaxEntriesKey: "all"},
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			scheme, err := utils.NewTestScheme()
+			if err != nil {
+				t.Fatal(err)
+			}
+			objects := []runtime.Object{
+				installation,
+				&userv1.User{ObjectMeta: v1.ObjectMeta{Name: "alice"}},
+				&userv1.User{ObjectMeta: v1.ObjectMeta{Name: "bob"}},
+			}
+			if scenario.ConfigMap != nil {
+				objects = append(objects, scenario.ConfigMap)
+			}
+			serverClient := utils.NewTestClient(scheme, objects...)
+			recorder := record.NewFakeRecorder(10)
+
+			auditor := NewAuditor(serverClient, recorder, installation)
+			auditor.Record(context.TODO(), AuditSystemThreeScale, AuditActionPromoted, "alice", "member of dedicated-admins", nil)
+			auditor.Record(context.TODO(), AuditSystemRHSSOUser, AuditActionDemoted, "bob", "", nil)
+			auditor.Record(context.TODO(), AuditSystemThreeScale, AuditActionDeleted, "deleted-user", "", errors.New("status code 500"))
+
+			if len(recorder.Events) != 3 {
+				t.Fatalf("expected an event per action, got %d", len(recorder.Events))
+			}
+			for _, want := range []string{"Normal UserProvisioned 3scale user alice promoted: member of dedicated-admins", "Normal UserProvisioned rhssouser user bob demoted", "Warning UserProvisioningFailed 3scale user deleted-user deleted failed: status code 500"} {
+				if event := <-recorder.Events; event != want {
+					t.Errorf("expected event %q, got %q", want, event)
+				}
+			}
+
+			configMap := &corev1.ConfigMap{}
+			err = serverClient.Get(context.TODO(), k8sclient.ObjectKey{Name: UserAuditTrailConfigMapName, Namespace: installation.Namespace}, configMap)
+			if scenario.ConfigMap == nil {
+				if !k8serr.IsNotFound(err) {
+					t.Fatalf("expected no audit trail to be created, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			entries, err := GetAuditTrail(configMap)
+			if err != nil {
+				t.Fatal(err)
+			}
+			usernames := []string{}
+			for _, entry := range entries {
+				usernames = append(usernames, entry.Username)
+			}
+			if strings.Join(usernames, ",") != strings.Join(scenario.WantEntries, ",") {
+				t.Errorf("expected entries for %v, got %v", scenario.WantEntries, entries)
+			}
+			if len(entries) > 0 && entries[len(entries)-1].Error != "status code 500" {
+				t.Errorf("expected the error of the failed action to be recorded, got %+v", entries[len(entries)-1])
+			}
+		})
+	}
+}
+
+func TestAuditorRecordReads(t *testing.T) {
+	installation := &integreatlyv1alpha1.RHMI{
+		ObjectMeta: v1.ObjectMeta{Name: "rhoam", Namespace: "testing-namespaces-test"},
+	}
+	scheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeClient := utils.NewTestClient(scheme,
+		installation,
+		&userv1.User{ObjectMeta: v1.ObjectMeta{Name: "alice"}},
+		&corev1.ConfigMap{
+			ObjectMeta: v1.ObjectMeta{Name: UserAuditTrailConfigMapName, Namespace: installation.Namespace},
+			Data:       map[string]string{auditMaxEntriesKey: "10"},
+		},
+	)
+	gets, lists := 0, 0
+	serverClient := &client.SigsClientInterfaceMock{
+		GetFunc: func(ctx context.Context, key types.NamespacedName, obj k8sclient.Object, opts ...k8sclient.GetOption) error {
+			gets++
+			return fakeClient.Get(ctx, key, obj, opts...)
+		},
+		ListFunc: func(ctx context.Context, list k8sclient.ObjectList, opts ...k8sclient.ListOption) error {
+			lists++
+			return fakeClient.List(ctx, list, opts...)
+		},
+		UpdateFunc: func(ctx context.Context, obj k8sclient.Object, opts ...k8sclient.UpdateOption) error {
+			return fakeClient.Update(ctx, obj, opts...)
+		},
+	}
+
+	auditor := NewAuditor(serverClient, record.NewFakeRecorder(10), installation)
+	auditor.Record(context.TODO(), AuditSystemRHSSO, AuditActionCreated, "alice", "", nil)
+
+	// the audit trail is updated by another reconciler in the meantime
+	configMap := &corev1.ConfigMap{}
+	if err := fakeClient.Get(context.TODO(), k8sclient.ObjectKey{Name: UserAuditTrailConfigMapName, Namespace: installation.Namespace}, configMap); err != nil {
+		t.Fatal(err)
+	}
+	configMap.Data[auditEntriesKey] = strings.Replace(configMap.Data[auditEntriesKey], "]", `,{"username":"external"}]`, 1)
+	if err := fakeClient.Update(context.TODO(), configMap); err != nil {
+		t.Fatal(err)
+	}
+
+	auditor.Record(context.TODO(), AuditSystemRHSSO, AuditActionUpdated, "alice", "", nil)
+	auditor.Record(context.TODO(), AuditSystemRHSSO, AuditActionDeleted, "deleted-user", "", nil)
+
+	if err := fakeClient.Get(context.TODO(), k8sclient.ObjectKey{Name: UserAuditTrailConfigMapName, Namespace: installation.Namespace}, configMap); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := GetAuditTrail(configMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	usernames := []string{}
+	for _, entry := range entries {
+		usernames = append(usernames, entry.Username)
+	}
+	if want := "alice,external,alice,deleted-user"; strings.Join(usernames, ",") != want {
+		t.Errorf("expected entries for %s, got %v", want, usernames)
+	}
+	// the audit trail is read again after the conflict only, the users are listed once
+	if gets != 2 || lists != 1 {
+		t.Errorf("expected 2 reads of the audit trail and 1 list of the users, got %d and %d", gets, lists)
+	}
+}
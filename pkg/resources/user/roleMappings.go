@@ -0,0 +1,184 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	usersv1 "github.com/openshift/api/user/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// RoleMappingsConfigMapName is the ConfigMap in the installation namespace that maps OpenShift groups
+	// to the roles their members get in 3scale and Keycloak
+	RoleMappingsConfigMapName = "user-role-mappings"
+	roleMappingsKey           = "mappings"
+
+	ThreeScaleAdminRole  = "admin"
+	ThreeScaleMemberRole = "member"
+
+	DedicatedAdminsGroupName = "dedicated-admins"
+	// DevelopersGroupName is the OpenShift group every user that is not excluded is a member of
+	DevelopersGroupName = "rhmi-developers"
+
+	// the attributes of the Keycloak users recording the realm roles, client roles and groups the role mappings
+	// granted them, so that only these are removed once the users leave the mapped groups
+	MappedRealmRolesAttribute  = "rhoam_mapped_realm_roles"
+	MappedClientRolesAttribute = "rhoam_mapped_client_roles"
+	MappedGroupsAttribute      = "rhoam_mapped_groups"
+)
+
+// RoleMapping grants the members of an OpenShift group roles in the products
+type RoleMapping struct {
+	Group string `json:"group"`
+	// ThreeScaleRole is the role of the members in the 3scale tenant, admin or member
+	ThreeScaleRole string `json:"threescaleRole,omitempty"`
+	// KeycloakRealmRoles are granted to the members in the realm of the cluster SSO
+	KeycloakRealmRoles []string `json:"keycloakRealmRoles,omitempty"`
+	// UserSSOAdmin makes the members administrators of the user SSO
+	UserSSOAdmin bool `json:"userSSOAdmin,omitempty"`
+}
+
+type RoleMappings []RoleMapping
+
+// DefaultRoleMappings make the dedicated admins administrators of 3scale and of the user SSO
+func DefaultRoleMappings() RoleMappings {
+	return RoleMappings{
+		{Group: DedicatedAdminsGroupName, ThreeScaleRole: ThreeScaleAdminRole, UserSSOAdmin: true},
+	}
+}
+
+func (m RoleMapping) Validate() error {
+	if m.Group == "" {
+		return fmt.Errorf("group is required")
+	}
+	if m.ThreeScaleRole != "" && m.ThreeScaleRole != ThreeScaleAdminRole && m.ThreeScaleRole != ThreeScaleMemberRole {
+		return fmt.Errorf("invalid 3scale role %s of group %s, must be %s or %s", m.ThreeScaleRole, m.Group, ThreeScaleAdminRole, ThreeScaleMemberRole)
+	}
+	for _, role := range m.KeycloakRealmRoles {
+		if role == "" {
+			return fmt.Errorf("empty keycloak realm role for group %s", m.Group)
+		}
+	}
+	return nil
+}
+
+// GetRoleMappings reads the role mappings of the installation, the defaults are used when the
+// installation does not configure them
+func GetRoleMappings(ctx context.Context, serverClient k8sclient.Client, namespace string) (RoleMappings, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := serverClient.Get(ctx, k8sclient.ObjectKey{Name: RoleMappingsConfigMapName, Namespace: namespace}, configMap); err != nil {
+		if k8serr.IsNotFound(err) {
+			return DefaultRoleMappings(), nil
+		}
+		return nil, fmt.Errorf("failed to get %s config map: %w", RoleMappingsConfigMapName, err)
+	}
+
+	value, ok := configMap.Data[roleMappingsKey]
+	if !ok {
+		return DefaultRoleMappings(), nil
+	}
+	mappings := RoleMappings{}
+	if err := json.Unmarshal([]byte(value), &mappings); err != nil {
+		return nil, fmt.Errorf("failed to parse %s key of %s config map: %w", roleMappingsKey, RoleMappingsConfigMapName, err)
+	}
+	for _, mapping := range mappings {
+		if err := mapping.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid role mapping: %w", err)
+		}
+	}
+	return mappings, nil
+}
+
+// forUser returns the mappings of the groups the user is a member of
+func (m RoleMappings) forUser(username string, groups *usersv1.GroupList) []RoleMapping {
+	mappings := []RoleMapping{}
+	for _, mapping := range m {
+		for _, group := range groups.Items {
+			if group.Name == mapping.Group && isGroupMember(username, group) {
+				mappings = append(mappings, mapping)
+				break
+			}
+		}
+	}
+	return mappings
+}
+
+// ThreeScaleRole returns the 3scale role the groups of the user are mapped to, admin taking precedence
+// over member. It is empty when none of the groups of the user are mapped to a 3scale role
+func (m RoleMappings) ThreeScaleRole(username string, groups *usersv1.GroupList) string {
+	role := ""
+	for _, mapping := range m.forUser(username, groups) {
+		if mapping.ThreeScaleRole == ThreeScaleAdminRole {
+			return ThreeScaleAdminRole
+		}
+		if mapping.ThreeScaleRole == ThreeScaleMemberRole {
+			role = ThreeScaleMemberRole
+		}
+	}
+	return role
+}
+
+// KeycloakRealmRoles returns the realm roles the groups of the user are mapped to
+func (m RoleMappings) KeycloakRealmRoles(username string, groups *usersv1.GroupList) []string {
+	roles := map[string]bool{}
+	for _, mapping := range m.forUser(username, groups) {
+		for _, role := range mapping.KeycloakRealmRoles {
+			roles[role] = true
+		}
+	}
+
+	realmRoles := make([]string, 0, len(roles))
+	for role := range roles {
+		realmRoles = append(realmRoles, role)
+	}
+	sort.Strings(realmRoles)
+	return realmRoles
+}
+
+// IsUserSSOAdmin returns true when any group of the user makes its members administrators of the user SSO
+func (m RoleMappings) IsUserSSOAdmin(username string, groups *usersv1.GroupList) bool {
+	for _, mapping := range m.forUser(username, groups) {
+		if mapping.UserSSOAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// MergeMapped returns the current values with the values granted by the mappings added, and the values the
+// mappings granted before but no longer grant removed. Values granted otherwise are kept
+func MergeMapped(current, previouslyMapped, mapped []string) []string {
+	revoked := map[string]bool{}
+	for _, value := range previouslyMapped {
+		revoked[value] = true
+	}
+	for _, value := range mapped {
+		delete(revoked, value)
+	}
+
+	merged := []string{}
+	seen := map[string]bool{}
+	for _, value := range append(append([]string{}, current...), mapped...) {
+		if revoked[value] || seen[value] {
+			continue
+		}
+		seen[value] = true
+		merged = append(merged, value)
+	}
+	return merged
+}
+
+func isGroupMember(username string, group usersv1.Group) bool {
+	for _, member := range group.Users {
+		if strings.EqualFold(member, username) {
+			return true
+		}
+	}
+	return false
+}
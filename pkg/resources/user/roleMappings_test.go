@@ -0,0 +1,174 @@
+package user
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/integr8ly/integreatly-operator/utils"
+	userv1 "github.com/openshift/api/user/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestGetRoleMappings(t *testing.T) {
+	scenarios := []struct {
+		Name         string
+		ConfigMap    *corev1.ConfigMap
+		WantMappings RoleMappings
+		WantErr      bool
+	}{
+		{
+			Name:         "the default mappings are used without a config map",
+			WantMappings: DefaultRoleMappings(),
+		},
+		{
+			Name: "mappings are read from the config map",
+			ConfigMap: &corev1.ConfigMap{
+				ObjectMeta: v1.ObjectMeta{Name: RoleMappingsConfigMapName, Namespace: "testing-namespaces-test"},
+				Data: map[string]string{
+					roleMappingsKey: `[{"group": "api-owners", "threescaleRole": "admin", "keycloakRealmRoles": ["owner"]}]`,
+				},
+			},
+			WantMappings: RoleMappings{{Group: "api-owners", ThreeScaleRole: ThreeScaleAdminRole, KeycloakRealmRoles: []string{"owner"}}},
+		},
+		{
+			Name: "unknown 3scale roles are rejected",
+			ConfigMap: &corev1.ConfigMap{
+				ObjectMeta: v1.ObjectMeta{Name: RoleMappingsConfigMapName, Namespace: "testing-namespaces-test"},
+				Data:       map[string]string{roleMappingsKey: `[{"group": "api-owners", "threescaleRole": "owner"}]`},
+			},
+			WantErr: true,
+		},
+		{
+			Name: "mappings without a group are rejected",
+			ConfigMap: &corev1.ConfigMap{
+				ObjectMeta: v1.ObjectMeta{Name: RoleMappingsConfigMapName, Namespace: "testing-namespaces-test"},
+				Data:       map[string]string{roleMappingsKey: `[{"threescaleRole": "member"}]`},
+			},
+			WantErr: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			scheme, err := utils.NewTestScheme()
+			if err != nil {
+				t.Fatal(err)
+			}
+			objects := []runtime.Object{}
+			if scenario.ConfigMap != nil {
+				objects = append(objects, scenario.ConfigMap)
+			}
+
+			mappings, err := GetRoleMappings(context.TODO(), utils.NewTestClient(scheme, objects...), "testing-namespaces-test")
+			if (err != nil) != scenario.WantErr {
+				t.Fatalf("unexpected error %v, wantErr %v", err, scenario.WantErr)
+			}
+			if !scenario.WantErr && !reflect.DeepEqual(mappings, scenario.WantMappings) {
+				t.Errorf("expected mappings %+v, got %+v", scenario.WantMappings, mappings)
+			}
+		})
+	}
+}
+
+func TestRoleMappingsForUser(t *testing.T) {
+	mappings := RoleMappings{
+		{Group: DedicatedAdminsGroupName, ThreeScaleRole: ThreeScaleAdminRole, UserSSOAdmin: true},
+		{Group: "developers", ThreeScaleRole: ThreeScaleMemberRole, KeycloakRealmRoles: []string{"developer"}},
+		{Group: "reviewers", KeycloakRealmRoles: []string{"reviewer", "developer"}},
+	}
+	groups := &userv1.GroupList{
+		Items: []userv1.Group{
+			{ObjectMeta: v1.ObjectMeta{Name: DedicatedAdminsGroupName}, Users: userv1.OptionalNames{"admin"}},
+			{ObjectMeta: v1.ObjectMeta{Name: "developers"}, Users: userv1.OptionalNames{"admin", "dev"}},
+			{ObjectMeta: v1.ObjectMeta{Name: "reviewers"}, Users: userv1.OptionalNames{"Dev"}},
+		},
+	}
+
+	scenarios := []struct {
+		Name               string
+		Username           string
+		WantThreeScaleRole string
+		WantRealmRoles     []string
+		WantUserSSOAdmin   bool
+	}{
+		{
+			Name:               "admin takes precedence over member",
+			Username:           "admin",
+			WantThreeScaleRole: ThreeScaleAdminRole,
+			WantRealmRoles:     []string{"developer"},
+			WantUserSSOAdmin:   true,
+		},
+		{
+			Name:               "roles of all groups are combined",
+			Username:           "dev",
+			WantThreeScaleRole: ThreeScaleMemberRole,
+			WantRealmRoles:     []string{"developer", "reviewer"},
+		},
+		{
+			Name:           "users without mapped groups have no roles",
+			Username:       "guest",
+			WantRealmRoles: []string{},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			if got := mappings.ThreeScaleRole(scenario.Username, groups); got != scenario.WantThreeScaleRole {
+				t.Errorf("expected 3scale role %q, got %q", scenario.WantThreeScaleRole, got)
+			}
+			if got := mappings.KeycloakRealmRoles(scenario.Username, groups); !reflect.DeepEqual(got, scenario.WantRealmRoles) {
+				t.Errorf("expected realm roles %v, got %v", scenario.WantRealmRoles, got)
+			}
+			if got := mappings.IsUserSSOAdmin(scenario.Username, groups); got != scenario.WantUserSSOAdmin {
+				t.Errorf("expected user SSO admin %v, got %v", scenario.WantUserSSOAdmin, got)
+			}
+		})
+	}
+}
+
+func TestMergeMapped(t *testing.T) {
+	scenarios := []struct {
+		Name             string
+		Current          []string
+		PreviouslyMapped []string
+		Mapped           []string
+		Want             []string
+	}{
+		{
+			Name:    "mapped values are granted",
+			Current: []string{"offline_access"},
+			Mapped:  []string{"developer"},
+			Want:    []string{"offline_access", "developer"},
+		},
+		{
+			Name:             "values the mappings no longer grant are removed",
+			Current:          []string{"offline_access", "developer", "reviewer"},
+			PreviouslyMapped: []string{"developer", "reviewer"},
+			Mapped:           []string{"reviewer"},
+			Want:             []string{"offline_access", "reviewer"},
+		},
+		{
+			Name:             "values granted otherwise are kept",
+			Current:          []string{"offline_access", "auditor"},
+			PreviouslyMapped: []string{"developer"},
+			Want:             []string{"offline_access", "auditor"},
+		},
+		{
+			Name:    "values are not duplicated",
+			Current: []string{"developer"},
+			Mapped:  []string{"developer"},
+			Want:    []string{"developer"},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			if got := MergeMapped(scenario.Current, scenario.PreviouslyMapped, scenario.Mapped); !reflect.DeepEqual(got, scenario.Want) {
+				t.Errorf("expected %v, got %v", scenario.Want, got)
+			}
+		})
+	}
+}
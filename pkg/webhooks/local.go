@@ -0,0 +1,130 @@
+package webhooks
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// LocalURLEnvVar is the URL the cluster reaches the webhook server on when the operator runs
+	// locally, e.g. https://192.168.1.10:8090. Setting it enables the webhooks outside of the cluster
+	LocalURLEnvVar = "WEBHOOKS_LOCAL_URL"
+	// LocalIgnoreFailuresEnvVar set to true registers the local webhooks with the Ignore failure policy,
+	// so that requests are admitted while the operator is stopped
+	LocalIgnoreFailuresEnvVar = "WEBHOOKS_LOCAL_IGNORE_FAILURES"
+
+	localCertValidity = 365 * 24 * time.Hour
+)
+
+// localCerts is a self-signed CA and a serving certificate issued by it
+type localCerts struct {
+	caCert  []byte
+	tlsCert []byte
+	tlsKey  []byte
+}
+
+func (webhookConfig *IntegreatlyWebhookConfig) local() bool {
+	return webhookConfig.LocalURL != ""
+}
+
+// setupLocalCerts generates the certificates of the webhook server when the operator runs locally, as
+// the service CA only issues them for the operator Service. The certificates are saved in a temporary
+// CertDir and the CA is kept to be set as the CA bundle of the webhook configurations
+func (webhookConfig *IntegreatlyWebhookConfig) setupLocalCerts() error {
+	localURL, err := url.Parse(webhookConfig.LocalURL)
+	if err != nil {
+		return fmt.Errorf("invalid %s %s: %w", LocalURLEnvVar, webhookConfig.LocalURL, err)
+	}
+	if localURL.Scheme != "https" || localURL.Hostname() == "" {
+		return fmt.Errorf("invalid %s %s: must be an https URL", LocalURLEnvVar, webhookConfig.LocalURL)
+	}
+
+	certs, err := generateLocalCerts(localURL.Hostname(), time.Now())
+	if err != nil {
+		return err
+	}
+
+	certDir, err := os.MkdirTemp("", "rhmi-webhook-certs")
+	if err != nil {
+		return err
+	}
+	webhookConfig.CertDir = certDir
+	if err := os.WriteFile(filepath.Join(certDir, "tls.crt"), certs.tlsCert, 0600); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(certDir, "tls.key"), certs.tlsKey, 0600); err != nil {
+		return err
+	}
+
+	webhookConfig.caBundle = certs.caCert
+	return nil
+}
+
+// generateLocalCerts generates a CA and a serving certificate for host, which is either a DNS name or
+// an IP address
+func generateLocalCerts(host string, now time.Time) (*localCerts, error) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate the CA key: %w", err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "rhmi-webhooks-local-ca"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(localCertValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the CA certificate: %w", err)
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate the serving key: %w", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: host},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(localCertValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		template.IPAddresses = []net.IP{ip}
+	} else {
+		template.DNSNames = []string{host}
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the serving certificate: %w", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &localCerts{
+		caCert:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+		tlsCert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}),
+		tlsKey:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+	}, nil
+}
@@ -0,0 +1,137 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/integr8ly/integreatly-operator/api/v1alpha1"
+	"github.com/integr8ly/integreatly-operator/utils"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestGenerateLocalCerts(t *testing.T) {
+	scenarios := []struct {
+		Name string
+		Host string
+	}{
+		{
+			Name: "certificate for an IP address",
+			Host: "192.168.1.10",
+		},
+		{
+			Name: "certificate for a DNS name",
+			Host: "webhooks.example.com",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			certs, err := generateLocalCerts(scenario.Host, time.Now())
+			if err != nil {
+				t.Fatalf("failed to generate certificates: %v", err)
+			}
+
+			keyPair, err := tls.X509KeyPair(certs.tlsCert, certs.tlsKey)
+			if err != nil {
+				t.Fatalf("expected a valid key pair: %v", err)
+			}
+			cert, err := x509.ParseCertificate(keyPair.Certificate[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			roots := x509.NewCertPool()
+			if !roots.AppendCertsFromPEM(certs.caCert) {
+				t.Fatal("expected a PEM encoded CA")
+			}
+			if _, err := cert.Verify(x509.VerifyOptions{DNSName: scenario.Host, Roots: roots}); err != nil {
+				t.Errorf("expected the certificate to be valid for %s: %v", scenario.Host, err)
+			}
+		})
+	}
+}
+
+func TestReconcileLocal(t *testing.T) {
+	testScheme, err := utils.NewTestScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	settings := IntegreatlyWebhookConfig{
+		Enabled:  true,
+		scheme:   testScheme,
+		LocalURL: "https://192.168.1.10:8090/",
+		Webhooks: []IntegreatlyWebhook{
+			{
+				Name: "rhmi-delete",
+				Rule: NewRule().
+					OneResource("integreatly.org", "v1alpha1", "rhmis").
+					ForDelete().
+					NamespacedScope(),
+				Register: AdmissionWebhookRegister{
+					Type: ValidatingType,
+					Path: "/delete-rhmi",
+				},
+			},
+		},
+	}
+	if err := settings.setupLocalCerts(); err != nil {
+		t.Fatalf("failed to set up the local certificates: %v", err)
+	}
+	defer os.RemoveAll(settings.CertDir)
+
+	if _, err := tls.LoadX509KeyPair(filepath.Join(settings.CertDir, "tls.crt"), filepath.Join(settings.CertDir, "tls.key")); err != nil {
+		t.Fatalf("expected the certificates to be saved for the webhook server: %v", err)
+	}
+
+	rhmi := &v1alpha1.RHMI{}
+	client := utils.NewTestClient(testScheme, rhmi)
+	if err := settings.Reconcile(context.TODO(), client, rhmi); err != nil {
+		t.Fatalf("Error reconciling webhook objects: %v", err)
+	}
+
+	vwc := &admissionv1.ValidatingWebhookConfiguration{}
+	if err := client.Get(context.TODO(), k8sclient.ObjectKey{Name: "rhmi-delete.integreatly.org"}, vwc); err != nil {
+		t.Fatalf("Error finding ValidatingWebhookConfig: %v", err)
+	}
+	clientConfig := vwc.Webhooks[0].ClientConfig
+	if clientConfig.URL == nil || *clientConfig.URL != "https://192.168.1.10:8090/delete-rhmi" {
+		t.Errorf("Expected the webhook to point to the local URL, got %+v", clientConfig)
+	}
+	if clientConfig.Service != nil {
+		t.Errorf("Expected no service reference, got %+v", clientConfig.Service)
+	}
+	if string(clientConfig.CABundle) != string(settings.caBundle) {
+		t.Error("Expected the CA bundle to be the generated CA")
+	}
+	if *vwc.Webhooks[0].FailurePolicy != admissionv1.Fail {
+		t.Errorf("Expected local webhooks to fail closed by default, got %s", *vwc.Webhooks[0].FailurePolicy)
+	}
+
+	settings.LocalIgnoreFailures = true
+	if err := settings.Reconcile(context.TODO(), client, rhmi); err != nil {
+		t.Fatalf("Error reconciling webhook objects: %v", err)
+	}
+	if err := client.Get(context.TODO(), k8sclient.ObjectKey{Name: "rhmi-delete.integreatly.org"}, vwc); err != nil {
+		t.Fatalf("Error finding ValidatingWebhookConfig: %v", err)
+	}
+	if *vwc.Webhooks[0].FailurePolicy != admissionv1.Ignore {
+		t.Errorf("Expected local webhooks to be ignored when opted in, got %s", *vwc.Webhooks[0].FailurePolicy)
+	}
+
+	services := &corev1.ServiceList{}
+	if err := client.List(context.TODO(), services); err != nil && !k8serr.IsNotFound(err) {
+		t.Fatal(err)
+	}
+	if len(services.Items) != 0 {
+		t.Errorf("Expected no operator service to be created, got %d", len(services.Items))
+	}
+}
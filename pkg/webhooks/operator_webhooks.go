@@ -10,6 +10,7 @@ import (
 
 	"github.com/operator-framework/operator-lifecycle-manager/pkg/lib/ownerutil"
 	pkgerr "github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -35,6 +36,15 @@ type IntegreatlyWebhookConfig struct {
 	CertDir     string
 	CAConfigMap string
 
+	// LocalURL is the URL the webhook server is reached on when the operator runs
+	// locally. When it's set, the certificates are generated by the operator and
+	// the webhook configurations point to the URL instead of the operator Service
+	LocalURL string
+	// LocalIgnoreFailures registers the webhooks with the Ignore failure policy when the operator runs
+	// locally, so that requests are admitted when it is stopped. The webhooks fail closed otherwise
+	LocalIgnoreFailures bool
+	caBundle            []byte
+
 	Webhooks []IntegreatlyWebhook
 }
 
@@ -97,13 +107,20 @@ func (webhookConfig *IntegreatlyWebhookConfig) SetupServer(mgr manager.Manager)
 		return err
 	}
 
-	// Create the service pointing to the operator pod
-	if err := webhookConfig.ReconcileService(context.TODO(), client, nil); err != nil {
-		return err
-	}
-	// Get the secret with the certificates for the service
-	if err := webhookConfig.setupCerts(context.TODO(), client); err != nil {
-		return err
+	if webhookConfig.local() {
+		// Generate the certificates for the local URL
+		if err := webhookConfig.setupLocalCerts(); err != nil {
+			return err
+		}
+	} else {
+		// Create the service pointing to the operator pod
+		if err := webhookConfig.ReconcileService(context.TODO(), client, nil); err != nil {
+			return err
+		}
+		// Get the secret with the certificates for the service
+		if err := webhookConfig.setupCerts(context.TODO(), client); err != nil {
+			return err
+		}
 	}
 
 	// Create a webhook server.
@@ -141,6 +158,10 @@ func (webhookConfig *IntegreatlyWebhookConfig) Reconcile(ctx context.Context, cl
 		return nil
 	}
 
+	if webhookConfig.local() {
+		return webhookConfig.reconcileWebhooks(ctx, client, webhookConfig.caBundle)
+	}
+
 	watchNS, err := k8s.GetWatchNamespace()
 	if err != nil {
 		return pkgerr.Wrap(err, "could not get watch namespace from operator_webhooks reconcile")
@@ -176,7 +197,11 @@ func (webhookConfig *IntegreatlyWebhookConfig) Reconcile(ctx context.Context, cl
 		return err
 	}
 
-	// Reconcile the webhooks
+	return webhookConfig.reconcileWebhooks(ctx, client, caBundle)
+}
+
+// reconcileWebhooks reconciles the webhook configuration CRs of each webhook
+func (webhookConfig *IntegreatlyWebhookConfig) reconcileWebhooks(ctx context.Context, client k8sclient.Client, caBundle []byte) error {
 	for _, webhook := range webhookConfig.Webhooks {
 		reconciler, err := webhook.Register.GetReconciler(webhookConfig.scheme)
 		if err != nil {
@@ -185,6 +210,10 @@ func (webhookConfig *IntegreatlyWebhookConfig) Reconcile(ctx context.Context, cl
 
 		reconciler.SetName(webhook.Name)
 		reconciler.SetRule(webhook.Rule)
+		reconciler.SetLocalURL(webhookConfig.LocalURL)
+		if webhookConfig.local() && webhookConfig.LocalIgnoreFailures {
+			reconciler.SetFailurePolicy(admissionv1.Ignore)
+		}
 
 		if err := reconciler.Reconcile(ctx, client, caBundle); err != nil {
 			return err
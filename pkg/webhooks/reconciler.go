@@ -18,6 +18,8 @@ import (
 type WebhookReconciler interface {
 	SetName(name string)
 	SetRule(rule RuleWithOperations)
+	SetLocalURL(localURL string)
+	SetFailurePolicy(failurePolicy admissionv1.FailurePolicyType)
 	Reconcile(ctx context.Context, client k8sclient.Client, caBundle []byte) error
 }
 
@@ -37,6 +39,18 @@ func (reconciler *CompositeWebhookReconciler) SetRule(rule RuleWithOperations) {
 	}
 }
 
+func (reconciler *CompositeWebhookReconciler) SetLocalURL(localURL string) {
+	for _, innerReconciler := range reconciler.Reconcilers {
+		innerReconciler.SetLocalURL(localURL)
+	}
+}
+
+func (reconciler *CompositeWebhookReconciler) SetFailurePolicy(failurePolicy admissionv1.FailurePolicyType) {
+	for _, innerReconciler := range reconciler.Reconcilers {
+		innerReconciler.SetFailurePolicy(failurePolicy)
+	}
+}
+
 func (reconciler *CompositeWebhookReconciler) Reconcile(ctx context.Context, client k8sclient.Client, caBundle []byte) error {
 	for _, innerReconciler := range reconciler.Reconcilers {
 		if err := innerReconciler.Reconcile(ctx, client, caBundle); err != nil {
@@ -48,31 +62,35 @@ func (reconciler *CompositeWebhookReconciler) Reconcile(ctx context.Context, cli
 }
 
 type ValidatingWebhookReconciler struct {
-	Path string
-	name string
-	rule RuleWithOperations
+	Path          string
+	name          string
+	rule          RuleWithOperations
+	localURL      string
+	failurePolicy admissionv1.FailurePolicyType
 }
 
 type MutatingWebhookReconciler struct {
-	Path string
-	name string
-	rule RuleWithOperations
+	Path          string
+	name          string
+	rule          RuleWithOperations
+	localURL      string
+	failurePolicy admissionv1.FailurePolicyType
 }
 
 func (reconciler *MutatingWebhookReconciler) Reconcile(ctx context.Context, client k8sclient.Client, caBundle []byte) error {
 	var (
 		sideEffects    = admissionv1.SideEffectClassNone
-		port           = int32(servicePort)
 		matchPolicy    = admissionv1.Exact
 		failurePolicy  = admissionv1.Fail
 		timeoutSeconds = int32(30)
 	)
-	watchNS, err := k8s.GetWatchNamespace()
+	clientConfig, err := webhookClientConfig(reconciler.Path, reconciler.localURL, caBundle)
 	if err != nil {
-		return pkgerr.Wrap(err, "could not get watch namespace from operator_webhooks reconcile")
+		return err
+	}
+	if reconciler.failurePolicy != "" {
+		failurePolicy = reconciler.failurePolicy
 	}
-	namespaceSegments := strings.Split(watchNS, "-")
-	namespacePrefix := strings.Join(namespaceSegments[0:2], "-") + "-"
 	cr := &admissionv1.MutatingWebhookConfiguration{
 		ObjectMeta: v1.ObjectMeta{
 			Name: fmt.Sprintf("%s.integreatly.org", reconciler.name),
@@ -82,17 +100,9 @@ func (reconciler *MutatingWebhookReconciler) Reconcile(ctx context.Context, clie
 	_, err = controllerutil.CreateOrUpdate(ctx, client, cr, func() error {
 		cr.Webhooks = []admissionv1.MutatingWebhook{
 			{
-				Name:        fmt.Sprintf("%s-mutating-config.integreatly.org", reconciler.name),
-				SideEffects: &sideEffects,
-				ClientConfig: admissionv1.WebhookClientConfig{
-					CABundle: caBundle,
-					Service: &admissionv1.ServiceReference{
-						Namespace: namespacePrefix + "operator",
-						Name:      operatorPodServiceName,
-						Path:      &reconciler.Path,
-						Port:      &port,
-					},
-				},
+				Name:         fmt.Sprintf("%s-mutating-config.integreatly.org", reconciler.name),
+				SideEffects:  &sideEffects,
+				ClientConfig: clientConfig,
 				Rules: []admissionv1.RuleWithOperations{
 					{
 						Operations: reconciler.rule.Operations,
@@ -118,17 +128,17 @@ func (reconciler *MutatingWebhookReconciler) Reconcile(ctx context.Context, clie
 func (reconciler *ValidatingWebhookReconciler) Reconcile(ctx context.Context, client k8sclient.Client, caBundle []byte) error {
 	var (
 		sideEffects    = admissionv1.SideEffectClassNone
-		port           = int32(servicePort)
 		matchPolicy    = admissionv1.Exact
 		failurePolicy  = admissionv1.Fail
 		timeoutSeconds = int32(30)
 	)
-	watchNS, err := k8s.GetWatchNamespace()
+	clientConfig, err := webhookClientConfig(reconciler.Path, reconciler.localURL, caBundle)
 	if err != nil {
-		return pkgerr.Wrap(err, "could not get watch namespace from operator_webhooks reconcile")
+		return err
+	}
+	if reconciler.failurePolicy != "" {
+		failurePolicy = reconciler.failurePolicy
 	}
-	namespaceSegments := strings.Split(watchNS, "-")
-	namespacePrefix := strings.Join(namespaceSegments[0:2], "-") + "-"
 	cr := &admissionv1.ValidatingWebhookConfiguration{
 		ObjectMeta: v1.ObjectMeta{
 			Name: fmt.Sprintf("%s.integreatly.org", reconciler.name),
@@ -138,17 +148,9 @@ func (reconciler *ValidatingWebhookReconciler) Reconcile(ctx context.Context, cl
 	_, err = controllerutil.CreateOrUpdate(ctx, client, cr, func() error {
 		cr.Webhooks = []admissionv1.ValidatingWebhook{
 			{
-				Name:        fmt.Sprintf("%s-validating-config.integreatly.org", reconciler.name),
-				SideEffects: &sideEffects,
-				ClientConfig: admissionv1.WebhookClientConfig{
-					CABundle: caBundle,
-					Service: &admissionv1.ServiceReference{
-						Namespace: namespacePrefix + "operator",
-						Name:      operatorPodServiceName,
-						Path:      &reconciler.Path,
-						Port:      &port,
-					},
-				},
+				Name:         fmt.Sprintf("%s-validating-config.integreatly.org", reconciler.name),
+				SideEffects:  &sideEffects,
+				ClientConfig: clientConfig,
 				Rules: []admissionv1.RuleWithOperations{
 					{
 						Operations: reconciler.rule.Operations,
@@ -186,3 +188,49 @@ func (reconciler *ValidatingWebhookReconciler) SetRule(rule RuleWithOperations)
 func (reconciler *MutatingWebhookReconciler) SetRule(rule RuleWithOperations) {
 	reconciler.rule = rule
 }
+
+func (reconciler *ValidatingWebhookReconciler) SetLocalURL(localURL string) {
+	reconciler.localURL = localURL
+}
+
+func (reconciler *MutatingWebhookReconciler) SetLocalURL(localURL string) {
+	reconciler.localURL = localURL
+}
+
+func (reconciler *ValidatingWebhookReconciler) SetFailurePolicy(failurePolicy admissionv1.FailurePolicyType) {
+	reconciler.failurePolicy = failurePolicy
+}
+
+func (reconciler *MutatingWebhookReconciler) SetFailurePolicy(failurePolicy admissionv1.FailurePolicyType) {
+	reconciler.failurePolicy = failurePolicy
+}
+
+// webhookClientConfig points the webhook to the path of the operator Service, or
+// of the local URL when the operator runs locally
+func webhookClientConfig(path, localURL string, caBundle []byte) (admissionv1.WebhookClientConfig, error) {
+	if localURL != "" {
+		url := strings.TrimSuffix(localURL, "/") + path
+		return admissionv1.WebhookClientConfig{
+			CABundle: caBundle,
+			URL:      &url,
+		}, nil
+	}
+
+	watchNS, err := k8s.GetWatchNamespace()
+	if err != nil {
+		return admissionv1.WebhookClientConfig{}, pkgerr.Wrap(err, "could not get watch namespace from operator_webhooks reconcile")
+	}
+	namespaceSegments := strings.Split(watchNS, "-")
+	namespacePrefix := strings.Join(namespaceSegments[0:2], "-") + "-"
+	port := int32(servicePort)
+
+	return admissionv1.WebhookClientConfig{
+		CABundle: caBundle,
+		Service: &admissionv1.ServiceReference{
+			Namespace: namespacePrefix + "operator",
+			Name:      operatorPodServiceName,
+			Path:      &path,
+			Port:      &port,
+		},
+	}, nil
+}